@@ -0,0 +1,443 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fileartifact provides an [artifact.Service] backed by the local
+// filesystem.
+//
+// Artifacts are stored under a root directory using the same layout as the
+// blob names of gcsartifact:
+//
+//	<root>/<app>/<user>/<session>/<file>/<version>
+//	<root>/<app>/<user>/user/<file>/<version>   (files named "user:...")
+//
+// with the names of the files escaped, e.g. "user%3Aprofile.png".
+//
+// Each version file holds the raw artifact bytes and is accompanied by a
+// "<version>.metadata.json" sidecar recording its MIME type. Path components
+// are escaped so that names cannot escape the root directory.
+package fileartifact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+// Config is the configuration of the filesystem artifact service.
+type Config struct {
+	// MaxArtifactSize is the maximum size in bytes of a single artifact
+	// version. Zero means no limit.
+	MaxArtifactSize int64
+}
+
+// fileService is a local filesystem implementation of the Service.
+type fileService struct {
+	root string
+	cfg  Config
+
+	// mu serializes version allocation within the process. Concurrent
+	// writers in other processes are handled by exclusive file creation.
+	mu sync.Mutex
+}
+
+// NewService creates an artifact service that stores artifacts under
+// rootDir. The directory is created if it does not exist.
+func NewService(rootDir string, cfg Config) (artifact.Service, error) {
+	if rootDir == "" {
+		return nil, fmt.Errorf("root directory is required")
+	}
+	if cfg.MaxArtifactSize < 0 {
+		return nil, fmt.Errorf("invalid max artifact size %d", cfg.MaxArtifactSize)
+	}
+	root, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve root directory: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create root directory: %w", err)
+	}
+	return &fileService{root: root, cfg: cfg}, nil
+}
+
+// metadataSuffix is the suffix of the sidecar file of a version.
+const metadataSuffix = ".metadata.json"
+
+// tempPrefix is the prefix of the temporary files used for atomic writes.
+const tempPrefix = ".tmp-"
+
+// metadata is the content of a version sidecar file.
+type metadata struct {
+	// MIMEType is the MIME type of inline data artifacts.
+	MIMEType string `json:"mimeType,omitempty"`
+	// Text is set when the artifact is a text part.
+	Text bool `json:"text,omitempty"`
+	// Size is the size of the artifact in bytes.
+	Size int64 `json:"size"`
+}
+
+// fileHasUserNamespace checks if a filename indicates a user-namespaced file.
+func fileHasUserNamespace(filename string) bool {
+	return strings.HasPrefix(filename, "user:")
+}
+
+// userScopedDir is the name of the directory that holds user-scoped files in
+// place of a session directory.
+const userScopedDir = "user"
+
+// escape returns name as a single safe path component. Colons, which
+// url.PathEscape keeps, are escaped too since Windows does not allow them
+// in file names, e.g. in the names of "user:" artifacts.
+func escape(name string) (string, error) {
+	if name == "" || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid path component %q", name)
+	}
+	escaped := strings.ReplaceAll(url.PathEscape(name), ":", "%3A")
+	if strings.HasPrefix(escaped, tempPrefix) {
+		escaped = "%2E" + escaped[1:]
+	}
+	return escaped, nil
+}
+
+func unescape(name string) (string, bool) {
+	s, err := url.PathUnescape(name)
+	return s, err == nil
+}
+
+// dir joins and escapes the given path components under the root directory.
+func (s *fileService) dir(components ...string) (string, error) {
+	parts := []string{s.root}
+	for _, c := range components {
+		escaped, err := escape(c)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, escaped)
+	}
+	return filepath.Join(parts...), nil
+}
+
+// fileDir returns the directory that holds the versions of a file.
+func (s *fileService) fileDir(appName, userID, sessionID, fileName string) (string, error) {
+	if fileHasUserNamespace(fileName) {
+		sessionID = userScopedDir
+	}
+	return s.dir(appName, userID, sessionID, fileName)
+}
+
+func versionPath(dir string, version int64) string {
+	return filepath.Join(dir, strconv.FormatInt(version, 10))
+}
+
+// versions returns the versions stored in dir in ascending order. Versions
+// whose sidecar was written but whose data was not are included only if
+// includeReserved is set.
+func versions(dir string, includeReserved bool) ([]int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read artifact directory: %w", err)
+	}
+	set := map[int64]bool{}
+	for _, e := range entries {
+		name := e.Name()
+		if includeReserved {
+			name = strings.TrimSuffix(name, metadataSuffix)
+		}
+		// if the file version is not convertible to number, just ignore it
+		v, err := strconv.ParseInt(name, 10, 64)
+		if err != nil || v <= 0 {
+			continue
+		}
+		set[v] = true
+	}
+	vs := slices.Collect(maps.Keys(set))
+	slices.Sort(vs)
+	return vs, nil
+}
+
+// Save implements [artifact.Service]
+func (s *fileService) Save(ctx context.Context, req *artifact.SaveRequest) (*artifact.SaveResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	dir, err := s.fileDir(req.AppName, req.UserID, req.SessionID, req.FileName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid save request: %w", err)
+	}
+
+	var data []byte
+	var meta metadata
+	if req.Part.InlineData != nil {
+		data = req.Part.InlineData.Data
+		meta.MIMEType = req.Part.InlineData.MIMEType
+	} else {
+		data = []byte(req.Part.Text)
+		meta.MIMEType = "text/plain"
+		meta.Text = true
+	}
+	meta.Size = int64(len(data))
+	if s.cfg.MaxArtifactSize > 0 && meta.Size > s.cfg.MaxArtifactSize {
+		return nil, fmt.Errorf("artifact size %d exceeds the limit of %d bytes", meta.Size, s.cfg.MaxArtifactSize)
+	}
+	metaJSON, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artifact metadata: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	dataTmp, err := writeTemp(dir, data)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(dataTmp)
+	metaTmp, err := writeTemp(dir, metaJSON)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(metaTmp)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := versions(dir, true)
+	if err != nil {
+		return nil, err
+	}
+	nextVersion := int64(1)
+	if len(existing) > 0 {
+		nextVersion = existing[len(existing)-1] + 1
+	}
+	// The sidecar is linked first: os.Link fails if the target exists, which
+	// reserves the version even against writers in other processes. The data
+	// file is renamed into place last, so a version becomes visible only
+	// once it is complete.
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		err := os.Link(metaTmp, versionPath(dir, nextVersion)+metadataSuffix)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to write artifact metadata: %w", err)
+		}
+		nextVersion++
+	}
+	if err := os.Rename(dataTmp, versionPath(dir, nextVersion)); err != nil {
+		os.Remove(versionPath(dir, nextVersion) + metadataSuffix)
+		return nil, fmt.Errorf("failed to write artifact: %w", err)
+	}
+	return &artifact.SaveResponse{Version: nextVersion}, nil
+}
+
+// writeTemp writes data to a new temporary file in dir and returns its path.
+func writeTemp(dir string, data []byte) (_ string, err error) {
+	f, err := os.CreateTemp(dir, tempPrefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close temporary file: %w", closeErr)
+		}
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+	if _, err := f.Write(data); err != nil {
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return "", fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// Delete implements [artifact.Service]
+func (s *fileService) Delete(ctx context.Context, req *artifact.DeleteRequest) error {
+	err := req.Validate()
+	if err != nil {
+		return fmt.Errorf("request validation failed: %w", err)
+	}
+	dir, err := s.fileDir(req.AppName, req.UserID, req.SessionID, req.FileName)
+	if err != nil {
+		return fmt.Errorf("invalid delete request: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.Version != 0 {
+		path := versionPath(dir, req.Version)
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to delete artifact: %w", err)
+		}
+		if err := os.Remove(path + metadataSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to delete artifact metadata: %w", err)
+		}
+		return nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to delete artifact: %w", err)
+	}
+	return nil
+}
+
+// Load implements [artifact.Service]
+func (s *fileService) Load(ctx context.Context, req *artifact.LoadRequest) (*artifact.LoadResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	dir, err := s.fileDir(req.AppName, req.UserID, req.SessionID, req.FileName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid load request: %w", err)
+	}
+
+	version := req.Version
+	if version <= 0 {
+		vs, err := versions(dir, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(vs) == 0 {
+			return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+		}
+		version = vs[len(vs)-1]
+	}
+
+	path := versionPath(dir, version)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	meta := metadata{MIMEType: "application/octet-stream"}
+	metaJSON, err := os.ReadFile(path + metadataSuffix)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(metaJSON, &meta); err != nil {
+			return nil, fmt.Errorf("failed to parse artifact metadata: %w", err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to read artifact metadata: %w", err)
+	}
+
+	if meta.Text {
+		return &artifact.LoadResponse{Part: genai.NewPartFromText(string(data))}, nil
+	}
+	return &artifact.LoadResponse{Part: genai.NewPartFromBytes(data, meta.MIMEType)}, nil
+}
+
+// List implements [artifact.Service]
+func (s *fileService) List(ctx context.Context, req *artifact.ListRequest) (*artifact.ListResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	sessionDir, err := s.dir(req.AppName, req.UserID, req.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid list request: %w", err)
+	}
+	userDir, err := s.dir(req.AppName, req.UserID, userScopedDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid list request: %w", err)
+	}
+
+	filenamesSet := map[string]bool{}
+	if err := fetchFilenames(sessionDir, filenamesSet); err != nil {
+		return nil, fmt.Errorf("failed to fetch session filenames: %w", err)
+	}
+	if err := fetchFilenames(userDir, filenamesSet); err != nil {
+		return nil, fmt.Errorf("failed to fetch user filenames: %w", err)
+	}
+
+	filenames := slices.Collect(maps.Keys(filenamesSet))
+	sort.Strings(filenames)
+	return &artifact.ListResponse{FileNames: filenames}, nil
+}
+
+// fetchFilenames adds the names of the files with at least one version
+// stored in dir to filenamesSet.
+func fetchFilenames(dir string, filenamesSet map[string]bool) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		name, ok := unescape(e.Name())
+		if !ok {
+			continue
+		}
+		vs, err := versions(filepath.Join(dir, e.Name()), false)
+		if err != nil {
+			return err
+		}
+		if len(vs) > 0 {
+			filenamesSet[name] = true
+		}
+	}
+	return nil
+}
+
+// Versions implements [artifact.Service] and returns an error if no versions are found.
+func (s *fileService) Versions(ctx context.Context, req *artifact.VersionsRequest) (*artifact.VersionsResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	dir, err := s.fileDir(req.AppName, req.UserID, req.SessionID, req.FileName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid versions request: %w", err)
+	}
+	vs, err := versions(dir, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(vs) == 0 {
+		return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+	}
+	return &artifact.VersionsResponse{Versions: vs}, nil
+}
+
+var _ artifact.Service = (*fileService)(nil)
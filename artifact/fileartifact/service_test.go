@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileartifact_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/artifact/fileartifact"
+	"google.golang.org/adk/internal/artifact/tests"
+)
+
+func TestFileArtifactService(t *testing.T) {
+	factory := func(t *testing.T) (artifact.Service, error) {
+		return fileartifact.NewService(t.TempDir(), fileartifact.Config{})
+	}
+	tests.TestArtifactService(t, "File", factory)
+}
+
+func TestFileArtifactService_Layout(t *testing.T) {
+	ctx := t.Context()
+	root := t.TempDir()
+	srv, err := fileartifact.NewService(root, fileartifact.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"notes.txt", "user:profile.png"} {
+		if _, err := srv.Save(ctx, &artifact.SaveRequest{
+			AppName: "app", UserID: "u1", SessionID: "../s1", FileName: name,
+			Part: genai.NewPartFromText("hello"),
+		}); err != nil {
+			t.Fatalf("Save(%q) error = %v", name, err)
+		}
+	}
+	for _, path := range []string{
+		"app/u1/..%2Fs1/notes.txt/1",
+		"app/u1/..%2Fs1/notes.txt/1.metadata.json",
+		"app/u1/user/user%3Aprofile.png/1",
+	} {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(path))); err != nil {
+			t.Errorf("Stat(%q) error = %v", path, err)
+		}
+	}
+
+	list, err := srv.List(ctx, &artifact.ListRequest{AppName: "app", UserID: "u1", SessionID: "../s1"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"notes.txt", "user:profile.png"}, list.FileNames); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Text parts are restored as text.
+	got, err := srv.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "u1", SessionID: "../s1", FileName: "notes.txt"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if diff := cmp.Diff(genai.NewPartFromText("hello"), got.Part); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := srv.Save(ctx, &artifact.SaveRequest{
+		AppName: "..", UserID: "u1", SessionID: "s1", FileName: "f",
+		Part: genai.NewPartFromText("hello"),
+	}); err == nil {
+		t.Error("Save() with app name \"..\" succeeded, want error")
+	}
+}
+
+func TestFileArtifactService_MaxArtifactSize(t *testing.T) {
+	ctx := t.Context()
+	srv, err := fileartifact.NewService(t.TempDir(), fileartifact.Config{MaxArtifactSize: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &artifact.SaveRequest{AppName: "app", UserID: "u1", SessionID: "s1", FileName: "f", Part: genai.NewPartFromBytes([]byte("12345"), "text/plain")}
+	if _, err := srv.Save(ctx, req); err == nil {
+		t.Error("Save() of an oversized artifact succeeded, want error")
+	}
+	req.Part = genai.NewPartFromBytes([]byte("1234"), "text/plain")
+	if _, err := srv.Save(ctx, req); err != nil {
+		t.Errorf("Save() error = %v", err)
+	}
+}
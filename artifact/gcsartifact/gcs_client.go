@@ -39,6 +39,7 @@ type gcsObject interface {
 	newReader(ctx context.Context) (io.ReadCloser, error)
 	delete(ctx context.Context) error
 	attrs(ctx context.Context) (*storage.ObjectAttrs, error)
+	update(ctx context.Context, uattrs storage.ObjectAttrsToUpdate) (*storage.ObjectAttrs, error)
 }
 
 // gcsObjectIterator
@@ -51,6 +52,9 @@ type gcsWriter interface {
 	io.Writer // Provides Write(p []byte) (n int, err error)
 	io.Closer // Provides Close() error
 	SetContentType(string)
+	SetMetadata(map[string]string)
+	// attrs returns the attributes of the written object after Close.
+	attrs() *storage.ObjectAttrs
 }
 
 // ---------------------- Wrapper Implementations for Real gcs Types --------------------------------
@@ -112,6 +116,11 @@ func (w *gcsObjectWrapper) attrs(ctx context.Context) (*storage.ObjectAttrs, err
 	return w.object.Attrs(ctx)
 }
 
+// Update implements the gcsObject interface for gcsObjectWrapper.
+func (w *gcsObjectWrapper) update(ctx context.Context, uattrs storage.ObjectAttrsToUpdate) (*storage.ObjectAttrs, error) {
+	return w.object.Update(ctx, uattrs)
+}
+
 // Create the wrapper for the real iterator.
 type gcsObjectIteratorWrapper struct {
 	iter *storage.ObjectIterator
@@ -138,6 +147,14 @@ func (g *gcsWriterWrapper) SetContentType(cType string) {
 	g.w.ContentType = cType
 }
 
+func (g *gcsWriterWrapper) SetMetadata(metadata map[string]string) {
+	g.w.Metadata = metadata
+}
+
+func (g *gcsWriterWrapper) attrs() *storage.ObjectAttrs {
+	return g.w.Attrs()
+}
+
 var (
 	_ gcsClient         = (*gcsClientWrapper)(nil)
 	_ gcsBucket         = (*gcsBucketWrapper)(nil)
@@ -19,6 +19,7 @@ import (
 	"context"
 	"io"
 	"io/fs"
+	"maps"
 	"strings"
 	"sync"
 	"testing"
@@ -47,6 +48,7 @@ func TestGCSArtifactService(t *testing.T) {
 		return newGCSArtifactServiceForTesting("new")
 	}
 	tests.TestArtifactService(t, "GCS", factory)
+	tests.TestArtifactMetadata(t, "GCS", factory)
 }
 
 // ---------------------------------- Mock Implementations -----------------------------------
@@ -114,6 +116,19 @@ type fakeObject struct {
 	data        []byte
 	deleted     bool
 	contentType string
+	metadata    map[string]string
+	created     time.Time
+}
+
+// objectAttrs returns the attributes of the object. f.mu must be held.
+func (f *fakeObject) objectAttrs() *storage.ObjectAttrs {
+	return &storage.ObjectAttrs{
+		Name:        f.name,
+		ContentType: f.contentType,
+		Size:        int64(len(f.data)),
+		Created:     f.created,
+		Metadata:    maps.Clone(f.metadata),
+	}
 }
 
 // NewWriter returns a fake writer that stores data in memory.
@@ -132,7 +147,20 @@ func (f *fakeObject) attrs(ctx context.Context) (*storage.ObjectAttrs, error) {
 	if f.deleted || f.data == nil {
 		return nil, storage.ErrObjectNotExist
 	}
-	return &storage.ObjectAttrs{Name: f.name, Created: time.Now(), ContentType: f.contentType}, nil
+	return f.objectAttrs(), nil
+}
+
+// Update replaces the custom metadata of the object.
+func (f *fakeObject) update(ctx context.Context, uattrs storage.ObjectAttrsToUpdate) (*storage.ObjectAttrs, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.deleted || f.data == nil {
+		return nil, storage.ErrObjectNotExist
+	}
+	if uattrs.Metadata != nil {
+		f.metadata = maps.Clone(uattrs.Metadata)
+	}
+	return f.objectAttrs(), nil
 }
 
 // Delete marks the object as deleted in memory.
@@ -158,6 +186,7 @@ type fakeWriter struct {
 	obj         *fakeObject
 	buffer      *bytes.Buffer
 	contentType string
+	metadata    map[string]string
 }
 
 func (w *fakeWriter) Write(p []byte) (n int, err error) {
@@ -169,9 +198,21 @@ func (w *fakeWriter) Close() error {
 	defer w.obj.mu.Unlock()
 	w.obj.data = w.buffer.Bytes()
 	w.obj.contentType = w.contentType
+	w.obj.metadata = w.metadata
+	w.obj.created = time.Now()
 	return nil
 }
 
+func (w *fakeWriter) SetMetadata(metadata map[string]string) {
+	w.metadata = metadata
+}
+
+func (w *fakeWriter) attrs() *storage.ObjectAttrs {
+	w.obj.mu.Lock()
+	defer w.obj.mu.Unlock()
+	return w.obj.objectAttrs()
+}
+
 // SetContentType implements the final piece of the interface.
 func (w *fakeWriter) SetContentType(cType string) {
 	w.contentType = cType
@@ -192,7 +233,9 @@ func (i *fakeObjectIterator) next() (*storage.ObjectAttrs, error) {
 	}
 	obj := i.objects[i.index]
 	i.index++
-	return &storage.ObjectAttrs{Name: obj.name, ContentType: obj.contentType}, nil
+	obj.mu.Lock()
+	defer obj.mu.Unlock()
+	return obj.objectAttrs(), nil
 }
 
 var (
@@ -20,7 +20,11 @@
 package gcsartifact
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
@@ -90,21 +94,102 @@ func buildUserPrefix(appName, userID string) string {
 	return fmt.Sprintf("%s/%s/user/", appName, userID)
 }
 
+// Custom object metadata keys holding the artifact metadata that GCS does
+// not record natively.
+const (
+	metadataKeyChecksum     = "adk-checksum-sha256"
+	metadataKeyAuthor       = "adk-author"
+	metadataKeyInvocationID = "adk-invocation-id"
+	metadataKeyAttributes   = "adk-attributes"
+)
+
+// objectMetadata returns the custom object metadata recording meta.
+func objectMetadata(meta *artifact.Metadata) map[string]string {
+	m := map[string]string{}
+	if meta.Checksum != "" {
+		m[metadataKeyChecksum] = meta.Checksum
+	}
+	if meta.Author != "" {
+		m[metadataKeyAuthor] = meta.Author
+	}
+	if meta.InvocationID != "" {
+		m[metadataKeyInvocationID] = meta.InvocationID
+	}
+	if len(meta.Attributes) > 0 {
+		// Attributes are stored as a single JSON value since GCS metadata
+		// keys are shared with the fields above.
+		if data, err := json.Marshal(meta.Attributes); err == nil {
+			m[metadataKeyAttributes] = string(data)
+		}
+	}
+	return m
+}
+
+// metadataFromAttrs returns the artifact metadata of a stored object.
+func metadataFromAttrs(fileName string, version int64, attrs *storage.ObjectAttrs) *artifact.Metadata {
+	meta := &artifact.Metadata{
+		FileName:     fileName,
+		Version:      version,
+		MIMEType:     attrs.ContentType,
+		Size:         attrs.Size,
+		CreateTime:   attrs.Created,
+		Checksum:     attrs.Metadata[metadataKeyChecksum],
+		Author:       attrs.Metadata[metadataKeyAuthor],
+		InvocationID: attrs.Metadata[metadataKeyInvocationID],
+	}
+	if v, ok := attrs.Metadata[metadataKeyAttributes]; ok {
+		// Malformed attributes set by other tools are ignored.
+		_ = json.Unmarshal([]byte(v), &meta.Attributes)
+	}
+	return meta
+}
+
 // Save implements [artifact.Service]
-func (s *gcsService) Save(ctx context.Context, req *artifact.SaveRequest) (_ *artifact.SaveResponse, err error) {
-	err = req.Validate()
+func (s *gcsService) Save(ctx context.Context, req *artifact.SaveRequest) (*artifact.SaveResponse, error) {
+	err := req.Validate()
 	if err != nil {
 		return nil, fmt.Errorf("request validation failed: %w", err)
 	}
-	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
-	newArtifact := req.Part
+	var data []byte
+	contentType := "text/plain"
+	if req.Part.InlineData != nil {
+		data = req.Part.InlineData.Data
+		contentType = req.Part.InlineData.MIMEType
+	} else {
+		data = []byte(req.Part.Text)
+	}
+	return s.write(ctx, req.AppName, req.UserID, req.SessionID, req.FileName, contentType, bytes.NewReader(data), &artifact.Metadata{
+		Checksum:     artifact.Checksum(data),
+		Author:       req.Author,
+		InvocationID: req.InvocationID,
+		Attributes:   req.Attributes,
+	})
+}
+
+// SaveStream implements [artifact.StreamingService]. The content is uploaded
+// to GCS as it is read.
+func (s *gcsService) SaveStream(ctx context.Context, req *artifact.SaveStreamRequest) (*artifact.SaveResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	return s.write(ctx, req.AppName, req.UserID, req.SessionID, req.FileName, req.MIMEType, req.Body, &artifact.Metadata{
+		Author:       req.Author,
+		InvocationID: req.InvocationID,
+		Attributes:   req.Attributes,
+	})
+}
 
+// write uploads the content of body as the next version of the file. If
+// meta has no checksum, it is computed while uploading and stored once the
+// object is written.
+func (s *gcsService) write(ctx context.Context, appName, userID, sessionID, fileName, contentType string, body io.Reader, meta *artifact.Metadata) (*artifact.SaveResponse, error) {
 	nextVersion := int64(1)
 
 	// TODO race condition, could use mutex but it's a remote resource so the issue would still occurs
 	// with multiple consumers, and gcs does not have transactions spanning several operations
 	response, err := s.versions(ctx, &artifact.VersionsRequest{
-		AppName: req.AppName, UserID: req.UserID, SessionID: req.SessionID, FileName: req.FileName,
+		AppName: appName, UserID: userID, SessionID: sessionID, FileName: fileName,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list artifact versions: %w", err)
@@ -114,26 +199,35 @@ func (s *gcsService) Save(ctx context.Context, req *artifact.SaveRequest) (_ *ar
 	}
 
 	blobName := buildBlobName(appName, userID, sessionID, fileName, nextVersion)
-	writer := s.bucket.object(blobName).newWriter(ctx)
-	defer func() {
-		if closeErr := writer.Close(); closeErr != nil && err == nil {
-			err = fmt.Errorf("failed to close blob writer: %w", closeErr)
-		}
-	}()
+	obj := s.bucket.object(blobName)
+
+	// Cancelling the writer context aborts the upload, so that a failed
+	// read does not leave a truncated version behind.
+	writeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	writer := obj.newWriter(writeCtx)
+	writer.SetContentType(contentType)
+	writer.SetMetadata(objectMetadata(meta))
+
+	hash := sha256.New()
+	if _, err := io.Copy(writer, io.TeeReader(body, hash)); err != nil {
+		cancel()
+		_ = writer.Close()
+		return nil, fmt.Errorf("failed to write blob to GCS: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close blob writer: %w", err)
+	}
+	attrs := writer.attrs()
 
-	if newArtifact.InlineData != nil {
-		writer.SetContentType(newArtifact.InlineData.MIMEType)
-		if _, err := writer.Write(newArtifact.InlineData.Data); err != nil {
-			return nil, fmt.Errorf("failed to write blob to GCS: %w", err)
-		}
-	} else {
-		writer.SetContentType("text/plain")
-		if _, err := writer.Write([]byte(newArtifact.Text)); err != nil {
-			return nil, fmt.Errorf("failed to write text to GCS: %w", err)
+	if meta.Checksum == "" {
+		meta.Checksum = hex.EncodeToString(hash.Sum(nil))
+		attrs, err = obj.update(ctx, storage.ObjectAttrsToUpdate{Metadata: objectMetadata(meta)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update blob metadata: %w", err)
 		}
 	}
-
-	return &artifact.SaveResponse{Version: nextVersion}, nil
+	return &artifact.SaveResponse{Version: nextVersion, Metadata: metadataFromAttrs(fileName, nextVersion, attrs)}, nil
 }
 
 // Delete implements [artifact.Service]
@@ -183,7 +277,32 @@ func (s *gcsService) Delete(ctx context.Context, req *artifact.DeleteRequest) er
 
 // Load implements [artifact.Service]
 func (s *gcsService) Load(ctx context.Context, req *artifact.LoadRequest) (_ *artifact.LoadResponse, err error) {
-	err = req.Validate()
+	resp, err := s.LoadStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close blob reader: %w", closeErr)
+		}
+	}()
+
+	// Read all the content into a byte slice
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read data from blob: %w", err)
+	}
+
+	// Create the genai.Part and return the response.
+	part := genai.NewPartFromBytes(data, resp.Metadata.MIMEType)
+
+	return &artifact.LoadResponse{Part: part, Metadata: resp.Metadata}, nil
+}
+
+// LoadStream implements [artifact.StreamingService]. The content is
+// downloaded from GCS as the returned body is read.
+func (s *gcsService) LoadStream(ctx context.Context, req *artifact.LoadRequest) (*artifact.LoadStreamResponse, error) {
+	err := req.Validate()
 	if err != nil {
 		return nil, fmt.Errorf("request validation failed: %w", err)
 	}
@@ -220,22 +339,7 @@ func (s *gcsService) Load(ctx context.Context, req *artifact.LoadRequest) (_ *ar
 	if err != nil {
 		return nil, fmt.Errorf("could not create reader for blob '%s': %w", blobName, err)
 	}
-	defer func() {
-		if closeErr := reader.Close(); closeErr != nil && err == nil {
-			err = fmt.Errorf("failed to close blob reader: %w", closeErr)
-		}
-	}()
-
-	// Read all the content into a byte slice
-	data, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, fmt.Errorf("could not read data from blob '%s': %w", blobName, err)
-	}
-
-	// Create the genai.Part and return the response.
-	part := genai.NewPartFromBytes(data, attrs.ContentType)
-
-	return &artifact.LoadResponse{Part: part}, nil
+	return &artifact.LoadStreamResponse{Body: reader, Metadata: metadataFromAttrs(fileName, version, attrs)}, nil
 }
 
 // fetchFilenamesFromPrefix is a reusable helper function.
@@ -317,6 +421,7 @@ func (s *gcsService) versions(ctx context.Context, req *artifact.VersionsRequest
 	blobsIterator := s.bucket.objects(ctx, query)
 
 	versions := make([]int64, 0)
+	var metadata []*artifact.Metadata
 	for {
 		blob, err := blobsIterator.next()
 		if err == iterator.Done {
@@ -335,8 +440,9 @@ func (s *gcsService) versions(ctx context.Context, req *artifact.VersionsRequest
 			continue
 		}
 		versions = append(versions, version)
+		metadata = append(metadata, metadataFromAttrs(fileName, version, blob))
 	}
-	return &artifact.VersionsResponse{Versions: versions}, nil
+	return &artifact.VersionsResponse{Versions: versions, Metadata: metadata}, nil
 }
 
 // Versions implements [artifact.Service] and returns an error if no versions are found.
@@ -350,3 +456,5 @@ func (s *gcsService) Versions(ctx context.Context, req *artifact.VersionsRequest
 	}
 	return response, nil
 }
+
+var _ artifact.StreamingService = (*gcsService)(nil)
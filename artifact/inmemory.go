@@ -15,8 +15,10 @@
 package artifact
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"iter"
 	"maps"
@@ -25,6 +27,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"google.golang.org/genai"
 	"rsc.io/omap"
@@ -36,7 +39,13 @@ import (
 type inMemoryService struct {
 	mu sync.RWMutex
 	// ordered(appName, userID, sessionID) -> session
-	artifacts omap.Map[string, *genai.Part]
+	artifacts omap.Map[string, *storedArtifact]
+}
+
+// storedArtifact is an artifact version with its metadata.
+type storedArtifact struct {
+	part     *genai.Part
+	metadata *Metadata
 }
 
 // InMemoryService returns a new in-memory artifact service.
@@ -80,8 +89,8 @@ func (ak *artifactKey) Decode(key string) error {
 // scan returns an iterator over all key-value pairs
 // in the range begin ≤ key ≤ end.
 // TODO: add a concurrent tests.
-func (s *inMemoryService) scan(lo, hi string) iter.Seq2[artifactKey, *genai.Part] {
-	return func(yield func(key artifactKey, val *genai.Part) bool) {
+func (s *inMemoryService) scan(lo, hi string) iter.Seq2[artifactKey, *storedArtifact] {
+	return func(yield func(key artifactKey, val *storedArtifact) bool) {
 		for k, val := range s.artifacts.Scan(lo, hi) {
 			var key artifactKey
 			if err := key.Decode(k); err != nil {
@@ -95,7 +104,7 @@ func (s *inMemoryService) scan(lo, hi string) iter.Seq2[artifactKey, *genai.Part
 	}
 }
 
-func (s *inMemoryService) find(appName, userID, sessionID, fileName string) (int64, *genai.Part, bool) {
+func (s *inMemoryService) find(appName, userID, sessionID, fileName string) (int64, *storedArtifact, bool) {
 	lo := artifactKey{AppName: appName, UserID: userID, SessionID: sessionID, FileName: fileName, Version: math.MaxInt64}.Encode()
 	hi := artifactKey{AppName: appName, UserID: userID, SessionID: sessionID, FileName: fileName, Version: 0}.Encode()
 	for key, val := range s.scan(lo, hi) {
@@ -105,7 +114,7 @@ func (s *inMemoryService) find(appName, userID, sessionID, fileName string) (int
 	return 0, nil, false
 }
 
-func (s *inMemoryService) get(appName, userID, sessionID, fileName string, version int64) (*genai.Part, bool) {
+func (s *inMemoryService) get(appName, userID, sessionID, fileName string, version int64) (*storedArtifact, bool) {
 	key := artifactKey{
 		AppName:   appName,
 		UserID:    userID,
@@ -116,7 +125,7 @@ func (s *inMemoryService) get(appName, userID, sessionID, fileName string, versi
 	return s.artifacts.Get(key)
 }
 
-func (s *inMemoryService) set(appName, userID, sessionID, fileName string, version int64, artifact *genai.Part) {
+func (s *inMemoryService) set(appName, userID, sessionID, fileName string, version int64, artifact *storedArtifact) {
 	key := artifactKey{
 		AppName:   appName,
 		UserID:    userID,
@@ -144,8 +153,22 @@ func (s *inMemoryService) Save(ctx context.Context, req *SaveRequest) (*SaveResp
 	if err != nil {
 		return nil, fmt.Errorf("request validation failed: %w", err)
 	}
-	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
-	artifact := req.Part
+	data, mimeType := partContent(req.Part)
+	metadata := &Metadata{
+		FileName:     req.FileName,
+		MIMEType:     mimeType,
+		Size:         int64(len(data)),
+		Checksum:     Checksum(data),
+		Author:       req.Author,
+		InvocationID: req.InvocationID,
+		Attributes:   maps.Clone(req.Attributes),
+	}
+	return s.save(req.AppName, req.UserID, req.SessionID, req.FileName, &storedArtifact{part: req.Part, metadata: metadata}), nil
+}
+
+// save stores artifact as the next version of the file and completes its
+// metadata.
+func (s *inMemoryService) save(appName, userID, sessionID, fileName string, artifact *storedArtifact) *SaveResponse {
 	// If file is user scoped, store it under user scope path
 	if fileHasUserNamespace(fileName) {
 		sessionID = userScopedArtifactKey
@@ -158,8 +181,33 @@ func (s *inMemoryService) Save(ctx context.Context, req *SaveRequest) (*SaveResp
 	if internalVer, _, ok := s.find(appName, userID, sessionID, fileName); ok {
 		nextVersion = internalVer + 1
 	}
+	artifact.metadata.Version = nextVersion
+	artifact.metadata.CreateTime = time.Now()
 	s.set(appName, userID, sessionID, fileName, nextVersion, artifact)
-	return &SaveResponse{Version: nextVersion}, nil
+	return &SaveResponse{Version: nextVersion, Metadata: artifact.metadata.clone()}
+}
+
+// SaveStream implements [StreamingService]. The content is read into memory.
+func (s *inMemoryService) SaveStream(ctx context.Context, req *SaveStreamRequest) (*SaveResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	metadata := &Metadata{
+		FileName:     req.FileName,
+		MIMEType:     req.MIMEType,
+		Size:         int64(len(data)),
+		Checksum:     Checksum(data),
+		Author:       req.Author,
+		InvocationID: req.InvocationID,
+		Attributes:   maps.Clone(req.Attributes),
+	}
+	part := genai.NewPartFromBytes(data, req.MIMEType)
+	return s.save(req.AppName, req.UserID, req.SessionID, req.FileName, &storedArtifact{part: part, metadata: metadata}), nil
 }
 
 // Delete implements [artifact.Service]
@@ -196,6 +244,28 @@ func (s *inMemoryService) Load(ctx context.Context, req *LoadRequest) (*LoadResp
 	if err != nil {
 		return nil, fmt.Errorf("request validation failed: %w", err)
 	}
+	artifact, err := s.load(req)
+	if err != nil {
+		return nil, err
+	}
+	return &LoadResponse{Part: artifact.part, Metadata: artifact.metadata.clone()}, nil
+}
+
+// LoadStream implements [StreamingService].
+func (s *inMemoryService) LoadStream(ctx context.Context, req *LoadRequest) (*LoadStreamResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	artifact, err := s.load(req)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := partContent(artifact.part)
+	return &LoadStreamResponse{Body: io.NopCloser(bytes.NewReader(data)), Metadata: artifact.metadata.clone()}, nil
+}
+
+func (s *inMemoryService) load(req *LoadRequest) (*storedArtifact, error) {
 	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
 	version := req.Version
 	// If file is user scoped, adjust artifactKey part
@@ -211,14 +281,14 @@ func (s *inMemoryService) Load(ctx context.Context, req *LoadRequest) (*LoadResp
 		if !ok {
 			return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
 		}
-		return &LoadResponse{Part: artifact}, nil
+		return artifact, nil
 	}
 	// pick the latest version
 	_, artifact, ok := s.find(appName, userID, sessionID, fileName)
 	if !ok {
 		return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
 	}
-	return &LoadResponse{Part: artifact}, nil
+	return artifact, nil
 }
 
 // List implements [artifact.Service]
@@ -273,16 +343,17 @@ func (s *inMemoryService) Versions(ctx context.Context, req *VersionsRequest) (*
 	defer s.mu.RUnlock()
 
 	var versions []int64
+	var metadata []*Metadata
 	lo := artifactKey{AppName: appName, UserID: userID, SessionID: sessionID, FileName: fileName, Version: math.MaxInt64}.Encode()
 	hi := artifactKey{AppName: appName, UserID: userID, SessionID: sessionID, FileName: fileName}.Encode()
-	// TODO(hyangah): extend omap to search key only and skip value decoding.
-	for key := range s.scan(lo, hi) {
+	for key, val := range s.scan(lo, hi) {
 		versions = append(versions, key.Version)
+		metadata = append(metadata, val.metadata.clone())
 	}
 	if len(versions) == 0 {
 		return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
 	}
-	return &VersionsResponse{Versions: versions}, nil
+	return &VersionsResponse{Versions: versions, Metadata: metadata}, nil
 }
 
-var _ Service = (*inMemoryService)(nil)
+var _ StreamingService = (*inMemoryService)(nil)
@@ -26,4 +26,5 @@ func TestInMemoryArtifactService(t *testing.T) {
 		return artifact.InMemoryService(), nil
 	}
 	tests.TestArtifactService(t, "InMemory", factory)
+	tests.TestArtifactMetadata(t, "InMemory", factory)
 }
@@ -0,0 +1,365 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package s3artifact provides an [artifact.Service] backed by Amazon S3 or
+// any S3-compatible object store such as MinIO.
+//
+// Objects are named like the blobs of gcsartifact:
+//
+//	<app>/<user>/<session>/<file>/<version>
+//	<app>/<user>/user/<file>/<version>   (files named "user:...")
+//
+// The MIME type of an artifact is stored as the Content-Type of its object.
+package s3artifact
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+// s3Service is an S3 implementation of the Service.
+type s3Service struct {
+	bucketName string
+	client     *s3.Client
+}
+
+// NewService creates an S3 artifact service for the specified bucket.
+//
+// The client is configured from the default AWS configuration sources
+// (environment, shared config files, instance metadata) and then from optFns.
+// Use [WithEndpoint] to connect to an S3-compatible store such as MinIO.
+func NewService(ctx context.Context, bucketName string, optFns ...func(*s3.Options)) (artifact.Service, error) {
+	if bucketName == "" {
+		return nil, fmt.Errorf("bucket name is required")
+	}
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &s3Service{
+		bucketName: bucketName,
+		client:     s3.NewFromConfig(cfg, optFns...),
+	}, nil
+}
+
+// WithEndpoint configures the client to send requests to endpoint, such as
+// "http://localhost:9000", with path-style addressing as required by MinIO
+// and most other S3-compatible stores.
+func WithEndpoint(endpoint string) func(*s3.Options) {
+	return func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+		o.UsePathStyle = true
+	}
+}
+
+// fileHasUserNamespace checks if a filename indicates a user-namespaced object.
+func fileHasUserNamespace(filename string) bool {
+	return strings.HasPrefix(filename, "user:")
+}
+
+// buildObjectKey constructs the object key in S3.
+func buildObjectKey(appName, userID, sessionID, fileName string, version int64) string {
+	return fmt.Sprintf("%s%d", buildObjectKeyPrefix(appName, userID, sessionID, fileName), version)
+}
+
+func buildObjectKeyPrefix(appName, userID, sessionID, fileName string) string {
+	if fileHasUserNamespace(fileName) {
+		return fmt.Sprintf("%s/%s/user/%s/", appName, userID, fileName)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s/", appName, userID, sessionID, fileName)
+}
+
+func buildSessionPrefix(appName, userID, sessionID string) string {
+	return fmt.Sprintf("%s/%s/%s/", appName, userID, sessionID)
+}
+
+func buildUserPrefix(appName, userID string) string {
+	return fmt.Sprintf("%s/%s/user/", appName, userID)
+}
+
+// statusCode returns the HTTP status code of an S3 error response, or 0.
+func statusCode(err error) int {
+	var respErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &respErr) {
+		return respErr.HTTPStatusCode()
+	}
+	return 0
+}
+
+// maxSaveAttempts bounds the number of versions Save tries to claim when
+// racing with concurrent writers.
+const maxSaveAttempts = 10
+
+// Save implements [artifact.Service]
+func (s *s3Service) Save(ctx context.Context, req *artifact.SaveRequest) (*artifact.SaveResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
+
+	var data []byte
+	contentType := "text/plain"
+	if req.Part.InlineData != nil {
+		data = req.Part.InlineData.Data
+		contentType = req.Part.InlineData.MIMEType
+	} else {
+		data = []byte(req.Part.Text)
+	}
+
+	versions, err := s.versions(ctx, appName, userID, sessionID, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+	}
+	nextVersion := int64(1)
+	if len(versions) > 0 {
+		nextVersion = slices.Max(versions) + 1
+	}
+
+	// The conditional write fails if another writer claimed the version
+	// first, in which case the next one is tried.
+	for range maxSaveAttempts {
+		_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(s.bucketName),
+			Key:         aws.String(buildObjectKey(appName, userID, sessionID, fileName, nextVersion)),
+			Body:        bytes.NewReader(data),
+			ContentType: aws.String(contentType),
+			IfNoneMatch: aws.String("*"),
+		})
+		if err == nil {
+			return &artifact.SaveResponse{Version: nextVersion}, nil
+		}
+		if code := statusCode(err); code != http.StatusPreconditionFailed && code != http.StatusConflict {
+			return nil, fmt.Errorf("failed to write object to S3: %w", err)
+		}
+		nextVersion++
+	}
+	return nil, fmt.Errorf("failed to write object to S3: too many concurrent writers for %q", fileName)
+}
+
+// Delete implements [artifact.Service]
+func (s *s3Service) Delete(ctx context.Context, req *artifact.DeleteRequest) error {
+	err := req.Validate()
+	if err != nil {
+		return fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
+
+	// Delete specific version
+	if req.Version != 0 {
+		if err := s.deleteObject(ctx, buildObjectKey(appName, userID, sessionID, fileName, req.Version)); err != nil {
+			return fmt.Errorf("failed to delete artifact: %w", err)
+		}
+		return nil
+	}
+
+	// Delete all versions
+	versions, err := s.versions(ctx, appName, userID, sessionID, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to fetch versions on delete artifact: %w", err)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	for _, version := range versions {
+		g.Go(func() error {
+			key := buildObjectKey(appName, userID, sessionID, fileName, version)
+			if err := s.deleteObject(gctx, key); err != nil {
+				return fmt.Errorf("failed to delete artifact %s: %w", key, err)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+func (s *s3Service) deleteObject(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// Load implements [artifact.Service]
+func (s *s3Service) Load(ctx context.Context, req *artifact.LoadRequest) (_ *artifact.LoadResponse, err error) {
+	err = req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
+	version := req.Version
+
+	if version == 0 {
+		versions, err := s.versions(ctx, appName, userID, sessionID, fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+		}
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+		}
+		version = slices.Max(versions)
+	}
+
+	key := buildObjectKey(appName, userID, sessionID, fileName, version)
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if statusCode(err) == http.StatusNotFound {
+			return nil, fmt.Errorf("artifact '%s' not found: %w", key, fs.ErrNotExist)
+		}
+		return nil, fmt.Errorf("could not get object '%s': %w", key, err)
+	}
+	defer func() {
+		if closeErr := out.Body.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close object reader: %w", closeErr)
+		}
+	}()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read data from object '%s': %w", key, err)
+	}
+	return &artifact.LoadResponse{Part: genai.NewPartFromBytes(data, aws.ToString(out.ContentType))}, nil
+}
+
+// List implements [artifact.Service]
+func (s *s3Service) List(ctx context.Context, req *artifact.ListRequest) (*artifact.ListResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+
+	// The session and user prefixes are listed concurrently.
+	var sessionFiles, userFiles []string
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		sessionFiles, err = s.fetchFilenames(gctx, buildSessionPrefix(appName, userID, sessionID))
+		if err != nil {
+			return fmt.Errorf("failed to fetch session filenames: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		userFiles, err = s.fetchFilenames(gctx, buildUserPrefix(appName, userID))
+		if err != nil {
+			return fmt.Errorf("failed to fetch user filenames: %w", err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	filenamesSet := map[string]bool{}
+	for _, name := range slices.Concat(sessionFiles, userFiles) {
+		filenamesSet[name] = true
+	}
+	filenames := slices.Collect(maps.Keys(filenamesSet))
+	sort.Strings(filenames)
+	return &artifact.ListResponse{FileNames: filenames}, nil
+}
+
+// fetchFilenames returns the names of the files stored directly under prefix.
+// Objects only exist for saved versions, so every common prefix is a file with
+// at least one version.
+func (s *s3Service) fetchFilenames(ctx context.Context, prefix string) ([]string, error) {
+	var filenames []string
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(s.bucketName),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing objects: %w", err)
+		}
+		for _, p := range page.CommonPrefixes {
+			// Note: filenames with path separators are rejected during validation.
+			name := strings.TrimSuffix(strings.TrimPrefix(aws.ToString(p.Prefix), prefix), "/")
+			if name != "" {
+				filenames = append(filenames, name)
+			}
+		}
+	}
+	return filenames, nil
+}
+
+// versions returns the versions of a file without failing if there are none.
+func (s *s3Service) versions(ctx context.Context, appName, userID, sessionID, fileName string) ([]int64, error) {
+	prefix := buildObjectKeyPrefix(appName, userID, sessionID, fileName)
+	versions := make([]int64, 0)
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(s.bucketName),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			version, err := strconv.ParseInt(strings.TrimPrefix(aws.ToString(obj.Key), prefix), 10, 64)
+			// if the file version is not convertible to number, just ignore it
+			if err != nil {
+				continue
+			}
+			versions = append(versions, version)
+		}
+	}
+	slices.Sort(versions)
+	return versions, nil
+}
+
+// Versions implements [artifact.Service] and returns an error if no versions are found.
+func (s *s3Service) Versions(ctx context.Context, req *artifact.VersionsRequest) (*artifact.VersionsResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	versions, err := s.versions(ctx, req.AppName, req.UserID, req.SessionID, req.FileName)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+	}
+	return &artifact.VersionsResponse{Versions: versions}, nil
+}
+
+var _ artifact.Service = (*s3Service)(nil)
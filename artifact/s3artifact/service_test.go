@@ -0,0 +1,248 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3artifact_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/artifact/s3artifact"
+	"google.golang.org/adk/internal/artifact/tests"
+)
+
+func TestS3ArtifactService(t *testing.T) {
+	factory := func(t *testing.T) (artifact.Service, error) {
+		return newTestService(t, newFakeS3("test-bucket"))
+	}
+	tests.TestArtifactService(t, "S3", factory)
+}
+
+func TestS3ArtifactService_ConcurrentSave(t *testing.T) {
+	ctx := t.Context()
+	srv, err := newTestService(t, newFakeS3("test-bucket"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 5
+	var wg sync.WaitGroup
+	versions := make([]int64, n)
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := srv.Save(ctx, &artifact.SaveRequest{
+				AppName: "app", UserID: "u1", SessionID: "s1", FileName: "f",
+				Part: genai.NewPartFromText(fmt.Sprint(i)),
+			})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			versions[i] = resp.Version
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	slices.Sort(versions)
+	if want := []int64{1, 2, 3, 4, 5}; !slices.Equal(versions, want) {
+		t.Errorf("Save() versions = %v, want %v", versions, want)
+	}
+}
+
+func newTestService(t *testing.T, fake *fakeS3) (artifact.Service, error) {
+	t.Helper()
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return s3artifact.NewService(t.Context(), fake.bucket, s3artifact.WithEndpoint(server.URL), func(o *s3.Options) {
+		o.Region = "us-east-1"
+		o.Credentials = aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "key", SecretAccessKey: "secret"}, nil
+		})
+	})
+}
+
+// fakeS3 is a minimal in-memory stand-in for the S3 API with path-style
+// addressing. Listings are paginated with a small page size to exercise
+// continuation tokens.
+type fakeS3 struct {
+	bucket   string
+	pageSize int
+
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+}
+
+func newFakeS3(bucket string) *fakeS3 {
+	return &fakeS3{bucket: bucket, pageSize: 2, objects: map[string]fakeObject{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		f.list(w, r)
+	case r.Method == http.MethodPut:
+		data, err := readBody(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequest")
+			return
+		}
+		if _, ok := f.objects[key]; ok && r.Header.Get("If-None-Match") == "*" {
+			writeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		f.objects[key] = fakeObject{data: data, contentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet:
+		obj, ok := f.objects[key]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		w.Write(obj.data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	Name                  string
+	Prefix                string
+	KeyCount              int
+	IsTruncated           bool
+	NextContinuationToken string `xml:",omitempty"`
+	Contents              []struct{ Key string }
+	CommonPrefixes        []struct{ Prefix string }
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	prefix, delimiter, token := q.Get("prefix"), q.Get("delimiter"), q.Get("continuation-token")
+
+	// Collect keys and common prefixes in lexicographic order.
+	entries := map[string]bool{} // entry -> is common prefix
+	for key := range f.objects {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		if i := strings.Index(rest, delimiter); delimiter != "" && i >= 0 {
+			entries[prefix+rest[:i+len(delimiter)]] = true
+		} else {
+			entries[key] = false
+		}
+	}
+	names := slices.Sorted(func(yield func(string) bool) {
+		for name := range entries {
+			if name > token && !yield(name) {
+				return
+			}
+		}
+	})
+
+	result := listBucketResult{Name: f.bucket, Prefix: prefix}
+	if len(names) > f.pageSize {
+		names = names[:f.pageSize]
+		result.IsTruncated = true
+		result.NextContinuationToken = names[len(names)-1]
+	}
+	for _, name := range names {
+		if entries[name] {
+			result.CommonPrefixes = append(result.CommonPrefixes, struct{ Prefix string }{name})
+		} else {
+			result.Contents = append(result.Contents, struct{ Key string }{name})
+		}
+	}
+	result.KeyCount = len(names)
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+// readBody reads a request body, decoding the aws-chunked encoding the SDK
+// uses to send trailing checksums.
+func readBody(r *http.Request) ([]byte, error) {
+	if !strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") {
+		return io.ReadAll(r.Body)
+	}
+	var data bytes.Buffer
+	br := bufio.NewReader(r.Body)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data.Bytes(), nil
+		}
+		if _, err := io.CopyN(&data, br, size); err != nil {
+			return nil, err
+		}
+		if _, err := br.Discard(2); err != nil { // CRLF
+			return nil, err
+		}
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
@@ -21,8 +21,12 @@ package artifact
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"maps"
 	"strings"
+	"time"
 
 	"google.golang.org/genai"
 )
@@ -61,6 +65,12 @@ type SaveRequest struct {
 	// If set, the artifact will be saved with this version.
 	// If unset, a new version will be created.
 	Version int64
+	// Author is the name of the agent or user that created the artifact.
+	Author string
+	// InvocationID is the ID of the invocation that created the artifact.
+	InvocationID string
+	// Attributes are custom key/value pairs stored with the version.
+	Attributes map[string]string
 }
 
 // validateRequiredStrings checks a slice of fields in order.
@@ -119,6 +129,9 @@ func validateFileName(name string) error {
 // SaveResponse is the return type of [ArtifactService.Save].
 type SaveResponse struct {
 	Version int64
+	// Metadata of the saved version. Nil if the service does not record
+	// metadata.
+	Metadata *Metadata
 }
 
 // LoadRequest is the parameter for [ArtifactService.Load].
@@ -159,6 +172,9 @@ func (req *LoadRequest) Validate() error {
 type LoadResponse struct {
 	// Part is the artifact stored.
 	Part *genai.Part
+	// Metadata of the loaded version. Nil if the service does not record
+	// metadata.
+	Metadata *Metadata
 }
 
 // DeleteRequest is the parameter for [ArtifactService.Delete].
@@ -258,4 +274,44 @@ func (req *VersionsRequest) Validate() error {
 // VersionsResponse is the parameter for [ArtifactService.Versions].
 type VersionsResponse struct {
 	Versions []int64
+	// Metadata of each version, in the same order as Versions. Nil if the
+	// service does not record metadata.
+	Metadata []*Metadata
+}
+
+// Metadata describes a stored artifact version.
+type Metadata struct {
+	FileName string
+	Version  int64
+	// MIMEType is the MIME type of the content. Text artifacts are
+	// reported as "text/plain".
+	MIMEType string
+	// Size is the size of the content in bytes.
+	Size int64
+	// Checksum is the hex-encoded SHA-256 digest of the content.
+	Checksum string
+	// CreateTime is the time the version was saved.
+	CreateTime time.Time
+	// Author is the name of the agent or user that created the artifact.
+	Author string
+	// InvocationID is the ID of the invocation that created the artifact.
+	InvocationID string
+	// Attributes are custom key/value pairs provided on save.
+	Attributes map[string]string
+}
+
+// Checksum returns the checksum of data in the format of [Metadata.Checksum].
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// clone returns a copy of m that does not share the attributes map.
+func (m *Metadata) clone() *Metadata {
+	if m == nil {
+		return nil
+	}
+	c := *m
+	c.Attributes = maps.Clone(m.Attributes)
+	return &c
 }
@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// StreamingService is implemented by services that can save and load
+// artifacts without holding their whole content in memory.
+//
+// Use the [SaveStream] and [LoadStream] functions to stream artifacts with
+// any [Service]; they fall back to buffering for services that do not
+// implement this interface.
+type StreamingService interface {
+	Service
+	// SaveStream saves the content read from req.Body as a new version.
+	SaveStream(ctx context.Context, req *SaveStreamRequest) (*SaveResponse, error)
+	// LoadStream opens an artifact version for reading. The caller must
+	// close the returned body.
+	LoadStream(ctx context.Context, req *LoadRequest) (*LoadStreamResponse, error)
+}
+
+// SaveStreamRequest is the parameter for [StreamingService.SaveStream].
+type SaveStreamRequest struct {
+	AppName, UserID, SessionID, FileName string
+	// MIMEType is the MIME type of the content.
+	MIMEType string
+	// Body is the content of the artifact. It is read until EOF.
+	Body io.Reader
+
+	// Below are optional fields.
+
+	// Author is the name of the agent or user that created the artifact.
+	Author string
+	// InvocationID is the ID of the invocation that created the artifact.
+	InvocationID string
+	// Attributes are custom key/value pairs stored with the version.
+	Attributes map[string]string
+}
+
+// Validate checks if the struct is valid or if it is missing fields.
+func (req *SaveStreamRequest) Validate() error {
+	fieldsToCheck := []requiredField{
+		{Name: "AppName", Value: req.AppName},
+		{Name: "UserID", Value: req.UserID},
+		{Name: "SessionID", Value: req.SessionID},
+		{Name: "FileName", Value: req.FileName},
+		{Name: "MIMEType", Value: req.MIMEType},
+	}
+	missingFields := validateRequiredStrings(fieldsToCheck)
+	if req.Body == nil {
+		missingFields = append(missingFields, "Body")
+	}
+	if len(missingFields) > 0 {
+		return fmt.Errorf("invalid save stream request: missing required fields: %s", strings.Join(missingFields, ", "))
+	}
+	if err := validateFileName(req.FileName); err != nil {
+		return err
+	}
+	return nil
+}
+
+// LoadStreamResponse is the return type of [StreamingService.LoadStream].
+type LoadStreamResponse struct {
+	// Body is the content of the artifact.
+	Body io.ReadCloser
+	// Metadata of the loaded version. Nil if the service does not record
+	// metadata.
+	Metadata *Metadata
+}
+
+// SaveStream saves the content read from req.Body with s. If s is not a
+// [StreamingService], the content is read into memory and saved with
+// [Service.Save].
+func SaveStream(ctx context.Context, s Service, req *SaveStreamRequest) (*SaveResponse, error) {
+	if ss, ok := s.(StreamingService); ok {
+		return ss.SaveStream(ctx, req)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	return s.Save(ctx, &SaveRequest{
+		AppName:      req.AppName,
+		UserID:       req.UserID,
+		SessionID:    req.SessionID,
+		FileName:     req.FileName,
+		Part:         genai.NewPartFromBytes(data, req.MIMEType),
+		Author:       req.Author,
+		InvocationID: req.InvocationID,
+		Attributes:   req.Attributes,
+	})
+}
+
+// LoadStream opens an artifact version with s for reading. If s is not a
+// [StreamingService], the artifact is loaded with [Service.Load] and its
+// content is served from memory.
+func LoadStream(ctx context.Context, s Service, req *LoadRequest) (*LoadStreamResponse, error) {
+	if ss, ok := s.(StreamingService); ok {
+		return ss.LoadStream(ctx, req)
+	}
+	resp, err := s.Load(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	data, mimeType := partContent(resp.Part)
+	meta := resp.Metadata
+	if meta == nil {
+		meta = &Metadata{
+			FileName: req.FileName,
+			Version:  req.Version,
+			MIMEType: mimeType,
+			Size:     int64(len(data)),
+			Checksum: Checksum(data),
+		}
+	}
+	return &LoadStreamResponse{Body: io.NopCloser(bytes.NewReader(data)), Metadata: meta}, nil
+}
+
+// partContent returns the content and MIME type of an artifact part.
+func partContent(part *genai.Part) ([]byte, string) {
+	if part == nil {
+		return nil, ""
+	}
+	if part.InlineData != nil {
+		return part.InlineData.Data, part.InlineData.MIMEType
+	}
+	return []byte(part.Text), "text/plain"
+}
@@ -0,0 +1,305 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// functooldoc generates the documentation of the functions wrapped as tools
+// by functiontool.NewFunc from their doc comments.
+//
+// Reflection does not provide the names of the parameters of a function,
+// so functooldoc parses the functions of the package in the current
+// directory and registers their parameter names and descriptions with
+// functiontool.RegisterFuncDoc. The description of a function is its doc
+// comment, and the descriptions of its parameters are listed in a
+// "Parameters:" section, "(optional)" marking the optional ones:
+//
+//	// getWeather returns the weather forecast of a city.
+//	//
+//	// Parameters:
+//	//   - city: name of the city, like "Paris"
+//	//   - days: (optional) number of days of the forecast
+//	func getWeather(ctx tool.Context, city string, days int) (string, error) {
+//
+// Usage, in a file of the package:
+//
+//	//go:generate go run google.golang.org/adk/cmd/functooldoc
+//
+// By default, the documentation of all the top-level functions whose first
+// parameter is a tool.Context is generated. The -funcs flag selects the
+// functions by name.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	toolPackage  = "google.golang.org/adk/tool"
+	generatedTag = "// Code generated by functooldoc. DO NOT EDIT."
+)
+
+func main() {
+	output := flag.String("output", "functooldoc_gen.go", "name of the generated file")
+	funcs := flag.String("funcs", "", "comma-separated names of the functions to document (default: all the functions with a tool.Context first parameter)")
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("functooldoc: ")
+
+	var names []string
+	if *funcs != "" {
+		names = strings.Split(*funcs, ",")
+	}
+	src, err := generate(".", names)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// funcDoc is the documentation of a function.
+type funcDoc struct {
+	name        string
+	description string
+	params      []paramDoc
+}
+
+type paramDoc struct {
+	name        string
+	description string
+	optional    bool
+}
+
+// generate returns the source of the file registering the documentation of
+// the functions of the package in dir with the names, or of all the
+// functions with a tool.Context first parameter if names is empty.
+func generate(dir string, names []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	pkgName := ""
+	var docs []funcDoc
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if isGenerated(file) {
+			continue
+		}
+		pkgName = file.Name.Name
+		toolName := importName(file, toolPackage)
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
+			}
+			selected := slices.Contains(names, fn.Name.Name)
+			if len(names) > 0 && !selected {
+				continue
+			}
+			if toolName == "" || !hasContextParam(fn, toolName) {
+				if selected {
+					return nil, fmt.Errorf("%s: the first parameter of function %s must be a tool.Context", fset.Position(fn.Pos()), fn.Name.Name)
+				}
+				continue
+			}
+			doc, err := parseFunc(fn)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", fset.Position(fn.Pos()), err)
+			}
+			docs = append(docs, doc)
+		}
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(docs, func(d funcDoc) bool { return d.name == name }) {
+			return nil, fmt.Errorf("function %q not found", name)
+		}
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no function to document in %s", dir)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n\npackage %s\n\nimport \"google.golang.org/adk/tool/functiontool\"\n\nfunc init() {\n", generatedTag, pkgName)
+	for _, doc := range docs {
+		fmt.Fprintf(&b, "functiontool.RegisterFuncDoc(%s, functiontool.FuncDoc{\nDescription: %s,\n", doc.name, strconv.Quote(doc.description))
+		if len(doc.params) > 0 {
+			b.WriteString("Params: []functiontool.Param{\n")
+			for _, p := range doc.params {
+				fmt.Fprintf(&b, "{Name: %s, Description: %s", strconv.Quote(p.name), strconv.Quote(p.description))
+				if p.optional {
+					b.WriteString(", Optional: true")
+				}
+				b.WriteString("},\n")
+			}
+			b.WriteString("},\n")
+		}
+		b.WriteString("})\n")
+	}
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
+
+func isGenerated(file *ast.File) bool {
+	for _, c := range file.Comments {
+		if c.Pos() > file.Package {
+			break
+		}
+		for _, line := range c.List {
+			if line.Text == generatedTag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// importName returns the name of the import of the package with the path in
+// the file, or "" if it is not imported.
+func importName(file *ast.File, path string) string {
+	for _, imp := range file.Imports {
+		if p, err := strconv.Unquote(imp.Path.Value); err != nil || p != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return filepath.Base(path)
+	}
+	return ""
+}
+
+// hasContextParam reports whether the first parameter of the function is a
+// tool.Context, the tool package being imported as toolName.
+func hasContextParam(fn *ast.FuncDecl, toolName string) bool {
+	params := fn.Type.Params.List
+	if len(params) == 0 {
+		return false
+	}
+	sel, ok := params[0].Type.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == toolName && sel.Sel.Name == "Context"
+}
+
+// parseFunc returns the documentation of a function from its doc comment.
+func parseFunc(fn *ast.FuncDecl) (funcDoc, error) {
+	doc := funcDoc{name: fn.Name.Name}
+	var names []string
+	for i, field := range fn.Type.Params.List {
+		if len(field.Names) == 0 {
+			return funcDoc{}, fmt.Errorf("the parameters of function %s must be named", doc.name)
+		}
+		for j, name := range field.Names {
+			if i == 0 && j == 0 {
+				// The tool.Context.
+				continue
+			}
+			if name.Name == "_" {
+				return funcDoc{}, fmt.Errorf("the parameters of function %s must be named", doc.name)
+			}
+			names = append(names, name.Name)
+		}
+	}
+
+	description, paramDescriptions := parseComment(fn.Doc.Text())
+	doc.description = strings.TrimSpace(strings.TrimPrefix(description, doc.name+" "))
+	if doc.description != description && doc.description != "" {
+		// "getWeather returns..." becomes "Returns...".
+		doc.description = strings.ToUpper(doc.description[:1]) + doc.description[1:]
+	}
+	for _, name := range names {
+		p := paramDoc{name: name, description: paramDescriptions[name]}
+		if rest, ok := strings.CutPrefix(p.description, "(optional)"); ok {
+			p.description, p.optional = strings.TrimSpace(rest), true
+		}
+		doc.params = append(doc.params, p)
+	}
+	return doc, nil
+}
+
+// parseComment splits a doc comment into the description and the
+// descriptions of the parameters listed in its "Parameters:" section.
+func parseComment(text string) (string, map[string]string) {
+	params := map[string]string{}
+	var description []string
+	inParams := false
+	current := ""
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "Parameters:" || trimmed == "Args:" {
+			inParams = true
+			continue
+		}
+		if !inParams {
+			description = append(description, line)
+			continue
+		}
+		item := strings.TrimSpace(strings.TrimLeft(trimmed, "-*"))
+		if name, desc, ok := strings.Cut(item, ":"); ok && item != trimmed && !strings.ContainsAny(name, " \t") {
+			current = name
+			params[current] = strings.TrimSpace(desc)
+			continue
+		}
+		switch {
+		case trimmed == "":
+			current = ""
+		case current != "":
+			// Continuation of the description of a parameter.
+			params[current] += " " + trimmed
+		default:
+			// The parameters section ended.
+			inParams = false
+			description = append(description, line)
+		}
+	}
+	return joinParagraphs(description), params
+}
+
+// joinParagraphs joins the lines of the paragraphs of a comment, keeping the
+// blank lines between the paragraphs.
+func joinParagraphs(lines []string) string {
+	var paragraphs []string
+	var current []string
+	for _, line := range append(lines, "") {
+		if line = strings.TrimSpace(line); line != "" {
+			current = append(current, line)
+			continue
+		}
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+			current = nil
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const weatherSrc = `package weather
+
+import (
+	adktool "google.golang.org/adk/tool"
+)
+
+// getWeather returns the weather forecast
+// of a city.
+//
+// The forecast comes from a stub.
+//
+// Parameters:
+//   - city: name of the city,
+//     like "Paris"
+//   - days: (optional) number of days
+func getWeather(ctx adktool.Context, city string, days int) (string, error) {
+	return "sunny", nil
+}
+
+// Sum sums numbers.
+func Sum(ctx adktool.Context, a, b int) int {
+	return a + b
+}
+
+// helper is not a tool.
+func helper(s string) string {
+	return s
+}
+`
+
+const want = `// Code generated by functooldoc. DO NOT EDIT.
+
+package weather
+
+import "google.golang.org/adk/tool/functiontool"
+
+func init() {
+	functiontool.RegisterFuncDoc(getWeather, functiontool.FuncDoc{
+		Description: "Returns the weather forecast of a city.\n\nThe forecast comes from a stub.",
+		Params: []functiontool.Param{
+			{Name: "city", Description: "name of the city, like \"Paris\""},
+			{Name: "days", Description: "number of days", Optional: true},
+		},
+	})
+	functiontool.RegisterFuncDoc(Sum, functiontool.FuncDoc{
+		Description: "Sums numbers.",
+		Params: []functiontool.Param{
+			{Name: "a", Description: ""},
+			{Name: "b", Description: ""},
+		},
+	})
+}
+`
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "weather.go"), []byte(weatherSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := generate(dir, nil)
+	if err != nil {
+		t.Fatalf("generate() failed: %v", err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("generate() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The generated file is skipped when generating again.
+	if err := os.WriteFile(filepath.Join(dir, "functooldoc_gen.go"), got, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := generate(dir, []string{"Sum"}); err != nil || !strings.Contains(string(got), "RegisterFuncDoc(Sum,") || strings.Contains(string(got), "getWeather") {
+		t.Errorf("generate(Sum) = %s, %v, want only Sum", got, err)
+	}
+	for _, funcs := range [][]string{{"missing"}, {"helper"}} {
+		if _, err := generate(dir, funcs); err == nil {
+			t.Errorf("generate(%v) succeeded, want error", funcs)
+		}
+	}
+}
@@ -23,9 +23,11 @@ import (
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
 	"google.golang.org/adk/memory"
+	"google.golang.org/adk/operation"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/telemetry"
+	"google.golang.org/adk/tool"
 )
 
 // Launcher is the main interface for running an ADK application.
@@ -56,11 +58,19 @@ type SubLauncher interface {
 
 // Config contains parameters for web & console execution: sessions, artifacts, agents etc
 type Config struct {
-	SessionService   session.Service
-	ArtifactService  artifact.Service
-	MemoryService    memory.Service
-	AgentLoader      agent.Loader
-	A2AOptions       []a2asrv.RequestHandlerOption
+	SessionService  session.Service
+	ArtifactService artifact.Service
+	MemoryService   memory.Service
+	AgentLoader     agent.Loader
+	A2AOptions      []a2asrv.RequestHandlerOption
+	// MCPTools are the tools exposed by the MCP server in addition to the agents.
+	MCPTools         []tool.Tool
 	PluginConfig     runner.PluginConfig
 	TelemetryOptions []telemetry.Option
+	// OperationStore persists the long-running operations started by the
+	// tools (optional).
+	OperationStore operation.Store
+	// MaxArtifactUploadSize is the maximum size in bytes of the artifacts
+	// uploaded through the REST API (default: 256 MiB).
+	MaxArtifactUploadSize int64
 }
@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mcp provides a sublauncher that serves agents and tools via MCP.
+package mcp
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/cmd/launcher/web"
+	"google.golang.org/adk/internal/cli/util"
+	"google.golang.org/adk/server/adkmcp"
+)
+
+// apiPath is the path of the MCP streamable HTTP endpoint.
+const apiPath = "/mcp"
+
+// mcpConfig contains parameters for launching ADK MCP server
+type mcpConfig struct {
+	agents string // comma-separated names of the agents to expose, all agents if empty
+}
+
+type mcpLauncher struct {
+	flags  *flag.FlagSet // flags are used to parse command-line arguments
+	config *mcpConfig
+}
+
+// NewLauncher creates new mcp launcher. It extends Web launcher
+func NewLauncher() web.Sublauncher {
+	config := &mcpConfig{}
+
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+
+	fs.StringVar(&config.agents, "mcp_agents", "", "Comma-separated names of the agents exposed as MCP tools. All agents are exposed if empty.")
+
+	return &mcpLauncher{
+		config: config,
+		flags:  fs,
+	}
+}
+
+// CommandLineSyntax implements web.Sublauncher. Returns the command-line syntax for the MCP launcher.
+func (m *mcpLauncher) CommandLineSyntax() string {
+	return util.FormatFlagUsage(m.flags)
+}
+
+// Keyword implements web.Sublauncher. Returns the command-line keyword for MCP launcher.
+func (m *mcpLauncher) Keyword() string {
+	return "mcp"
+}
+
+func (m *mcpLauncher) Parse(args []string) ([]string, error) {
+	err := m.flags.Parse(args)
+	if err != nil || !m.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse mcp flags: %v", err)
+	}
+	restArgs := m.flags.Args()
+	return restArgs, nil
+}
+
+// SetupSubrouters implements the web.Sublauncher interface. It adds the MCP path to the main router.
+func (m *mcpLauncher) SetupSubrouters(router *mux.Router, config *launcher.Config) error {
+	names := config.AgentLoader.ListAgents()
+	if m.config.agents != "" {
+		names = strings.Split(m.config.agents, ",")
+	}
+	var agents []agent.Agent
+	for _, name := range names {
+		a, err := config.AgentLoader.LoadAgent(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		agents = append(agents, a)
+	}
+
+	handler, err := adkmcp.NewHandler(adkmcp.Config{
+		AppName:         config.AgentLoader.RootAgent().Name(),
+		Agents:          agents,
+		Tools:           config.MCPTools,
+		SessionService:  config.SessionService,
+		ArtifactService: config.ArtifactService,
+		MemoryService:   config.MemoryService,
+		PluginConfig:    config.PluginConfig,
+	})
+	if err != nil {
+		return err
+	}
+	router.Methods("GET", "POST", "DELETE").Path(apiPath).Handler(handler)
+	return nil
+}
+
+// SimpleDescription implements web.Sublauncher
+func (m *mcpLauncher) SimpleDescription() string {
+	return fmt.Sprintf("starts MCP server which exposes agents and tools over streamable HTTP on %s path", apiPath)
+}
+
+// UserMessage implements web.Sublauncher.
+func (m *mcpLauncher) UserMessage(webURL string, printer func(v ...any)) {
+	printer(fmt.Sprintf("       mcp:  you can access MCP using streamable HTTP transport: %s%s", webURL, apiPath))
+}
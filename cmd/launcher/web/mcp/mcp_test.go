@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"iter"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/cmd/launcher/web"
+	"google.golang.org/adk/session"
+)
+
+func getFreePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	if err := listener.Close(); err != nil {
+		t.Fatalf("listener.Close() error = %v", err)
+	}
+	return port
+}
+
+func TestWebLauncher_ServesMCP(t *testing.T) {
+	ctx := t.Context()
+
+	port := getFreePort(t)
+
+	l := web.NewLauncher(NewLauncher())
+	_, err := l.Parse([]string{"--port", strconv.Itoa(port), "mcp"})
+	if err != nil {
+		t.Fatalf("web.NewLauncher() error = %v", err)
+	}
+
+	wantMessage := "Hello, world!"
+	agnt, err := agent.New(agent.Config{
+		Name:        "hello_world_agent",
+		Description: "Greets the world.",
+		Run: func(ic agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				event := session.NewEvent(ic.InvocationID())
+				event.Author = ic.Agent().Name()
+				event.Content = genai.NewContentFromText(wantMessage, genai.RoleModel)
+				yield(event, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("agent.New() error = %v", err)
+	}
+	config := &launcher.Config{
+		AgentLoader:    agent.NewSingleLoader(agnt),
+		SessionService: session.InMemoryService(),
+	}
+
+	go func() {
+		if err := l.Run(t.Context(), config); err != nil {
+			t.Errorf("launcher.Run() error = %v", err)
+		}
+	}()
+
+	client := mcpsdk.NewClient(&mcpsdk.Implementation{Name: "client", Version: "v1.0.0"}, nil)
+	var cs *mcpsdk.ClientSession
+	for retry := range 3 {
+		time.Sleep(10 * time.Millisecond) // give server time to start
+		cs, err = client.Connect(ctx, &mcpsdk.StreamableClientTransport{Endpoint: "http://localhost:" + strconv.Itoa(port) + apiPath}, nil)
+		if err == nil {
+			break
+		}
+		if retry == 2 {
+			t.Fatalf("client.Connect() error = %v", err)
+		}
+	}
+	defer cs.Close()
+
+	res, err := cs.CallTool(ctx, &mcpsdk.CallToolParams{Name: "hello_world_agent", Arguments: map[string]any{"request": "Hi!"}})
+	if err != nil {
+		t.Fatalf("cs.CallTool() error = %v", err)
+	}
+	if len(res.Content) != 1 {
+		t.Fatalf("len(res.Content) = %d, want 1", len(res.Content))
+	}
+	if got, ok := res.Content[0].(*mcpsdk.TextContent); !ok || got.Text != wantMessage {
+		t.Fatalf("res.Content[0] = %v, want %q", res.Content[0], wantMessage)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+)
+
+// Key is an AES key identified by a key ID.
+type Key struct {
+	// ID identifies the key. It is stored with every ciphertext and must be
+	// unique within a keyring. At most 255 bytes.
+	ID string
+	// Secret is the AES key: 16, 24 or 32 bytes for AES-128, AES-192 or
+	// AES-256.
+	Secret []byte
+}
+
+// aesGCM is a keyring based [Encryptor].
+type aesGCM struct {
+	primary Key
+	keys    map[string][]byte
+}
+
+// NewAESGCM returns an [Encryptor] that encrypts with AES-GCM.
+//
+// New data is always encrypted with the primary key. Data is decrypted with
+// the key whose ID is recorded in the ciphertext, which can be the primary
+// key or any of the previous keys. To rotate keys, make the new key primary
+// and pass the old one as a previous key until all data encrypted with it has
+// been rewritten or expired.
+func NewAESGCM(primary Key, previous ...Key) (Encryptor, error) {
+	e := &aesGCM{primary: primary, keys: make(map[string][]byte)}
+	for _, k := range append([]Key{primary}, previous...) {
+		if err := validateKeyID(k.ID); err != nil {
+			return nil, err
+		}
+		switch len(k.Secret) {
+		case 16, 24, 32:
+		default:
+			return nil, fmt.Errorf("key %q: AES key must be 16, 24 or 32 bytes, got %d", k.ID, len(k.Secret))
+		}
+		if _, ok := e.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicate key ID %q", k.ID)
+		}
+		e.keys[k.ID] = k.Secret
+	}
+	return e, nil
+}
+
+// Encrypt implements [Encryptor]. The ciphertext layout is
+// format || len(keyID) || keyID || nonce || sealed.
+func (e *aesGCM) Encrypt(ctx context.Context, plaintext, associatedData []byte) ([]byte, error) {
+	out := []byte{formatAESGCM}
+	out = appendKeyID(out, e.primary.ID)
+	return seal(out, e.primary.Secret, plaintext, associatedData)
+}
+
+// Decrypt implements [Encryptor].
+func (e *aesGCM) Decrypt(ctx context.Context, ciphertext, associatedData []byte) ([]byte, error) {
+	if len(ciphertext) == 0 || ciphertext[0] != formatAESGCM {
+		return nil, fmt.Errorf("%w: not an AES-GCM ciphertext", ErrDecrypt)
+	}
+	keyID, rest, err := readKeyID(ciphertext[1:])
+	if err != nil {
+		return nil, err
+	}
+	key, ok := e.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key ID %q", ErrDecrypt, keyID)
+	}
+	return open(key, rest, associatedData)
+}
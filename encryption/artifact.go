@@ -0,0 +1,137 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+// artifactService is an [artifact.Service] that encrypts artifacts before
+// passing them to the wrapped service.
+type artifactService struct {
+	service artifact.Service
+	enc     Encryptor
+}
+
+// NewArtifactService returns an [artifact.Service] that stores artifacts in
+// service encrypted by enc.
+//
+// The whole part, including its MIME type, is encrypted and stored as a blob
+// of type [EncryptedMIMEType]. File names and versions are not encrypted.
+func NewArtifactService(service artifact.Service, enc Encryptor) (artifact.Service, error) {
+	if service == nil {
+		return nil, fmt.Errorf("artifact service is required")
+	}
+	if enc == nil {
+		return nil, fmt.Errorf("encryptor is required")
+	}
+	return &artifactService{service: service, enc: enc}, nil
+}
+
+// Save implements [artifact.Service].
+func (s *artifactService) Save(ctx context.Context, req *artifact.SaveRequest) (*artifact.SaveResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	plaintext, err := json.Marshal(req.Part)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artifact: %w", err)
+	}
+	ciphertext, err := s.enc.Encrypt(ctx, plaintext, artifactAssociatedData(req.AppName, req.UserID, req.SessionID, req.FileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt artifact: %w", err)
+	}
+	encReq := *req
+	encReq.Part = &genai.Part{InlineData: &genai.Blob{MIMEType: EncryptedMIMEType, Data: ciphertext}}
+	resp, err := s.service.Save(ctx, &encReq)
+	if err != nil {
+		return nil, err
+	}
+	return &artifact.SaveResponse{Version: resp.Version, Metadata: plaintextMetadata(resp.Metadata, req.Part)}, nil
+}
+
+// Load implements [artifact.Service]. Artifacts stored without encryption are
+// returned as is.
+func (s *artifactService) Load(ctx context.Context, req *artifact.LoadRequest) (*artifact.LoadResponse, error) {
+	resp, err := s.service.Load(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	part := resp.Part
+	if part == nil || part.InlineData == nil || part.InlineData.MIMEType != EncryptedMIMEType {
+		return resp, nil
+	}
+	plaintext, err := s.enc.Decrypt(ctx, part.InlineData.Data, artifactAssociatedData(req.AppName, req.UserID, req.SessionID, req.FileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt artifact: %w", err)
+	}
+	var decrypted *genai.Part
+	if err := json.Unmarshal(plaintext, &decrypted); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal artifact: %w", err)
+	}
+	return &artifact.LoadResponse{Part: decrypted, Metadata: plaintextMetadata(resp.Metadata, decrypted)}, nil
+}
+
+// Delete implements [artifact.Service].
+func (s *artifactService) Delete(ctx context.Context, req *artifact.DeleteRequest) error {
+	return s.service.Delete(ctx, req)
+}
+
+// List implements [artifact.Service].
+func (s *artifactService) List(ctx context.Context, req *artifact.ListRequest) (*artifact.ListResponse, error) {
+	return s.service.List(ctx, req)
+}
+
+// Versions implements [artifact.Service]. The MIME type, size and checksum in
+// the returned metadata describe the stored ciphertext.
+func (s *artifactService) Versions(ctx context.Context, req *artifact.VersionsRequest) (*artifact.VersionsResponse, error) {
+	return s.service.Versions(ctx, req)
+}
+
+// plaintextMetadata returns a copy of the metadata of a stored ciphertext
+// that describes the plaintext part instead.
+func plaintextMetadata(meta *artifact.Metadata, part *genai.Part) *artifact.Metadata {
+	if meta == nil || part == nil {
+		return meta
+	}
+	m := *meta
+	data, mimeType := []byte(part.Text), "text/plain"
+	if part.InlineData != nil {
+		data, mimeType = part.InlineData.Data, part.InlineData.MIMEType
+	}
+	m.MIMEType = mimeType
+	m.Size = int64(len(data))
+	m.Checksum = artifact.Checksum(data)
+	return &m
+}
+
+// artifactAssociatedData binds an artifact to its file name and owner.
+// User-scoped artifacts are shared by all sessions of the user, so they are
+// not bound to the session.
+func artifactAssociatedData(appName, userID, sessionID, fileName string) []byte {
+	if strings.HasPrefix(fileName, "user:") {
+		return associatedData("artifact", appName, userID, fileName)
+	}
+	return associatedData("artifact", appName, userID, sessionID, fileName)
+}
+
+var _ artifact.Service = (*artifactService)(nil)
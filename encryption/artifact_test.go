@@ -0,0 +1,74 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption_test
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/encryption"
+	"google.golang.org/adk/internal/artifact/tests"
+)
+
+func TestEncryptedArtifactService(t *testing.T) {
+	factory := func(t *testing.T) (artifact.Service, error) {
+		return encryption.NewArtifactService(artifact.InMemoryService(), mustAESGCM(t, newKey("k1")))
+	}
+	tests.TestArtifactService(t, "Encrypted", factory)
+}
+
+func TestArtifactService_StoresCiphertext(t *testing.T) {
+	ctx := t.Context()
+	stored := artifact.InMemoryService()
+	service, err := encryption.NewArtifactService(stored, mustAESGCM(t, newKey("k1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Save(ctx, &artifact.SaveRequest{
+		AppName: "app", UserID: "user", SessionID: "s1", FileName: "report.pdf",
+		Part: genai.NewPartFromBytes([]byte("confidential"), "application/pdf"),
+	}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	raw, err := stored.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "s1", FileName: "report.pdf"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if raw.Part.InlineData.MIMEType != encryption.EncryptedMIMEType || bytes.Contains(raw.Part.InlineData.Data, []byte("confidential")) {
+		t.Errorf("stored artifact = %+v, want encrypted blob", raw.Part.InlineData)
+	}
+
+	loaded, err := service.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "s1", FileName: "report.pdf"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded.Metadata; got.MIMEType != "application/pdf" || got.Size != int64(len("confidential")) {
+		t.Errorf("Load() metadata MIME type, size = %q, %d, want plaintext values", got.MIMEType, got.Size)
+	}
+
+	// A ciphertext copied to another session does not decrypt.
+	if _, err := stored.Save(ctx, &artifact.SaveRequest{
+		AppName: "app", UserID: "user", SessionID: "s2", FileName: "report.pdf", Part: raw.Part,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "s2", FileName: "report.pdf"}); err == nil {
+		t.Error("Load() of a copied ciphertext succeeded, want error")
+	}
+}
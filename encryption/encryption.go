@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package encryption provides encryption at rest for sessions and artifacts.
+//
+// An [Encryptor] seals and opens byte payloads. Two implementations are
+// provided: [NewAESGCM] uses a local keyring of AES keys identified by key
+// IDs, which allows rotating keys while old data stays readable, and
+// [NewEnvelope] delegates key management to an external KMS through the
+// [KeyManager] interface.
+//
+// [NewSessionService] and [NewArtifactService] wrap existing services and
+// transparently encrypt data before it reaches the underlying storage and
+// decrypt it when it is read back. Data written before encryption was
+// enabled is returned unchanged, so encryption can be turned on for an
+// existing deployment.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// Encryptor encrypts and decrypts payloads.
+//
+// The associated data is authenticated but not encrypted; decryption fails
+// if it differs from the value used for encryption. It binds a ciphertext to
+// its owner so that it cannot be copied to another record.
+type Encryptor interface {
+	// Encrypt returns the ciphertext of plaintext. The ciphertext is
+	// self-describing: it records the key needed to decrypt it.
+	Encrypt(ctx context.Context, plaintext, associatedData []byte) ([]byte, error)
+	// Decrypt returns the plaintext of a ciphertext produced by Encrypt.
+	Decrypt(ctx context.Context, ciphertext, associatedData []byte) ([]byte, error)
+}
+
+// ErrDecrypt is returned when a ciphertext cannot be decrypted, e.g. because
+// it was tampered with, the associated data differs or the key is unknown.
+var ErrDecrypt = errors.New("failed to decrypt")
+
+// Ciphertext format versions, stored in the first byte of every ciphertext.
+const (
+	formatAESGCM   byte = 1
+	formatEnvelope byte = 2
+)
+
+// seal encrypts plaintext with AES-GCM using a random nonce and returns
+// nonce || ciphertext appended to dst.
+func seal(dst, key, plaintext, associatedData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	dst = append(dst, nonce...)
+	return aead.Seal(dst, nonce, plaintext, associatedData), nil
+}
+
+// open reverses seal.
+func open(key, sealed, associatedData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: ciphertext too short", ErrDecrypt)
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AES key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// appendKeyID appends a length-prefixed key ID.
+func appendKeyID(dst []byte, keyID string) []byte {
+	dst = append(dst, byte(len(keyID)))
+	return append(dst, keyID...)
+}
+
+// readKeyID reads a length-prefixed key ID and returns it with the rest of b.
+func readKeyID(b []byte) (string, []byte, error) {
+	if len(b) < 1 || len(b) < 1+int(b[0]) {
+		return "", nil, fmt.Errorf("%w: malformed key ID", ErrDecrypt)
+	}
+	n := int(b[0])
+	return string(b[1 : 1+n]), b[1+n:], nil
+}
+
+func validateKeyID(keyID string) error {
+	if keyID == "" {
+		return fmt.Errorf("key ID is required")
+	}
+	if len(keyID) > 255 {
+		return fmt.Errorf("key ID %q is longer than 255 bytes", keyID)
+	}
+	return nil
+}
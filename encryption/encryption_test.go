@@ -0,0 +1,175 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/adk/encryption"
+)
+
+func newKey(id string) encryption.Key {
+	return encryption.Key{ID: id, Secret: bytes.Repeat([]byte(id[:1]), 32)}
+}
+
+func mustAESGCM(t *testing.T, primary encryption.Key, previous ...encryption.Key) encryption.Encryptor {
+	t.Helper()
+	enc, err := encryption.NewAESGCM(primary, previous...)
+	if err != nil {
+		t.Fatalf("NewAESGCM() error = %v", err)
+	}
+	return enc
+}
+
+func TestAESGCM(t *testing.T) {
+	ctx := t.Context()
+	enc := mustAESGCM(t, newKey("k1"))
+	plaintext := []byte("secret")
+	ad := []byte("owner")
+
+	ciphertext, err := enc.Encrypt(ctx, plaintext, ad)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Errorf("Encrypt() = %q, contains the plaintext", ciphertext)
+	}
+	got, err := enc.Decrypt(ctx, ciphertext, ad)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Decrypt() = %q, want %q", got, plaintext)
+	}
+
+	if _, err := enc.Decrypt(ctx, ciphertext, []byte("other owner")); !errors.Is(err, encryption.ErrDecrypt) {
+		t.Errorf("Decrypt() with different associated data error = %v, want ErrDecrypt", err)
+	}
+	tampered := bytes.Clone(ciphertext)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := enc.Decrypt(ctx, tampered, ad); !errors.Is(err, encryption.ErrDecrypt) {
+		t.Errorf("Decrypt() of tampered ciphertext error = %v, want ErrDecrypt", err)
+	}
+	if _, err := enc.Decrypt(ctx, ciphertext[:5], ad); !errors.Is(err, encryption.ErrDecrypt) {
+		t.Errorf("Decrypt() of truncated ciphertext error = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestAESGCM_KeyRotation(t *testing.T) {
+	ctx := t.Context()
+	old := mustAESGCM(t, newKey("k1"))
+	ciphertext, err := old.Encrypt(ctx, []byte("secret"), nil)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	rotated := mustAESGCM(t, newKey("k2"), newKey("k1"))
+	if got, err := rotated.Decrypt(ctx, ciphertext, nil); err != nil || string(got) != "secret" {
+		t.Errorf("Decrypt() with rotated keyring = %q, %v, want %q", got, err, "secret")
+	}
+	newCiphertext, err := rotated.Encrypt(ctx, []byte("secret"), nil)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if _, err := old.Decrypt(ctx, newCiphertext, nil); !errors.Is(err, encryption.ErrDecrypt) {
+		t.Errorf("Decrypt() with unknown key ID error = %v, want ErrDecrypt", err)
+	}
+
+	retired := mustAESGCM(t, newKey("k2"))
+	if _, err := retired.Decrypt(ctx, ciphertext, nil); !errors.Is(err, encryption.ErrDecrypt) {
+		t.Errorf("Decrypt() with retired key error = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestNewAESGCM_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		primary  encryption.Key
+		previous []encryption.Key
+	}{
+		{name: "missing key ID", primary: encryption.Key{Secret: make([]byte, 32)}},
+		{name: "invalid key size", primary: encryption.Key{ID: "k1", Secret: make([]byte, 10)}},
+		{name: "duplicate key ID", primary: newKey("k1"), previous: []encryption.Key{newKey("k1")}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := encryption.NewAESGCM(tc.primary, tc.previous...); err == nil {
+				t.Error("NewAESGCM() succeeded, want error")
+			}
+		})
+	}
+}
+
+// fakeKMS wraps data keys with a local AES-GCM keyring.
+type fakeKMS struct {
+	keyID string
+	kek   encryption.Encryptor
+	calls int
+}
+
+func (k *fakeKMS) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	k.calls++
+	wrapped, err := k.kek.Encrypt(ctx, dataKey, []byte(k.keyID))
+	return k.keyID, wrapped, err
+}
+
+func (k *fakeKMS) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	k.calls++
+	if keyID != k.keyID {
+		return nil, fmt.Errorf("unknown key %q", keyID)
+	}
+	return k.kek.Decrypt(ctx, wrapped, []byte(keyID))
+}
+
+func TestEnvelope(t *testing.T) {
+	ctx := t.Context()
+	kms := &fakeKMS{keyID: "projects/p/keys/k1", kek: mustAESGCM(t, newKey("kek"))}
+	enc, err := encryption.NewEnvelope(kms)
+	if err != nil {
+		t.Fatalf("NewEnvelope() error = %v", err)
+	}
+
+	ciphertext, err := enc.Encrypt(ctx, []byte("secret"), []byte("owner"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	got, err := enc.Decrypt(ctx, ciphertext, []byte("owner"))
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(got) != "secret" {
+		t.Errorf("Decrypt() = %q, want %q", got, "secret")
+	}
+	if kms.calls != 2 {
+		t.Errorf("KMS calls = %d, want 2", kms.calls)
+	}
+	if _, err := enc.Decrypt(ctx, ciphertext, []byte("other")); !errors.Is(err, encryption.ErrDecrypt) {
+		t.Errorf("Decrypt() with different associated data error = %v, want ErrDecrypt", err)
+	}
+
+	kms.keyID = "projects/p/keys/k2"
+	if _, err := enc.Decrypt(ctx, ciphertext, []byte("owner")); !errors.Is(err, encryption.ErrDecrypt) {
+		t.Errorf("Decrypt() with unknown KMS key error = %v, want ErrDecrypt", err)
+	}
+
+	aes := mustAESGCM(t, newKey("k1"))
+	if _, err := aes.Decrypt(ctx, ciphertext, []byte("owner")); !errors.Is(err, encryption.ErrDecrypt) {
+		t.Errorf("AES-GCM Decrypt() of envelope ciphertext error = %v, want ErrDecrypt", err)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+)
+
+// KeyManager is implemented by external key management services (KMS).
+//
+// The KMS holds the key encryption keys; they never leave it. It only wraps
+// and unwraps the short-lived data encryption keys generated by
+// [NewEnvelope].
+type KeyManager interface {
+	// WrapKey encrypts a data encryption key with the current key
+	// encryption key and returns the ID of that key with the wrapped key.
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	// UnwrapKey decrypts a data encryption key wrapped by the key
+	// encryption key with the given ID.
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// envelope is a [KeyManager] based [Encryptor].
+type envelope struct {
+	km KeyManager
+}
+
+// NewEnvelope returns an [Encryptor] that uses envelope encryption.
+//
+// Every payload is encrypted with AES-256-GCM under a fresh data encryption
+// key. The data key is wrapped by the [KeyManager] and stored next to the
+// ciphertext, so each Encrypt and Decrypt call performs one KMS request. Key
+// rotation is handled by the KMS: ciphertexts record the key ID reported by
+// WrapKey and pass it back to UnwrapKey.
+func NewEnvelope(km KeyManager) (Encryptor, error) {
+	if km == nil {
+		return nil, fmt.Errorf("key manager is required")
+	}
+	return &envelope{km: km}, nil
+}
+
+// Encrypt implements [Encryptor]. The ciphertext layout is
+// format || len(keyID) || keyID || len(wrapped) || wrapped || nonce || sealed.
+func (e *envelope) Encrypt(ctx context.Context, plaintext, associatedData []byte) ([]byte, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	keyID, wrapped, err := e.km.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	if err := validateKeyID(keyID); err != nil {
+		return nil, fmt.Errorf("invalid key ID returned by key manager: %w", err)
+	}
+	if len(wrapped) > 0xffff {
+		return nil, fmt.Errorf("wrapped data key is too large: %d bytes", len(wrapped))
+	}
+	out := []byte{formatEnvelope}
+	out = appendKeyID(out, keyID)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(wrapped)))
+	out = append(out, wrapped...)
+	return seal(out, dataKey, plaintext, associatedData)
+}
+
+// Decrypt implements [Encryptor].
+func (e *envelope) Decrypt(ctx context.Context, ciphertext, associatedData []byte) ([]byte, error) {
+	if len(ciphertext) == 0 || ciphertext[0] != formatEnvelope {
+		return nil, fmt.Errorf("%w: not an envelope ciphertext", ErrDecrypt)
+	}
+	keyID, rest, err := readKeyID(ciphertext[1:])
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < 2 {
+		return nil, fmt.Errorf("%w: malformed wrapped key", ErrDecrypt)
+	}
+	n := int(binary.BigEndian.Uint16(rest))
+	rest = rest[2:]
+	if len(rest) < n {
+		return nil, fmt.Errorf("%w: malformed wrapped key", ErrDecrypt)
+	}
+	wrapped, sealed := rest[:n], rest[n:]
+	dataKey, err := e.km.UnwrapKey(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to unwrap data key: %v", ErrDecrypt, err)
+	}
+	return open(dataKey, sealed, associatedData)
+}
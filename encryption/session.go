@@ -0,0 +1,519 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+// EncryptedMIMEType is the MIME type of the blobs that hold encrypted event
+// contents and artifacts in the underlying storage.
+const EncryptedMIMEType = "application/vnd.google.adk.encrypted"
+
+// encryptedValuePrefix marks encrypted state values and tool confirmation
+// payloads, which are stored as strings.
+const encryptedValuePrefix = "adk-encrypted:"
+
+// SessionConfig selects the parts of a session that are encrypted.
+type SessionConfig struct {
+	// Content encrypts the content of events.
+	Content bool
+	// State encrypts state values: the initial state of new sessions and
+	// the state deltas of events. Keys are stored in plain text because
+	// session services route them by their scope prefix.
+	State bool
+	// StateKeys optionally restricts State encryption to the keys for which
+	// it returns true. Keys include their scope prefix, e.g. "user:email".
+	StateKeys func(key string) bool
+	// Actions encrypts the hints and payloads of the tool confirmations
+	// requested by events.
+	Actions bool
+}
+
+// sessionService is a [session.Service] that encrypts data before passing
+// it to the wrapped service.
+type sessionService struct {
+	service session.Service
+	enc     Encryptor
+	cfg     SessionConfig
+}
+
+// NewSessionService returns a [session.Service] that stores sessions in
+// service with the fields selected by cfg encrypted by enc.
+//
+// Sessions returned by the wrapper hold decrypted state and events, and must
+// be passed back to the wrapper, not to the underlying service. State values
+// are JSON encoded before encryption, so numbers are read back as float64
+// like with any JSON based storage.
+func NewSessionService(service session.Service, enc Encryptor, cfg SessionConfig) (session.Service, error) {
+	if service == nil {
+		return nil, fmt.Errorf("session service is required")
+	}
+	if enc == nil {
+		return nil, fmt.Errorf("encryptor is required")
+	}
+	return &sessionService{service: service, enc: enc, cfg: cfg}, nil
+}
+
+// Create implements [session.Service].
+func (s *sessionService) Create(ctx context.Context, req *session.CreateRequest) (*session.CreateResponse, error) {
+	encReq := *req
+	state, err := s.encryptState(ctx, req.AppName, req.UserID, req.State)
+	if err != nil {
+		return nil, err
+	}
+	encReq.State = state
+	resp, err := s.service.Create(ctx, &encReq)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := s.decryptSession(ctx, resp.Session)
+	if err != nil {
+		return nil, err
+	}
+	return &session.CreateResponse{Session: sess}, nil
+}
+
+// Get implements [session.Service].
+func (s *sessionService) Get(ctx context.Context, req *session.GetRequest) (*session.GetResponse, error) {
+	resp, err := s.service.Get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := s.decryptSession(ctx, resp.Session)
+	if err != nil {
+		return nil, err
+	}
+	return &session.GetResponse{Session: sess}, nil
+}
+
+// List implements [session.Service].
+func (s *sessionService) List(ctx context.Context, req *session.ListRequest) (*session.ListResponse, error) {
+	resp, err := s.service.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]session.Session, 0, len(resp.Sessions))
+	for _, stored := range resp.Sessions {
+		sess, err := s.decryptSession(ctx, stored)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	return &session.ListResponse{Sessions: sessions}, nil
+}
+
+// Delete implements [session.Service].
+func (s *sessionService) Delete(ctx context.Context, req *session.DeleteRequest) error {
+	return s.service.Delete(ctx, req)
+}
+
+// AppendEvent implements [session.Service].
+func (s *sessionService) AppendEvent(ctx context.Context, curSession session.Session, event *session.Event) error {
+	if curSession == nil {
+		return fmt.Errorf("session is nil")
+	}
+	if event == nil {
+		return fmt.Errorf("event is nil")
+	}
+	if event.Partial {
+		return nil
+	}
+	sess, ok := curSession.(*encryptedSession)
+	if !ok {
+		return fmt.Errorf("unexpected session type %T", curSession)
+	}
+
+	stored, err := s.encryptEvent(ctx, sess, event)
+	if err != nil {
+		return err
+	}
+	if err := s.service.AppendEvent(ctx, sess.stored, stored); err != nil {
+		return err
+	}
+	// The underlying service may assign or normalize these fields.
+	event.ID = stored.ID
+	event.Timestamp = stored.Timestamp
+	sess.appendEvent(event)
+	return nil
+}
+
+func (s *sessionService) encryptEvent(ctx context.Context, sess session.Session, event *session.Event) (*session.Event, error) {
+	stored := *event
+	if s.cfg.Content && event.Content != nil {
+		content, err := s.encryptContent(ctx, sess.AppName(), sess.UserID(), sess.ID(), event.Content)
+		if err != nil {
+			return nil, err
+		}
+		stored.Content = content
+	}
+	delta, err := s.encryptState(ctx, sess.AppName(), sess.UserID(), event.Actions.StateDelta)
+	if err != nil {
+		return nil, err
+	}
+	stored.Actions.StateDelta = delta
+	if s.cfg.Actions && len(event.Actions.RequestedToolConfirmations) > 0 {
+		confirmations := make(map[string]toolconfirmation.ToolConfirmation, len(event.Actions.RequestedToolConfirmations))
+		for id, c := range event.Actions.RequestedToolConfirmations {
+			encrypted, err := s.encryptConfirmation(ctx, sess.AppName(), sess.UserID(), sess.ID(), id, c)
+			if err != nil {
+				return nil, err
+			}
+			confirmations[id] = encrypted
+		}
+		stored.Actions.RequestedToolConfirmations = confirmations
+	}
+	return &stored, nil
+}
+
+func (s *sessionService) decryptEvent(ctx context.Context, sess session.Session, stored *session.Event) (*session.Event, error) {
+	event := *stored
+	content, err := s.decryptContent(ctx, sess.AppName(), sess.UserID(), sess.ID(), stored.Content)
+	if err != nil {
+		return nil, fmt.Errorf("event %q: %w", stored.ID, err)
+	}
+	event.Content = content
+	delta, err := s.decryptState(ctx, sess.AppName(), sess.UserID(), stored.Actions.StateDelta)
+	if err != nil {
+		return nil, fmt.Errorf("event %q: %w", stored.ID, err)
+	}
+	event.Actions.StateDelta = delta
+	if len(stored.Actions.RequestedToolConfirmations) > 0 {
+		confirmations := make(map[string]toolconfirmation.ToolConfirmation, len(stored.Actions.RequestedToolConfirmations))
+		for id, c := range stored.Actions.RequestedToolConfirmations {
+			decrypted, err := s.decryptConfirmation(ctx, sess.AppName(), sess.UserID(), sess.ID(), id, c)
+			if err != nil {
+				return nil, fmt.Errorf("event %q: %w", stored.ID, err)
+			}
+			confirmations[id] = decrypted
+		}
+		event.Actions.RequestedToolConfirmations = confirmations
+	}
+	return &event, nil
+}
+
+func (s *sessionService) decryptSession(ctx context.Context, stored session.Session) (*encryptedSession, error) {
+	state, err := s.decryptState(ctx, stored.AppName(), stored.UserID(), maps.Collect(stored.State().All()))
+	if err != nil {
+		return nil, fmt.Errorf("session %q: %w", stored.ID(), err)
+	}
+	if state == nil {
+		state = make(map[string]any)
+	}
+	events := make([]*session.Event, 0, stored.Events().Len())
+	for e := range stored.Events().All() {
+		event, err := s.decryptEvent(ctx, stored, e)
+		if err != nil {
+			return nil, fmt.Errorf("session %q: %w", stored.ID(), err)
+		}
+		events = append(events, event)
+	}
+	return &encryptedSession{stored: stored, state: state, events: events}, nil
+}
+
+func (s *sessionService) encryptState(ctx context.Context, appName, userID string, state map[string]any) (map[string]any, error) {
+	if !s.cfg.State || len(state) == 0 {
+		return state, nil
+	}
+	encrypted := make(map[string]any, len(state))
+	for key, value := range state {
+		if strings.HasPrefix(key, session.KeyPrefixTemp) || (s.cfg.StateKeys != nil && !s.cfg.StateKeys(key)) {
+			encrypted[key] = value
+			continue
+		}
+		v, err := s.encryptValue(ctx, value, stateAssociatedData(appName, userID, key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt state key %q: %w", key, err)
+		}
+		encrypted[key] = v
+	}
+	return encrypted, nil
+}
+
+// decryptState decrypts the encrypted values of state. Values stored in
+// plain text are returned as is.
+func (s *sessionService) decryptState(ctx context.Context, appName, userID string, state map[string]any) (map[string]any, error) {
+	if len(state) == 0 {
+		return state, nil
+	}
+	decrypted := make(map[string]any, len(state))
+	for key, value := range state {
+		v, err := s.decryptValue(ctx, value, stateAssociatedData(appName, userID, key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt state key %q: %w", key, err)
+		}
+		decrypted[key] = v
+	}
+	return decrypted, nil
+}
+
+// encryptContent encrypts the content of an event bound to its session, so
+// that it cannot be decrypted as the content of another session. Event IDs
+// are not bound, the wrapped service may assign them.
+func (s *sessionService) encryptContent(ctx context.Context, appName, userID, sessionID string, content *genai.Content) (*genai.Content, error) {
+	plaintext, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal content: %w", err)
+	}
+	ciphertext, err := s.enc.Encrypt(ctx, plaintext, associatedData("content", appName, userID, sessionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt content: %w", err)
+	}
+	// The role is kept so that storage-side filtering by role still works.
+	return &genai.Content{
+		Role:  content.Role,
+		Parts: []*genai.Part{{InlineData: &genai.Blob{MIMEType: EncryptedMIMEType, Data: ciphertext}}},
+	}, nil
+}
+
+func (s *sessionService) decryptContent(ctx context.Context, appName, userID, sessionID string, content *genai.Content) (*genai.Content, error) {
+	if content == nil || len(content.Parts) != 1 || content.Parts[0].InlineData == nil || content.Parts[0].InlineData.MIMEType != EncryptedMIMEType {
+		return content, nil
+	}
+	plaintext, err := s.enc.Decrypt(ctx, content.Parts[0].InlineData.Data, associatedData("content", appName, userID, sessionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt content: %w", err)
+	}
+	var decrypted *genai.Content
+	if err := json.Unmarshal(plaintext, &decrypted); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal content: %w", err)
+	}
+	return decrypted, nil
+}
+
+// confirmationPayload is the encrypted part of a tool confirmation.
+type confirmationPayload struct {
+	Hint    string `json:"hint"`
+	Payload any    `json:"payload"`
+}
+
+func (s *sessionService) encryptConfirmation(ctx context.Context, appName, userID, sessionID, id string, c toolconfirmation.ToolConfirmation) (toolconfirmation.ToolConfirmation, error) {
+	payload, err := s.encryptValue(ctx, confirmationPayload{Hint: c.Hint, Payload: c.Payload}, associatedData("confirmation", appName, userID, sessionID, id))
+	if err != nil {
+		return c, fmt.Errorf("failed to encrypt tool confirmation %q: %w", id, err)
+	}
+	return toolconfirmation.ToolConfirmation{Confirmed: c.Confirmed, Payload: payload}, nil
+}
+
+func (s *sessionService) decryptConfirmation(ctx context.Context, appName, userID, sessionID, id string, c toolconfirmation.ToolConfirmation) (toolconfirmation.ToolConfirmation, error) {
+	encrypted, ok := c.Payload.(string)
+	if !ok || !strings.HasPrefix(encrypted, encryptedValuePrefix) {
+		return c, nil
+	}
+	plaintext, err := s.decryptBytes(ctx, encrypted, associatedData("confirmation", appName, userID, sessionID, id))
+	if err != nil {
+		return c, fmt.Errorf("failed to decrypt tool confirmation %q: %w", id, err)
+	}
+	var p confirmationPayload
+	if err := json.Unmarshal(plaintext, &p); err != nil {
+		return c, fmt.Errorf("failed to unmarshal tool confirmation %q: %w", id, err)
+	}
+	return toolconfirmation.ToolConfirmation{Hint: p.Hint, Confirmed: c.Confirmed, Payload: p.Payload}, nil
+}
+
+// encryptValue returns the JSON encoding of value encrypted and encoded as a
+// prefixed base64 string.
+func (s *sessionService) encryptValue(ctx context.Context, value any, ad []byte) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := s.enc.Encrypt(ctx, plaintext, ad)
+	if err != nil {
+		return "", err
+	}
+	return encryptedValuePrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// decryptValue reverses encryptValue. Other values are returned as is.
+func (s *sessionService) decryptValue(ctx context.Context, value any, ad []byte) (any, error) {
+	encrypted, ok := value.(string)
+	if !ok || !strings.HasPrefix(encrypted, encryptedValuePrefix) {
+		return value, nil
+	}
+	plaintext, err := s.decryptBytes(ctx, encrypted, ad)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(plaintext, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (s *sessionService) decryptBytes(ctx context.Context, encrypted string, ad []byte) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, encryptedValuePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+	return s.enc.Decrypt(ctx, ciphertext, ad)
+}
+
+// stateAssociatedData binds a state value to its key and owner. App state is
+// shared by all users, so it is not bound to the user.
+func stateAssociatedData(appName, userID, key string) []byte {
+	if strings.HasPrefix(key, session.KeyPrefixApp) {
+		return associatedData("state", appName, key)
+	}
+	return associatedData("state", appName, userID, key)
+}
+
+// associatedData joins its parts into associated data for an [Encryptor].
+func associatedData(parts ...string) []byte {
+	return []byte(strings.Join(parts, "\x00"))
+}
+
+// encryptedSession is the decrypted view of a session stored by the wrapped
+// service.
+type encryptedSession struct {
+	stored session.Session
+
+	// guards all mutable fields
+	mu     sync.RWMutex
+	state  map[string]any
+	events []*session.Event
+}
+
+func (s *encryptedSession) ID() string {
+	return s.stored.ID()
+}
+
+func (s *encryptedSession) AppName() string {
+	return s.stored.AppName()
+}
+
+func (s *encryptedSession) UserID() string {
+	return s.stored.UserID()
+}
+
+func (s *encryptedSession) LastUpdateTime() time.Time {
+	return s.stored.LastUpdateTime()
+}
+
+func (s *encryptedSession) State() session.State {
+	return &state{mu: &s.mu, state: s.state}
+}
+
+func (s *encryptedSession) Events() session.Events {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return events(s.events)
+}
+
+// appendEvent mirrors the changes the wrapped service applied to the stored
+// session on the decrypted view.
+func (s *encryptedSession) appendEvent(event *session.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Temporary keys are not persisted by the wrapped service.
+	if len(event.Actions.StateDelta) > 0 {
+		trimmed := *event
+		trimmed.Actions.StateDelta = make(map[string]any, len(event.Actions.StateDelta))
+		for k, v := range event.Actions.StateDelta {
+			if !strings.HasPrefix(k, session.KeyPrefixTemp) {
+				trimmed.Actions.StateDelta[k] = v
+			}
+		}
+		maps.Copy(s.state, trimmed.Actions.StateDelta)
+		event = &trimmed
+	}
+	s.events = append(s.events, event)
+}
+
+type events []*session.Event
+
+func (e events) All() iter.Seq[*session.Event] {
+	return func(yield func(*session.Event) bool) {
+		for _, event := range e {
+			if !yield(event) {
+				return
+			}
+		}
+	}
+}
+
+func (e events) Len() int {
+	return len(e)
+}
+
+func (e events) At(i int) *session.Event {
+	if i >= 0 && i < len(e) {
+		return e[i]
+	}
+	return nil
+}
+
+type state struct {
+	mu    *sync.RWMutex
+	state map[string]any
+}
+
+func (s *state) Get(key string) (any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.state[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+	return val, nil
+}
+
+func (s *state) All() iter.Seq2[string, any] {
+	return func(yield func(key string, val any) bool) {
+		s.mu.RLock()
+
+		for k, v := range s.state {
+			s.mu.RUnlock()
+			if !yield(k, v) {
+				return
+			}
+			s.mu.RLock()
+		}
+
+		s.mu.RUnlock()
+	}
+}
+
+func (s *state) Set(key string, value any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state[key] = value
+	return nil
+}
+
+var (
+	_ session.Service = (*sessionService)(nil)
+	_ session.Session = (*encryptedSession)(nil)
+	_ session.Events  = (*events)(nil)
+	_ session.State   = (*state)(nil)
+)
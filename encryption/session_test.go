@@ -0,0 +1,236 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption_test
+
+import (
+	"encoding/json"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/encryption"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+func TestSessionService(t *testing.T) {
+	ctx := t.Context()
+	stored := session.InMemoryService()
+	service, err := encryption.NewSessionService(stored, mustAESGCM(t, newKey("k1")), encryption.SessionConfig{
+		Content: true,
+		State:   true,
+		Actions: true,
+		StateKeys: func(key string) bool {
+			return key != "public"
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSessionService() error = %v", err)
+	}
+
+	created, err := service.Create(ctx, &session.CreateRequest{
+		AppName:   "app",
+		UserID:    "user",
+		SessionID: "s1",
+		State:     map[string]any{"email": "a@example.com", "user:phone": "123", "public": "visible"},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if got, _ := created.Session.State().Get("email"); got != "a@example.com" {
+		t.Errorf("Create() state[email] = %v, want decrypted value", got)
+	}
+
+	event := &session.Event{
+		ID:        "e1",
+		Author:    "agent",
+		Timestamp: time.Now(),
+		LLMResponse: model.LLMResponse{
+			Content: genai.NewContentFromText("your SSN is 000-00-0000", genai.RoleModel),
+		},
+		Actions: session.EventActions{
+			StateDelta: map[string]any{"ssn": "000-00-0000", "temp:scratch": "x"},
+			RequestedToolConfirmations: map[string]toolconfirmation.ToolConfirmation{
+				"call1": {Hint: "transfer to 000-00-0000?", Payload: map[string]any{"amount": 10.0}},
+			},
+		},
+	}
+	if err := service.AppendEvent(ctx, created.Session, event); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+	if got, _ := created.Session.State().Get("ssn"); got != "000-00-0000" {
+		t.Errorf("state[ssn] after AppendEvent() = %v, want decrypted value", got)
+	}
+	if _, err := created.Session.State().Get("temp:scratch"); err == nil {
+		t.Error("state[temp:scratch] is set after AppendEvent(), want temporary keys skipped")
+	}
+	if got, want := event.Content.Parts[0].Text, "your SSN is 000-00-0000"; got != want {
+		t.Errorf("AppendEvent() modified the event content: got %q, want %q", got, want)
+	}
+
+	// The underlying storage holds no plain text except the opted-out key.
+	raw, err := stored.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	rawJSON, err := json.Marshal(struct {
+		State  map[string]any
+		Events []*session.Event
+	}{maps.Collect(raw.Session.State().All()), []*session.Event{raw.Session.Events().At(0)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"a@example.com", "123", "000-00-0000", "amount"} {
+		if strings.Contains(string(rawJSON), secret) {
+			t.Errorf("stored session contains %q in plain text: %s", secret, rawJSON)
+		}
+	}
+	if !strings.Contains(string(rawJSON), "visible") {
+		t.Errorf("stored session does not contain the opted-out state value in plain text: %s", rawJSON)
+	}
+
+	got, err := service.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	wantState := map[string]any{"email": "a@example.com", "user:phone": "123", "public": "visible", "ssn": "000-00-0000"}
+	if diff := cmp.Diff(wantState, maps.Collect(got.Session.State().All())); diff != "" {
+		t.Errorf("Get() state mismatch (-want +got):\n%s", diff)
+	}
+	gotEvent := got.Session.Events().At(0)
+	if diff := cmp.Diff(event.Content, gotEvent.Content); diff != "" {
+		t.Errorf("Get() event content mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(event.Actions.RequestedToolConfirmations, gotEvent.Actions.RequestedToolConfirmations); diff != "" {
+		t.Errorf("Get() tool confirmations mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"ssn": "000-00-0000"}, gotEvent.Actions.StateDelta); diff != "" {
+		t.Errorf("Get() state delta mismatch (-want +got):\n%s", diff)
+	}
+
+	list, err := service.List(ctx, &session.ListRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Sessions) != 1 {
+		t.Fatalf("List() returned %d sessions, want 1", len(list.Sessions))
+	}
+	if got, _ := list.Sessions[0].State().Get("user:phone"); got != "123" {
+		t.Errorf("List() state[user:phone] = %v, want decrypted value", got)
+	}
+
+	other, err := encryption.NewSessionService(stored, mustAESGCM(t, newKey("k2")), encryption.SessionConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"}); err == nil {
+		t.Error("Get() with a different key succeeded, want error")
+	}
+}
+
+func TestSessionService_ContentBoundToSession(t *testing.T) {
+	ctx := t.Context()
+	stored := session.InMemoryService()
+	service, err := encryption.NewSessionService(stored, mustAESGCM(t, newKey("k1")), encryption.SessionConfig{Content: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"s1", "s2"} {
+		if _, err := service.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s1, err := service.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.AppendEvent(ctx, s1.Session, &session.Event{
+		ID: "e1", Timestamp: time.Now(),
+		LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("secret", genai.RoleUser)},
+	}); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+
+	// The encrypted event is copied to another session of the same user.
+	raw, err := stored.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawS2, err := stored.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved := *raw.Session.Events().At(0)
+	if err := stored.AppendEvent(ctx, rawS2.Session, &moved); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s2"}); err == nil {
+		t.Error("Get() decrypted the content moved from another session, want error")
+	}
+}
+
+func TestSessionService_PlaintextPassthrough(t *testing.T) {
+	ctx := t.Context()
+	stored := session.InMemoryService()
+	created, err := stored.Create(ctx, &session.CreateRequest{
+		AppName: "app", UserID: "user", SessionID: "s1",
+		State: map[string]any{"legacy": "value"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stored.AppendEvent(ctx, created.Session, &session.Event{
+		ID: "e1", Timestamp: time.Now(),
+		LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("legacy", genai.RoleUser)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	service, err := encryption.NewSessionService(stored, mustAESGCM(t, newKey("k1")), encryption.SessionConfig{Content: true, State: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := service.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if v, _ := got.Session.State().Get("legacy"); v != "value" {
+		t.Errorf("state[legacy] = %v, want %q", v, "value")
+	}
+	if text := got.Session.Events().At(0).Content.Parts[0].Text; text != "legacy" {
+		t.Errorf("event text = %q, want %q", text, "legacy")
+	}
+}
+
+func TestSessionService_AppendEventRequiresWrappedSession(t *testing.T) {
+	ctx := t.Context()
+	stored := session.InMemoryService()
+	created, err := stored.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service, err := encryption.NewSessionService(stored, mustAESGCM(t, newKey("k1")), encryption.SessionConfig{State: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.AppendEvent(ctx, created.Session, session.NewEvent("inv")); err == nil {
+		t.Error("AppendEvent() with an unwrapped session succeeded, want error")
+	}
+}
@@ -31,14 +31,22 @@ type Artifacts struct {
 	SessionID string
 }
 
+// Save saves the artifact. When ctx is an agent context, as with the
+// contexts passed to tools and callbacks, the agent name and invocation ID
+// are recorded as the artifact author and invocation.
 func (a *Artifacts) Save(ctx context.Context, name string, data *genai.Part) (*artifact.SaveResponse, error) {
-	return a.Service.Save(ctx, &artifact.SaveRequest{
+	req := &artifact.SaveRequest{
 		AppName:   a.AppName,
 		UserID:    a.UserID,
 		SessionID: a.SessionID,
 		FileName:  name,
 		Part:      data,
-	})
+	}
+	if rctx, ok := ctx.(agent.ReadonlyContext); ok {
+		req.Author = rctx.AgentName()
+		req.InvocationID = rctx.InvocationID()
+	}
+	return a.Service.Save(ctx, req)
 }
 
 func (a *Artifacts) Load(ctx context.Context, name string) (*artifact.LoadResponse, error) {
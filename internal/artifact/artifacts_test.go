@@ -15,11 +15,14 @@
 package artifact_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/genai"
 
+	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
 	artifactinternal "google.golang.org/adk/internal/artifact"
 )
@@ -122,3 +125,65 @@ func TestArtifacts_Errors(t *testing.T) {
 		t.Errorf("LoadVersion(\"existsArtifact\", 99) succeeded, want error")
 	}
 }
+
+// fakeAgentContext is an agent context that only knows its agent and
+// invocation.
+type fakeAgentContext struct {
+	agent.ReadonlyContext
+	context.Context
+}
+
+func (c fakeAgentContext) Deadline() (time.Time, bool) { return c.Context.Deadline() }
+func (c fakeAgentContext) Done() <-chan struct{}       { return c.Context.Done() }
+func (c fakeAgentContext) Err() error                  { return c.Context.Err() }
+func (c fakeAgentContext) Value(key any) any           { return c.Context.Value(key) }
+func (c fakeAgentContext) AgentName() string           { return "writer" }
+func (c fakeAgentContext) InvocationID() string        { return "inv-1" }
+
+func TestArtifacts_RecordsAuthor(t *testing.T) {
+	service := artifact.InMemoryService()
+	a := artifactinternal.Artifacts{
+		Service:   service,
+		AppName:   "testApp",
+		UserID:    "testUser",
+		SessionID: "testSession",
+	}
+	ctx := fakeAgentContext{Context: t.Context()}
+	if _, err := a.Save(ctx, "testArtifact", genai.NewPartFromText("test data")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	resp, err := a.Load(t.Context(), "testArtifact")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := resp.Metadata; got.Author != "writer" || got.InvocationID != "inv-1" {
+		t.Errorf("Load() metadata author, invocation = %q, %q, want %q, %q", got.Author, got.InvocationID, "writer", "inv-1")
+	}
+}
+
+func TestURI(t *testing.T) {
+	for _, name := range []string{"chart.png", "user:report 1.pdf", "a?b#c%d"} {
+		uri := artifactinternal.URI(name, 3)
+		if !artifactinternal.IsURI(uri) {
+			t.Errorf("IsURI(%q) = false, want true", uri)
+		}
+		gotName, gotVersion, err := artifactinternal.ParseURI(uri)
+		if err != nil {
+			t.Fatalf("ParseURI(%q) error = %v", uri, err)
+		}
+		if gotName != name || gotVersion != 3 {
+			t.Errorf("ParseURI(%q) = %q, %d, want %q, 3", uri, gotName, gotVersion, name)
+		}
+	}
+	for _, uri := range []string{"gs://bucket/file", "artifact:", "artifact:name", "artifact:name?version=x"} {
+		if _, _, err := artifactinternal.ParseURI(uri); err == nil {
+			t.Errorf("ParseURI(%q) succeeded, want error", uri)
+		}
+	}
+}
+
+func TestFileName(t *testing.T) {
+	if got, want := artifactinternal.FileName("tool/a\\b_call1", "image/png"), "tool_a_b_call1.png"; got != want {
+		t.Errorf("FileName() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// uriScheme is the scheme of the URIs referencing an artifact of the
+// current session.
+const uriScheme = "artifact"
+
+// FileName returns the name of an artifact made of prefix followed by the
+// file extension of mimeType. Path separators, which artifact names cannot
+// contain, are replaced by underscores.
+func FileName(prefix, mimeType string) string {
+	name := prefix
+	if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+		name += exts[0]
+	}
+	return strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+}
+
+// URI returns a URI referencing the given version of the artifact name of
+// the current session, like "artifact:chart.png?version=1".
+func URI(name string, version int64) string {
+	u := &url.URL{
+		Scheme:   uriScheme,
+		Opaque:   url.PathEscape(name),
+		RawQuery: url.Values{"version": {strconv.FormatInt(version, 10)}}.Encode(),
+	}
+	return u.String()
+}
+
+// IsURI reports whether uri references an artifact of the current session.
+func IsURI(uri string) bool {
+	return strings.HasPrefix(uri, uriScheme+":")
+}
+
+// ParseURI returns the artifact name and version referenced by a URI
+// returned by URI.
+func ParseURI(uri string) (name string, version int64, err error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != uriScheme {
+		return "", 0, fmt.Errorf("invalid artifact URI %q", uri)
+	}
+	if name, err = url.PathUnescape(u.Opaque); err != nil || name == "" {
+		return "", 0, fmt.Errorf("invalid artifact name in URI %q", uri)
+	}
+	if version, err = strconv.ParseInt(u.Query().Get("version"), 10, 64); err != nil {
+		return "", 0, fmt.Errorf("invalid artifact version in URI %q", uri)
+	}
+	return name, version, nil
+}
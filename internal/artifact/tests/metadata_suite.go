@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+// TestArtifactMetadata tests a service that records artifact metadata and
+// implements [artifact.StreamingService].
+func TestArtifactMetadata(t *testing.T, name string, factory func(t *testing.T) (artifact.Service, error)) {
+	t.Run(fmt.Sprintf("Test%sArtifactService_Metadata", name), func(t *testing.T) {
+		ctx := t.Context()
+		srv, err := factory(t)
+		if err != nil {
+			t.Fatalf("Failed to set up service: %v", err)
+		}
+		before := time.Now().Add(-time.Second)
+
+		saved, err := srv.Save(ctx, &artifact.SaveRequest{
+			AppName: "app", UserID: "user", SessionID: "session", FileName: "report.csv",
+			Part:         genai.NewPartFromBytes([]byte("a,b\n1,2\n"), "text/csv"),
+			Author:       "analyst",
+			InvocationID: "inv-1",
+			Attributes:   map[string]string{"source": "warehouse"},
+		})
+		if err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+		if _, err := srv.Save(ctx, &artifact.SaveRequest{
+			AppName: "app", UserID: "user", SessionID: "session", FileName: "report.csv",
+			Part: genai.NewPartFromText("v2"),
+		}); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+
+		ignoreTime := cmpopts.IgnoreFields(artifact.Metadata{}, "CreateTime")
+		wantV1 := &artifact.Metadata{
+			FileName:     "report.csv",
+			Version:      1,
+			MIMEType:     "text/csv",
+			Size:         8,
+			Checksum:     artifact.Checksum([]byte("a,b\n1,2\n")),
+			Author:       "analyst",
+			InvocationID: "inv-1",
+			Attributes:   map[string]string{"source": "warehouse"},
+		}
+		wantV2 := &artifact.Metadata{
+			FileName: "report.csv",
+			Version:  2,
+			MIMEType: "text/plain",
+			Size:     2,
+			Checksum: artifact.Checksum([]byte("v2")),
+		}
+		if diff := cmp.Diff(wantV1, saved.Metadata, ignoreTime, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Save() metadata mismatch (-want +got):\n%s", diff)
+		}
+
+		loaded, err := srv.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "session", FileName: "report.csv", Version: 1})
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if diff := cmp.Diff(wantV1, loaded.Metadata, ignoreTime, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Load() metadata mismatch (-want +got):\n%s", diff)
+		}
+		if loaded.Metadata.CreateTime.Before(before) {
+			t.Errorf("Load() CreateTime = %v, want after %v", loaded.Metadata.CreateTime, before)
+		}
+
+		versions, err := srv.Versions(ctx, &artifact.VersionsRequest{AppName: "app", UserID: "user", SessionID: "session", FileName: "report.csv"})
+		if err != nil {
+			t.Fatalf("Versions() failed: %v", err)
+		}
+		if len(versions.Metadata) != len(versions.Versions) {
+			t.Fatalf("Versions() returned %d metadata for %d versions", len(versions.Metadata), len(versions.Versions))
+		}
+		got := map[int64]*artifact.Metadata{}
+		for i, v := range versions.Versions {
+			got[v] = versions.Metadata[i]
+		}
+		want := map[int64]*artifact.Metadata{1: wantV1, 2: wantV2}
+		if diff := cmp.Diff(want, got, ignoreTime, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Versions() metadata mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run(fmt.Sprintf("Test%sArtifactService_Stream", name), func(t *testing.T) {
+		ctx := t.Context()
+		srv, err := factory(t)
+		if err != nil {
+			t.Fatalf("Failed to set up service: %v", err)
+		}
+		if _, ok := srv.(artifact.StreamingService); !ok {
+			t.Fatalf("service %T does not implement artifact.StreamingService", srv)
+		}
+
+		content := bytes.Repeat([]byte("0123456789"), 100_000)
+		saved, err := artifact.SaveStream(ctx, srv, &artifact.SaveStreamRequest{
+			AppName: "app", UserID: "user", SessionID: "session", FileName: "user:video.bin",
+			MIMEType:   "application/octet-stream",
+			Body:       bytes.NewReader(content),
+			Attributes: map[string]string{"codec": "none"},
+		})
+		if err != nil {
+			t.Fatalf("SaveStream() failed: %v", err)
+		}
+		want := &artifact.Metadata{
+			FileName:   "user:video.bin",
+			Version:    1,
+			MIMEType:   "application/octet-stream",
+			Size:       int64(len(content)),
+			Checksum:   artifact.Checksum(content),
+			Attributes: map[string]string{"codec": "none"},
+		}
+		ignoreTime := cmpopts.IgnoreFields(artifact.Metadata{}, "CreateTime")
+		if diff := cmp.Diff(want, saved.Metadata, ignoreTime, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("SaveStream() metadata mismatch (-want +got):\n%s", diff)
+		}
+
+		loaded, err := artifact.LoadStream(ctx, srv, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "other", FileName: "user:video.bin"})
+		if err != nil {
+			t.Fatalf("LoadStream() failed: %v", err)
+		}
+		defer loaded.Body.Close()
+		data, err := io.ReadAll(loaded.Body)
+		if err != nil {
+			t.Fatalf("ReadAll() failed: %v", err)
+		}
+		if !bytes.Equal(data, content) {
+			t.Errorf("LoadStream() returned %d bytes, want %d bytes of saved content", len(data), len(content))
+		}
+		if diff := cmp.Diff(want, loaded.Metadata, ignoreTime, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("LoadStream() metadata mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
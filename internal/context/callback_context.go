@@ -76,7 +76,11 @@ type callbackContext struct {
 	eventActions  *session.EventActions
 }
 
+// Artifacts returns nil if no artifact service is configured.
 func (c *callbackContext) Artifacts() agent.Artifacts {
+	if c.artifacts.Artifacts == nil {
+		return nil
+	}
 	return c.artifacts
 }
 
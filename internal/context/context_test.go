@@ -45,6 +45,13 @@ func TestCallbackContext(t *testing.T) {
 	}
 }
 
+func TestCallbackContext_ArtifactsWithoutService(t *testing.T) {
+	inv := NewInvocationContext(t.Context(), InvocationContextParams{})
+	if got := NewCallbackContext(inv).Artifacts(); got != nil {
+		t.Errorf("Artifacts() = %v without artifact service, want nil", got)
+	}
+}
+
 type testKey struct{}
 
 func TestWithContext(t *testing.T) {
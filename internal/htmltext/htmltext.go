@@ -0,0 +1,341 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package htmltext converts HTML to text for the models.
+package htmltext
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Text returns the text of an HTML fragment, with tags and scripts removed,
+// entities decoded and whitespace collapsed.
+func Text(s string) string {
+	var sb strings.Builder
+	skipped := 0
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(sb.String()), " ")
+		case html.TextToken:
+			if skipped == 0 {
+				sb.Write(z.Text())
+			}
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			switch {
+			case skippedTags[string(name)] && tt == html.StartTagToken:
+				skipped++
+			case skippedTags[string(name)] && tt == html.EndTagToken:
+				skipped = max(skipped-1, 0)
+			case blockTags[string(name)]:
+				// Tags separate words only if they are blocks.
+				sb.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// skippedTags are the elements whose contents are not part of the text.
+var skippedTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+	"svg": true, "canvas": true, "iframe": true, "object": true,
+}
+
+// blockTags are the elements starting a new block of text.
+var blockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true,
+	"dd": true, "details": true, "div": true, "dl": true, "dt": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true, "header": true,
+	"html": true, "main": true, "nav": true, "p": true, "section": true, "summary": true,
+	"table": true, "tbody": true, "thead": true, "tfoot": true, "caption": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "pre": true, "hr": true, "br": true, "tr": true,
+	"td": true, "th": true,
+}
+
+// Markdown converts an HTML document to Markdown-like text, keeping
+// headings, paragraphs, lists, links, emphasis, code and table rows, and
+// returns it with the title of the document. Relative links are resolved
+// against base if it is not nil.
+func Markdown(doc string, base *url.URL) (title, text string) {
+	w := &markdownWriter{base: base}
+	z := html.NewTokenizer(strings.NewReader(doc))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(w.title.String()), " "), w.String()
+		case html.TextToken:
+			w.text(string(z.Text()))
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			attrs := map[string]string{}
+			for hasAttr {
+				var k, v []byte
+				k, v, hasAttr = z.TagAttr()
+				attrs[string(k)] = string(v)
+			}
+			w.start(string(name), attrs, tt == html.SelfClosingTagToken)
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			w.end(string(name))
+		}
+	}
+}
+
+// markdownWriter writes the Markdown of the tokens of an HTML document.
+type markdownWriter struct {
+	base *url.URL
+	out  strings.Builder
+
+	title   strings.Builder
+	inTitle bool
+	// skipped is the stack of open elements whose contents are skipped.
+	skipped []string
+	pre     int
+	// lists holds the item counter of the open lists, -1 for unordered
+	// lists.
+	lists []int
+	// hrefs holds the targets of the open links.
+	hrefs []string
+	// newlines is the number of line breaks to write before the next
+	// text.
+	newlines int
+	// space reports whether a space is pending before the next text.
+	space bool
+	// noSpace suppresses the pending space after opening markup like "[".
+	noSpace bool
+	// prefix is written at the start of the next line, like a list marker.
+	prefix string
+}
+
+func (w *markdownWriter) String() string {
+	return strings.TrimSpace(w.out.String())
+}
+
+// block requests n line breaks before the next text.
+func (w *markdownWriter) block(n int) {
+	w.newlines = max(w.newlines, n)
+	w.space = false
+}
+
+// write writes inline markup or text, flushing the pending breaks.
+func (w *markdownWriter) write(s string) {
+	if w.out.Len() > 0 {
+		if w.newlines > 0 {
+			w.out.WriteString(strings.Repeat("\n", w.newlines))
+		} else if w.space && !w.noSpace {
+			w.out.WriteByte(' ')
+		}
+	}
+	w.newlines, w.space, w.noSpace = 0, false, false
+	if w.prefix != "" {
+		w.out.WriteString(w.prefix)
+		w.prefix = ""
+	}
+	w.out.WriteString(s)
+}
+
+// open writes opening inline markup, which the following text sticks to.
+func (w *markdownWriter) open(s string) {
+	w.write(s)
+	w.noSpace = true
+}
+
+// close writes closing inline markup, which sticks to the preceding text.
+func (w *markdownWriter) close(s string) {
+	space := w.space
+	w.space = false
+	w.write(s)
+	w.space = space
+}
+
+func (w *markdownWriter) text(s string) {
+	switch {
+	case w.inTitle:
+		w.title.WriteString(s)
+	case len(w.skipped) > 0:
+	case w.pre > 0:
+		w.write(s)
+	default:
+		if s != "" && isSpace(s[0]) {
+			w.space = true
+		}
+		fields := strings.Fields(s)
+		if len(fields) == 0 {
+			return
+		}
+		w.write(strings.Join(fields, " "))
+		w.space = isSpace(s[len(s)-1])
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func (w *markdownWriter) start(name string, attrs map[string]string, selfClosing bool) {
+	if name == "title" && !selfClosing {
+		w.inTitle = true
+		return
+	}
+	if skippedTags[name] {
+		if !selfClosing {
+			w.skipped = append(w.skipped, name)
+		}
+		return
+	}
+	if len(w.skipped) > 0 {
+		return
+	}
+
+	switch name {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level, _ := strconv.Atoi(name[1:])
+		w.block(2)
+		w.prefix = strings.Repeat("#", level) + " "
+	case "br":
+		w.block(1)
+	case "hr":
+		w.block(2)
+		w.write("---")
+		w.block(2)
+	case "pre":
+		w.block(2)
+		w.write("```\n")
+		w.pre++
+	case "code":
+		if w.pre == 0 {
+			w.open("`")
+		}
+	case "strong", "b":
+		w.open("**")
+	case "em", "i":
+		w.open("_")
+	case "ul":
+		w.lists = append(w.lists, -1)
+		w.block(2)
+	case "ol":
+		w.lists = append(w.lists, 0)
+		w.block(2)
+	case "li":
+		w.block(1)
+		indent := strings.Repeat("  ", max(len(w.lists)-1, 0))
+		marker := "- "
+		if n := len(w.lists); n > 0 && w.lists[n-1] >= 0 {
+			w.lists[n-1]++
+			marker = strconv.Itoa(w.lists[n-1]) + ". "
+		}
+		w.prefix = indent + marker
+	case "a":
+		w.hrefs = append(w.hrefs, w.resolve(attrs["href"]))
+		if w.hrefs[len(w.hrefs)-1] != "" {
+			w.open("[")
+		}
+	case "img":
+		if alt := strings.TrimSpace(attrs["alt"]); alt != "" {
+			if src := w.resolve(attrs["src"]); src != "" {
+				w.write("![" + alt + "](" + src + ")")
+			}
+		}
+	case "tr":
+		w.block(1)
+	case "td", "th":
+		w.open("| ")
+	case "blockquote":
+		w.block(2)
+		w.prefix = "> "
+	default:
+		if blockTags[name] {
+			w.block(2)
+		}
+	}
+}
+
+func (w *markdownWriter) end(name string) {
+	if name == "title" {
+		w.inTitle = false
+		return
+	}
+	if n := len(w.skipped); n > 0 {
+		if w.skipped[n-1] == name {
+			w.skipped = w.skipped[:n-1]
+		}
+		return
+	}
+
+	switch name {
+	case "pre":
+		if w.pre > 0 {
+			w.pre--
+			w.write("\n```")
+			w.block(2)
+		}
+	case "code":
+		if w.pre == 0 {
+			w.close("`")
+		}
+	case "strong", "b":
+		w.close("**")
+	case "em", "i":
+		w.close("_")
+	case "ul", "ol":
+		if n := len(w.lists); n > 0 {
+			w.lists = w.lists[:n-1]
+		}
+		w.block(2)
+	case "a":
+		if n := len(w.hrefs); n > 0 {
+			if href := w.hrefs[n-1]; href != "" {
+				w.close("](" + href + ")")
+			}
+			w.hrefs = w.hrefs[:n-1]
+		}
+	case "td", "th":
+		w.space = true
+	case "tr":
+		w.write("|")
+		w.block(1)
+	case "li":
+		w.block(1)
+	default:
+		if blockTags[name] {
+			w.block(2)
+		}
+	}
+}
+
+// resolve returns the absolute URL of a link, or "" for links which are not
+// useful to the model like fragments and scripts.
+func (w *markdownWriter) resolve(href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return ""
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	if w.base != nil {
+		u = w.base.ResolveReference(u)
+	}
+	return u.String()
+}
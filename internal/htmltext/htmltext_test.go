@@ -0,0 +1,76 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package htmltext
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "inline tags", input: "Go is a <b>statically typed</b> language", want: "Go is a statically typed language"},
+		{name: "entities", input: "Tom &amp; Jerry &lt;3", want: "Tom & Jerry <3"},
+		{name: "blocks", input: "<p>one</p><p>two</p>", want: "one two"},
+		{name: "scripts", input: "a<script>var x = 1;</script> b", want: "a b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.input); got != tt.want {
+				t.Errorf("Text(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	doc := `<!DOCTYPE html>
+<html><head><title> The  Page </title><style>p { color: red }</style></head>
+<body>
+<nav><a href="#main">Skip</a></nav>
+<h1>Heading</h1>
+<p>Some <b>bold</b> and <em>emphasized</em> text with a <a href="/docs?q=1">link</a>.</p>
+<ul><li>one</li><li>two<ol><li>nested</li></ol></li></ul>
+<pre><code>func main() {
+	fmt.Println("hi")
+}</code></pre>
+<table><tr><th>Name</th><th>Value</th></tr><tr><td>a</td><td>1</td></tr></table>
+<script>alert("x")</script>
+<p>Line<br>break &amp; <code>inline</code> <img src="logo.png" alt="Logo"></p>
+</body></html>`
+	base, _ := url.Parse("https://example.com/page/")
+
+	title, text := Markdown(doc, base)
+
+	if title != "The Page" {
+		t.Errorf("Markdown() title = %q, want %q", title, "The Page")
+	}
+	want := "Skip\n\n" +
+		"# Heading\n\n" +
+		"Some **bold** and _emphasized_ text with a [link](https://example.com/docs?q=1).\n\n" +
+		"- one\n- two\n\n  1. nested\n\n" +
+		"```\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```\n\n" +
+		"| Name | Value |\n| a | 1 |\n\n" +
+		"Line\nbreak & `inline` ![Logo](https://example.com/page/logo.png)"
+	if diff := cmp.Diff(want, text); diff != "" {
+		t.Errorf("Markdown() text mismatch (-want +got):\n%s", diff)
+	}
+}
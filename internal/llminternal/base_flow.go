@@ -188,7 +188,14 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 
 			// Handle function calls.
 
-			ev, err := f.handleFunctionCalls(ctx, tools, resp.LLMResponse, nil)
+			stopped := false
+			ev, err := f.handleFunctionCalls(ctx, tools, resp.LLMResponse, nil, func(ev *session.Event) bool {
+				stopped = stopped || !yield(ev, nil)
+				return !stopped
+			})
+			if stopped {
+				return
+			}
 			if err != nil {
 				yield(nil, err)
 				return
@@ -553,10 +560,12 @@ Suggested fixes:
 }
 
 // handleFunctionCalls calls the functions and returns the function response event.
+// The intermediate results of streaming tools are passed to yieldPartial as
+// partial events if it is not nil.
 //
 // TODO: accept filters to include/exclude function calls.
 // TODO: check feasibility of running tool.Run concurrently.
-func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[string]tool.Tool, resp *model.LLMResponse, toolConfirmations map[string]*toolconfirmation.ToolConfirmation) (mergedEvent *session.Event, err error) {
+func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[string]tool.Tool, resp *model.LLMResponse, toolConfirmations map[string]*toolconfirmation.ToolConfirmation, yieldPartial func(*session.Event) bool) (mergedEvent *session.Event, err error) {
 	var fnResponseEvents []*session.Event
 	fnCalls := utils.FunctionCalls(resp.Content)
 	toolNames := slices.Collect(maps.Keys(toolsDict))
@@ -599,27 +608,32 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 					result = map[string]any{"error": err.Error()}
 				}
 			} else {
-				result = f.callTool(toolCtx, funcTool, fnCall.Args)
+				var partial func(map[string]any) bool
+				if yieldPartial != nil {
+					partial = func(result map[string]any) bool {
+						ev := newFunctionResponseEvent(ctx, fnCall, result)
+						ev.Partial = true
+						return yieldPartial(ev)
+					}
+				}
+				result = f.callTool(toolCtx, funcTool, fnCall.Args, partial)
 			}
 
-			// TODO: handle long-running tool.
-			ev := session.NewEvent(ctx.InvocationID())
-			ev.LLMResponse = model.LLMResponse{
-				Content: &genai.Content{
-					Role: "user",
-					Parts: []*genai.Part{
-						{
-							FunctionResponse: &genai.FunctionResponse{
-								ID:       fnCall.ID,
-								Name:     fnCall.Name,
-								Response: result,
-							},
-						},
-					},
-				},
+			var fnParts []*genai.FunctionResponsePart
+			if parts := toolinternal.AttachedParts(toolCtx); len(parts) > 0 {
+				var artifacts agent.Artifacts
+				if ctx.Artifacts() != nil {
+					artifacts = toolCtx.Artifacts()
+				}
+				var err error
+				if fnParts, err = functionResponseParts(toolCtx, artifacts, fnCall, parts); err != nil {
+					result = map[string]any{"error": err.Error()}
+				}
 			}
-			ev.Author = ctx.Agent().Name()
-			ev.Branch = ctx.Branch()
+
+			// TODO: handle long-running tool.
+			ev := newFunctionResponseEvent(ctx, fnCall, result)
+			ev.Content.Parts[0].FunctionResponse.Parts = fnParts
 			ev.Actions = *toolCtx.Actions()
 
 			traceTool := curTool
@@ -651,6 +665,29 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 	return mergedEvent, nil
 }
 
+// newFunctionResponseEvent returns an event with the response of a function
+// call.
+func newFunctionResponseEvent(ctx agent.InvocationContext, fnCall *genai.FunctionCall, result map[string]any) *session.Event {
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.LLMResponse = model.LLMResponse{
+		Content: &genai.Content{
+			Role: "user",
+			Parts: []*genai.Part{
+				{
+					FunctionResponse: &genai.FunctionResponse{
+						ID:       fnCall.ID,
+						Name:     fnCall.Name,
+						Response: result,
+					},
+				},
+			},
+		},
+	}
+	ev.Author = ctx.Agent().Name()
+	ev.Branch = ctx.Branch()
+	return ev
+}
+
 func (f *Flow) runOnToolErrorCallbacks(toolCtx tool.Context, tool tool.Tool, fArgs map[string]any, err error) (map[string]any, error) {
 	pluginManager := pluginManagerFromContext(toolCtx)
 	if pluginManager != nil {
@@ -662,7 +699,9 @@ func (f *Flow) runOnToolErrorCallbacks(toolCtx tool.Context, tool tool.Tool, fAr
 	return f.invokeOnToolErrorCallbacks(toolCtx, tool, fArgs, err)
 }
 
-func (f *Flow) callTool(toolCtx tool.Context, tool toolinternal.FunctionTool, fArgs map[string]any) map[string]any {
+// callTool calls a tool with its callbacks. The results of a streaming tool
+// are passed to partial as they are yielded if it is not nil.
+func (f *Flow) callTool(toolCtx tool.Context, tool toolinternal.FunctionTool, fArgs map[string]any, partial func(map[string]any) bool) map[string]any {
 	var response map[string]any
 	var err error
 	pluginManager := pluginManagerFromContext(toolCtx)
@@ -674,7 +713,7 @@ func (f *Flow) callTool(toolCtx tool.Context, tool toolinternal.FunctionTool, fA
 	}
 
 	if response == nil && err == nil {
-		response, err = tool.Run(toolCtx, fArgs)
+		response, err = runTool(toolCtx, tool, fArgs, partial)
 	}
 
 	var errorResponse map[string]any
@@ -704,11 +743,44 @@ func (f *Flow) callTool(toolCtx tool.Context, tool toolinternal.FunctionTool, fA
 	}
 
 	if err != nil {
-		return map[string]any{"error": err.Error()}
+		return toolErrorResponse(err)
 	}
 	return response
 }
 
+// toolErrorResponse returns the response reporting the error of a tool to the
+// model, with the structured details of the error if it has some.
+func toolErrorResponse(err error) map[string]any {
+	resp := map[string]any{}
+	var details toolinternal.ErrorDetails
+	if errors.As(err, &details) {
+		maps.Copy(resp, details.ErrorDetails())
+	}
+	resp["error"] = err.Error()
+	return resp
+}
+
+// runTool runs a tool. If the tool is a streaming tool and partial is not
+// nil, its results are passed to partial and the last one is returned. The
+// tool is stopped when partial returns false.
+func runTool(toolCtx tool.Context, t toolinternal.FunctionTool, fArgs map[string]any, partial func(map[string]any) bool) (map[string]any, error) {
+	st, ok := t.(toolinternal.StreamingFunctionTool)
+	if !ok || partial == nil {
+		return t.Run(toolCtx, fArgs)
+	}
+	var last map[string]any
+	for result, err := range st.RunStream(toolCtx, fArgs) {
+		if err != nil {
+			return nil, err
+		}
+		last = result
+		if !partial(result) {
+			break
+		}
+	}
+	return last, nil
+}
+
 func (f *Flow) invokeBeforeToolCallbacks(toolCtx tool.Context, tool tool.Tool, fArgs map[string]any) (map[string]any, error) {
 	for _, callback := range f.BeforeToolCallbacks {
 		result, err := callback(toolCtx, tool, fArgs)
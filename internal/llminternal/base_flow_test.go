@@ -16,16 +16,22 @@ package llminternal
 
 import (
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/genai"
 
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	artifactinternal "google.golang.org/adk/internal/artifact"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolpolicy"
 )
 
 type mockFunctionTool struct {
@@ -102,6 +108,21 @@ func TestCallTool(t *testing.T) {
 			args: map[string]any{"key": "value"},
 			want: map[string]any{"error": "tool error"},
 		},
+		{
+			name: "tool error with details",
+			tool: &mockFunctionTool{
+				name: "testTool",
+				runFunc: func(ctx tool.Context, args map[string]any) (map[string]any, error) {
+					return nil, fmt.Errorf("call failed: %w", &toolpolicy.Error{Tool: "testTool", Reason: toolpolicy.ReasonRateLimit, RetryAfter: 2 * time.Second})
+				},
+			},
+			args: map[string]any{"key": "value"},
+			want: map[string]any{
+				"error":               `call failed: tool "testTool" is rate limited, retry after 2s`,
+				"policy_violation":    "rate_limit",
+				"retry_after_seconds": 2.0,
+			},
+		},
 		{
 			name: "before callback returns result",
 			tool: &mockFunctionTool{
@@ -395,7 +416,7 @@ func TestCallTool(t *testing.T) {
 				OnToolErrorCallbacks: tc.onToolErrorCallbacks,
 			}
 			ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{})
-			got := f.callTool(toolinternal.NewToolContext(ctx, "", nil, nil), tc.tool, tc.args)
+			got := f.callTool(toolinternal.NewToolContext(ctx, "", nil, nil), tc.tool, tc.args, nil)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("callTool() mismatch (-want +got):\n%s", diff)
 			}
@@ -575,3 +596,99 @@ func TestMergeEventActions(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleFunctionCalls_AttachedParts(t *testing.T) {
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifacts := &artifactinternal.Artifacts{
+		Service:   artifact.InMemoryService(),
+		AppName:   "app",
+		UserID:    "user",
+		SessionID: "session",
+	}
+	chartTool := &mockFunctionTool{
+		name: "chart",
+		runFunc: func(ctx tool.Context, args map[string]any) (map[string]any, error) {
+			err := tool.AttachParts(ctx,
+				genai.NewPartFromBytes([]byte("png"), "image/png"),
+				genai.NewPartFromURI("gs://bucket/data.csv", "text/csv"),
+			)
+			return map[string]any{"title": "sales"}, err
+		},
+	}
+	fnCall := &genai.FunctionCall{ID: "call1", Name: "chart"}
+
+	csvPart := &genai.FunctionResponsePart{FileData: &genai.FunctionResponseFileData{FileURI: "gs://bucket/data.csv", MIMEType: "text/csv"}}
+	for _, tc := range []struct {
+		name         string
+		artifacts    agent.Artifacts
+		wantBlobName string
+		wantParts    []*genai.FunctionResponsePart
+	}{
+		{
+			name:         "with artifacts",
+			artifacts:    artifacts,
+			wantBlobName: "chart_call1_0.png",
+			// Only the reference to the artifact is stored in the event.
+			wantParts: []*genai.FunctionResponsePart{
+				{FileData: &genai.FunctionResponseFileData{FileURI: artifactinternal.URI("chart_call1_0.png", 1), MIMEType: "image/png", DisplayName: "chart_call1_0.png"}},
+				csvPart,
+			},
+		},
+		{
+			name: "without artifacts",
+			wantParts: []*genai.FunctionResponsePart{
+				{InlineData: &genai.FunctionResponseBlob{MIMEType: "image/png", Data: []byte("png")}},
+				csvPart,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a, Artifacts: tc.artifacts})
+			f := &Flow{}
+			ev, err := f.handleFunctionCalls(ctx, map[string]tool.Tool{"chart": chartTool}, &model.LLMResponse{
+				Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{FunctionCall: fnCall}}},
+			}, nil, nil)
+			if err != nil {
+				t.Fatalf("handleFunctionCalls() error = %v", err)
+			}
+			want := &genai.FunctionResponse{
+				ID:       "call1",
+				Name:     "chart",
+				Response: map[string]any{"title": "sales"},
+				Parts:    tc.wantParts,
+			}
+			if diff := cmp.Diff(want, ev.Content.Parts[0].FunctionResponse); diff != "" {
+				t.Errorf("function response mismatch (-want +got):\n%s", diff)
+			}
+			if tc.artifacts == nil {
+				return
+			}
+			if diff := cmp.Diff(map[string]int64{tc.wantBlobName: 1}, ev.Actions.ArtifactDelta); diff != "" {
+				t.Errorf("ArtifactDelta mismatch (-want +got):\n%s", diff)
+			}
+			resp, err := artifacts.Load(t.Context(), tc.wantBlobName)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := string(resp.Part.InlineData.Data); got != "png" {
+				t.Errorf("artifact data = %q, want %q", got, "png")
+			}
+
+			// The artifact is loaded back when the request is built.
+			contents := []*genai.Content{clone(ev.Content)}
+			if err := loadArtifactReferences(ctx, contents); err != nil {
+				t.Fatalf("loadArtifactReferences() error = %v", err)
+			}
+			wantLoaded := []*genai.FunctionResponsePart{
+				{InlineData: &genai.FunctionResponseBlob{MIMEType: "image/png", Data: []byte("png"), DisplayName: tc.wantBlobName}},
+				csvPart,
+			}
+			if diff := cmp.Diff(wantLoaded, contents[0].Parts[0].FunctionResponse.Parts); diff != "" {
+				t.Errorf("loaded function response parts mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -57,6 +57,10 @@ func ContentsRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest
 			yield(nil, err)
 			return
 		}
+		if err := loadArtifactReferences(ctx, contents); err != nil {
+			yield(nil, err)
+			return
+		}
 		req.Contents = append(req.Contents, contents...)
 	}
 }
@@ -15,11 +15,14 @@
 package llminternal
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	artifactinternal "google.golang.org/adk/internal/artifact"
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
@@ -93,3 +96,93 @@ func generateRequestConfirmationEvent(
 		Actions:            session.EventActions{},
 	}
 }
+
+// functionResponseParts converts the parts attached by a tool to the parts of
+// its function response. If artifacts is not nil, the inline data is saved as
+// artifacts and the response only references them, so that the data is not
+// stored in the session; the references are resolved by
+// loadArtifactReferences when the request is built.
+func functionResponseParts(ctx context.Context, artifacts agent.Artifacts, fnCall *genai.FunctionCall, parts []*genai.Part) ([]*genai.FunctionResponsePart, error) {
+	var ret []*genai.FunctionResponsePart
+	for i, part := range parts {
+		switch {
+		case part == nil:
+		case part.InlineData != nil && artifacts != nil:
+			name := artifactinternal.FileName(fmt.Sprintf("%s_%s_%d", fnCall.Name, fnCall.ID, i), part.InlineData.MIMEType)
+			resp, err := artifacts.Save(ctx, name, part)
+			if err != nil {
+				return nil, fmt.Errorf("failed to save part %d of tool %q response as artifact: %w", i, fnCall.Name, err)
+			}
+			ret = append(ret, &genai.FunctionResponsePart{
+				FileData: &genai.FunctionResponseFileData{
+					FileURI:     artifactinternal.URI(name, resp.Version),
+					MIMEType:    part.InlineData.MIMEType,
+					DisplayName: name,
+				},
+			})
+		case part.InlineData != nil:
+			ret = append(ret, &genai.FunctionResponsePart{
+				InlineData: &genai.FunctionResponseBlob{
+					MIMEType:    part.InlineData.MIMEType,
+					Data:        part.InlineData.Data,
+					DisplayName: part.InlineData.DisplayName,
+				},
+			})
+		case part.FileData != nil:
+			ret = append(ret, &genai.FunctionResponsePart{
+				FileData: &genai.FunctionResponseFileData{
+					FileURI:     part.FileData.FileURI,
+					MIMEType:    part.FileData.MIMEType,
+					DisplayName: part.FileData.DisplayName,
+				},
+			})
+		default:
+			return nil, fmt.Errorf("part %d of tool %q response has neither inline nor file data", i, fnCall.Name)
+		}
+	}
+	return ret, nil
+}
+
+// loadArtifactReferences replaces the artifact references in the function
+// responses of contents with the inline data of the artifacts. References
+// are dropped when no artifact service is configured.
+func loadArtifactReferences(ctx agent.InvocationContext, contents []*genai.Content) error {
+	for _, content := range contents {
+		for _, part := range content.Parts {
+			fr := part.FunctionResponse
+			if fr == nil {
+				continue
+			}
+			var parts []*genai.FunctionResponsePart
+			for _, p := range fr.Parts {
+				if p.FileData == nil || !artifactinternal.IsURI(p.FileData.FileURI) {
+					parts = append(parts, p)
+					continue
+				}
+				if ctx.Artifacts() == nil {
+					continue
+				}
+				name, version, err := artifactinternal.ParseURI(p.FileData.FileURI)
+				if err != nil {
+					return err
+				}
+				resp, err := ctx.Artifacts().LoadVersion(ctx, name, int(version))
+				if err != nil {
+					return fmt.Errorf("failed to load artifact %q of tool %q response: %w", name, fr.Name, err)
+				}
+				if resp.Part == nil || resp.Part.InlineData == nil {
+					return fmt.Errorf("artifact %q of tool %q response has no inline data", name, fr.Name)
+				}
+				parts = append(parts, &genai.FunctionResponsePart{
+					InlineData: &genai.FunctionResponseBlob{
+						MIMEType:    p.FileData.MIMEType,
+						Data:        resp.Part.InlineData.Data,
+						DisplayName: name,
+					},
+				})
+			}
+			fr.Parts = parts
+		}
+	}
+	return nil
+}
@@ -161,10 +161,14 @@ func RequestConfirmationRequestProcessor(ctx agent.InvocationContext, req *model
 				toolsToResumeConfirmation[callID] = cc.confirmation
 			}
 
+			stopped := false
 			ev, err := f.handleFunctionCalls(ctx, toolsmap, &model.LLMResponse{
 				Content: &genai.Content{Parts: parts, Role: genai.RoleUser},
-			}, toolsToResumeConfirmation)
-			if !yield(ev, err) {
+			}, toolsToResumeConfirmation, func(ev *session.Event) bool {
+				stopped = stopped || !yield(ev, nil)
+				return !stopped
+			})
+			if stopped || !yield(ev, err) {
 				return
 			}
 		}
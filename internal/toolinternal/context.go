@@ -17,6 +17,8 @@ package toolinternal
 import (
 	"context"
 	"fmt"
+	"iter"
+	"maps"
 
 	"github.com/google/uuid"
 	"google.golang.org/genai"
@@ -82,9 +84,14 @@ type toolContext struct {
 	eventActions      *session.EventActions
 	artifacts         *internalArtifacts
 	toolConfirmation  *toolconfirmation.ToolConfirmation
+	attachedParts     []*genai.Part
 }
 
+// Artifacts returns nil if no artifact service is configured.
 func (c *toolContext) Artifacts() agent.Artifacts {
+	if c.artifacts.Artifacts == nil {
+		return nil
+	}
 	return c.artifacts
 }
 
@@ -131,3 +138,126 @@ func (c *toolContext) RequestConfirmation(hint string, payload any) error {
 	c.eventActions.SkipSummarization = true
 	return nil
 }
+
+func (c *toolContext) AttachParts(parts ...*genai.Part) {
+	c.attachedParts = append(c.attachedParts, parts...)
+}
+
+// AttachedParts returns the parts attached to the response of a tool with
+// AttachParts.
+func AttachedParts(ctx tool.Context) []*genai.Part {
+	if c, ok := ctx.(*toolContext); ok {
+		return c.attachedParts
+	}
+	return nil
+}
+
+// InvocationContext returns the invocation context of a tool context, or
+// nil if ctx was not created by NewToolContext.
+func InvocationContext(ctx tool.Context) agent.InvocationContext {
+	if c, ok := ctx.(*toolContext); ok {
+		return c.invocationContext
+	}
+	return nil
+}
+
+// Detach returns a copy of ctx recording its state changes, actions and
+// attached parts apart from ctx, for executions which may be abandoned while
+// they still run, and the function applying them to ctx once the execution
+// returned. ok is false if ctx was not created by NewToolContext.
+func Detach(ctx tool.Context) (detached tool.Context, merge func() error, ok bool) {
+	c, ok := ctx.(*toolContext)
+	if !ok {
+		return nil, nil, false
+	}
+	actions := &session.EventActions{StateDelta: make(map[string]any)}
+	d := &toolContext{
+		CallbackContext:   &detachedCallbackContext{CallbackContext: c.CallbackContext, delta: actions.StateDelta},
+		invocationContext: c.invocationContext,
+		functionCallID:    c.functionCallID,
+		eventActions:      actions,
+		artifacts: &internalArtifacts{
+			Artifacts:    c.artifacts.Artifacts,
+			eventActions: actions,
+		},
+		toolConfirmation: c.toolConfirmation,
+	}
+	merge = func() error {
+		for key, value := range actions.StateDelta {
+			if err := c.State().Set(key, value); err != nil {
+				return fmt.Errorf("failed to set state %q: %w", key, err)
+			}
+		}
+		live := c.eventActions
+		if len(actions.ArtifactDelta) > 0 {
+			if live.ArtifactDelta == nil {
+				live.ArtifactDelta = make(map[string]int64)
+			}
+			maps.Copy(live.ArtifactDelta, actions.ArtifactDelta)
+		}
+		if len(actions.RequestedToolConfirmations) > 0 {
+			if live.RequestedToolConfirmations == nil {
+				live.RequestedToolConfirmations = make(map[string]toolconfirmation.ToolConfirmation)
+			}
+			maps.Copy(live.RequestedToolConfirmations, actions.RequestedToolConfirmations)
+		}
+		if actions.SkipSummarization {
+			live.SkipSummarization = true
+		}
+		if actions.TransferToAgent != "" {
+			live.TransferToAgent = actions.TransferToAgent
+		}
+		if actions.Escalate {
+			live.Escalate = true
+		}
+		c.attachedParts = append(c.attachedParts, d.attachedParts...)
+		return nil
+	}
+	return d, merge, true
+}
+
+// detachedCallbackContext is the callback context of a detached tool
+// context, whose state changes are only recorded in delta.
+type detachedCallbackContext struct {
+	agent.CallbackContext
+	delta map[string]any
+}
+
+func (c *detachedCallbackContext) State() session.State {
+	return &detachedState{base: c.CallbackContext.State(), delta: c.delta}
+}
+
+type detachedState struct {
+	base  session.State
+	delta map[string]any
+}
+
+func (s *detachedState) Get(key string) (any, error) {
+	if val, ok := s.delta[key]; ok {
+		return val, nil
+	}
+	return s.base.Get(key)
+}
+
+func (s *detachedState) Set(key string, val any) error {
+	s.delta[key] = val
+	return nil
+}
+
+func (s *detachedState) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for key, val := range s.delta {
+			if !yield(key, val) {
+				return
+			}
+		}
+		for key, val := range s.base.All() {
+			if _, ok := s.delta[key]; ok {
+				continue
+			}
+			if !yield(key, val) {
+				return
+			}
+		}
+	}
+}
@@ -34,6 +34,14 @@ func TestToolContext(t *testing.T) {
 	}
 }
 
+func TestToolContext_ArtifactsWithoutService(t *testing.T) {
+	inv := contextinternal.NewInvocationContext(t.Context(), contextinternal.InvocationContextParams{})
+	toolCtx := NewToolContext(inv, "fn1", &session.EventActions{}, nil)
+	if got := toolCtx.Artifacts(); got != nil {
+		t.Errorf("Artifacts() = %v without artifact service, want nil", got)
+	}
+}
+
 func TestRequestConfirmation_SetsSkipSummarization(t *testing.T) {
 	inv := contextinternal.NewInvocationContext(t.Context(), contextinternal.InvocationContextParams{})
 	actions := &session.EventActions{}
@@ -16,6 +16,8 @@
 package toolinternal
 
 import (
+	"iter"
+
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/model"
@@ -28,6 +30,20 @@ type FunctionTool interface {
 	Run(ctx tool.Context, args any) (result map[string]any, err error)
 }
 
+// StreamingFunctionTool is a FunctionTool whose intermediate results are
+// delivered to the client as partial events while it runs. Only the last
+// result of RunStream is sent to the model.
+type StreamingFunctionTool interface {
+	FunctionTool
+	RunStream(ctx tool.Context, args any) iter.Seq2[map[string]any, error]
+}
+
+// ErrorDetails is implemented by the errors of tools with structured details
+// reported to the model alongside the error message.
+type ErrorDetails interface {
+	ErrorDetails() map[string]any
+}
+
 type RequestProcessor interface {
 	ProcessRequest(ctx tool.Context, req *model.LLMRequest) error
 }
@@ -148,6 +148,7 @@ func (m *openaiModel) convertContent(content *genai.Content) ([]*OpenAIMessage,
 	var contentParts []any // Use interface slice for multimodal content (text + images)
 	var toolCalls []ToolCall
 	var functionResponses []*OpenAIMessage
+	var responseMedia []any
 
 	for _, part := range content.Parts {
 		switch {
@@ -224,6 +225,15 @@ func (m *openaiModel) convertContent(content *genai.Content) ([]*OpenAIMessage,
 				Content:    string(responseJSON),
 				ToolCallID: toolCallID,
 			})
+			// Tool messages only carry text, so the media parts of the
+			// response follow them in a user message.
+			if media := convertFunctionResponseParts(part.FunctionResponse.Parts); len(media) > 0 {
+				responseMedia = append(responseMedia, ContentPartText{
+					Type: "text",
+					Text: fmt.Sprintf("Content returned by tool %s:", part.FunctionResponse.Name),
+				})
+				responseMedia = append(responseMedia, media...)
+			}
 
 		case part.ExecutableCode != nil:
 			// Represent executable code as text
@@ -303,10 +313,56 @@ func (m *openaiModel) convertContent(content *genai.Content) ([]*OpenAIMessage,
 
 	// Add function response messages
 	messages = append(messages, functionResponses...)
+	if len(responseMedia) > 0 {
+		messages = append(messages, &OpenAIMessage{
+			Role:    "user",
+			Content: convertContentToMessage(responseMedia),
+		})
+	}
 
 	return messages, nil
 }
 
+// convertFunctionResponseParts converts the media parts of a function
+// response to content parts: images become image parts, other data is
+// described as text.
+func convertFunctionResponseParts(parts []*genai.FunctionResponsePart) []any {
+	var contentParts []any
+	for _, part := range parts {
+		switch {
+		case part == nil:
+		case part.InlineData != nil && strings.HasPrefix(part.InlineData.MIMEType, "image/"):
+			contentParts = append(contentParts, ContentPartImage{
+				Type: "image_url",
+				ImageURL: struct {
+					URL string `json:"url"`
+				}{URL: fmt.Sprintf("data:%s;base64,%s",
+					part.InlineData.MIMEType,
+					base64.StdEncoding.EncodeToString(part.InlineData.Data))},
+			})
+		case part.InlineData != nil:
+			contentParts = append(contentParts, ContentPartText{
+				Type: "text",
+				Text: fmt.Sprintf("[%s data %s, %d bytes]", part.InlineData.MIMEType, part.InlineData.DisplayName, len(part.InlineData.Data)),
+			})
+		case part.FileData != nil && strings.HasPrefix(part.FileData.MIMEType, "image/") &&
+			(strings.HasPrefix(part.FileData.FileURI, "http://") || strings.HasPrefix(part.FileData.FileURI, "https://")):
+			contentParts = append(contentParts, ContentPartImage{
+				Type: "image_url",
+				ImageURL: struct {
+					URL string `json:"url"`
+				}{URL: part.FileData.FileURI},
+			})
+		case part.FileData != nil:
+			contentParts = append(contentParts, ContentPartText{
+				Type: "text",
+				Text: part.FileData.FileURI,
+			})
+		}
+	}
+	return contentParts
+}
+
 // stripMarkdownCodeFence extracts content from ```json ... ``` blocks.
 // Local LLMs without response_format enforcement often wrap JSON in code fences.
 func stripMarkdownCodeFence(text string) string {
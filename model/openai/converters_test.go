@@ -241,6 +241,54 @@ func TestConvertContent_UserMessageUnchanged(t *testing.T) {
 	}
 }
 
+// TestConvertContent_FunctionResponseParts verifies that the media parts of a
+// function response follow the tool message in a user message.
+func TestConvertContent_FunctionResponseParts(t *testing.T) {
+	m := &openaiModel{name: "gpt-4o"}
+
+	content := &genai.Content{
+		Role: "user",
+		Parts: []*genai.Part{{
+			FunctionResponse: &genai.FunctionResponse{
+				ID:       "call_1",
+				Name:     "chart",
+				Response: map[string]any{"title": "sales"},
+				Parts: []*genai.FunctionResponsePart{
+					{InlineData: &genai.FunctionResponseBlob{MIMEType: "image/png", Data: []byte("png")}},
+					{FileData: &genai.FunctionResponseFileData{FileURI: "gs://bucket/data.csv", MIMEType: "text/csv"}},
+				},
+			},
+		}},
+	}
+
+	msgs, err := m.convertContent(content)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(msgs))
+	}
+	if msgs[0].Role != "tool" || msgs[0].ToolCallID != "call_1" || msgs[0].Content != `{"title":"sales"}` {
+		t.Errorf("Unexpected tool message: %+v", msgs[0])
+	}
+	if msgs[1].Role != "user" {
+		t.Errorf("Expected user message after the tool message, got role %q", msgs[1].Role)
+	}
+	parts, ok := msgs[1].Content.([]any)
+	if !ok || len(parts) != 3 {
+		t.Fatalf("Expected 3 content parts, got %#v", msgs[1].Content)
+	}
+	if text, ok := parts[0].(ContentPartText); !ok || text.Text != "Content returned by tool chart:" {
+		t.Errorf("Unexpected first part: %#v", parts[0])
+	}
+	if image, ok := parts[1].(ContentPartImage); !ok || image.ImageURL.URL != "data:image/png;base64,cG5n" {
+		t.Errorf("Unexpected image part: %#v", parts[1])
+	}
+	if text, ok := parts[2].(ContentPartText); !ok || text.Text != "gs://bucket/data.csv" {
+		t.Errorf("Unexpected file part: %#v", parts[2])
+	}
+}
+
 // TestConvertToOpenAIMessages_Stateless tests the stateless conversion with system instruction,
 // JSON mode, and multi-turn contents.
 func TestConvertToOpenAIMessages_Stateless(t *testing.T) {
@@ -0,0 +1,315 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package operation provides long-running operations started by tools.
+//
+// A long-running tool calls [Start] with a function doing the work in the
+// background, and returns the operation handle it gets to the model. The
+// function reports its progress with [Handle.ReportProgress], and the
+// operation completes with the result of the function. The operations are
+// persisted in a [Store], and their updates are appended to the session as
+// events, whose custom metadata holds the operation under
+// [EventMetadataKey]: by the invocation which started the operation while it
+// runs, and by the next invocation of the session afterwards.
+//
+// Once an operation is completed,
+// [google.golang.org/adk/runner.Runner.ResumeOperation] resumes the agent
+// with the result of the operation as the response of the function call, so
+// that clients do not have to build the function response themselves.
+// Operations can also be completed by other systems, like a human approval
+// workflow, with [google.golang.org/adk/runner.Runner.CompleteOperation].
+//
+// Example:
+//
+//	func render(ctx tool.Context, args renderArgs) (map[string]any, error) {
+//		return operation.Start(ctx, func(ctx context.Context, h *operation.Handle) (map[string]any, error) {
+//			for i, frame := range args.Frames {
+//				...
+//				h.ReportProgress(ctx, map[string]any{"rendered_frames": i + 1})
+//			}
+//			return map[string]any{"video": "video.mp4"}, nil
+//		})
+//	}
+//
+// The runner must be configured with an OperationStore, like
+// [InMemoryStore], for the tools to start operations.
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+// Status is the status of an operation.
+type Status string
+
+const (
+	// StatusRunning is the status of operations in progress.
+	StatusRunning Status = "running"
+	// StatusSucceeded is the status of operations completed with a result.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed is the status of operations completed with an error.
+	StatusFailed Status = "failed"
+)
+
+// Done reports whether the operation with this status is completed.
+func (s Status) Done() bool {
+	return s == StatusSucceeded || s == StatusFailed
+}
+
+// Operation is a long-running operation started by a tool.
+type Operation struct {
+	// ID identifies the operation.
+	ID string
+	// AppName, UserID and SessionID identify the session of the
+	// invocation which started the operation.
+	AppName   string
+	UserID    string
+	SessionID string
+	// InvocationID, AgentName and Branch identify the invocation and the
+	// agent which started the operation.
+	InvocationID string
+	AgentName    string
+	Branch       string
+	// FunctionCallID is the ID of the function call of the tool which
+	// started the operation.
+	FunctionCallID string
+
+	Status Status
+	// Progress is the last progress reported by the operation.
+	Progress map[string]any
+	// Result is the result of a succeeded operation.
+	Result map[string]any
+	// Error is the error of a failed operation.
+	Error string
+	// Resumed reports whether the agent was resumed with the result of the
+	// operation.
+	Resumed bool
+	// Published reports whether the last update of the operation was
+	// appended to its session. The updates made while no invocation of the
+	// session runs are appended by its next invocation.
+	Published bool
+
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+// Clone returns a copy of the operation.
+func (op *Operation) Clone() *Operation {
+	c := *op
+	c.Progress = maps.Clone(op.Progress)
+	c.Result = maps.Clone(op.Result)
+	return &c
+}
+
+// Response returns the function response of the operation: its result if it
+// succeeded, or its error if it failed.
+func (op *Operation) Response() map[string]any {
+	if op.Status == StatusFailed {
+		return map[string]any{"error": op.Error}
+	}
+	if op.Result == nil {
+		return map[string]any{}
+	}
+	return maps.Clone(op.Result)
+}
+
+// EventMetadataKey is the key of the custom metadata of the events holding
+// the updates of operations.
+const EventMetadataKey = "adk_operation"
+
+// NewEvent returns the event appended to the session of an operation when
+// it is updated. It has no content, so it is not part of the conversation
+// with the model, and holds the operation in its custom metadata.
+func NewEvent(op *Operation) *session.Event {
+	event := session.NewEvent(op.InvocationID)
+	event.Author = op.AgentName
+	event.Branch = op.Branch
+	metadata := map[string]any{
+		"id":               op.ID,
+		"function_call_id": op.FunctionCallID,
+		"status":           string(op.Status),
+	}
+	if op.Progress != nil {
+		metadata["progress"] = maps.Clone(op.Progress)
+	}
+	if op.Status == StatusSucceeded {
+		metadata["result"] = maps.Clone(op.Result)
+	}
+	if op.Error != "" {
+		metadata["error"] = op.Error
+	}
+	event.LLMResponse = model.LLMResponse{
+		CustomMetadata: map[string]any{EventMetadataKey: metadata},
+	}
+	return event
+}
+
+// Publisher publishes the updates of an operation.
+type Publisher func(ctx context.Context, op *Operation) error
+
+type contextKey struct{}
+
+type tracker struct {
+	store   Store
+	publish Publisher
+}
+
+// NewContext returns a context where the tools start operations persisted
+// in store, whose updates are published with publish if it is not nil. The
+// runner sets it up when it has an OperationStore.
+func NewContext(ctx context.Context, store Store, publish Publisher) context.Context {
+	return context.WithValue(ctx, contextKey{}, &tracker{store: store, publish: publish})
+}
+
+// Func does the work of an operation and returns its result.
+type Func func(ctx context.Context, h *Handle) (map[string]any, error)
+
+// Start starts an operation running fn in the background, and returns the
+// handle of the operation, which the tool returns to the model as its
+// response. The context of fn is not canceled when the invocation ends.
+func Start(ctx tool.Context, fn Func) (map[string]any, error) {
+	t, _ := ctx.Value(contextKey{}).(*tracker)
+	if t == nil {
+		return nil, errors.New("operations are not enabled: the runner has no OperationStore")
+	}
+	now := time.Now()
+	op := &Operation{
+		ID:             uuid.NewString(),
+		AppName:        ctx.AppName(),
+		UserID:         ctx.UserID(),
+		SessionID:      ctx.SessionID(),
+		InvocationID:   ctx.InvocationID(),
+		AgentName:      ctx.AgentName(),
+		Branch:         ctx.Branch(),
+		FunctionCallID: ctx.FunctionCallID(),
+		Status:         StatusRunning,
+		// The function response of the tool records the start of the
+		// operation.
+		Published:  true,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := t.store.Create(ctx, op); err != nil {
+		return nil, fmt.Errorf("failed to create operation: %w", err)
+	}
+	h := &Handle{id: op.ID, tracker: t}
+	go h.run(context.WithoutCancel(ctx), fn)
+	return map[string]any{
+		"operation_id": op.ID,
+		"status":       string(StatusRunning),
+	}, nil
+}
+
+// Handle is the handle of a running operation passed to its function.
+type Handle struct {
+	id      string
+	tracker *tracker
+}
+
+// ID returns the ID of the operation.
+func (h *Handle) ID() string {
+	return h.id
+}
+
+// ReportProgress records the progress of the operation and publishes it.
+// It fails if the operation is already completed.
+func (h *Handle) ReportProgress(ctx context.Context, progress map[string]any) error {
+	return h.update(ctx, func(op *Operation) error {
+		if op.Status.Done() {
+			return fmt.Errorf("operation %s is already %s", op.ID, op.Status)
+		}
+		op.Progress = maps.Clone(progress)
+		return nil
+	})
+}
+
+func (h *Handle) run(ctx context.Context, fn Func) {
+	result, err := func() (result map[string]any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic in operation: %v", r)
+			}
+		}()
+		return fn(ctx, h)
+	}()
+	err = h.update(ctx, func(op *Operation) error {
+		if op.Status.Done() {
+			// The operation was completed by someone else.
+			return errAlreadyDone
+		}
+		complete(op, result, err)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errAlreadyDone) {
+		log.Printf("Failed to complete operation %s: %v", h.id, err)
+	}
+}
+
+var errAlreadyDone = errors.New("operation is already completed")
+
+func (h *Handle) update(ctx context.Context, fn func(op *Operation) error) error {
+	op, err := h.tracker.store.Update(ctx, h.id, func(op *Operation) error {
+		if err := fn(op); err != nil {
+			return err
+		}
+		op.UpdateTime = time.Now()
+		op.Published = false
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if h.tracker.publish != nil {
+		if err := h.tracker.publish(ctx, op); err != nil {
+			return fmt.Errorf("failed to publish operation update: %w", err)
+		}
+	}
+	return nil
+}
+
+// Complete completes a running operation with result, or with err if it is
+// not nil. It is used by the runner to complete operations outside of their
+// function.
+func Complete(ctx context.Context, store Store, id string, result map[string]any, err error) (*Operation, error) {
+	return store.Update(ctx, id, func(op *Operation) error {
+		if op.Status.Done() {
+			return fmt.Errorf("operation %s is already %s", op.ID, op.Status)
+		}
+		complete(op, result, err)
+		op.UpdateTime = time.Now()
+		op.Published = false
+		return nil
+	})
+}
+
+func complete(op *Operation, result map[string]any, err error) {
+	if err != nil {
+		op.Status = StatusFailed
+		op.Error = err.Error()
+		return
+	}
+	op.Status = StatusSucceeded
+	op.Result = maps.Clone(result)
+}
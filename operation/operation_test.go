@@ -0,0 +1,273 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operation_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/operation"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+func createToolContext(t *testing.T, ctx context.Context) tool.Context {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := session.InMemoryService().Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := icontext.NewInvocationContext(ctx, icontext.InvocationContextParams{Agent: a, Session: resp.Session})
+	return toolinternal.NewToolContext(invCtx, "call1", &session.EventActions{}, nil)
+}
+
+type publisher struct {
+	mu      sync.Mutex
+	updates []*operation.Operation
+	done    chan struct{}
+}
+
+func newPublisher() *publisher {
+	return &publisher{done: make(chan struct{})}
+}
+
+func (p *publisher) publish(ctx context.Context, op *operation.Operation) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.updates = append(p.updates, op)
+	if op.Status.Done() {
+		close(p.done)
+	}
+	return nil
+}
+
+func (p *publisher) wait(t *testing.T) []*operation.Operation {
+	t.Helper()
+	select {
+	case <-p.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the operation to complete")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.updates
+}
+
+func TestStart(t *testing.T) {
+	store := operation.InMemoryStore()
+	pub := newPublisher()
+	ctx := createToolContext(t, operation.NewContext(t.Context(), store, pub.publish))
+
+	resp, err := operation.Start(ctx, func(ctx context.Context, h *operation.Handle) (map[string]any, error) {
+		if err := h.ReportProgress(ctx, map[string]any{"percent": 50}); err != nil {
+			return nil, err
+		}
+		return map[string]any{"answer": 42}, nil
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	id, _ := resp["operation_id"].(string)
+	if id == "" || resp["status"] != "running" {
+		t.Fatalf("Start() = %v, want a running operation", resp)
+	}
+
+	updates := pub.wait(t)
+	if len(updates) != 2 {
+		t.Fatalf("got %d updates, want 2", len(updates))
+	}
+	if diff := cmp.Diff(map[string]any{"percent": 50}, updates[0].Progress); diff != "" {
+		t.Errorf("progress mismatch (-want +got):\n%s", diff)
+	}
+
+	op, err := store.Get(t.Context(), id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if op.Status != operation.StatusSucceeded || op.FunctionCallID != "call1" || op.AgentName != "agent" {
+		t.Errorf("Get() = %+v, want a succeeded operation of call1 by agent", op)
+	}
+	if diff := cmp.Diff(map[string]any{"answer": 42}, op.Response()); diff != "" {
+		t.Errorf("Response() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := operation.Complete(t.Context(), store, id, nil, nil); err == nil {
+		t.Error("Complete() on a completed operation succeeded, want error")
+	}
+}
+
+func TestStart_Failure(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      operation.Func
+		wantErr string
+	}{
+		{
+			name: "error",
+			fn: func(ctx context.Context, h *operation.Handle) (map[string]any, error) {
+				return nil, errors.New("boom")
+			},
+			wantErr: "boom",
+		},
+		{
+			name: "panic",
+			fn: func(ctx context.Context, h *operation.Handle) (map[string]any, error) {
+				panic("boom")
+			},
+			wantErr: "panic in operation: boom",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := operation.InMemoryStore()
+			pub := newPublisher()
+			ctx := createToolContext(t, operation.NewContext(t.Context(), store, pub.publish))
+
+			if _, err := operation.Start(ctx, tc.fn); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			updates := pub.wait(t)
+			op := updates[len(updates)-1]
+			if op.Status != operation.StatusFailed || op.Error != tc.wantErr {
+				t.Errorf("operation = %+v, want failed with %q", op, tc.wantErr)
+			}
+			if diff := cmp.Diff(map[string]any{"error": tc.wantErr}, op.Response()); diff != "" {
+				t.Errorf("Response() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStart_NoStore(t *testing.T) {
+	ctx := createToolContext(t, t.Context())
+	_, err := operation.Start(ctx, func(ctx context.Context, h *operation.Handle) (map[string]any, error) {
+		return nil, nil
+	})
+	if err == nil {
+		t.Error("Start() without a store succeeded, want error")
+	}
+}
+
+func TestComplete_BeforeFunction(t *testing.T) {
+	store := operation.InMemoryStore()
+	pub := newPublisher()
+	ctx := createToolContext(t, operation.NewContext(t.Context(), store, pub.publish))
+
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	resp, err := operation.Start(ctx, func(ctx context.Context, h *operation.Handle) (map[string]any, error) {
+		defer close(finished)
+		<-release
+		return map[string]any{"from": "function"}, nil
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	id := resp["operation_id"].(string)
+
+	op, err := operation.Complete(t.Context(), store, id, map[string]any{"from": "caller"}, nil)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if op.Status != operation.StatusSucceeded {
+		t.Errorf("Complete() status = %s, want %s", op.Status, operation.StatusSucceeded)
+	}
+	close(release)
+	<-finished
+
+	// The result of the function, stored after it returns, does not override
+	// the explicit completion.
+	time.Sleep(10 * time.Millisecond)
+	op, err = store.Get(t.Context(), id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"from": "caller"}, op.Result); diff != "" {
+		t.Errorf("Result mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestInMemoryStore(t *testing.T) {
+	ctx := t.Context()
+	store := operation.InMemoryStore()
+	now := time.Now()
+	for i, id := range []string{"b", "a", "c"} {
+		op := &operation.Operation{
+			ID:         id,
+			AppName:    "app",
+			UserID:     "user",
+			SessionID:  "session",
+			Status:     operation.StatusRunning,
+			CreateTime: now.Add(time.Duration(i) * time.Second),
+		}
+		if id == "c" {
+			op.SessionID = "other"
+		}
+		if err := store.Create(ctx, op); err != nil {
+			t.Fatalf("Create(%q) error = %v", id, err)
+		}
+	}
+	if err := store.Create(ctx, &operation.Operation{ID: "a"}); err == nil {
+		t.Error("Create() of a duplicate succeeded, want error")
+	}
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, operation.ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+
+	ops, err := store.List(ctx, &operation.ListRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var ids []string
+	for _, op := range ops {
+		ids = append(ids, op.ID)
+	}
+	if diff := cmp.Diff([]string{"b", "a"}, ids); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := store.Update(ctx, "a", func(op *operation.Operation) error {
+		return errors.New("rejected")
+	}); err == nil {
+		t.Error("Update() with a failing function succeeded, want error")
+	}
+	op, err := store.Update(ctx, "a", func(op *operation.Operation) error {
+		op.Status = operation.StatusSucceeded
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	op.Status = operation.StatusFailed // must not change the stored copy
+	got, err := store.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status != operation.StatusSucceeded {
+		t.Errorf("Get() status = %s, want %s", got.Status, operation.StatusSucceeded)
+	}
+}
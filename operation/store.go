@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operation
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrNotFound is returned by stores for unknown operations.
+var ErrNotFound = errors.New("operation not found")
+
+// Store persists operations.
+type Store interface {
+	// Create stores a new operation.
+	Create(ctx context.Context, op *Operation) error
+	// Get returns an operation, or ErrNotFound.
+	Get(ctx context.Context, id string) (*Operation, error)
+	// Update atomically applies fn to an operation and returns the updated
+	// operation. The operation is not changed if fn fails.
+	Update(ctx context.Context, id string, fn func(op *Operation) error) (*Operation, error)
+	// List returns the operations of a session, oldest first.
+	List(ctx context.Context, req *ListRequest) ([]*Operation, error)
+}
+
+// ListRequest selects the operations returned by Store.List.
+type ListRequest struct {
+	AppName   string
+	UserID    string
+	SessionID string
+}
+
+// InMemoryStore returns a Store keeping the operations in memory.
+func InMemoryStore() Store {
+	return &inMemoryStore{operations: map[string]*Operation{}}
+}
+
+type inMemoryStore struct {
+	mu         sync.Mutex
+	operations map[string]*Operation
+}
+
+func (s *inMemoryStore) Create(ctx context.Context, op *Operation) error {
+	if op == nil || op.ID == "" {
+		return errors.New("operation ID is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.operations[op.ID]; ok {
+		return fmt.Errorf("operation %s already exists", op.ID)
+	}
+	s.operations[op.ID] = op.Clone()
+	return nil
+}
+
+func (s *inMemoryStore) Get(ctx context.Context, id string) (*Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op, ok := s.operations[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return op.Clone(), nil
+}
+
+func (s *inMemoryStore) Update(ctx context.Context, id string, fn func(op *Operation) error) (*Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op, ok := s.operations[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	updated := op.Clone()
+	if err := fn(updated); err != nil {
+		return nil, err
+	}
+	updated.ID = id
+	s.operations[id] = updated
+	return updated.Clone(), nil
+}
+
+func (s *inMemoryStore) List(ctx context.Context, req *ListRequest) ([]*Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ops []*Operation
+	for _, op := range s.operations {
+		if op.AppName == req.AppName && op.UserID == req.UserID && op.SessionID == req.SessionID {
+			ops = append(ops, op.Clone())
+		}
+	}
+	slices.SortFunc(ops, func(a, b *Operation) int {
+		return cmp.Or(a.CreateTime.Compare(b.CreateTime), cmp.Compare(a.ID, b.ID))
+	})
+	return ops, nil
+}
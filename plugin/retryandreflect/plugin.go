@@ -22,13 +22,17 @@ package retryandreflect
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"strings"
 	"sync"
 	"text/template"
+	"time"
 
 	"google.golang.org/adk/plugin"
 	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolpolicy"
 
 	_ "embed"
 )
@@ -209,6 +213,7 @@ type templateData struct {
 	ArgsSummary  string
 	RetryCount   int
 	MaxRetries   int
+	PolicyNote   string
 }
 
 func (r *retryAndReflect) createToolReflectionResponse(tool tool.Tool, toolArgs map[string]any, toolErr error, retryCount int) map[string]any {
@@ -221,6 +226,7 @@ func (r *retryAndReflect) createToolReflectionResponse(tool tool.Tool, toolArgs
 		ArgsSummary:  argsSummary,
 		RetryCount:   retryCount,
 		MaxRetries:   r.maxRetries,
+		PolicyNote:   policyNote(toolErr),
 	}
 
 	var buf bytes.Buffer
@@ -229,13 +235,13 @@ func (r *retryAndReflect) createToolReflectionResponse(tool tool.Tool, toolArgs
 		return nil
 	}
 
-	return map[string]any{
+	return withPolicyDetails(map[string]any{
 		"response_type":       reflectAndRetryResponseType,
 		"error_type":          fmt.Sprintf("%T", toolErr),
 		"error_details":       toolErr.Error(),
 		"retry_count":         retryCount,
 		"reflection_guidance": strings.TrimSpace(buf.String()),
-	}
+	}, toolErr)
 }
 
 func (r *retryAndReflect) createToolRetryExceedMsg(tool tool.Tool, toolArgs map[string]any, toolErr error) map[string]any {
@@ -254,11 +260,45 @@ func (r *retryAndReflect) createToolRetryExceedMsg(tool tool.Tool, toolArgs map[
 		return nil
 	}
 
-	return map[string]any{
+	return withPolicyDetails(map[string]any{
 		"response_type":       reflectAndRetryResponseType,
 		"error_type":          fmt.Sprintf("%T", toolErr),
 		"error_details":       toolErr.Error(),
 		"retry_count":         r.maxRetries,
 		"reflection_guidance": strings.TrimSpace(buf.String()),
+	}, toolErr)
+}
+
+// policyNote returns the guidance for a call rejected or stopped by the
+// policy of the tool, or "" if err is not a policy violation.
+func policyNote(err error) string {
+	var policyErr *toolpolicy.Error
+	if !errors.As(err, &policyErr) {
+		return ""
+	}
+	var note string
+	switch policyErr.Reason {
+	case toolpolicy.ReasonTimeout:
+		note = fmt.Sprintf("The tool did not complete within %v. Consider a smaller or simpler request.", policyErr.Timeout)
+	case toolpolicy.ReasonConcurrencyLimit, toolpolicy.ReasonRateLimit:
+		note = "The tool is busy and rejected the call, the arguments are not the cause of the failure."
+	case toolpolicy.ReasonCircuitOpen:
+		note = "The tool is temporarily disabled after repeated failures, the arguments are not the cause of the failure. Prefer another approach."
+	default:
+		note = "The call was rejected by the execution policy of the tool."
+	}
+	if policyErr.RetryAfter > 0 {
+		note += fmt.Sprintf(" Do not call it again before %v.", policyErr.RetryAfter.Round(time.Second))
+	}
+	return note
+}
+
+// withPolicyDetails adds the details of the policy violation reported by err
+// to resp, if any.
+func withPolicyDetails(resp map[string]any, err error) map[string]any {
+	var policyErr *toolpolicy.Error
+	if errors.As(err, &policyErr) {
+		maps.Copy(resp, policyErr.ErrorDetails())
 	}
+	return resp
 }
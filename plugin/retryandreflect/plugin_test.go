@@ -16,10 +16,13 @@ package retryandreflect
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolpolicy"
 )
 
 type mockTool struct {
@@ -237,3 +240,33 @@ func TestRetryAndReflect_Scopes(t *testing.T) {
 		t.Errorf("expected 2 failures in global scope")
 	}
 }
+
+func TestRetryAndReflect_PolicyError(t *testing.T) {
+	r := &retryAndReflect{
+		maxRetries:            2,
+		scope:                 Invocation,
+		scopedFailureCounters: make(map[string]map[string]int),
+	}
+
+	ctx := &mockContext{invocationID: "inv1"}
+	tl := &mockTool{name: "test-tool"}
+	err := fmt.Errorf("call failed: %w", &toolpolicy.Error{
+		Tool:       "test-tool",
+		Reason:     toolpolicy.ReasonCircuitOpen,
+		RetryAfter: 30 * time.Second,
+	})
+
+	res, _ := r.onToolError(ctx, tl, nil, err)
+	if res["policy_violation"] != "circuit_open" || res["retry_after_seconds"] != 30.0 {
+		t.Errorf("expected the policy details in the response, got %v", res)
+	}
+	guidance := res["reflection_guidance"].(string)
+	if !strings.Contains(guidance, "temporarily disabled") || !strings.Contains(guidance, "before 30s") {
+		t.Errorf("expected guidance to explain the policy violation, got %v", guidance)
+	}
+
+	res, _ = r.onToolError(ctx, tl, nil, errors.New("fail"))
+	if _, ok := res["policy_violation"]; ok || strings.Contains(res["reflection_guidance"].(string), "Tool Policy") {
+		t.Errorf("expected no policy details for other errors, got %v", res)
+	}
+}
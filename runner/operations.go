@@ -0,0 +1,241 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/operation"
+	"google.golang.org/adk/session"
+)
+
+// CompleteOperation completes a running operation with result, or with
+// opErr if it is not nil, for operations completed outside of the tool which
+// started them, like a human approval workflow. The agent is resumed with
+// ResumeOperation. The update is appended to the session by its next
+// invocation.
+func (r *Runner) CompleteOperation(ctx context.Context, operationID string, result map[string]any, opErr error) (*operation.Operation, error) {
+	if r.operationStore == nil {
+		return nil, errors.New("operation store is not configured")
+	}
+	if _, err := r.getOperation(ctx, operationID); err != nil {
+		return nil, err
+	}
+	return operation.Complete(ctx, r.operationStore, operationID, result, opErr)
+}
+
+// ResumeOperation resumes the agent which started a completed operation,
+// sending it the result of the operation as the response of the function
+// call of the tool. An operation resumes the agent only once: it is marked
+// as resumed before the function response is appended to the session, and
+// unmarked if the response cannot be appended.
+func (r *Runner) ResumeOperation(ctx context.Context, operationID string, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		if r.operationStore == nil {
+			yield(nil, errors.New("operation store is not configured"))
+			return
+		}
+		op, err := r.getOperation(ctx, operationID)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		resp, err := r.sessionService.Get(ctx, &session.GetRequest{
+			AppName:   op.AppName,
+			UserID:    op.UserID,
+			SessionID: op.SessionID,
+		})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		call := findFunctionCall(resp.Session.Events(), op.FunctionCallID)
+		if call == nil {
+			yield(nil, fmt.Errorf("function call %s of operation %s not found in the session", op.FunctionCallID, op.ID))
+			return
+		}
+
+		if err := checkResumable(op); err != nil {
+			yield(nil, err)
+			return
+		}
+		// The operation is claimed in the store, so that concurrent calls
+		// cannot both append the function response.
+		claim := func(ctx context.Context) (func(context.Context) error, error) {
+			_, err := r.operationStore.Update(ctx, operationID, func(op *operation.Operation) error {
+				if err := checkResumable(op); err != nil {
+					return err
+				}
+				op.Resumed = true
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			release := func(ctx context.Context) error {
+				_, err := r.operationStore.Update(context.WithoutCancel(ctx), operationID, func(op *operation.Operation) error {
+					op.Resumed = false
+					return nil
+				})
+				if err != nil {
+					return fmt.Errorf("failed to release operation %s: %w", operationID, err)
+				}
+				return nil
+			}
+			return release, nil
+		}
+
+		msg := &genai.Content{
+			Role: genai.RoleUser,
+			Parts: []*genai.Part{{
+				FunctionResponse: &genai.FunctionResponse{
+					ID:       op.FunctionCallID,
+					Name:     call.Name,
+					Response: op.Response(),
+				},
+			}},
+		}
+		for event, err := range r.run(ctx, op.UserID, op.SessionID, msg, cfg, claim) {
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
+
+// getOperation returns an operation of the app of the runner.
+func (r *Runner) getOperation(ctx context.Context, operationID string) (*operation.Operation, error) {
+	op, err := r.operationStore.Get(ctx, operationID)
+	if err != nil {
+		return nil, err
+	}
+	if op.AppName != r.appName {
+		return nil, fmt.Errorf("%w: %s", operation.ErrNotFound, operationID)
+	}
+	return op, nil
+}
+
+// checkResumable returns an error if op cannot resume the agent.
+func checkResumable(op *operation.Operation) error {
+	if !op.Status.Done() {
+		return fmt.Errorf("operation %s is still %s", op.ID, op.Status)
+	}
+	if op.Resumed {
+		return fmt.Errorf("operation %s was already resumed", op.ID)
+	}
+	return nil
+}
+
+// unpublishedOperations returns the operations of a session whose last
+// update is not appended to the session.
+func (r *Runner) unpublishedOperations(ctx context.Context, sess session.Session) ([]*operation.Operation, error) {
+	ops, err := r.operationStore.List(ctx, &operation.ListRequest{
+		AppName:   sess.AppName(),
+		UserID:    sess.UserID(),
+		SessionID: sess.ID(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operations of the session: %w", err)
+	}
+	return slices.DeleteFunc(ops, func(op *operation.Operation) bool { return op.Published }), nil
+}
+
+func findFunctionCall(events session.Events, id string) *genai.FunctionCall {
+	for i := events.Len() - 1; i >= 0; i-- {
+		for _, call := range utils.FunctionCalls(events.At(i).Content) {
+			if call.ID == id {
+				return call
+			}
+		}
+	}
+	return nil
+}
+
+// operationEvents delivers the updates of the operations started during an
+// invocation as events of the invocation while it runs, since appending them
+// to the session concurrently would make the session of the invocation
+// stale. The updates made once the invocation ends are kept in the store and
+// delivered by the next invocation of the session.
+type operationEvents struct {
+	mu      sync.Mutex
+	running bool
+	pending []*operation.Operation
+}
+
+func (o *operationEvents) publish(ctx context.Context, op *operation.Operation) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.running {
+		o.pending = append(o.pending, op)
+	}
+	return nil
+}
+
+// take returns the pending updates.
+func (o *operationEvents) take() []*operation.Operation {
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	pending := o.pending
+	o.pending = nil
+	return pending
+}
+
+// stop stops delivering the updates when the invocation ends. The pending
+// updates are left to the next invocation.
+func (o *operationEvents) stop() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.running = false
+	o.pending = nil
+}
+
+// yieldOperationEvents appends the events of operation updates to the session
+// of the invocation, marks the updates as published and yields the events.
+func (r *Runner) yieldOperationEvents(ctx context.Context, storedSession session.Session, ops []*operation.Operation, yield func(*session.Event, error) bool) bool {
+	for _, op := range ops {
+		event := operation.NewEvent(op)
+		if err := r.sessionService.AppendEvent(ctx, storedSession, event); err != nil {
+			yield(nil, fmt.Errorf("failed to add event to session: %w", err))
+			return false
+		}
+		_, err := r.operationStore.Update(ctx, op.ID, func(stored *operation.Operation) error {
+			// A later update is still to be published.
+			if stored.UpdateTime.Equal(op.UpdateTime) {
+				stored.Published = true
+			}
+			return nil
+		})
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to mark update of operation %s as published: %w", op.ID, err))
+			return false
+		}
+		if !yield(event, nil) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,419 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/operation"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type exportArgs struct {
+	Table string `json:"table"`
+}
+
+func newOperationRunner(t *testing.T, responses []*genai.Content, fn operation.Func, plugins ...*plugin.Plugin) (*runner.Runner, *testutil.MockModel, session.Service, operation.Store) {
+	t.Helper()
+	exportTool, err := functiontool.New(functiontool.Config{
+		Name:          "export",
+		Description:   "exports a table",
+		IsLongRunning: true,
+	}, func(ctx tool.Context, args exportArgs) (map[string]any, error) {
+		return operation.Start(ctx, fn)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := &testutil.MockModel{Responses: responses}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: model,
+		Tools: []tool.Tool{exportTool},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	store := operation.InMemoryStore()
+	r, err := runner.New(runner.Config{
+		AppName:        "app",
+		Agent:          a,
+		SessionService: sessionService,
+		OperationStore: store,
+		PluginConfig:   runner.PluginConfig{Plugins: plugins},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, model, sessionService, store
+}
+
+func startOperation(t *testing.T, r *runner.Runner, store operation.Store) *operation.Operation {
+	t.Helper()
+	events, err := testutil.CollectEvents(r.Run(t.Context(), "user", "session", genai.NewContentFromText("export users", genai.RoleUser), agent.RunConfig{}))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	var responses []*genai.FunctionResponse
+	for _, event := range events {
+		if event.Content == nil {
+			continue
+		}
+		for _, part := range event.Content.Parts {
+			if part.FunctionResponse != nil {
+				responses = append(responses, part.FunctionResponse)
+			}
+		}
+	}
+	if len(responses) != 1 {
+		t.Fatalf("got %d function responses, want 1", len(responses))
+	}
+	id, _ := responses[0].Response["operation_id"].(string)
+	op, err := store.Get(t.Context(), id)
+	if err != nil {
+		t.Fatalf("Get(%q) error = %v", id, err)
+	}
+	return op
+}
+
+func waitOperation(t *testing.T, store operation.Store, id string) *operation.Operation {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		op, err := store.Get(t.Context(), id)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", id, err)
+		}
+		if op.Status.Done() {
+			return op
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("operation %s is still %s", id, op.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// resumeText resumes an operation and returns the texts of the events and
+// the statuses of the operation events it yields.
+func resumeText(t *testing.T, r *runner.Runner, id string) (texts []string, statuses []any) {
+	t.Helper()
+	for event, err := range r.ResumeOperation(t.Context(), id, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("ResumeOperation() error = %v", err)
+		}
+		if md, ok := event.CustomMetadata[operation.EventMetadataKey].(map[string]any); ok {
+			statuses = append(statuses, md["status"])
+		}
+		if event.Content == nil {
+			continue
+		}
+		for _, part := range event.Content.Parts {
+			if part.Text != "" {
+				texts = append(texts, part.Text)
+			}
+		}
+	}
+	return texts, statuses
+}
+
+// operationStatuses returns the statuses of the operation events of the
+// session.
+func operationStatuses(t *testing.T, sessionService session.Service) []any {
+	t.Helper()
+	resp, err := sessionService.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statuses []any
+	for event := range resp.Session.Events().All() {
+		if md, ok := event.CustomMetadata[operation.EventMetadataKey].(map[string]any); ok {
+			statuses = append(statuses, md["status"])
+		}
+	}
+	return statuses
+}
+
+func TestRunner_ResumeOperation(t *testing.T) {
+	release := make(chan struct{})
+	r, model, sessionService, store := newOperationRunner(t, []*genai.Content{
+		genai.NewContentFromFunctionCall("export", map[string]any{"table": "users"}, genai.RoleModel),
+		genai.NewContentFromText("export started", genai.RoleModel),
+		genai.NewContentFromText("export done", genai.RoleModel),
+	}, func(ctx context.Context, h *operation.Handle) (map[string]any, error) {
+		<-release
+		if err := h.ReportProgress(ctx, map[string]any{"rows": 10}); err != nil {
+			return nil, err
+		}
+		return map[string]any{"file": "users.csv"}, nil
+	})
+
+	op := startOperation(t, r, store)
+	if op.Status != operation.StatusRunning || op.AgentName != "agent" || op.SessionID != "session" {
+		t.Fatalf("operation = %+v, want running operation of agent in session", op)
+	}
+	for event, err := range r.ResumeOperation(t.Context(), op.ID, agent.RunConfig{}) {
+		if err == nil {
+			t.Fatalf("ResumeOperation() of a running operation yielded %v, want error", event)
+		}
+	}
+
+	close(release)
+	op = waitOperation(t, store, op.ID)
+	if diff := cmp.Diff(map[string]any{"file": "users.csv"}, op.Result); diff != "" {
+		t.Errorf("Result mismatch (-want +got):\n%s", diff)
+	}
+
+	// The updates made after the invocation are kept in the store until the
+	// next invocation of the session.
+	if statuses := operationStatuses(t, sessionService); len(statuses) != 0 {
+		t.Errorf("operation event statuses = %v, want none before the next invocation", statuses)
+	}
+	if op.Published || op.Resumed {
+		t.Errorf("operation = %+v, want unpublished and not resumed", op)
+	}
+
+	texts, statuses := resumeText(t, r, op.ID)
+	if diff := cmp.Diff([]string{"export done"}, texts); diff != "" {
+		t.Errorf("ResumeOperation() mismatch (-want +got):\n%s", diff)
+	}
+	// Only the last update is delivered.
+	if diff := cmp.Diff([]any{"succeeded"}, statuses); diff != "" {
+		t.Errorf("ResumeOperation() operation event statuses mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]any{"succeeded"}, operationStatuses(t, sessionService)); diff != "" {
+		t.Errorf("operation event statuses mismatch (-want +got):\n%s", diff)
+	}
+	op, err := store.Get(t.Context(), op.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !op.Published || !op.Resumed {
+		t.Errorf("operation = %+v, want published and resumed", op)
+	}
+	last := model.Requests[len(model.Requests)-1]
+	got := last.Contents[len(last.Contents)-1].Parts[0].FunctionResponse
+	if got == nil || got.Name != "export" {
+		t.Fatalf("last request content = %+v, want the function response of the operation", last.Contents[len(last.Contents)-1])
+	}
+	if diff := cmp.Diff(map[string]any{"file": "users.csv"}, got.Response); diff != "" {
+		t.Errorf("function response mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, err := range r.ResumeOperation(t.Context(), op.ID, agent.RunConfig{}) {
+		if err == nil {
+			t.Error("second ResumeOperation() succeeded, want error")
+		}
+	}
+}
+
+func TestRunner_CompleteOperation(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	r, _, _, store := newOperationRunner(t, []*genai.Content{
+		genai.NewContentFromFunctionCall("export", map[string]any{"table": "users"}, genai.RoleModel),
+		genai.NewContentFromText("waiting for approval", genai.RoleModel),
+		genai.NewContentFromText("approved", genai.RoleModel),
+	}, func(ctx context.Context, h *operation.Handle) (map[string]any, error) {
+		<-release
+		return nil, nil
+	})
+
+	op := startOperation(t, r, store)
+	op, err := r.CompleteOperation(t.Context(), op.ID, map[string]any{"approved": true}, nil)
+	if err != nil {
+		t.Fatalf("CompleteOperation() error = %v", err)
+	}
+	if op.Status != operation.StatusSucceeded {
+		t.Errorf("CompleteOperation() status = %s, want %s", op.Status, operation.StatusSucceeded)
+	}
+	if _, err := r.CompleteOperation(t.Context(), op.ID, nil, nil); err == nil {
+		t.Error("second CompleteOperation() succeeded, want error")
+	}
+	if _, err := r.CompleteOperation(t.Context(), "missing", nil, nil); err == nil {
+		t.Error("CompleteOperation() of a missing operation succeeded, want error")
+	}
+	texts, statuses := resumeText(t, r, op.ID)
+	if diff := cmp.Diff([]string{"approved"}, texts); diff != "" {
+		t.Errorf("ResumeOperation() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]any{"succeeded"}, statuses); diff != "" {
+		t.Errorf("ResumeOperation() operation event statuses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunner_ResumeOperationFailure(t *testing.T) {
+	r, _, sessionService, store := newOperationRunner(t, []*genai.Content{
+		genai.NewContentFromFunctionCall("export", map[string]any{"table": "users"}, genai.RoleModel),
+		genai.NewContentFromText("export started", genai.RoleModel),
+	}, func(ctx context.Context, h *operation.Handle) (map[string]any, error) {
+		return map[string]any{"file": "users.csv"}, nil
+	})
+	op := startOperation(t, r, store)
+	waitOperation(t, store, op.ID)
+
+	// The resumed run fails before appending the function response, so the
+	// operation can be resumed again.
+	if err := sessionService.Delete(t.Context(), &session.DeleteRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range r.ResumeOperation(t.Context(), op.ID, agent.RunConfig{}) {
+		if err == nil {
+			t.Fatal("ResumeOperation() of a deleted session succeeded, want error")
+		}
+	}
+	op, err := store.Get(t.Context(), op.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Resumed {
+		t.Error("operation is resumed after a failed ResumeOperation()")
+	}
+}
+
+func TestRunner_ResumeOperationAppendFailure(t *testing.T) {
+	var failAppend atomic.Bool
+	p, err := plugin.New(plugin.Config{
+		Name: "failing",
+		OnUserMessageCallback: func(ctx agent.InvocationContext, msg *genai.Content) (*genai.Content, error) {
+			if failAppend.Load() {
+				return nil, errors.New("append failed")
+			}
+			return nil, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _, _, store := newOperationRunner(t, []*genai.Content{
+		genai.NewContentFromFunctionCall("export", map[string]any{"table": "users"}, genai.RoleModel),
+		genai.NewContentFromText("export started", genai.RoleModel),
+		genai.NewContentFromText("export done", genai.RoleModel),
+	}, func(ctx context.Context, h *operation.Handle) (map[string]any, error) {
+		return map[string]any{"file": "users.csv"}, nil
+	}, p)
+	op := startOperation(t, r, store)
+	waitOperation(t, store, op.ID)
+
+	// The function response is not appended, so the claim of the operation
+	// is released and it can be resumed again.
+	failAppend.Store(true)
+	if _, err := testutil.CollectEvents(r.ResumeOperation(t.Context(), op.ID, agent.RunConfig{})); err == nil {
+		t.Fatal("ResumeOperation() with a failing append succeeded, want error")
+	}
+	op, err = store.Get(t.Context(), op.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Resumed {
+		t.Error("operation is resumed after a failed append")
+	}
+
+	failAppend.Store(false)
+	texts, _ := resumeText(t, r, op.ID)
+	if diff := cmp.Diff([]string{"export done"}, texts); diff != "" {
+		t.Errorf("ResumeOperation() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunner_ResumeOperationConcurrently(t *testing.T) {
+	const resumes = 5
+	// The appends of the function response wait for each other for a
+	// while, so that the resumes would all append it without the claim of
+	// the operation.
+	var arrived atomic.Int32
+	allArrived := make(chan struct{})
+	p, err := plugin.New(plugin.Config{
+		Name: "waiting",
+		OnUserMessageCallback: func(ctx agent.InvocationContext, msg *genai.Content) (*genai.Content, error) {
+			if msg.Parts[0].FunctionResponse == nil {
+				return nil, nil
+			}
+			if arrived.Add(1) == resumes {
+				close(allArrived)
+			}
+			select {
+			case <-allArrived:
+			case <-time.After(100 * time.Millisecond):
+			}
+			return nil, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _, sessionService, store := newOperationRunner(t, []*genai.Content{
+		genai.NewContentFromFunctionCall("export", map[string]any{"table": "users"}, genai.RoleModel),
+		genai.NewContentFromText("export started", genai.RoleModel),
+		genai.NewContentFromText("export done", genai.RoleModel),
+		genai.NewContentFromText("export done again", genai.RoleModel),
+	}, func(ctx context.Context, h *operation.Handle) (map[string]any, error) {
+		return map[string]any{"file": "users.csv"}, nil
+	}, p)
+	op := startOperation(t, r, store)
+	waitOperation(t, store, op.ID)
+
+	var succeeded atomic.Int32
+	var wg sync.WaitGroup
+	for range resumes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, err := range r.ResumeOperation(t.Context(), op.ID, agent.RunConfig{}) {
+				if err != nil {
+					return
+				}
+			}
+			succeeded.Add(1)
+		}()
+	}
+	wg.Wait()
+	if got := succeeded.Load(); got != 1 {
+		t.Errorf("%d of %d concurrent ResumeOperation() calls succeeded, want 1", got, resumes)
+	}
+
+	resp, err := sessionService.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var responses int
+	for event := range resp.Session.Events().All() {
+		if event.Author == "user" && event.Content != nil && event.Content.Parts[0].FunctionResponse != nil {
+			responses++
+		}
+	}
+	if responses != 1 {
+		t.Errorf("session has %d function responses of the user, want 1", responses)
+	}
+}
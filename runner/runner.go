@@ -17,6 +17,7 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"log"
@@ -36,6 +37,7 @@ import (
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/operation"
 	"google.golang.org/adk/plugin"
 	"google.golang.org/adk/session"
 )
@@ -53,6 +55,10 @@ type Config struct {
 	MemoryService memory.Service
 	// optional
 	PluginConfig PluginConfig
+	// OperationStore persists the long-running operations started by the
+	// tools with operation.Start. If nil, the tools cannot start
+	// operations.
+	OperationStore operation.Store
 }
 
 type PluginConfig struct {
@@ -89,6 +95,7 @@ func New(cfg Config) (*Runner, error) {
 		sessionService:  cfg.SessionService,
 		artifactService: cfg.ArtifactService,
 		memoryService:   cfg.MemoryService,
+		operationStore:  cfg.OperationStore,
 		parents:         parents,
 		pluginManager:   pluginManager,
 	}, nil
@@ -103,6 +110,7 @@ type Runner struct {
 	sessionService  session.Service
 	artifactService artifact.Service
 	memoryService   memory.Service
+	operationStore  operation.Store
 
 	parents       parentmap.Map
 	pluginManager *plugininternal.PluginManager
@@ -112,6 +120,13 @@ type Runner struct {
 // For each user message it finds the proper agent within an agent tree to
 // continue the conversation within the session.
 func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.Content, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return r.run(ctx, userID, sessionID, msg, cfg, nil)
+}
+
+// run implements Run, calling claim, if not nil, right before msg is
+// appended to the session, and the release function it returns if msg cannot
+// be appended.
+func (r *Runner) run(ctx context.Context, userID, sessionID string, msg *genai.Content, cfg agent.RunConfig, claim func(context.Context) (release func(context.Context) error, err error)) iter.Seq2[*session.Event, error] {
 	// TODO(hakim): we need to validate whether cfg is compatible with the Agent.
 	//   see adk-python/src/google/adk/runners.py Runner._new_invocation_context.
 	// TODO: setup tracer.
@@ -139,6 +154,22 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 			StreamingMode: runconfig.StreamingMode(cfg.StreamingMode),
 		})
 		ctx = plugininternal.ToContext(ctx, r.pluginManager)
+		var opEvents *operationEvents
+		if r.operationStore != nil {
+			// The updates of operations made while no invocation of the
+			// session was running are delivered first.
+			ops, err := r.unpublishedOperations(ctx, storedSession)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !r.yieldOperationEvents(ctx, storedSession, ops, yield) {
+				return
+			}
+			opEvents = &operationEvents{running: true}
+			ctx = operation.NewContext(ctx, r.operationStore, opEvents.publish)
+			defer opEvents.stop()
+		}
 
 		var artifacts agent.Artifacts
 		if r.artifactService != nil {
@@ -168,8 +199,18 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 			UserContent: msg,
 			RunConfig:   &cfg,
 		})
+		var release func(context.Context) error
+		if claim != nil {
+			if release, err = claim(ctx); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
 		ctx, err = r.appendMessageToSession(ctx, storedSession, msg, cfg.SaveInputBlobsAsArtifacts, r.pluginManager)
 		if err != nil {
+			if release != nil {
+				err = errors.Join(err, release(ctx))
+			}
 			yield(nil, err)
 			return
 		}
@@ -228,7 +269,11 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 			if !yield(event, nil) {
 				return
 			}
+			if !r.yieldOperationEvents(ctx, storedSession, opEvents.take(), yield) {
+				return
+			}
 		}
+		r.yieldOperationEvents(ctx, storedSession, opEvents.take(), yield)
 	}
 }
 
@@ -0,0 +1,178 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/llminternal"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool/agenttool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+func (s *server) newAgentTool(a agent.Agent) (*mcp.Tool, error) {
+	// The agent takes the same arguments as when used as an agent tool.
+	t, ok := agenttool.New(a, nil).(toolinternal.FunctionTool)
+	if !ok {
+		return nil, fmt.Errorf("agent %q cannot be used as a tool", a.Name())
+	}
+	return s.newTool(t)
+}
+
+// agentHandler runs the agent for MCP tool calls in the ADK session of the
+// MCP session. Tool confirmations requested by the agent are asked from the
+// client and passed back to the agent until it completes.
+func (s *server) agentHandler(a agent.Agent) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := arguments(req)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		msg, err := agentInput(a, args)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		userID, err := s.userID(req)
+		if err != nil {
+			return nil, err
+		}
+		sessionID, err := s.sessions.sessionID(ctx, req.Session, sessionKey{userID: userID, agentName: a.Name()})
+		if err != nil {
+			return nil, err
+		}
+		r, err := s.newRunner(a)
+		if err != nil {
+			return nil, err
+		}
+
+		var output string
+		for msg != nil {
+			var confirmations []*genai.FunctionCall
+			for event, err := range r.Run(ctx, userID, sessionID, msg, agent.RunConfig{}) {
+				if err != nil {
+					return errorResult(fmt.Errorf("error during execution of agent %s: %w", a.Name(), err)), nil
+				}
+				if event.ErrorCode != "" || event.ErrorMessage != "" {
+					return errorResult(fmt.Errorf("error from agent %q (code: %q, message: %q)", a.Name(), event.ErrorCode, event.ErrorMessage)), nil
+				}
+				if event.Partial {
+					continue
+				}
+				for _, call := range utils.FunctionCalls(event.Content) {
+					if call.Name == toolconfirmation.FunctionCallName {
+						confirmations = append(confirmations, call)
+					}
+				}
+				if text := eventText(event); text != "" {
+					output = text
+				}
+			}
+			msg, err = s.confirm(ctx, req.Session, confirmations)
+			if err != nil {
+				return errorResult(err), nil
+			}
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: output}}}, nil
+	}
+}
+
+// confirm asks the client for the requested tool confirmations and returns
+// the message passing them to the agent, or nil if none were requested.
+func (s *server) confirm(ctx context.Context, ss *mcp.ServerSession, calls []*genai.FunctionCall) (*genai.Content, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+	msg := &genai.Content{Role: genai.RoleUser}
+	for _, call := range calls {
+		toolName := call.Name
+		if original, err := toolconfirmation.OriginalCallFrom(call); err == nil {
+			toolName = original.Name
+		}
+		var requested toolconfirmation.ToolConfirmation
+		if raw, ok := call.Args["toolConfirmation"]; ok {
+			data, err := json.Marshal(raw)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal confirmation request of tool %q: %w", toolName, err)
+			}
+			if err := json.Unmarshal(data, &requested); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal confirmation request of tool %q: %w", toolName, err)
+			}
+		}
+		confirmed, err := elicitConfirmation(ctx, ss, toolName, requested.Hint)
+		if err != nil {
+			return nil, err
+		}
+		response := map[string]any{"confirmed": confirmed}
+		if requested.Payload != nil {
+			response["payload"] = requested.Payload
+		}
+		msg.Parts = append(msg.Parts, &genai.Part{FunctionResponse: &genai.FunctionResponse{
+			ID:       call.ID,
+			Name:     toolconfirmation.FunctionCallName,
+			Response: response,
+		}})
+	}
+	return msg, nil
+}
+
+// agentInput returns the message for the agent from the tool call
+// arguments, like the agent tool does.
+func agentInput(a agent.Agent, args map[string]any) (*genai.Content, error) {
+	if llmAgent, ok := a.(llminternal.Agent); ok {
+		if schema := llminternal.Reveal(llmAgent).InputSchema; schema != nil {
+			if err := utils.ValidateMapOnSchema(args, schema, true); err != nil {
+				return nil, fmt.Errorf("argument validation failed for agent %s: %w", a.Name(), err)
+			}
+			data, err := json.Marshal(args)
+			if err != nil {
+				return nil, fmt.Errorf("error serializing tool arguments for agent %s: %w", a.Name(), err)
+			}
+			return genai.NewContentFromText(string(data), genai.RoleUser), nil
+		}
+	}
+	input, ok := args["request"]
+	if !ok {
+		return nil, fmt.Errorf("missing required argument 'request' for agent %s", a.Name())
+	}
+	text, ok := input.(string)
+	if !ok {
+		text = fmt.Sprint(input)
+	}
+	return genai.NewContentFromText(text, genai.RoleUser), nil
+}
+
+// eventText returns the text of an agent's response event.
+func eventText(event *session.Event) string {
+	if event.Author == "user" || event.Content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range event.Content.Parts {
+		if part.Text != "" && !part.Thought {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
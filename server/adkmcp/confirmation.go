@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkmcp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// confirmedField is the field of the elicited confirmation.
+const confirmedField = "confirmed"
+
+// elicitConfirmation asks the MCP client to confirm a call of the named
+// tool. It reports whether the user accepted the elicitation and confirmed
+// the call.
+func elicitConfirmation(ctx context.Context, ss *mcp.ServerSession, toolName, hint string) (bool, error) {
+	if ss == nil {
+		return false, fmt.Errorf("tool %q requires confirmation, which is not supported without an MCP session", toolName)
+	}
+	res, err := ss.Elicit(ctx, &mcp.ElicitParams{
+		Message: cmp.Or(hint, fmt.Sprintf("Do you want to run tool %q?", toolName)),
+		RequestedSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				confirmedField: {
+					Type:        "boolean",
+					Description: fmt.Sprintf("Whether to run tool %q.", toolName),
+				},
+			},
+			Required: []string{confirmedField},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to request confirmation of tool %q: %w", toolName, err)
+	}
+	if res.Action != "accept" {
+		return false, nil
+	}
+	confirmed, _ := res.Content[confirmedField].(bool)
+	return confirmed, nil
+}
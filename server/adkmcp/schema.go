@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkmcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/genai"
+)
+
+// inputSchema returns the MCP input schema of a function declaration. MCP
+// requires tool inputs to be objects, so declarations without parameters
+// accept an empty object.
+func inputSchema(decl *genai.FunctionDeclaration) (*jsonschema.Schema, error) {
+	var schema *jsonschema.Schema
+	switch {
+	case decl.ParametersJsonSchema != nil:
+		if s, ok := decl.ParametersJsonSchema.(*jsonschema.Schema); ok {
+			schema = s
+			break
+		}
+		data, err := json.Marshal(decl.ParametersJsonSchema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal parameters schema of %q: %w", decl.Name, err)
+		}
+		schema = &jsonschema.Schema{}
+		if err := json.Unmarshal(data, schema); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal parameters schema of %q: %w", decl.Name, err)
+		}
+	case decl.Parameters != nil:
+		schema = fromGenaiSchema(decl.Parameters)
+	default:
+		schema = &jsonschema.Schema{}
+	}
+	if schema.Type == "" && len(schema.Types) == 0 {
+		schema.Type = "object"
+	}
+	return schema, nil
+}
+
+// fromGenaiSchema converts a genai schema, which uses the OpenAPI subset of
+// JSON schema, to a JSON schema.
+func fromGenaiSchema(s *genai.Schema) *jsonschema.Schema {
+	if s == nil {
+		return nil
+	}
+	out := &jsonschema.Schema{
+		Title:       s.Title,
+		Description: s.Description,
+		Format:      s.Format,
+		Pattern:     s.Pattern,
+		Minimum:     s.Minimum,
+		Maximum:     s.Maximum,
+		MinItems:    intPtr(s.MinItems),
+		MaxItems:    intPtr(s.MaxItems),
+		MinLength:   intPtr(s.MinLength),
+		MaxLength:   intPtr(s.MaxLength),
+		Required:    s.Required,
+		Items:       fromGenaiSchema(s.Items),
+	}
+	if s.Type != "" {
+		t := strings.ToLower(string(s.Type))
+		if s.Nullable != nil && *s.Nullable {
+			out.Types = []string{t, "null"}
+		} else {
+			out.Type = t
+		}
+	}
+	for _, e := range s.Enum {
+		out.Enum = append(out.Enum, e)
+	}
+	if len(s.Properties) > 0 {
+		out.Properties = make(map[string]*jsonschema.Schema, len(s.Properties))
+		for name, p := range s.Properties {
+			out.Properties[name] = fromGenaiSchema(p)
+		}
+	}
+	for _, a := range s.AnyOf {
+		out.AnyOf = append(out.AnyOf, fromGenaiSchema(a))
+	}
+	if s.Default != nil {
+		if data, err := json.Marshal(s.Default); err == nil {
+			out.Default = data
+		}
+	}
+	return out
+}
+
+func intPtr(v *int64) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package adkmcp allows to expose ADK agents and tools via MCP.
+//
+// Function tools are published as MCP tools with their JSON schemas, and
+// agents as MCP tools taking the same arguments as the agent tool
+// (see [google.golang.org/adk/tool/agenttool]). The server can be run over
+// any MCP transport, for example over stdio:
+//
+//	server, err := adkmcp.NewServer(adkmcp.Config{AppName: "app", Agents: []agent.Agent{a}})
+//	...
+//	err = server.Run(ctx, &mcp.StdioTransport{})
+//
+// or over streamable HTTP with [NewHandler].
+package adkmcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/version"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+// DefaultUserID is the ID of the user calling the tools if
+// [Config.UserIDProvider] is not set.
+const DefaultUserID = "mcp_user"
+
+// Config provides the configuration of an MCP server exposing ADK agents
+// and tools.
+type Config struct {
+	// AppName is the name of the application the sessions are created for.
+	// It is also the name of the MCP server implementation.
+	AppName string
+	// Agents are exposed as MCP tools named after the agents.
+	Agents []agent.Agent
+	// Tools are exposed as MCP tools. Only function tools, which declare
+	// their parameters, are supported.
+	Tools []tool.Tool
+
+	// SessionService stores the sessions of the MCP clients. Each MCP
+	// session maps to one ADK session per exposed agent and one shared by
+	// the exposed tools, deleted when the MCP session closes. If nil, an
+	// in-memory service is used.
+	SessionService  session.Service
+	ArtifactService artifact.Service
+	MemoryService   memory.Service
+	PluginConfig    runner.PluginConfig
+
+	// UserIDProvider returns the ID of the user calling a tool, for
+	// example from the request's token info. If nil, DefaultUserID is used.
+	UserIDProvider func(*mcp.CallToolRequest) (string, error)
+}
+
+// NewServer returns an MCP server exposing the configured agents and tools.
+//
+// When an agent or tool requests a confirmation, the server asks the MCP
+// client for it through elicitation. Calls requiring a confirmation fail if
+// the client does not support elicitation.
+func NewServer(cfg Config) (*mcp.Server, error) {
+	if cfg.AppName == "" {
+		return nil, errors.New("app name is required")
+	}
+	if len(cfg.Agents) == 0 && len(cfg.Tools) == 0 {
+		return nil, errors.New("at least one agent or tool is required")
+	}
+	if cfg.SessionService == nil {
+		cfg.SessionService = session.InMemoryService()
+	}
+
+	s := &server{
+		cfg:      cfg,
+		sessions: newSessionMap(cfg.SessionService, cfg.AppName),
+	}
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: cfg.AppName, Version: version.Version}, nil)
+	names := map[string]bool{}
+	for _, t := range cfg.Tools {
+		ft, ok := t.(toolinternal.FunctionTool)
+		if !ok {
+			return nil, fmt.Errorf("tool %q is not a function tool", t.Name())
+		}
+		mcpTool, err := s.newTool(ft)
+		if err != nil {
+			return nil, err
+		}
+		if names[mcpTool.Name] {
+			return nil, fmt.Errorf("duplicate tool name %q", mcpTool.Name)
+		}
+		names[mcpTool.Name] = true
+		mcpServer.AddTool(mcpTool, s.toolHandler(ft))
+	}
+	for _, a := range cfg.Agents {
+		mcpTool, err := s.newAgentTool(a)
+		if err != nil {
+			return nil, err
+		}
+		if names[mcpTool.Name] {
+			return nil, fmt.Errorf("duplicate tool name %q", mcpTool.Name)
+		}
+		names[mcpTool.Name] = true
+		mcpServer.AddTool(mcpTool, s.agentHandler(a))
+	}
+	return mcpServer, nil
+}
+
+// NewHandler returns an HTTP handler serving the MCP server for the
+// configured agents and tools over the streamable HTTP transport.
+func NewHandler(cfg Config) (http.Handler, error) {
+	mcpServer, err := NewServer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return mcpServer }, nil), nil
+}
+
+type server struct {
+	cfg      Config
+	sessions *sessionMap
+}
+
+// newRunner returns a runner for the agent with the configured services.
+func (s *server) newRunner(a agent.Agent) (*runner.Runner, error) {
+	return runner.New(runner.Config{
+		AppName:         s.cfg.AppName,
+		Agent:           a,
+		SessionService:  s.cfg.SessionService,
+		ArtifactService: s.cfg.ArtifactService,
+		MemoryService:   s.cfg.MemoryService,
+		PluginConfig:    s.cfg.PluginConfig,
+	})
+}
+
+// userID returns the ID of the user calling a tool.
+func (s *server) userID(req *mcp.CallToolRequest) (string, error) {
+	if s.cfg.UserIDProvider == nil {
+		return DefaultUserID, nil
+	}
+	return s.cfg.UserIDProvider(req)
+}
+
+// arguments decodes the arguments of a tool call.
+func arguments(req *mcp.CallToolRequest) (map[string]any, error) {
+	args := map[string]any{}
+	if len(req.Params.Arguments) == 0 {
+		return args, nil
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+		return nil, fmt.Errorf("failed to decode arguments of tool %q: %w", req.Params.Name, err)
+	}
+	return args, nil
+}
+
+// errorResult reports a tool error to the client as a tool result, so that
+// the model calling the tool can see it.
+func errorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkmcp_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/server/adkmcp"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type counterArgs struct {
+	Increment int `json:"increment"`
+}
+
+type counterResult struct {
+	Count int `json:"count"`
+}
+
+func newCounterTool(t *testing.T) tool.Tool {
+	t.Helper()
+	counter, err := functiontool.New(functiontool.Config{Name: "count", Description: "Increments the counter."}, func(ctx tool.Context, args counterArgs) (counterResult, error) {
+		count, _ := ctx.State().Get("count")
+		n, _ := count.(int)
+		n += args.Increment
+		if err := ctx.State().Set("count", n); err != nil {
+			return counterResult{}, err
+		}
+		return counterResult{Count: n}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return counter
+}
+
+type transferArgs struct {
+	Amount int `json:"amount"`
+}
+
+func newTransferTool(t *testing.T) tool.Tool {
+	t.Helper()
+	transfer, err := functiontool.New(functiontool.Config{Name: "transfer", Description: "Transfers money.", RequireConfirmation: true}, func(ctx tool.Context, args transferArgs) (map[string]any, error) {
+		return map[string]any{"transferred": args.Amount}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transfer
+}
+
+// connect connects an MCP client answering elicitations with confirmed.
+func connect(t *testing.T, server *mcp.Server, confirmed bool) *mcp.ClientSession {
+	t.Helper()
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v1.0.0"}, &mcp.ClientOptions{
+		ElicitationHandler: func(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			return &mcp.ElicitResult{Action: "accept", Content: map[string]any{"confirmed": confirmed}}, nil
+		},
+	})
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+func resultText(t *testing.T, res *mcp.CallToolResult) string {
+	t.Helper()
+	if len(res.Content) != 1 {
+		t.Fatalf("CallTool() returned %d contents, want 1", len(res.Content))
+	}
+	text, ok := res.Content[0].(*mcp.TextContent)
+	if !ok {
+		t.Fatalf("CallTool() content = %T, want text", res.Content[0])
+	}
+	return text.Text
+}
+
+func TestServer_Tools(t *testing.T) {
+	sessionService := session.InMemoryService()
+	server, err := adkmcp.NewServer(adkmcp.Config{
+		AppName:        "app",
+		Tools:          []tool.Tool{newCounterTool(t), newTransferTool(t)},
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	cs := connect(t, server, true)
+
+	tools, err := cs.ListTools(t.Context(), nil)
+	if err != nil {
+		t.Fatalf("ListTools() failed: %v", err)
+	}
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	if diff := cmp.Diff([]string{"count", "transfer"}, names); diff != "" {
+		t.Errorf("ListTools() names mismatch (-want +got):\n%s", diff)
+	}
+
+	for i, want := range []float64{2, 5} {
+		res, err := cs.CallTool(t.Context(), &mcp.CallToolParams{Name: "count", Arguments: map[string]any{"increment": 2 + i}})
+		if err != nil {
+			t.Fatalf("CallTool() failed: %v", err)
+		}
+		if res.IsError {
+			t.Fatalf("CallTool() returned error: %s", resultText(t, res))
+		}
+		if diff := cmp.Diff(map[string]any{"count": want}, res.StructuredContent); diff != "" {
+			t.Errorf("CallTool() structured content mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	// The calls of the MCP session share an ADK session storing the state.
+	list, err := sessionService.List(t.Context(), &session.ListRequest{AppName: "app", UserID: adkmcp.DefaultUserID})
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(list.Sessions) != 1 {
+		t.Fatalf("List() returned %d sessions, want 1", len(list.Sessions))
+	}
+	got, err := sessionService.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: adkmcp.DefaultUserID, SessionID: list.Sessions[0].ID()})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if count, err := got.Session.State().Get("count"); err != nil || count != 5 {
+		t.Errorf("session state count = %v, %v, want 5", count, err)
+	}
+
+	res, err := cs.CallTool(t.Context(), &mcp.CallToolParams{Name: "transfer", Arguments: map[string]any{"amount": 10}})
+	if err != nil {
+		t.Fatalf("CallTool() failed: %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("CallTool() of confirmed tool returned error: %s", resultText(t, res))
+	}
+	if diff := cmp.Diff(map[string]any{"transferred": float64(10)}, res.StructuredContent); diff != "" {
+		t.Errorf("CallTool() structured content mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServer_SessionsDeletedOnClose(t *testing.T) {
+	sessionService := session.InMemoryService()
+	server, err := adkmcp.NewServer(adkmcp.Config{
+		AppName:        "app",
+		Tools:          []tool.Tool{newCounterTool(t)},
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	cs := connect(t, server, true)
+	if _, err := cs.CallTool(t.Context(), &mcp.CallToolParams{Name: "count", Arguments: map[string]any{"increment": 1}}); err != nil {
+		t.Fatalf("CallTool() failed: %v", err)
+	}
+	sessions := func() int {
+		list, err := sessionService.List(t.Context(), &session.ListRequest{AppName: "app", UserID: adkmcp.DefaultUserID})
+		if err != nil {
+			t.Fatalf("List() failed: %v", err)
+		}
+		return len(list.Sessions)
+	}
+	if got := sessions(); got != 1 {
+		t.Fatalf("List() returned %d sessions, want 1", got)
+	}
+
+	if err := cs.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	// The sessions are deleted asynchronously once the server notices the
+	// closed connection.
+	for deadline := time.Now().Add(5 * time.Second); sessions() != 0; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the ADK session is not deleted after the MCP session closed")
+		}
+	}
+}
+
+func TestServer_ToolConfirmationRejected(t *testing.T) {
+	server, err := adkmcp.NewServer(adkmcp.Config{AppName: "app", Tools: []tool.Tool{newTransferTool(t)}})
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	cs := connect(t, server, false)
+
+	res, err := cs.CallTool(t.Context(), &mcp.CallToolParams{Name: "transfer", Arguments: map[string]any{"amount": 10}})
+	if err != nil {
+		t.Fatalf("CallTool() failed: %v", err)
+	}
+	if !res.IsError {
+		t.Errorf("CallTool() of rejected tool = %v, want error", res.StructuredContent)
+	}
+}
+
+func TestServer_Agents(t *testing.T) {
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		{Role: genai.RoleModel, Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{Name: "transfer", Args: map[string]any{"amount": 10}}}}},
+		genai.NewContentFromText("Transferred 10.", genai.RoleModel),
+	}}
+	banker, err := llmagent.New(llmagent.Config{
+		Name:        "banker",
+		Description: "Handles money transfers.",
+		Model:       model,
+		Tools:       []tool.Tool{newTransferTool(t)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := adkmcp.NewServer(adkmcp.Config{AppName: "app", Agents: []agent.Agent{banker}})
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	cs := connect(t, server, true)
+
+	tools, err := cs.ListTools(t.Context(), nil)
+	if err != nil {
+		t.Fatalf("ListTools() failed: %v", err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "banker" || tools.Tools[0].Description != "Handles money transfers." {
+		t.Fatalf("ListTools() = %v, want the banker agent", tools.Tools)
+	}
+
+	res, err := cs.CallTool(t.Context(), &mcp.CallToolParams{Name: "banker", Arguments: map[string]any{"request": "Send 10."}})
+	if err != nil {
+		t.Fatalf("CallTool() failed: %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("CallTool() returned error: %s", resultText(t, res))
+	}
+	if got, want := resultText(t, res), "Transferred 10."; got != want {
+		t.Errorf("CallTool() = %q, want %q", got, want)
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	handler, err := adkmcp.NewHandler(adkmcp.Config{AppName: "app", Tools: []tool.Tool{newCounterTool(t)}})
+	if err != nil {
+		t.Fatalf("NewHandler() failed: %v", err)
+	}
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v1.0.0"}, nil)
+	cs, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{Endpoint: httpServer.URL}, nil)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer cs.Close()
+
+	res, err := cs.CallTool(t.Context(), &mcp.CallToolParams{Name: "count", Arguments: map[string]any{"increment": 1}})
+	if err != nil {
+		t.Fatalf("CallTool() failed: %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"count": float64(1)}, res.StructuredContent); diff != "" {
+		t.Errorf("CallTool() structured content mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewServer_Errors(t *testing.T) {
+	if _, err := adkmcp.NewServer(adkmcp.Config{Tools: []tool.Tool{newCounterTool(t)}}); err == nil {
+		t.Error("NewServer() without app name succeeded, want error")
+	}
+	if _, err := adkmcp.NewServer(adkmcp.Config{AppName: "app"}); err == nil {
+		t.Error("NewServer() without agents and tools succeeded, want error")
+	}
+	if _, err := adkmcp.NewServer(adkmcp.Config{AppName: "app", Tools: []tool.Tool{newCounterTool(t), newCounterTool(t)}}); err == nil {
+		t.Error("NewServer() with duplicate tools succeeded, want error")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/session"
+)
+
+// sessionKey identifies an ADK session of an MCP session.
+type sessionKey struct {
+	userID string
+	// agentName is the name of the exposed agent, or empty for the session
+	// shared by the exposed tools.
+	agentName string
+}
+
+// sessionMap maps MCP sessions onto ADK sessions, which are created on the
+// first tool call of an MCP session and deleted when it closes.
+type sessionMap struct {
+	service session.Service
+	appName string
+
+	mu sync.Mutex
+	// groups are the ADK sessions of the open MCP sessions, by MCP
+	// session ID.
+	groups map[string]*sessionGroup
+}
+
+// sessionGroup is the set of ADK sessions of an MCP session.
+type sessionGroup struct {
+	entries map[sessionKey]*sessionEntry
+	closed  bool
+}
+
+// sessionEntry is an ADK session, created by the first call needing it while
+// the other calls wait for done.
+type sessionEntry struct {
+	done chan struct{}
+	id   string
+	err  error
+}
+
+func newSessionMap(service session.Service, appName string) *sessionMap {
+	return &sessionMap{
+		service: service,
+		appName: appName,
+		groups:  map[string]*sessionGroup{},
+	}
+}
+
+// sessionID returns the ID of the ADK session for key in the MCP session ss,
+// creating it if needed. ss is nil for transports without sessions.
+func (m *sessionMap) sessionID(ctx context.Context, ss *mcp.ServerSession, key sessionKey) (string, error) {
+	var mcpSessionID string
+	if ss != nil {
+		mcpSessionID = ss.ID()
+	}
+
+	m.mu.Lock()
+	g, ok := m.groups[mcpSessionID]
+	if !ok {
+		g = &sessionGroup{entries: map[sessionKey]*sessionEntry{}}
+		m.groups[mcpSessionID] = g
+		if ss != nil {
+			go m.deleteOnClose(ss, mcpSessionID, g)
+		}
+	}
+	e, ok := g.entries[key]
+	if !ok {
+		e = &sessionEntry{done: make(chan struct{})}
+		g.entries[key] = e
+	}
+	m.mu.Unlock()
+
+	if ok {
+		select {
+		case <-e.done:
+			return e.id, e.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	// The session is created without holding the lock, so that the calls
+	// of the other sessions do not wait for it.
+	resp, err := m.service.Create(ctx, &session.CreateRequest{
+		AppName: m.appName,
+		UserID:  key.userID,
+	})
+	m.mu.Lock()
+	closed := g.closed
+	if err != nil || closed {
+		// A later call retries the creation.
+		delete(g.entries, key)
+	}
+	m.mu.Unlock()
+	switch {
+	case err != nil:
+		e.err = fmt.Errorf("failed to create session: %w", err)
+	case closed:
+		m.delete(context.WithoutCancel(ctx), key, resp.Session.ID())
+		e.err = errors.New("the MCP session is closed")
+	default:
+		e.id = resp.Session.ID()
+	}
+	close(e.done)
+	return e.id, e.err
+}
+
+// deleteOnClose deletes the ADK sessions of g once the MCP session ss
+// closes.
+func (m *sessionMap) deleteOnClose(ss *mcp.ServerSession, mcpSessionID string, g *sessionGroup) {
+	_ = ss.Wait()
+
+	m.mu.Lock()
+	g.closed = true
+	if m.groups[mcpSessionID] == g {
+		delete(m.groups, mcpSessionID)
+	}
+	entries := maps.Clone(g.entries)
+	m.mu.Unlock()
+
+	ctx := context.Background()
+	for key, e := range entries {
+		<-e.done
+		if e.err == nil {
+			m.delete(ctx, key, e.id)
+		}
+	}
+}
+
+// delete deletes an ADK session. Errors are ignored, the session is then left
+// to the retention of the session service.
+func (m *sessionMap) delete(ctx context.Context, key sessionKey, id string) {
+	_ = m.service.Delete(ctx, &session.DeleteRequest{
+		AppName:   m.appName,
+		UserID:    key.userID,
+		SessionID: id,
+	})
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkmcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func (s *server) newTool(t toolinternal.FunctionTool) (*mcp.Tool, error) {
+	decl := t.Declaration()
+	if decl == nil {
+		return nil, fmt.Errorf("tool %q has no declaration", t.Name())
+	}
+	schema, err := inputSchema(decl)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.Tool{
+		Name:        t.Name(),
+		Description: cmp.Or(decl.Description, t.Description()),
+		InputSchema: schema,
+	}, nil
+}
+
+// toolHandler runs the tool for MCP tool calls. The tool runs in an
+// invocation of an agent named after it, so that the call and its state
+// and artifact changes are recorded in the ADK session like in an agent
+// run.
+func (s *server) toolHandler(t toolinternal.FunctionTool) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := arguments(req)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		userID, err := s.userID(req)
+		if err != nil {
+			return nil, err
+		}
+		sessionID, err := s.sessions.sessionID(ctx, req.Session, sessionKey{userID: userID})
+		if err != nil {
+			return nil, err
+		}
+
+		call := &genai.FunctionCall{ID: utils.GenerateFunctionCallID(), Name: t.Name(), Args: args}
+		var result map[string]any
+		var runErr error
+		a, err := agent.New(agent.Config{
+			Name: t.Name(),
+			Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+				return func(yield func(*session.Event, error) bool) {
+					callEvent := session.NewEvent(ctx.InvocationID())
+					callEvent.Author = t.Name()
+					callEvent.LLMResponse = model.LLMResponse{
+						Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{FunctionCall: call}}},
+					}
+					if !yield(callEvent, nil) {
+						return
+					}
+
+					var actions *session.EventActions
+					actions, result, runErr = s.runTool(ctx, req.Session, t, call)
+					response := result
+					if runErr != nil {
+						response = map[string]any{"error": runErr.Error()}
+					}
+					responseEvent := session.NewEvent(ctx.InvocationID())
+					responseEvent.Author = t.Name()
+					responseEvent.Actions = *actions
+					responseEvent.LLMResponse = model.LLMResponse{
+						Content: &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+							ID:       call.ID,
+							Name:     call.Name,
+							Response: response,
+						}}}},
+					}
+					yield(responseEvent, nil)
+				}
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		r, err := s.newRunner(a)
+		if err != nil {
+			return nil, err
+		}
+		for _, err := range r.Run(ctx, userID, sessionID, nil, agent.RunConfig{}) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to run tool %q: %w", t.Name(), err)
+			}
+		}
+		if runErr != nil {
+			return errorResult(runErr), nil
+		}
+		return structuredResult(result)
+	}
+}
+
+// runTool runs the tool and, if the tool requests a confirmation, asks the
+// client for it and runs the tool again with the answer.
+func (s *server) runTool(ctx agent.InvocationContext, ss *mcp.ServerSession, t toolinternal.FunctionTool, call *genai.FunctionCall) (*session.EventActions, map[string]any, error) {
+	actions := &session.EventActions{StateDelta: map[string]any{}}
+	result, err := t.Run(toolinternal.NewToolContext(ctx, call.ID, actions, nil), call.Args)
+	requested, ok := actions.RequestedToolConfirmations[call.ID]
+	if !ok {
+		return actions, result, err
+	}
+
+	actions = &session.EventActions{StateDelta: map[string]any{}}
+	confirmed, err := elicitConfirmation(ctx, ss, t.Name(), requested.Hint)
+	if err != nil {
+		return actions, nil, err
+	}
+	confirmation := requested
+	confirmation.Confirmed = confirmed
+	result, err = t.Run(toolinternal.NewToolContext(ctx, call.ID, actions, &confirmation), call.Args)
+	return actions, result, err
+}
+
+// structuredResult returns the result of a function tool as structured
+// content, also serialized as text for clients not supporting it.
+func structuredResult(result map[string]any) (*mcp.CallToolResult, error) {
+	if result == nil {
+		result = map[string]any{}
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return errorResult(fmt.Errorf("failed to marshal tool result: %w", err)), nil
+	}
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: string(data)}},
+		StructuredContent: result,
+	}, nil
+}
@@ -15,8 +15,15 @@
 package controllers
 
 import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 
@@ -25,8 +32,16 @@ import (
 )
 
 // ArtifactsAPIController is the controller for the Artifacts API.
+// DefaultMaxUploadSize is the default maximum size in bytes of the artifacts
+// uploaded with UploadArtifactHandler.
+const DefaultMaxUploadSize = 256 << 20
+
 type ArtifactsAPIController struct {
 	artifactService artifact.Service
+
+	// MaxUploadSize is the maximum size in bytes of the artifacts uploaded
+	// with UploadArtifactHandler. Zero means DefaultMaxUploadSize.
+	MaxUploadSize int64
 }
 
 func NewArtifactsAPIController(artifactService artifact.Service) *ArtifactsAPIController {
@@ -179,3 +194,191 @@ func (c *ArtifactsAPIController) DeleteArtifactHandler(rw http.ResponseWriter, r
 	}
 	EncodeJSONResponse(nil, http.StatusOK, rw)
 }
+
+// artifactParameters returns the session and artifact name of an artifact
+// route.
+func artifactParameters(vars map[string]string) (models.SessionID, string, error) {
+	sessionID, err := models.SessionIDFromHTTPParameters(vars)
+	if err != nil {
+		return sessionID, "", err
+	}
+	if sessionID.ID == "" {
+		return sessionID, "", fmt.Errorf("session_id parameter is required")
+	}
+	artifactName := vars["artifact_name"]
+	if artifactName == "" {
+		return sessionID, "", fmt.Errorf("artifact_name parameter is required")
+	}
+	return sessionID, artifactName, nil
+}
+
+// artifactErrorStatus returns the HTTP status for an artifact service error.
+func artifactErrorStatus(err error) int {
+	if errors.Is(err, fs.ErrNotExist) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// ListArtifactVersionsHandler lists the versions of an artifact.
+func (c *ArtifactsAPIController) ListArtifactVersionsHandler(rw http.ResponseWriter, req *http.Request) {
+	sessionID, artifactName, err := artifactParameters(mux.Vars(req))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := c.artifactService.Versions(req.Context(), &artifact.VersionsRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+		FileName:  artifactName,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), artifactErrorStatus(err))
+		return
+	}
+	versions := slices.Clone(resp.Versions)
+	slices.Sort(versions)
+	EncodeJSONResponse(versions, http.StatusOK, rw)
+}
+
+// ListArtifactVersionsMetadataHandler lists the metadata of all versions of
+// an artifact, ordered by version.
+func (c *ArtifactsAPIController) ListArtifactVersionsMetadataHandler(rw http.ResponseWriter, req *http.Request) {
+	sessionID, artifactName, err := artifactParameters(mux.Vars(req))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := c.artifactService.Versions(req.Context(), &artifact.VersionsRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+		FileName:  artifactName,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), artifactErrorStatus(err))
+		return
+	}
+	versions := make([]models.ArtifactVersion, len(resp.Versions))
+	for i, v := range resp.Versions {
+		meta := &artifact.Metadata{FileName: artifactName, Version: v}
+		if i < len(resp.Metadata) && resp.Metadata[i] != nil {
+			meta = resp.Metadata[i]
+		}
+		versions[i] = models.FromArtifactMetadata(meta)
+	}
+	slices.SortFunc(versions, func(a, b models.ArtifactVersion) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	EncodeJSONResponse(versions, http.StatusOK, rw)
+}
+
+// DownloadArtifactHandler streams the raw content of an artifact version.
+// The latest version is returned unless the "version" query parameter is set.
+func (c *ArtifactsAPIController) DownloadArtifactHandler(rw http.ResponseWriter, req *http.Request) {
+	sessionID, artifactName, err := artifactParameters(mux.Vars(req))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	loadReq := &artifact.LoadRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+		FileName:  artifactName,
+	}
+	if version := req.URL.Query().Get("version"); version != "" {
+		versionInt, err := strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			http.Error(rw, "version parameter must be an integer", http.StatusBadRequest)
+			return
+		}
+		loadReq.Version = versionInt
+	}
+
+	resp, err := artifact.LoadStream(req.Context(), c.artifactService, loadReq)
+	if err != nil {
+		http.Error(rw, err.Error(), artifactErrorStatus(err))
+		return
+	}
+	defer resp.Body.Close()
+
+	header := rw.Header()
+	header.Set("Content-Type", "application/octet-stream")
+	if meta := resp.Metadata; meta != nil {
+		if meta.MIMEType != "" {
+			header.Set("Content-Type", meta.MIMEType)
+		}
+		if meta.Size > 0 {
+			header.Set("Content-Length", strconv.FormatInt(meta.Size, 10))
+		}
+		if meta.Version > 0 {
+			header.Set("X-Artifact-Version", strconv.FormatInt(meta.Version, 10))
+		}
+		if meta.Checksum != "" {
+			header.Set("ETag", strconv.Quote(meta.Checksum))
+		}
+	}
+	rw.WriteHeader(http.StatusOK)
+	// The status is already sent, so a failed copy can only abort the body.
+	_, _ = io.Copy(rw, resp.Body)
+}
+
+// UploadArtifactHandler saves the raw request body as a new artifact
+// version without buffering it when the artifact service supports
+// streaming. The Content-Type header is stored as the MIME type, and each
+// "attribute" query parameter of the form key=value is stored as a custom
+// attribute. Invalid requests are rejected with 400 and bodies larger than
+// MaxUploadSize with 413.
+func (c *ArtifactsAPIController) UploadArtifactHandler(rw http.ResponseWriter, req *http.Request) {
+	sessionID, artifactName, err := artifactParameters(mux.Vars(req))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	mimeType := req.Header.Get("Content-Type")
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	var attributes map[string]string
+	for _, attr := range req.URL.Query()["attribute"] {
+		key, value, ok := strings.Cut(attr, "=")
+		if !ok || key == "" {
+			http.Error(rw, fmt.Sprintf("invalid attribute %q: want key=value", attr), http.StatusBadRequest)
+			return
+		}
+		if attributes == nil {
+			attributes = map[string]string{}
+		}
+		attributes[key] = value
+	}
+
+	saveReq := &artifact.SaveStreamRequest{
+		AppName:    sessionID.AppName,
+		UserID:     sessionID.UserID,
+		SessionID:  sessionID.ID,
+		FileName:   artifactName,
+		MIMEType:   mimeType,
+		Body:       http.MaxBytesReader(rw, req.Body, cmp.Or(c.MaxUploadSize, DefaultMaxUploadSize)),
+		Attributes: attributes,
+	}
+	if err := saveReq.Validate(); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := artifact.SaveStream(req.Context(), c.artifactService, saveReq)
+	if err != nil {
+		if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+			http.Error(rw, fmt.Sprintf("artifact exceeds the maximum size of %d bytes", maxBytesErr.Limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(rw, err.Error(), artifactErrorStatus(err))
+		return
+	}
+	meta := resp.Metadata
+	if meta == nil {
+		meta = &artifact.Metadata{FileName: artifactName, Version: resp.Version, MIMEType: mimeType, Attributes: attributes}
+	}
+	EncodeJSONResponse(models.FromArtifactMetadata(meta), http.StatusCreated, rw)
+}
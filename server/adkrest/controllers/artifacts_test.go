@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/server/adkrest/controllers"
+	"google.golang.org/adk/server/adkrest/internal/models"
+)
+
+func TestArtifactContent(t *testing.T) {
+	apiController := controllers.NewArtifactsAPIController(artifact.InMemoryService())
+	vars := map[string]string{"app_name": "testApp", "user_id": "testUser", "session_id": "testSession", "artifact_name": "data.csv"}
+	base := "/apps/testApp/users/testUser/sessions/testSession/artifacts/data.csv"
+
+	for _, body := range []string{"a,b\n", "a,b\n1,2\n"} {
+		req := httptest.NewRequest(http.MethodPut, base+"/content?attribute=source=test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv")
+		req = mux.SetURLVars(req, vars)
+		rr := httptest.NewRecorder()
+		apiController.UploadArtifactHandler(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("UploadArtifactHandler() status = %d, body = %s", rr.Code, rr.Body.String())
+		}
+	}
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, base+"/content?version=1", nil), vars)
+	rr := httptest.NewRecorder()
+	apiController.DownloadArtifactHandler(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("DownloadArtifactHandler() status = %d, body = %s", rr.Code, rr.Body.String())
+	}
+	if got, want := rr.Body.String(), "a,b\n"; got != want {
+		t.Errorf("DownloadArtifactHandler() body = %q, want %q", got, want)
+	}
+	if got, want := rr.Header().Get("Content-Type"), "text/csv"; got != want {
+		t.Errorf("DownloadArtifactHandler() Content-Type = %q, want %q", got, want)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest(http.MethodGet, base+"/versions/metadata", nil), vars)
+	rr = httptest.NewRecorder()
+	apiController.ListArtifactVersionsMetadataHandler(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("ListArtifactVersionsMetadataHandler() status = %d, body = %s", rr.Code, rr.Body.String())
+	}
+	var got []models.ArtifactVersion
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []models.ArtifactVersion{
+		{Version: 1, FileName: "data.csv", MIMEType: "text/csv", Size: 4, Checksum: artifact.Checksum([]byte("a,b\n")), CustomMetadata: map[string]string{"source": "test"}},
+		{Version: 2, FileName: "data.csv", MIMEType: "text/csv", Size: 8, Checksum: artifact.Checksum([]byte("a,b\n1,2\n")), CustomMetadata: map[string]string{"source": "test"}},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(models.ArtifactVersion{}, "CreateTime")); diff != "" {
+		t.Errorf("ListArtifactVersionsMetadataHandler() mismatch (-want +got):\n%s", diff)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest(http.MethodGet, base+"/content?version=3", nil), vars)
+	rr = httptest.NewRecorder()
+	apiController.DownloadArtifactHandler(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("DownloadArtifactHandler() of a missing version status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestUploadArtifact_Errors(t *testing.T) {
+	apiController := controllers.NewArtifactsAPIController(artifact.InMemoryService())
+	apiController.MaxUploadSize = 4
+	tests := []struct {
+		name         string
+		artifactName string
+		body         string
+		want         int
+	}{
+		{name: "invalid name", artifactName: `dir\data.csv`, body: "a", want: http.StatusBadRequest},
+		{name: "too large", artifactName: "data.csv", body: "a,b\n1,2\n", want: http.StatusRequestEntityTooLarge},
+		{name: "within limit", artifactName: "data.csv", body: "a,b\n", want: http.StatusCreated},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/apps/testApp/users/testUser/sessions/testSession/artifacts/data.csv/content", strings.NewReader(tc.body))
+			req = mux.SetURLVars(req, map[string]string{"app_name": "testApp", "user_id": "testUser", "session_id": "testSession", "artifact_name": tc.artifactName})
+			rr := httptest.NewRecorder()
+			apiController.UploadArtifactHandler(rr, req)
+			if rr.Code != tc.want {
+				t.Errorf("UploadArtifactHandler() status = %d, want %d, body = %s", rr.Code, tc.want, rr.Body.String())
+			}
+		})
+	}
+}
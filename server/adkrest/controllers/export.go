@@ -0,0 +1,186 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/session/export"
+)
+
+// maxImportSize is the maximum size in bytes of an uploaded bundle.
+const maxImportSize = 256 << 20
+
+// ExportAPIController is the controller for the session export/import API.
+type ExportAPIController struct {
+	sessionService  session.Service
+	artifactService artifact.Service
+}
+
+// NewExportAPIController creates a new ExportAPIController.
+// artifactService may be nil, in which case artifacts are neither exported
+// nor imported.
+func NewExportAPIController(sessionService session.Service, artifactService artifact.Service) *ExportAPIController {
+	return &ExportAPIController{sessionService: sessionService, artifactService: artifactService}
+}
+
+// ExportSessionHandler downloads a session as an [export.Bundle].
+// The format query parameter selects "json" (default) or "jsonl", and
+// skip_artifacts=true leaves artifacts out of the bundle. Missing sessions
+// are reported with 404.
+func (c *ExportAPIController) ExportSessionHandler(rw http.ResponseWriter, req *http.Request) {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sessionID.ID == "" {
+		http.Error(rw, "session_id parameter is required", http.StatusBadRequest)
+		return
+	}
+	query := req.URL.Query()
+	format, err := export.ParseFormat(query.Get("format"))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	skipArtifacts, err := parseBoolParam(query.Get("skip_artifacts"))
+	if err != nil {
+		http.Error(rw, "skip_artifacts parameter must be a boolean", http.StatusBadRequest)
+		return
+	}
+
+	bundle, err := export.Export(req.Context(), c.sessionService, c.artifactService, &export.ExportRequest{
+		AppName:       sessionID.AppName,
+		UserID:        sessionID.UserID,
+		SessionID:     sessionID.ID,
+		SkipArtifacts: skipArtifacts,
+	})
+	if errors.Is(err, export.ErrSessionNotFound) {
+		http.Error(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.Encode(&buf, bundle, format); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", format.ContentType())
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", sessionID.ID+"."+string(format)))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(rw)
+}
+
+// ImportSessionHandler uploads an [export.Bundle] and creates a session from
+// it. The session ID from the path overrides the one recorded in the bundle,
+// and the session is created for the app and user from the path.
+// shared_state=true also imports the app and user state, see
+// [export.ImportRequest.SharedState]. Invalid
+// bundles are rejected with 400, bundles larger than 256 MiB with 413 and
+// imports into an existing session with 409.
+func (c *ExportAPIController) ImportSessionHandler(rw http.ResponseWriter, req *http.Request) {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sessionID.ID == "" {
+		http.Error(rw, "session_id parameter is required", http.StatusBadRequest)
+		return
+	}
+	query := req.URL.Query()
+	format, err := export.ParseFormat(query.Get("format"))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	skipArtifacts, err := parseBoolParam(query.Get("skip_artifacts"))
+	if err != nil {
+		http.Error(rw, "skip_artifacts parameter must be a boolean", http.StatusBadRequest)
+		return
+	}
+	sharedState, err := parseBoolParam(query.Get("shared_state"))
+	if err != nil {
+		http.Error(rw, "shared_state parameter must be a boolean", http.StatusBadRequest)
+		return
+	}
+
+	bundle, err := export.Decode(http.MaxBytesReader(rw, req.Body, maxImportSize), format)
+	if err != nil {
+		if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+			http.Error(rw, fmt.Sprintf("bundle exceeds the maximum size of %d bytes", maxBytesErr.Limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := export.Import(req.Context(), c.sessionService, c.artifactService, &export.ImportRequest{
+		Bundle:        bundle,
+		AppName:       sessionID.AppName,
+		UserID:        sessionID.UserID,
+		SessionID:     sessionID.ID,
+		SkipArtifacts: skipArtifacts || c.artifactService == nil,
+		SharedState:   sharedState,
+	})
+	switch {
+	case errors.Is(err, export.ErrInvalidBundle):
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, export.ErrSessionExists):
+		http.Error(rw, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Return the stored session, the one returned by Import may not reflect
+	// the state merged by the session service.
+	stored, err := c.sessionService.Get(req.Context(), &session.GetRequest{
+		AppName:   resp.Session.AppName(),
+		UserID:    resp.Session.UserID(),
+		SessionID: resp.Session.ID(),
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respSession, err := models.FromSession(stored.Session)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	EncodeJSONResponse(respSession, http.StatusOK, rw)
+}
+
+func parseBoolParam(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/server/adkrest/controllers"
+	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/session"
+)
+
+func TestExportImportSession(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	artifactService := artifact.InMemoryService()
+
+	created, err := sessionService.Create(ctx, &session.CreateRequest{
+		AppName: "testApp", UserID: "testUser", SessionID: "source",
+		State: map[string]any{"foo": "bar"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &session.Event{
+		ID:        "event1",
+		Author:    "user",
+		Timestamp: time.Now(),
+		LLMResponse: model.LLMResponse{
+			Content: genai.NewContentFromText("hello", genai.RoleUser),
+		},
+	}
+	if err := sessionService.AppendEvent(ctx, created.Session, event); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := artifactService.Save(ctx, &artifact.SaveRequest{
+		AppName: "testApp", UserID: "testUser", SessionID: "source", FileName: "notes.txt",
+		Part: genai.NewPartFromText("notes"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	apiController := controllers.NewExportAPIController(sessionService, artifactService)
+
+	for _, format := range []string{"json", "jsonl"} {
+		t.Run(format, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions/source/export?format="+format, nil)
+			req = mux.SetURLVars(req, map[string]string{"app_name": "testApp", "user_id": "testUser", "session_id": "source"})
+			rr := httptest.NewRecorder()
+			apiController.ExportSessionHandler(rr, req)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("ExportSessionHandler() status = %d, body = %s", rr.Code, rr.Body.String())
+			}
+			bundle := rr.Body.Bytes()
+
+			targetID := "imported-" + format
+			req = httptest.NewRequest(http.MethodPost, "/apps/testApp/users/testUser/sessions/"+targetID+"/import?format="+format, bytes.NewReader(bundle))
+			req = mux.SetURLVars(req, map[string]string{"app_name": "testApp", "user_id": "testUser", "session_id": targetID})
+			rr = httptest.NewRecorder()
+			apiController.ImportSessionHandler(rr, req)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("ImportSessionHandler() status = %d, body = %s", rr.Code, rr.Body.String())
+			}
+
+			var got models.Session
+			if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if got.ID != targetID || len(got.Events) != 1 || got.State["foo"] != "bar" {
+				t.Errorf("ImportSessionHandler() = %+v, want session %q with 1 event and the exported state", got, targetID)
+			}
+			if _, err := artifactService.Load(ctx, &artifact.LoadRequest{
+				AppName: "testApp", UserID: "testUser", SessionID: targetID, FileName: "notes.txt",
+			}); err != nil {
+				t.Errorf("imported artifact not found: %v", err)
+			}
+		})
+	}
+}
+
+func TestExportSession_InvalidFormat(t *testing.T) {
+	apiController := controllers.NewExportAPIController(session.InMemoryService(), nil)
+	req := httptest.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions/s/export?format=xml", nil)
+	req = mux.SetURLVars(req, map[string]string{"app_name": "testApp", "user_id": "testUser", "session_id": "s"})
+	rr := httptest.NewRecorder()
+	apiController.ExportSessionHandler(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("ExportSessionHandler() status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestExportSession_NotFound(t *testing.T) {
+	apiController := controllers.NewExportAPIController(session.InMemoryService(), nil)
+	req := httptest.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions/missing/export", nil)
+	req = mux.SetURLVars(req, map[string]string{"app_name": "testApp", "user_id": "testUser", "session_id": "missing"})
+	rr := httptest.NewRecorder()
+	apiController.ExportSessionHandler(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("ExportSessionHandler() status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestImportSession_Errors(t *testing.T) {
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "existing"}); err != nil {
+		t.Fatal(err)
+	}
+	apiController := controllers.NewExportAPIController(sessionService, nil)
+	tests := []struct {
+		name      string
+		sessionID string
+		body      string
+		want      int
+	}{
+		{name: "malformed", sessionID: "new", body: `{`, want: http.StatusBadRequest},
+		{name: "unsupported version", sessionID: "new", body: `{"formatVersion": 1000}`, want: http.StatusBadRequest},
+		{name: "existing session", sessionID: "existing", body: `{"formatVersion": 1}`, want: http.StatusConflict},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/apps/testApp/users/testUser/sessions/"+tc.sessionID+"/import", strings.NewReader(tc.body))
+			req = mux.SetURLVars(req, map[string]string{"app_name": "testApp", "user_id": "testUser", "session_id": tc.sessionID})
+			rr := httptest.NewRecorder()
+			apiController.ImportSessionHandler(rr, req)
+			if rr.Code != tc.want {
+				t.Errorf("ImportSessionHandler() status = %d, want %d, body = %s", rr.Code, tc.want, rr.Body.String())
+			}
+		})
+	}
+}
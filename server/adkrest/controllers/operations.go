@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/operation"
+	"google.golang.org/adk/server/adkrest/internal/models"
+)
+
+// ListOperationsHandler lists the long-running operations of a session.
+func (c *RuntimeAPIController) ListOperationsHandler(rw http.ResponseWriter, req *http.Request) error {
+	if c.operationStore == nil {
+		return newStatusError(errors.New("operations are not enabled"), http.StatusNotFound)
+	}
+	sessionID, err := sessionIDFromRequest(req)
+	if err != nil {
+		return err
+	}
+	ops, err := c.operationStore.List(req.Context(), &operation.ListRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+	})
+	if err != nil {
+		return newStatusError(fmt.Errorf("failed to list operations: %w", err), http.StatusInternalServerError)
+	}
+	result := []models.Operation{}
+	for _, op := range ops {
+		result = append(result, models.FromOperation(op))
+	}
+	EncodeJSONResponse(result, http.StatusOK, rw)
+	return nil
+}
+
+// GetOperationHandler returns a long-running operation.
+func (c *RuntimeAPIController) GetOperationHandler(rw http.ResponseWriter, req *http.Request) error {
+	op, err := c.operationFromRequest(req)
+	if err != nil {
+		return err
+	}
+	EncodeJSONResponse(models.FromOperation(op), http.StatusOK, rw)
+	return nil
+}
+
+// CompleteOperationHandler completes a long-running operation if a result or
+// an error is given, and resumes the agent with the result of the
+// operation. It returns the events of the resumed run.
+func (c *RuntimeAPIController) CompleteOperationHandler(rw http.ResponseWriter, req *http.Request) error {
+	op, err := c.operationFromRequest(req)
+	if err != nil {
+		return err
+	}
+	var completeRequest models.CompleteOperationRequest
+	if req.ContentLength != 0 {
+		d := json.NewDecoder(req.Body)
+		d.DisallowUnknownFields()
+		if err := d.Decode(&completeRequest); err != nil {
+			return newStatusError(fmt.Errorf("failed to decode request: %w", err), http.StatusBadRequest)
+		}
+	}
+
+	r, rCfg, err := c.getRunner(op.AppName, completeRequest.Streaming)
+	if err != nil {
+		return err
+	}
+	if completeRequest.Result != nil || completeRequest.Error != "" {
+		var opErr error
+		if completeRequest.Error != "" {
+			opErr = errors.New(completeRequest.Error)
+		}
+		if _, err := r.CompleteOperation(req.Context(), op.ID, completeRequest.Result, opErr); err != nil {
+			return newStatusError(fmt.Errorf("failed to complete operation: %w", err), http.StatusConflict)
+		}
+	}
+
+	var events []models.Event
+	for event, err := range r.ResumeOperation(req.Context(), op.ID, *rCfg) {
+		if err != nil {
+			if len(events) == 0 {
+				return newStatusError(fmt.Errorf("failed to resume operation: %w", err), http.StatusConflict)
+			}
+			return newStatusError(fmt.Errorf("failed to run agent: %w", err), http.StatusInternalServerError)
+		}
+		events = append(events, models.FromSessionEvent(*event))
+	}
+	EncodeJSONResponse(events, http.StatusOK, rw)
+	return nil
+}
+
+// operationFromRequest returns the operation of the request, which must
+// belong to the session of the request.
+func (c *RuntimeAPIController) operationFromRequest(req *http.Request) (*operation.Operation, error) {
+	if c.operationStore == nil {
+		return nil, newStatusError(errors.New("operations are not enabled"), http.StatusNotFound)
+	}
+	sessionID, err := sessionIDFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	operationID := mux.Vars(req)["operation_id"]
+	if operationID == "" {
+		return nil, newStatusError(errors.New("operation_id parameter is required"), http.StatusBadRequest)
+	}
+	op, err := c.operationStore.Get(req.Context(), operationID)
+	if errors.Is(err, operation.ErrNotFound) {
+		return nil, newStatusError(err, http.StatusNotFound)
+	}
+	if err != nil {
+		return nil, newStatusError(fmt.Errorf("failed to get operation: %w", err), http.StatusInternalServerError)
+	}
+	if op.AppName != sessionID.AppName || op.UserID != sessionID.UserID || op.SessionID != sessionID.ID {
+		return nil, newStatusError(fmt.Errorf("%w: %s", operation.ErrNotFound, operationID), http.StatusNotFound)
+	}
+	return op, nil
+}
+
+func sessionIDFromRequest(req *http.Request) (models.SessionID, error) {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		return sessionID, newStatusError(err, http.StatusBadRequest)
+	}
+	if sessionID.ID == "" {
+		return sessionID, newStatusError(errors.New("session_id parameter is required"), http.StatusBadRequest)
+	}
+	return sessionID, nil
+}
@@ -24,6 +24,7 @@ import (
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
 	"google.golang.org/adk/memory"
+	"google.golang.org/adk/operation"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/server/adkrest/internal/models"
 	"google.golang.org/adk/session"
@@ -37,11 +38,12 @@ type RuntimeAPIController struct {
 	artifactService artifact.Service
 	agentLoader     agent.Loader
 	pluginConfig    runner.PluginConfig
+	operationStore  operation.Store
 }
 
 // NewRuntimeAPIController creates the controller for the Runtime API.
-func NewRuntimeAPIController(sessionService session.Service, memoryService memory.Service, agentLoader agent.Loader, artifactService artifact.Service, sseTimeout time.Duration, pluginConfig runner.PluginConfig) *RuntimeAPIController {
-	return &RuntimeAPIController{sessionService: sessionService, memoryService: memoryService, agentLoader: agentLoader, artifactService: artifactService, sseTimeout: sseTimeout, pluginConfig: pluginConfig}
+func NewRuntimeAPIController(sessionService session.Service, memoryService memory.Service, agentLoader agent.Loader, artifactService artifact.Service, sseTimeout time.Duration, pluginConfig runner.PluginConfig, operationStore operation.Store) *RuntimeAPIController {
+	return &RuntimeAPIController{sessionService: sessionService, memoryService: memoryService, agentLoader: agentLoader, artifactService: artifactService, sseTimeout: sseTimeout, pluginConfig: pluginConfig, operationStore: operationStore}
 }
 
 // RunAgent executes a non-streaming agent run for a given session and message.
@@ -69,7 +71,7 @@ func (c *RuntimeAPIController) runAgent(ctx context.Context, runAgentRequest mod
 		return nil, err
 	}
 
-	r, rCfg, err := c.getRunner(runAgentRequest)
+	r, rCfg, err := c.getRunner(runAgentRequest.AppName, runAgentRequest.Streaming)
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +112,7 @@ func (c *RuntimeAPIController) RunSSEHandler(rw http.ResponseWriter, req *http.R
 		return err
 	}
 
-	r, rCfg, err := c.getRunner(runAgentRequest)
+	r, rCfg, err := c.getRunner(runAgentRequest.AppName, runAgentRequest.Streaming)
 	if err != nil {
 		return err
 	}
@@ -170,19 +172,20 @@ func (c *RuntimeAPIController) validateSessionExists(ctx context.Context, appNam
 	return nil
 }
 
-func (c *RuntimeAPIController) getRunner(req models.RunAgentRequest) (*runner.Runner, *agent.RunConfig, error) {
-	curAgent, err := c.agentLoader.LoadAgent(req.AppName)
+func (c *RuntimeAPIController) getRunner(appName string, streaming bool) (*runner.Runner, *agent.RunConfig, error) {
+	curAgent, err := c.agentLoader.LoadAgent(appName)
 	if err != nil {
 		return nil, nil, newStatusError(fmt.Errorf("failed to load agent: %w", err), http.StatusInternalServerError)
 	}
 
 	r, err := runner.New(runner.Config{
-		AppName:         req.AppName,
+		AppName:         appName,
 		Agent:           curAgent,
 		SessionService:  c.sessionService,
 		MemoryService:   c.memoryService,
 		ArtifactService: c.artifactService,
 		PluginConfig:    c.pluginConfig,
+		OperationStore:  c.operationStore,
 	},
 	)
 	if err != nil {
@@ -190,7 +193,7 @@ func (c *RuntimeAPIController) getRunner(req models.RunAgentRequest) (*runner.Ru
 	}
 
 	streamingMode := agent.StreamingModeNone
-	if req.Streaming {
+	if streaming {
 		streamingMode = agent.StreamingModeSSE
 	}
 	return r, &agent.RunConfig{
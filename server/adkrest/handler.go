@@ -33,15 +33,21 @@ func NewHandler(config *launcher.Config, sseWriteTimeout time.Duration) http.Han
 	config.TelemetryOptions = append(config.TelemetryOptions, telemetry.WithSpanProcessors(debugTelemetry.SpanProcessor()))
 	config.TelemetryOptions = append(config.TelemetryOptions, telemetry.WithLogRecordProcessors(debugTelemetry.LogProcessor()))
 
+	runtimeController := controllers.NewRuntimeAPIController(config.SessionService, config.MemoryService, config.AgentLoader, config.ArtifactService, sseWriteTimeout, config.PluginConfig, config.OperationStore)
+	artifactsController := controllers.NewArtifactsAPIController(config.ArtifactService)
+	artifactsController.MaxUploadSize = config.MaxArtifactUploadSize
+
 	router := mux.NewRouter().StrictSlash(true)
 	// TODO: Allow taking a prefix to allow customizing the path
 	// where the ADK REST API will be served.
 	setupRouter(router,
 		routers.NewSessionsAPIRouter(controllers.NewSessionsAPIController(config.SessionService)),
-		routers.NewRuntimeAPIRouter(controllers.NewRuntimeAPIController(config.SessionService, config.MemoryService, config.AgentLoader, config.ArtifactService, sseWriteTimeout, config.PluginConfig)),
+		routers.NewRuntimeAPIRouter(runtimeController),
+		routers.NewOperationsAPIRouter(runtimeController),
 		routers.NewAppsAPIRouter(controllers.NewAppsAPIController(config.AgentLoader)),
 		routers.NewDebugAPIRouter(controllers.NewDebugAPIController(config.SessionService, config.AgentLoader, debugTelemetry)),
-		routers.NewArtifactsAPIRouter(controllers.NewArtifactsAPIController(config.ArtifactService)),
+		routers.NewArtifactsAPIRouter(artifactsController),
+		routers.NewExportAPIRouter(controllers.NewExportAPIController(config.SessionService, config.ArtifactService)),
 		&routers.EvalAPIRouter{},
 	)
 	return router
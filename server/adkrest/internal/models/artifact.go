@@ -0,0 +1,51 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"google.golang.org/adk/artifact"
+)
+
+// ArtifactVersion describes a stored artifact version.
+type ArtifactVersion struct {
+	Version  int64  `json:"version"`
+	FileName string `json:"fileName"`
+	MIMEType string `json:"mimeType,omitempty"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum,omitempty"`
+	// CreateTime is the creation time in seconds since the Unix epoch.
+	CreateTime     float64           `json:"createTime,omitempty"`
+	Author         string            `json:"author,omitempty"`
+	InvocationID   string            `json:"invocationId,omitempty"`
+	CustomMetadata map[string]string `json:"customMetadata,omitempty"`
+}
+
+// FromArtifactMetadata converts artifact metadata to its REST representation.
+func FromArtifactMetadata(m *artifact.Metadata) ArtifactVersion {
+	v := ArtifactVersion{
+		Version:        m.Version,
+		FileName:       m.FileName,
+		MIMEType:       m.MIMEType,
+		Size:           m.Size,
+		Checksum:       m.Checksum,
+		Author:         m.Author,
+		InvocationID:   m.InvocationID,
+		CustomMetadata: m.Attributes,
+	}
+	if !m.CreateTime.IsZero() {
+		v.CreateTime = float64(m.CreateTime.UnixNano()) / 1e9
+	}
+	return v
+}
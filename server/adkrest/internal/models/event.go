@@ -44,6 +44,7 @@ type Event struct {
 	Interrupted        bool                     `json:"interrupted"`
 	ErrorCode          string                   `json:"errorCode"`
 	ErrorMessage       string                   `json:"errorMessage"`
+	CustomMetadata     map[string]any           `json:"customMetadata,omitempty"`
 	Actions            EventActions             `json:"actions"`
 }
 
@@ -64,6 +65,7 @@ func ToSessionEvent(event Event) *session.Event {
 			Interrupted:       event.Interrupted,
 			ErrorCode:         event.ErrorCode,
 			ErrorMessage:      event.ErrorMessage,
+			CustomMetadata:    event.CustomMetadata,
 		},
 		Actions: session.EventActions{
 			StateDelta:    event.Actions.StateDelta,
@@ -88,6 +90,7 @@ func FromSessionEvent(event session.Event) Event {
 		Interrupted:        event.LLMResponse.Interrupted,
 		ErrorCode:          event.LLMResponse.ErrorCode,
 		ErrorMessage:       event.LLMResponse.ErrorMessage,
+		CustomMetadata:     event.LLMResponse.CustomMetadata,
 		Actions: EventActions{
 			StateDelta:    event.Actions.StateDelta,
 			ArtifactDelta: event.Actions.ArtifactDelta,
@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"google.golang.org/adk/operation"
+)
+
+// Operation represents a long-running operation started by a tool.
+type Operation struct {
+	ID             string         `json:"id"`
+	AppName        string         `json:"appName"`
+	UserID         string         `json:"userId"`
+	SessionID      string         `json:"sessionId"`
+	InvocationID   string         `json:"invocationId"`
+	Author         string         `json:"author"`
+	FunctionCallID string         `json:"functionCallId"`
+	Status         string         `json:"status"`
+	Progress       map[string]any `json:"progress,omitempty"`
+	Result         map[string]any `json:"result,omitempty"`
+	Error          string         `json:"error,omitempty"`
+	Resumed        bool           `json:"resumed"`
+	CreateTime     int64          `json:"createTime"`
+	UpdateTime     int64          `json:"updateTime"`
+}
+
+// CompleteOperationRequest completes and resumes an operation. Result or
+// Error complete a running operation; both are omitted to resume an
+// operation which is already completed.
+type CompleteOperationRequest struct {
+	Result    map[string]any `json:"result,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Streaming bool           `json:"streaming,omitempty"`
+}
+
+// FromOperation converts an operation.Operation to an Operation.
+func FromOperation(op *operation.Operation) Operation {
+	return Operation{
+		ID:             op.ID,
+		AppName:        op.AppName,
+		UserID:         op.UserID,
+		SessionID:      op.SessionID,
+		InvocationID:   op.InvocationID,
+		Author:         op.AgentName,
+		FunctionCallID: op.FunctionCallID,
+		Status:         string(op.Status),
+		Progress:       op.Progress,
+		Result:         op.Result,
+		Error:          op.Error,
+		Resumed:        op.Resumed,
+		CreateTime:     op.CreateTime.Unix(),
+		UpdateTime:     op.UpdateTime.Unix(),
+	}
+}
@@ -45,6 +45,32 @@ func (r *ArtifactsAPIRouter) Routes() Routes {
 			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/artifacts/{artifact_name}",
 			HandlerFunc: r.artifactsController.LoadArtifactHandler,
 		},
+		Route{
+			Name:        "ListArtifactVersions",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/artifacts/{artifact_name}/versions",
+			HandlerFunc: r.artifactsController.ListArtifactVersionsHandler,
+		},
+		Route{
+			// Registered before LoadArtifactVersion, whose {version} would
+			// otherwise match "metadata".
+			Name:        "ListArtifactVersionsMetadata",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/artifacts/{artifact_name}/versions/metadata",
+			HandlerFunc: r.artifactsController.ListArtifactVersionsMetadataHandler,
+		},
+		Route{
+			Name:        "DownloadArtifact",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/artifacts/{artifact_name}/content",
+			HandlerFunc: r.artifactsController.DownloadArtifactHandler,
+		},
+		Route{
+			Name:        "UploadArtifact",
+			Methods:     []string{http.MethodPut},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/artifacts/{artifact_name}/content",
+			HandlerFunc: r.artifactsController.UploadArtifactHandler,
+		},
 		Route{
 			Name:        "LoadArtifactVersion",
 			Methods:     []string{http.MethodGet},
@@ -0,0 +1,49 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routers
+
+import (
+	"net/http"
+
+	"google.golang.org/adk/server/adkrest/controllers"
+)
+
+// ExportAPIRouter defines the routes for the session export/import API.
+type ExportAPIRouter struct {
+	exportController *controllers.ExportAPIController
+}
+
+// NewExportAPIRouter creates a new ExportAPIRouter.
+func NewExportAPIRouter(controller *controllers.ExportAPIController) *ExportAPIRouter {
+	return &ExportAPIRouter{exportController: controller}
+}
+
+// Routes returns the routes for the session export/import API.
+func (r *ExportAPIRouter) Routes() Routes {
+	return Routes{
+		Route{
+			Name:        "ExportSession",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/export",
+			HandlerFunc: r.exportController.ExportSessionHandler,
+		},
+		Route{
+			Name:        "ImportSession",
+			Methods:     []string{http.MethodPost},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/import",
+			HandlerFunc: r.exportController.ImportSessionHandler,
+		},
+	}
+}
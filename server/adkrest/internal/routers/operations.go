@@ -0,0 +1,56 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routers
+
+import (
+	"net/http"
+
+	"google.golang.org/adk/server/adkrest/controllers"
+)
+
+// OperationsAPIRouter defines the routes for the long-running operations
+// API.
+type OperationsAPIRouter struct {
+	runtimeController *controllers.RuntimeAPIController
+}
+
+// NewOperationsAPIRouter creates a new OperationsAPIRouter.
+func NewOperationsAPIRouter(controller *controllers.RuntimeAPIController) *OperationsAPIRouter {
+	return &OperationsAPIRouter{runtimeController: controller}
+}
+
+// Routes returns the routes for the operations API.
+func (r *OperationsAPIRouter) Routes() Routes {
+	return Routes{
+		Route{
+			Name:        "ListOperations",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/operations",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.ListOperationsHandler),
+		},
+		Route{
+			Name:        "GetOperation",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/operations/{operation_id}",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.GetOperationHandler),
+		},
+		Route{
+			Name:        "CompleteOperation",
+			Methods:     []string{http.MethodPost, http.MethodOptions},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/operations/{operation_id}/complete",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.CompleteOperationHandler),
+		},
+	}
+}
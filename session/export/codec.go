@@ -0,0 +1,192 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Format is the serialization format of a [Bundle].
+type Format string
+
+const (
+	// FormatJSON encodes the bundle as a single JSON document.
+	FormatJSON Format = "json"
+	// FormatJSONL encodes the bundle as JSON lines: a header record with the
+	// session and its state, followed by one record per event and one record
+	// per artifact version. It is convenient for large sessions and for
+	// inspecting events with line-oriented tools.
+	FormatJSONL Format = "jsonl"
+)
+
+// ParseFormat returns the [Format] with the given name. An empty name
+// selects [FormatJSON].
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatJSONL:
+		return FormatJSONL, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q, must be one of %q, %q", name, FormatJSON, FormatJSONL)
+	}
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	if f == FormatJSONL {
+		return "application/x-ndjson"
+	}
+	return "application/json"
+}
+
+// recordKind identifies the records of the JSONL format.
+type recordKind string
+
+const (
+	recordHeader   recordKind = "header"
+	recordEvent    recordKind = "event"
+	recordArtifact recordKind = "artifact"
+)
+
+// record is a single line of the JSONL format.
+type record struct {
+	Kind recordKind `json:"kind"`
+
+	// Set for header records.
+	FormatVersion int          `json:"formatVersion,omitempty"`
+	ExportTime    *time.Time   `json:"exportTime,omitempty"`
+	Session       *SessionInfo `json:"session,omitempty"`
+	State         *State       `json:"state,omitempty"`
+
+	// Set for event records.
+	Event *Event `json:"event,omitempty"`
+
+	// Set for artifact records.
+	Artifact *Artifact `json:"artifact,omitempty"`
+}
+
+// Encode writes the bundle to w in the given format.
+func Encode(w io.Writer, b *Bundle, f Format) error {
+	if b == nil {
+		return fmt.Errorf("bundle is nil")
+	}
+	enc := json.NewEncoder(w)
+	switch f {
+	case FormatJSON:
+		if err := enc.Encode(b); err != nil {
+			return fmt.Errorf("failed to encode bundle: %w", err)
+		}
+		return nil
+	case FormatJSONL:
+		header := record{
+			Kind:          recordHeader,
+			FormatVersion: b.FormatVersion,
+			ExportTime:    &b.ExportTime,
+			Session:       &b.Session,
+			State:         &b.State,
+		}
+		if err := enc.Encode(header); err != nil {
+			return fmt.Errorf("failed to encode bundle header: %w", err)
+		}
+		for _, e := range b.Events {
+			if err := enc.Encode(record{Kind: recordEvent, Event: e}); err != nil {
+				return fmt.Errorf("failed to encode event: %w", err)
+			}
+		}
+		for _, a := range b.Artifacts {
+			if err := enc.Encode(record{Kind: recordArtifact, Artifact: a}); err != nil {
+				return fmt.Errorf("failed to encode artifact: %w", err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown export format %q", f)
+	}
+}
+
+// Decode reads a bundle in the given format from r.
+// It returns an error if the bundle was written by a newer, unsupported
+// version of the format.
+func Decode(r io.Reader, f Format) (*Bundle, error) {
+	var b *Bundle
+	var err error
+	switch f {
+	case FormatJSON:
+		b = &Bundle{}
+		if err = json.NewDecoder(r).Decode(b); err != nil {
+			return nil, fmt.Errorf("failed to decode bundle: %w", err)
+		}
+	case FormatJSONL:
+		b, err = decodeJSONL(r)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown export format %q", f)
+	}
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func decodeJSONL(r io.Reader) (*Bundle, error) {
+	dec := json.NewDecoder(r)
+	var b *Bundle
+	for {
+		var rec record
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode bundle record: %w", err)
+		}
+		if b == nil && rec.Kind != recordHeader {
+			return nil, fmt.Errorf("%w: first record must be a %q record, got %q", ErrInvalidBundle, recordHeader, rec.Kind)
+		}
+		switch rec.Kind {
+		case recordHeader:
+			if b != nil {
+				return nil, fmt.Errorf("%w: duplicate %q record", ErrInvalidBundle, recordHeader)
+			}
+			b = &Bundle{FormatVersion: rec.FormatVersion, Events: []*Event{}}
+			if rec.ExportTime != nil {
+				b.ExportTime = *rec.ExportTime
+			}
+			if rec.Session != nil {
+				b.Session = *rec.Session
+			}
+			if rec.State != nil {
+				b.State = *rec.State
+			}
+		case recordEvent:
+			b.Events = append(b.Events, rec.Event)
+		case recordArtifact:
+			b.Artifacts = append(b.Artifacts, rec.Artifact)
+		default:
+			return nil, fmt.Errorf("%w: unknown record kind %q", ErrInvalidBundle, rec.Kind)
+		}
+	}
+	if b == nil {
+		return nil, fmt.Errorf("%w: missing %q record", ErrInvalidBundle, recordHeader)
+	}
+	return b, nil
+}
@@ -0,0 +1,525 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export moves sessions between [session.Service] implementations.
+//
+// A session is exported together with its events, its state split by scope
+// (app, user and session) and the artifacts it references into a [Bundle].
+// Bundles are serialized with [Encode] as a single JSON document or as JSON
+// lines, and can be imported with [Import] into any [session.Service] and
+// [artifact.Service] pair, e.g. to reproduce a production conversation
+// against a local in-memory setup.
+package export
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+var (
+	// ErrInvalidBundle is returned by [Decode] and [Import] for bundles that
+	// cannot be imported, like bundles of an unsupported format version.
+	ErrInvalidBundle = errors.New("invalid bundle")
+	// ErrSessionExists is returned by [Import] if the target session
+	// already exists.
+	ErrSessionExists = errors.New("session already exists")
+	// ErrSessionNotFound is returned by [Export] if the session service
+	// cannot get the session.
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// FormatVersion is the version of the bundle format produced by this package.
+// Bundles with a higher version are rejected by [Decode].
+const FormatVersion = 1
+
+// Bundle is a portable snapshot of a session.
+type Bundle struct {
+	// FormatVersion is the version of the bundle format.
+	FormatVersion int `json:"formatVersion"`
+	// ExportTime is the time the bundle was created.
+	ExportTime time.Time `json:"exportTime"`
+	// Session identifies the exported session.
+	Session SessionInfo `json:"session"`
+	// State is the session state at export time, split by scope.
+	State State `json:"state"`
+	// Events are the session events in chronological order.
+	Events []*Event `json:"events"`
+	// Artifacts holds every stored version of the artifacts visible to the
+	// session, including user-scoped ones.
+	Artifacts []*Artifact `json:"artifacts,omitempty"`
+}
+
+// SessionInfo identifies the exported session.
+type SessionInfo struct {
+	AppName        string    `json:"appName"`
+	UserID         string    `json:"userId"`
+	ID             string    `json:"id"`
+	LastUpdateTime time.Time `json:"lastUpdateTime"`
+}
+
+// State holds the session state split by scope. Keys are stored without
+// their scope prefix (see [session.KeyPrefixApp] and [session.KeyPrefixUser]).
+type State struct {
+	App     map[string]any `json:"app,omitempty"`
+	User    map[string]any `json:"user,omitempty"`
+	Session map[string]any `json:"session,omitempty"`
+}
+
+// Event is the serialized form of a [session.Event].
+type Event struct {
+	ID                 string    `json:"id"`
+	Timestamp          time.Time `json:"timestamp"`
+	InvocationID       string    `json:"invocationId,omitempty"`
+	Branch             string    `json:"branch,omitempty"`
+	Author             string    `json:"author,omitempty"`
+	LongRunningToolIDs []string  `json:"longRunningToolIds,omitempty"`
+
+	Content           *genai.Content                              `json:"content,omitempty"`
+	CitationMetadata  *genai.CitationMetadata                     `json:"citationMetadata,omitempty"`
+	GroundingMetadata *genai.GroundingMetadata                    `json:"groundingMetadata,omitempty"`
+	UsageMetadata     *genai.GenerateContentResponseUsageMetadata `json:"usageMetadata,omitempty"`
+	CustomMetadata    map[string]any                              `json:"customMetadata,omitempty"`
+	TurnComplete      bool                                        `json:"turnComplete,omitempty"`
+	Interrupted       bool                                        `json:"interrupted,omitempty"`
+	ErrorCode         string                                      `json:"errorCode,omitempty"`
+	ErrorMessage      string                                      `json:"errorMessage,omitempty"`
+	FinishReason      genai.FinishReason                          `json:"finishReason,omitempty"`
+	LogprobsResult    *genai.LogprobsResult                       `json:"logprobsResult,omitempty"`
+	AvgLogprobs       float64                                     `json:"avgLogprobs,omitempty"`
+
+	Actions EventActions `json:"actions"`
+}
+
+// EventActions is the serialized form of [session.EventActions].
+type EventActions struct {
+	StateDelta                 map[string]any                               `json:"stateDelta,omitempty"`
+	ArtifactDelta              map[string]int64                             `json:"artifactDelta,omitempty"`
+	RequestedToolConfirmations map[string]toolconfirmation.ToolConfirmation `json:"requestedToolConfirmations,omitempty"`
+	SkipSummarization          bool                                         `json:"skipSummarization,omitempty"`
+	TransferToAgent            string                                       `json:"transferToAgent,omitempty"`
+	Escalate                   bool                                         `json:"escalate,omitempty"`
+}
+
+// Artifact is a single stored version of an artifact.
+type Artifact struct {
+	FileName string      `json:"fileName"`
+	Version  int64       `json:"version"`
+	Part     *genai.Part `json:"part"`
+}
+
+// ExportRequest is the parameter for [Export].
+type ExportRequest struct {
+	AppName, UserID, SessionID string
+
+	// SkipArtifacts excludes artifacts from the bundle.
+	SkipArtifacts bool
+}
+
+// Export reads a session and the artifacts visible to it and returns them as
+// a [Bundle].
+//
+// artifactService may be nil, in which case no artifacts are exported.
+func Export(ctx context.Context, sessionService session.Service, artifactService artifact.Service, req *ExportRequest) (*Bundle, error) {
+	if sessionService == nil {
+		return nil, fmt.Errorf("session service is required")
+	}
+	if req == nil {
+		return nil, fmt.Errorf("export request is required")
+	}
+	resp, err := sessionService.Get(ctx, &session.GetRequest{
+		AppName:   req.AppName,
+		UserID:    req.UserID,
+		SessionID: req.SessionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSessionNotFound, err)
+	}
+	sess := resp.Session
+
+	bundle := &Bundle{
+		FormatVersion: FormatVersion,
+		ExportTime:    time.Now(),
+		Session: SessionInfo{
+			AppName:        sess.AppName(),
+			UserID:         sess.UserID(),
+			ID:             sess.ID(),
+			LastUpdateTime: sess.LastUpdateTime(),
+		},
+		State:  splitState(sess.State()),
+		Events: make([]*Event, 0, sess.Events().Len()),
+	}
+	for event := range sess.Events().All() {
+		bundle.Events = append(bundle.Events, fromSessionEvent(event))
+	}
+
+	if artifactService == nil || req.SkipArtifacts {
+		return bundle, nil
+	}
+	artifacts, err := exportArtifacts(ctx, artifactService, sess.AppName(), sess.UserID(), sess.ID())
+	if err != nil {
+		return nil, err
+	}
+	bundle.Artifacts = artifacts
+	return bundle, nil
+}
+
+func exportArtifacts(ctx context.Context, artifactService artifact.Service, appName, userID, sessionID string) ([]*Artifact, error) {
+	listResp, err := artifactService.List(ctx, &artifact.ListRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+
+	var artifacts []*Artifact
+	for _, fileName := range listResp.FileNames {
+		versionsResp, err := artifactService.Versions(ctx, &artifact.VersionsRequest{
+			AppName:   appName,
+			UserID:    userID,
+			SessionID: sessionID,
+			FileName:  fileName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions of artifact %q: %w", fileName, err)
+		}
+		versions := slices.Clone(versionsResp.Versions)
+		slices.Sort(versions)
+		for _, version := range versions {
+			loadResp, err := artifactService.Load(ctx, &artifact.LoadRequest{
+				AppName:   appName,
+				UserID:    userID,
+				SessionID: sessionID,
+				FileName:  fileName,
+				Version:   version,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to load artifact %q version %d: %w", fileName, version, err)
+			}
+			artifacts = append(artifacts, &Artifact{
+				FileName: fileName,
+				Version:  version,
+				Part:     loadResp.Part,
+			})
+		}
+	}
+	return artifacts, nil
+}
+
+// ImportRequest is the parameter for [Import].
+type ImportRequest struct {
+	// Bundle is the session snapshot to import.
+	Bundle *Bundle
+
+	// Below are optional fields.
+
+	// AppName, UserID and SessionID override the identifiers recorded in
+	// the bundle. Empty values keep the identifiers from the bundle.
+	AppName, UserID, SessionID string
+	// SkipArtifacts leaves the artifacts of the bundle out of the import.
+	SkipArtifacts bool
+	// SharedState imports the app and user state of the bundle and the
+	// app: and user: keys of the state deltas of its events, overwriting
+	// the state shared with the other sessions of the target app and user.
+	// Without it, only the session state is imported.
+	SharedState bool
+}
+
+// ImportResponse is the return type of [Import].
+type ImportResponse struct {
+	// Session is the imported session.
+	Session session.Session
+	// ArtifactVersions maps the artifact versions recorded in the bundle to
+	// the versions assigned by the target artifact service, keyed by file name.
+	ArtifactVersions map[string]map[int64]int64
+}
+
+// Import creates a new session from a [Bundle].
+//
+// The session is created with the exported state, the artifacts are saved in
+// version order and the events are appended one by one, so the target
+// services apply the same state and artifact deltas the source did. Unless
+// ImportRequest.SharedState is set, the app and user state are left out, and
+// so are the app: and user: keys of the state deltas of the events. Artifact
+// services assign their own version numbers; ArtifactDelta entries of the
+// imported events are rewritten to point at the new versions.
+//
+// The import is atomic: if it fails after the session was created, the
+// session and the artifact versions saved so far are deleted. The import
+// fails with [ErrInvalidBundle] if the bundle cannot be imported, and with
+// [ErrSessionExists] if the target session already exists.
+//
+// artifactService may be nil if the bundle has no artifacts or if
+// ImportRequest.SkipArtifacts is set.
+func Import(ctx context.Context, sessionService session.Service, artifactService artifact.Service, req *ImportRequest) (*ImportResponse, error) {
+	if sessionService == nil {
+		return nil, fmt.Errorf("session service is required")
+	}
+	if req == nil || req.Bundle == nil {
+		return nil, fmt.Errorf("import request with a bundle is required")
+	}
+	b := req.Bundle
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	importArtifacts := !req.SkipArtifacts && len(b.Artifacts) > 0
+	if importArtifacts && artifactService == nil {
+		return nil, fmt.Errorf("bundle has %d artifacts but no artifact service was provided", len(b.Artifacts))
+	}
+
+	appName := cmp.Or(req.AppName, b.Session.AppName)
+	userID := cmp.Or(req.UserID, b.Session.UserID)
+	sessionID := cmp.Or(req.SessionID, b.Session.ID)
+
+	// Session services report existing sessions with their own errors, so
+	// the target is looked up first to report conflicts consistently.
+	if _, err := sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID}); err == nil && sessionID != "" {
+		return nil, fmt.Errorf("%w: %q", ErrSessionExists, sessionID)
+	}
+	createResp, err := sessionService.Create(ctx, &session.CreateRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: sessionID,
+		State:     b.State.merge(req.SharedState),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	sess := createResp.Session
+
+	resp := &ImportResponse{ArtifactVersions: make(map[string]map[int64]int64)}
+	if err := importContents(ctx, sessionService, artifactService, sess, b, importArtifacts, req.SharedState, resp); err != nil {
+		rollback(ctx, sessionService, artifactService, sess, resp.ArtifactVersions)
+		return nil, err
+	}
+	resp.Session = sess
+	return resp, nil
+}
+
+// importContents saves the artifacts of b and appends its events to sess,
+// recording the new artifact versions in resp. The app: and user: keys of
+// the state deltas are dropped unless sharedState is set.
+func importContents(ctx context.Context, sessionService session.Service, artifactService artifact.Service, sess session.Session, b *Bundle, importArtifacts, sharedState bool, resp *ImportResponse) error {
+	if importArtifacts {
+		// Versions are saved in ascending order so the target assigns
+		// increasing version numbers in the same order as the source.
+		artifacts := slices.Clone(b.Artifacts)
+		slices.SortStableFunc(artifacts, func(a, b *Artifact) int {
+			return cmp.Or(strings.Compare(a.FileName, b.FileName), cmp.Compare(a.Version, b.Version))
+		})
+		for _, a := range artifacts {
+			saveResp, err := artifactService.Save(ctx, &artifact.SaveRequest{
+				AppName:   sess.AppName(),
+				UserID:    sess.UserID(),
+				SessionID: sess.ID(),
+				FileName:  a.FileName,
+				Part:      a.Part,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to save artifact %q version %d: %w", a.FileName, a.Version, err)
+			}
+			versions, ok := resp.ArtifactVersions[a.FileName]
+			if !ok {
+				versions = make(map[int64]int64)
+				resp.ArtifactVersions[a.FileName] = versions
+			}
+			versions[a.Version] = saveResp.Version
+		}
+	}
+
+	for _, e := range b.Events {
+		event := e.toSessionEvent()
+		if !sharedState {
+			maps.DeleteFunc(event.Actions.StateDelta, func(key string, _ any) bool {
+				return strings.HasPrefix(key, session.KeyPrefixApp) || strings.HasPrefix(key, session.KeyPrefixUser)
+			})
+		}
+		for fileName, version := range event.Actions.ArtifactDelta {
+			if newVersion, ok := resp.ArtifactVersions[fileName][version]; ok {
+				event.Actions.ArtifactDelta[fileName] = newVersion
+			}
+		}
+		if err := sessionService.AppendEvent(ctx, sess, event); err != nil {
+			return fmt.Errorf("failed to append event %q: %w", e.ID, err)
+		}
+	}
+	return nil
+}
+
+// rollback deletes a partially imported session and the artifact versions
+// saved for it. Errors are ignored, the import error is reported instead.
+func rollback(ctx context.Context, sessionService session.Service, artifactService artifact.Service, sess session.Session, artifactVersions map[string]map[int64]int64) {
+	for fileName, versions := range artifactVersions {
+		for _, version := range versions {
+			_ = artifactService.Delete(ctx, &artifact.DeleteRequest{
+				AppName:   sess.AppName(),
+				UserID:    sess.UserID(),
+				SessionID: sess.ID(),
+				FileName:  fileName,
+				Version:   version,
+			})
+		}
+	}
+	_ = sessionService.Delete(ctx, &session.DeleteRequest{
+		AppName:   sess.AppName(),
+		UserID:    sess.UserID(),
+		SessionID: sess.ID(),
+	})
+}
+
+func (b *Bundle) validate() error {
+	if b.FormatVersion <= 0 {
+		return fmt.Errorf("%w: missing format version", ErrInvalidBundle)
+	}
+	if b.FormatVersion > FormatVersion {
+		return fmt.Errorf("%w: unsupported format version %d, the latest supported version is %d", ErrInvalidBundle, b.FormatVersion, FormatVersion)
+	}
+	var errs []error
+	for i, e := range b.Events {
+		if e == nil {
+			errs = append(errs, fmt.Errorf("event %d is nil", i))
+		}
+	}
+	for i, a := range b.Artifacts {
+		if a == nil || a.FileName == "" || a.Part == nil {
+			errs = append(errs, fmt.Errorf("artifact %d is missing a file name or content", i))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidBundle, errors.Join(errs...))
+	}
+	return nil
+}
+
+// splitState splits the merged session state into its scopes.
+// Temporary keys are never persisted and are dropped.
+func splitState(state session.State) State {
+	s := State{
+		App:     make(map[string]any),
+		User:    make(map[string]any),
+		Session: make(map[string]any),
+	}
+	for key, value := range state.All() {
+		if k, ok := strings.CutPrefix(key, session.KeyPrefixApp); ok {
+			s.App[k] = value
+		} else if k, ok := strings.CutPrefix(key, session.KeyPrefixUser); ok {
+			s.User[k] = value
+		} else if !strings.HasPrefix(key, session.KeyPrefixTemp) {
+			s.Session[key] = value
+		}
+	}
+	return s
+}
+
+// merge returns the state as a single map with scope prefixes, in the form
+// expected by [session.CreateRequest]. The app and user state are left out
+// unless shared is set.
+func (s State) merge(shared bool) map[string]any {
+	merged := make(map[string]any, len(s.App)+len(s.User)+len(s.Session))
+	maps.Copy(merged, s.Session)
+	if !shared {
+		return merged
+	}
+	for k, v := range s.App {
+		merged[session.KeyPrefixApp+k] = v
+	}
+	for k, v := range s.User {
+		merged[session.KeyPrefixUser+k] = v
+	}
+	return merged
+}
+
+func fromSessionEvent(e *session.Event) *Event {
+	return &Event{
+		ID:                 e.ID,
+		Timestamp:          e.Timestamp,
+		InvocationID:       e.InvocationID,
+		Branch:             e.Branch,
+		Author:             e.Author,
+		LongRunningToolIDs: e.LongRunningToolIDs,
+		Content:            e.Content,
+		CitationMetadata:   e.CitationMetadata,
+		GroundingMetadata:  e.GroundingMetadata,
+		UsageMetadata:      e.UsageMetadata,
+		CustomMetadata:     e.CustomMetadata,
+		TurnComplete:       e.TurnComplete,
+		Interrupted:        e.Interrupted,
+		ErrorCode:          e.ErrorCode,
+		ErrorMessage:       e.ErrorMessage,
+		FinishReason:       e.FinishReason,
+		LogprobsResult:     e.LogprobsResult,
+		AvgLogprobs:        e.AvgLogprobs,
+		Actions: EventActions{
+			StateDelta:                 e.Actions.StateDelta,
+			ArtifactDelta:              e.Actions.ArtifactDelta,
+			RequestedToolConfirmations: e.Actions.RequestedToolConfirmations,
+			SkipSummarization:          e.Actions.SkipSummarization,
+			TransferToAgent:            e.Actions.TransferToAgent,
+			Escalate:                   e.Actions.Escalate,
+		},
+	}
+}
+
+// toSessionEvent returns a new [session.Event]. Maps are copied so the
+// bundle is not modified when the event is appended to a session.
+func (e *Event) toSessionEvent() *session.Event {
+	stateDelta := maps.Clone(e.Actions.StateDelta)
+	if stateDelta == nil {
+		stateDelta = make(map[string]any)
+	}
+	return &session.Event{
+		ID:                 e.ID,
+		Timestamp:          e.Timestamp,
+		InvocationID:       e.InvocationID,
+		Branch:             e.Branch,
+		Author:             e.Author,
+		LongRunningToolIDs: slices.Clone(e.LongRunningToolIDs),
+		LLMResponse: model.LLMResponse{
+			Content:           e.Content,
+			CitationMetadata:  e.CitationMetadata,
+			GroundingMetadata: e.GroundingMetadata,
+			UsageMetadata:     e.UsageMetadata,
+			CustomMetadata:    maps.Clone(e.CustomMetadata),
+			TurnComplete:      e.TurnComplete,
+			Interrupted:       e.Interrupted,
+			ErrorCode:         e.ErrorCode,
+			ErrorMessage:      e.ErrorMessage,
+			FinishReason:      e.FinishReason,
+			LogprobsResult:    e.LogprobsResult,
+			AvgLogprobs:       e.AvgLogprobs,
+		},
+		Actions: session.EventActions{
+			StateDelta:                 stateDelta,
+			ArtifactDelta:              maps.Clone(e.Actions.ArtifactDelta),
+			RequestedToolConfirmations: maps.Clone(e.Actions.RequestedToolConfirmations),
+			SkipSummarization:          e.Actions.SkipSummarization,
+			TransferToAgent:            e.Actions.TransferToAgent,
+			Escalate:                   e.Actions.Escalate,
+		},
+	}
+}
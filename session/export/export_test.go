@@ -0,0 +1,350 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/session/export"
+)
+
+const (
+	appName   = "testApp"
+	userID    = "testUser"
+	sessionID = "testSession"
+)
+
+func setupSource(t *testing.T) (session.Service, artifact.Service) {
+	t.Helper()
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	artifactService := artifact.InMemoryService()
+
+	created, err := sessionService.Create(ctx, &session.CreateRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: sessionID,
+		State:     map[string]any{"initial": "value", "app:shared": "a", "user:pref": "dark"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, content := range []string{"v1", "v2", "v3"} {
+		if _, err := artifactService.Save(ctx, &artifact.SaveRequest{
+			AppName: appName, UserID: userID, SessionID: sessionID, FileName: "report.txt",
+			Part: genai.NewPartFromBytes([]byte(content), "text/plain"),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Version 1 is deleted so the exported versions are not contiguous.
+	if err := artifactService.Delete(ctx, &artifact.DeleteRequest{
+		AppName: appName, UserID: userID, SessionID: sessionID, FileName: "report.txt", Version: 1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := artifactService.Save(ctx, &artifact.SaveRequest{
+		AppName: appName, UserID: userID, SessionID: sessionID, FileName: "user:profile.txt",
+		Part: genai.NewPartFromText("profile"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	events := []*session.Event{
+		{
+			ID:           "event1",
+			InvocationID: "inv1",
+			Author:       "user",
+			Timestamp:    time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+			LLMResponse: model.LLMResponse{
+				Content: genai.NewContentFromText("hello", genai.RoleUser),
+			},
+		},
+		{
+			ID:           "event2",
+			InvocationID: "inv1",
+			Author:       "agent",
+			Branch:       "root",
+			Timestamp:    time.Date(2025, 1, 1, 10, 0, 1, 0, time.UTC),
+			LLMResponse: model.LLMResponse{
+				Content:      genai.NewContentFromText("hi", genai.RoleModel),
+				TurnComplete: true,
+				AvgLogprobs:  -0.25,
+				LogprobsResult: &genai.LogprobsResult{
+					ChosenCandidates: []*genai.LogprobsResultCandidate{{Token: "hi", LogProbability: -0.25}},
+				},
+			},
+			Actions: session.EventActions{
+				StateDelta:    map[string]any{"counter": 1.0, "app:shared": "b", "temp:scratch": "x"},
+				ArtifactDelta: map[string]int64{"report.txt": 3},
+			},
+		},
+	}
+	for _, event := range events {
+		if err := sessionService.AppendEvent(ctx, created.Session, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return sessionService, artifactService
+}
+
+func TestExportImport(t *testing.T) {
+	for _, format := range []export.Format{export.FormatJSON, export.FormatJSONL} {
+		t.Run(string(format), func(t *testing.T) {
+			ctx := t.Context()
+			srcSessions, srcArtifacts := setupSource(t)
+
+			bundle, err := export.Export(ctx, srcSessions, srcArtifacts, &export.ExportRequest{
+				AppName: appName, UserID: userID, SessionID: sessionID,
+			})
+			if err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+
+			wantState := export.State{
+				App:     map[string]any{"shared": "b"},
+				User:    map[string]any{"pref": "dark"},
+				Session: map[string]any{"initial": "value", "counter": 1.0},
+			}
+			if diff := cmp.Diff(wantState, bundle.State); diff != "" {
+				t.Errorf("Export() state mismatch (-want +got):\n%s", diff)
+			}
+			var gotVersions []string
+			for _, a := range bundle.Artifacts {
+				gotVersions = append(gotVersions, fmt.Sprintf("%s@%d", a.FileName, a.Version))
+			}
+			if diff := cmp.Diff([]string{"report.txt@2", "report.txt@3", "user:profile.txt@1"}, gotVersions); diff != "" {
+				t.Errorf("Export() artifacts mismatch (-want +got):\n%s", diff)
+			}
+
+			var buf bytes.Buffer
+			if err := export.Encode(&buf, bundle, format); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			decoded, err := export.Decode(&buf, format)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if diff := cmp.Diff(bundle, decoded); diff != "" {
+				t.Errorf("Decode() mismatch (-want +got):\n%s", diff)
+			}
+
+			dstSessions := session.InMemoryService()
+			dstArtifacts := artifact.InMemoryService()
+			imported, err := export.Import(ctx, dstSessions, dstArtifacts, &export.ImportRequest{
+				Bundle:      decoded,
+				SessionID:   "imported",
+				SharedState: true,
+			})
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			wantVersions := map[string]map[int64]int64{
+				"report.txt":       {2: 1, 3: 2},
+				"user:profile.txt": {1: 1},
+			}
+			if diff := cmp.Diff(wantVersions, imported.ArtifactVersions); diff != "" {
+				t.Errorf("Import() artifact versions mismatch (-want +got):\n%s", diff)
+			}
+
+			got, err := dstSessions.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: "imported"})
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			gotState := maps.Collect(got.Session.State().All())
+			wantMergedState := map[string]any{"initial": "value", "counter": 1.0, "app:shared": "b", "user:pref": "dark"}
+			if diff := cmp.Diff(wantMergedState, gotState); diff != "" {
+				t.Errorf("imported state mismatch (-want +got):\n%s", diff)
+			}
+
+			srcSession, err := srcSessions.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var wantEvents []*session.Event
+			for e := range srcSession.Session.Events().All() {
+				wantEvents = append(wantEvents, e)
+			}
+			var gotEvents []*session.Event
+			for e := range got.Session.Events().All() {
+				gotEvents = append(gotEvents, e)
+			}
+			// The artifact delta points to the renumbered version.
+			wantEvents[1].Actions.ArtifactDelta = map[string]int64{"report.txt": 2}
+			if diff := cmp.Diff(wantEvents, gotEvents, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("imported events mismatch (-want +got):\n%s", diff)
+			}
+
+			loaded, err := dstArtifacts.Load(ctx, &artifact.LoadRequest{AppName: appName, UserID: userID, SessionID: "imported", FileName: "report.txt"})
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got, want := string(loaded.Part.InlineData.Data), "v3"; got != want {
+				t.Errorf("Load() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestImport_KeepsSharedState(t *testing.T) {
+	ctx := t.Context()
+	srcSessions, srcArtifacts := setupSource(t)
+	bundle, err := export.Export(ctx, srcSessions, srcArtifacts, &export.ExportRequest{
+		AppName: appName, UserID: userID, SessionID: sessionID,
+	})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	dstSessions := session.InMemoryService()
+	if _, err := dstSessions.Create(ctx, &session.CreateRequest{
+		AppName: appName, UserID: userID, SessionID: "current",
+		State: map[string]any{"app:shared": "current", "user:pref": "light"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := export.Import(ctx, dstSessions, artifact.InMemoryService(), &export.ImportRequest{
+		Bundle:    bundle,
+		SessionID: "imported",
+	}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	got, err := dstSessions.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: "imported"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	wantState := map[string]any{"initial": "value", "counter": 1.0, "app:shared": "current", "user:pref": "light"}
+	if diff := cmp.Diff(wantState, maps.Collect(got.Session.State().All())); diff != "" {
+		t.Errorf("imported state mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"counter": 1.0}, got.Session.Events().At(1).Actions.StateDelta); diff != "" {
+		t.Errorf("imported state delta mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExport_SessionNotFound(t *testing.T) {
+	_, err := export.Export(t.Context(), session.InMemoryService(), nil, &export.ExportRequest{
+		AppName: appName, UserID: userID, SessionID: "missing",
+	})
+	if !errors.Is(err, export.ErrSessionNotFound) {
+		t.Errorf("Export() error = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	ctx := t.Context()
+	srcSessions, srcArtifacts := setupSource(t)
+	bundle, err := export.Export(ctx, srcSessions, srcArtifacts, &export.ExportRequest{
+		AppName: appName, UserID: userID, SessionID: sessionID,
+	})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	t.Run("existing session", func(t *testing.T) {
+		if _, err := export.Import(ctx, srcSessions, srcArtifacts, &export.ImportRequest{Bundle: bundle}); !errors.Is(err, export.ErrSessionExists) {
+			t.Errorf("Import() error = %v, want ErrSessionExists", err)
+		}
+	})
+	t.Run("missing artifact service", func(t *testing.T) {
+		_, err := export.Import(ctx, session.InMemoryService(), nil, &export.ImportRequest{Bundle: bundle})
+		if err == nil || !strings.Contains(err.Error(), "artifact service") {
+			t.Errorf("Import() error = %v, want artifact service error", err)
+		}
+	})
+	t.Run("skip artifacts", func(t *testing.T) {
+		if _, err := export.Import(ctx, session.InMemoryService(), nil, &export.ImportRequest{Bundle: bundle, SkipArtifacts: true}); err != nil {
+			t.Errorf("Import() error = %v", err)
+		}
+	})
+	t.Run("newer format", func(t *testing.T) {
+		newer := *bundle
+		newer.FormatVersion = export.FormatVersion + 1
+		if _, err := export.Import(ctx, session.InMemoryService(), artifact.InMemoryService(), &export.ImportRequest{Bundle: &newer}); !errors.Is(err, export.ErrInvalidBundle) {
+			t.Errorf("Import() error = %v, want ErrInvalidBundle", err)
+		}
+	})
+	t.Run("rollback", func(t *testing.T) {
+		dstSessions := &failingAppendService{Service: session.InMemoryService()}
+		dstArtifacts := artifact.InMemoryService()
+		req := &export.ImportRequest{Bundle: bundle, SessionID: "imported"}
+		if _, err := export.Import(ctx, dstSessions, dstArtifacts, req); err == nil {
+			t.Fatal("Import() succeeded, want append error")
+		}
+		if _, err := dstSessions.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: "imported"}); err == nil {
+			t.Error("Get() found the partially imported session, want it deleted")
+		}
+		list, err := dstArtifacts.List(ctx, &artifact.ListRequest{AppName: appName, UserID: userID, SessionID: "imported"})
+		if err != nil || len(list.FileNames) != 0 {
+			t.Errorf("List() = %v, %v, want the imported artifacts deleted", list, err)
+		}
+
+		// The import can be retried once the failure is gone.
+		dstSessions.ok = true
+		if _, err := export.Import(ctx, dstSessions, dstArtifacts, req); err != nil {
+			t.Errorf("Import() retry error = %v", err)
+		}
+	})
+}
+
+// failingAppendService fails to append events unless ok is set.
+type failingAppendService struct {
+	session.Service
+	ok bool
+}
+
+func (s *failingAppendService) AppendEvent(ctx context.Context, sess session.Session, event *session.Event) error {
+	if !s.ok {
+		return errors.New("append failed")
+	}
+	return s.Service.AppendEvent(ctx, sess, event)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format export.Format
+	}{
+		{name: "jsonl without header", input: `{"kind":"event","event":{"id":"e1"}}`, format: export.FormatJSONL},
+		{name: "jsonl empty", input: ``, format: export.FormatJSONL},
+		{name: "jsonl unknown kind", input: `{"kind":"header","formatVersion":1}` + "\n" + `{"kind":"other"}`, format: export.FormatJSONL},
+		{name: "json missing version", input: `{"events":[]}`, format: export.FormatJSON},
+		{name: "unknown format", input: `{}`, format: export.Format("xml")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := export.Decode(strings.NewReader(tc.input), tc.format); err == nil {
+				t.Error("Decode() succeeded, want error")
+			}
+		})
+	}
+}
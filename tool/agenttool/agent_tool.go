@@ -20,6 +20,8 @@ package agenttool
 import (
 	"encoding/json"
 	"fmt"
+	"iter"
+	"maps"
 	"strings"
 
 	"google.golang.org/genai"
@@ -27,6 +29,7 @@ import (
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
 	"google.golang.org/adk/internal/llminternal"
+	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/model"
@@ -39,6 +42,13 @@ import (
 type agentTool struct {
 	agent             agent.Agent
 	skipSummarization bool
+
+	stateIn           Filter
+	stateOut          Filter
+	artifactsIn       Filter
+	artifactsOut      Filter
+	copyParentHistory bool
+	reportUsage       bool
 }
 
 // Config holds the configuration for an agent tool.
@@ -46,21 +56,85 @@ type Config struct {
 	// SkipSummarization, if true, will cause the agent to skip summarization
 	// after the sub-agent finishes execution.
 	SkipSummarization bool
+
+	// StateIn selects the keys of the parent session state copied to the
+	// session of the sub-agent. If nil, all the keys are copied except the
+	// internal ones prefixed with "_adk".
+	StateIn Filter
+	// StateOut selects the keys of the state changes of the sub-agent
+	// applied to the parent session state. If nil, the changes are
+	// discarded.
+	StateOut Filter
+	// ArtifactsIn selects the artifacts of the parent session whose latest
+	// version is copied to the artifacts of the sub-agent. If nil, the
+	// sub-agent starts without artifacts.
+	ArtifactsIn Filter
+	// ArtifactsOut selects the artifacts saved by the sub-agent whose
+	// latest version is saved to the parent session, which requires an
+	// artifact service. If nil, the artifacts of the sub-agent are
+	// discarded.
+	ArtifactsOut Filter
+
+	// CopyParentHistory copies the history of the parent session to the
+	// session of the sub-agent, so that it sees the conversation so far:
+	// the events of the branch of the calling agent are copied, without
+	// their actions. The sub-agent still runs in its own in-memory session,
+	// and its events are not added to the parent session.
+	CopyParentHistory bool
+	// StreamPartialEvents forwards the text of the partial events of the
+	// sub-agent to the parent event stream while it runs, as partial
+	// function responses with the "author" and "text" of the event. Only
+	// the final result is sent to the model.
+	StreamPartialEvents bool
+	// ReportUsage adds the token usage of the model calls of the sub-agent
+	// to the result, under UsageKey.
+	ReportUsage bool
+}
+
+// UsageKey is the key of the token usage of the sub-agent in the result of
+// the tool, a *genai.GenerateContentResponseUsageMetadata, when
+// [Config.ReportUsage] is set.
+const UsageKey = "usage_metadata"
+
+// Filter selects state keys or artifact names.
+type Filter func(name string) bool
+
+// Names returns a Filter selecting the given names.
+func Names(names ...string) Filter {
+	m := make(map[string]bool, len(names))
+	for _, name := range names {
+		m[name] = true
+	}
+	return func(name string) bool {
+		return m[name]
+	}
+}
+
+// All is a Filter selecting all names.
+func All(string) bool {
+	return true
 }
 
 // New creates a new agent tool.
 // If cfg is nil, skipSummarization defaults to false.
 func New(agent agent.Agent, cfg *Config) tool.Tool {
 	if cfg == nil {
-		return &agentTool{
-			agent:             agent,
-			skipSummarization: false,
-		}
+		cfg = &Config{}
 	}
-	return &agentTool{
+	t := &agentTool{
 		agent:             agent,
 		skipSummarization: cfg.SkipSummarization,
+		stateIn:           cfg.StateIn,
+		stateOut:          cfg.StateOut,
+		artifactsIn:       cfg.ArtifactsIn,
+		artifactsOut:      cfg.ArtifactsOut,
+		copyParentHistory: cfg.CopyParentHistory,
+		reportUsage:       cfg.ReportUsage,
+	}
+	if cfg.StreamPartialEvents {
+		return &streamingAgentTool{agentTool: t}
 	}
+	return t
 }
 
 // Name implements tool.Tool.
@@ -118,6 +192,13 @@ func (t *agentTool) Declaration() *genai.FunctionDeclaration {
 // It creates a new session for the sub-agent, runs the agent, and returns
 // the final result.
 func (t *agentTool) Run(toolCtx tool.Context, args any) (map[string]any, error) {
+	return t.run(toolCtx, args, nil)
+}
+
+// run runs the wrapped agent, passing the text of its partial events to
+// partial if not nil. It stops and returns nil results when partial
+// returns false.
+func (t *agentTool) run(toolCtx tool.Context, args any, partial func(map[string]any) bool) (map[string]any, error) {
 	margs, ok := args.(map[string]any)
 	if !ok {
 		return nil, fmt.Errorf("agentTool expects map[string]any arguments, got %T", args)
@@ -165,13 +246,14 @@ func (t *agentTool) Run(toolCtx tool.Context, args any) (map[string]any, error)
 	}
 
 	sessionService := session.InMemoryService()
+	artifactService := artifact.InMemoryService()
 
 	r, err := runner.New(runner.Config{
 		AppName:        t.agent.Name(),
 		Agent:          t.agent,
 		SessionService: sessionService,
 		// TODO - use forwarding_artifact_service as in python.
-		ArtifactService: artifact.InMemoryService(),
+		ArtifactService: artifactService,
 		MemoryService:   memory.InMemoryService(),
 	})
 	if err != nil {
@@ -181,10 +263,11 @@ func (t *agentTool) Run(toolCtx tool.Context, args any) (map[string]any, error)
 	stateMap := make(map[string]any)
 
 	for k, v := range toolCtx.State().All() {
-		// Filter out adk internal states.
-		if !strings.HasPrefix(k, "_adk") {
-			stateMap[k] = v
+		// Filter out adk internal states unless selected otherwise.
+		if (t.stateIn != nil && !t.stateIn(k)) || (t.stateIn == nil && strings.HasPrefix(k, "_adk")) {
+			continue
 		}
+		stateMap[k] = v
 	}
 
 	subSession, err := sessionService.Create(toolCtx, &session.CreateRequest{
@@ -195,6 +278,14 @@ func (t *agentTool) Run(toolCtx tool.Context, args any) (map[string]any, error)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session for sub-agent %s: %w", t.agent.Name(), err)
 	}
+	if t.copyParentHistory {
+		if err := t.copyHistory(toolCtx, sessionService, subSession.Session); err != nil {
+			return nil, err
+		}
+	}
+	if err := t.copyArtifactsIn(toolCtx, artifactService, subSession.Session); err != nil {
+		return nil, err
+	}
 
 	// TODO(dpasiukevich): verify agent loop termination.
 	eventCh := r.Run(toolCtx, subSession.Session.UserID(), subSession.Session.ID(), content, agent.RunConfig{
@@ -202,6 +293,9 @@ func (t *agentTool) Run(toolCtx tool.Context, args any) (map[string]any, error)
 	})
 
 	var lastEvent *session.Event
+	stateDelta := make(map[string]any)
+	artifactDelta := make(map[string]bool)
+	var usage *genai.GenerateContentResponseUsageMetadata
 	for event, err := range eventCh {
 		if err != nil {
 			return nil, fmt.Errorf("error during execution of sub-agent %s: %w", t.agent.Name(), err)
@@ -212,29 +306,59 @@ func (t *agentTool) Run(toolCtx tool.Context, args any) (map[string]any, error)
 		if event.LLMResponse.Content != nil {
 			lastEvent = event
 		}
+		if event.Partial {
+			if text := contentText(event.LLMResponse.Content); partial != nil && text != "" {
+				if !partial(map[string]any{"author": event.Author, "text": text}) {
+					return nil, nil
+				}
+			}
+			continue
+		}
+		maps.Copy(stateDelta, event.Actions.StateDelta)
+		for name := range event.Actions.ArtifactDelta {
+			artifactDelta[name] = true
+		}
+		if t.reportUsage {
+			usage = addUsage(usage, event.UsageMetadata)
+		}
 	}
 
+	if t.stateOut != nil {
+		for k, v := range stateDelta {
+			if !strings.HasPrefix(k, "_adk") && t.stateOut(k) {
+				if err := toolCtx.State().Set(k, v); err != nil {
+					return nil, fmt.Errorf("failed to set state %q from sub-agent %s: %w", k, t.agent.Name(), err)
+				}
+			}
+		}
+	}
+	if err := t.copyArtifactsOut(toolCtx, artifactService, subSession.Session, artifactDelta); err != nil {
+		return nil, err
+	}
+
+	result, err := t.result(lastEvent)
+	if err != nil {
+		return nil, err
+	}
+	if usage != nil {
+		result[UsageKey] = usage
+	}
+	return result, nil
+}
+
+// result returns the result of the tool from the last event of the
+// sub-agent.
+func (t *agentTool) result(lastEvent *session.Event) (map[string]any, error) {
 	if lastEvent == nil {
 		return map[string]any{}, nil
 	}
 
-	lastContent := lastEvent.LLMResponse.Content
-	var textParts []string
-	for _, part := range lastContent.Parts {
-		if part != nil && part.Text != "" {
-			textParts = append(textParts, part.Text)
-		}
-	}
-	outputText := strings.Join(textParts, "\n")
+	outputText := contentText(lastEvent.LLMResponse.Content)
 
 	if outputText == "" {
 		return map[string]any{}, nil
 	}
-	if isLllmAgent {
-		internalLlmAgent, ok := t.agent.(llminternal.Agent)
-		if !ok {
-			return nil, fmt.Errorf("internal error: failed to convert to llm agent")
-		}
+	if internalLlmAgent, ok := t.agent.(llminternal.Agent); ok && internalLlmAgent != nil {
 		if agentOutputSchema := llminternal.Reveal(internalLlmAgent).OutputSchema; agentOutputSchema != nil {
 			// Assuming schemautils.ValidateOutputSchema parses the JSON string outputText
 			// and validates it against the agentOutputSchema, returning a map[string]any.
@@ -285,3 +409,158 @@ func (t *agentTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) erro
 	}
 	return nil
 }
+
+// streamingAgentTool is an agent tool forwarding the partial events of the
+// sub-agent.
+type streamingAgentTool struct {
+	*agentTool
+}
+
+// RunStream runs the wrapped agent, yielding the text of its partial events
+// and then the final result.
+func (t *streamingAgentTool) RunStream(toolCtx tool.Context, args any) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		stopped := false
+		result, err := t.run(toolCtx, args, func(partial map[string]any) bool {
+			stopped = !yield(partial, nil)
+			return !stopped
+		})
+		if !stopped {
+			yield(result, err)
+		}
+	}
+}
+
+// ProcessRequest adds the agent tool's function declaration to the LLM
+// request, registering the streaming tool as the tool to call.
+func (t *streamingAgentTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	if err := t.agentTool.ProcessRequest(ctx, req); err != nil {
+		return err
+	}
+	req.Tools[t.Name()] = t
+	return nil
+}
+
+// copyHistory appends the events of the parent session on the branch of
+// the calling agent to the session of the sub-agent. The actions of the
+// events are dropped, so that the state of the sub-agent is not changed.
+func (t *agentTool) copyHistory(toolCtx tool.Context, sessionService session.Service, subSession session.Session) error {
+	invCtx := toolinternal.InvocationContext(toolCtx)
+	if invCtx == nil || invCtx.Session() == nil {
+		return fmt.Errorf("no parent session to copy the history of to sub-agent %s", t.agent.Name())
+	}
+	for ev := range invCtx.Session().Events().All() {
+		if ev.Partial || !eventBelongsToBranch(invCtx.Branch(), ev) {
+			continue
+		}
+		copied := *ev
+		copied.Actions = session.EventActions{}
+		if err := sessionService.AppendEvent(toolCtx, subSession, &copied); err != nil {
+			return fmt.Errorf("failed to copy the parent session for sub-agent %s: %w", t.agent.Name(), err)
+		}
+	}
+	return nil
+}
+
+// eventBelongsToBranch reports whether an event is visible on a branch,
+// like the events added to the contents of a request.
+func eventBelongsToBranch(branch string, ev *session.Event) bool {
+	return branch == "" || ev.Branch == "" || ev.Branch == branch || strings.HasPrefix(branch, ev.Branch+".")
+}
+
+// copyArtifactsIn copies the latest version of the parent artifacts
+// selected by artifactsIn to the artifacts of the sub-agent.
+func (t *agentTool) copyArtifactsIn(toolCtx tool.Context, artifactService artifact.Service, subSession session.Session) error {
+	if t.artifactsIn == nil || toolCtx.Artifacts() == nil {
+		return nil
+	}
+	list, err := toolCtx.Artifacts().List(toolCtx)
+	if err != nil {
+		return fmt.Errorf("failed to list artifacts for sub-agent %s: %w", t.agent.Name(), err)
+	}
+	for _, name := range list.FileNames {
+		if !t.artifactsIn(name) {
+			continue
+		}
+		loaded, err := toolCtx.Artifacts().Load(toolCtx, name)
+		if err != nil {
+			return fmt.Errorf("failed to load artifact %q for sub-agent %s: %w", name, t.agent.Name(), err)
+		}
+		_, err = artifactService.Save(toolCtx, &artifact.SaveRequest{
+			AppName:   subSession.AppName(),
+			UserID:    subSession.UserID(),
+			SessionID: subSession.ID(),
+			FileName:  name,
+			Part:      loaded.Part,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy artifact %q to sub-agent %s: %w", name, t.agent.Name(), err)
+		}
+	}
+	return nil
+}
+
+// copyArtifactsOut saves the latest version of the artifacts saved by the
+// sub-agent and selected by artifactsOut to the parent session.
+func (t *agentTool) copyArtifactsOut(toolCtx tool.Context, artifactService artifact.Service, subSession session.Session, saved map[string]bool) error {
+	if t.artifactsOut == nil {
+		return nil
+	}
+	for name := range saved {
+		if !t.artifactsOut(name) {
+			continue
+		}
+		if toolCtx.Artifacts() == nil {
+			return fmt.Errorf("no artifact service to save artifact %q of sub-agent %s", name, t.agent.Name())
+		}
+		loaded, err := artifactService.Load(toolCtx, &artifact.LoadRequest{
+			AppName:   subSession.AppName(),
+			UserID:    subSession.UserID(),
+			SessionID: subSession.ID(),
+			FileName:  name,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load artifact %q of sub-agent %s: %w", name, t.agent.Name(), err)
+		}
+		if _, err := toolCtx.Artifacts().Save(toolCtx, name, loaded.Part); err != nil {
+			return fmt.Errorf("failed to save artifact %q of sub-agent %s: %w", name, t.agent.Name(), err)
+		}
+	}
+	return nil
+}
+
+// contentText returns the text parts of c joined by newlines.
+func contentText(c *genai.Content) string {
+	if c == nil {
+		return ""
+	}
+	var textParts []string
+	for _, part := range c.Parts {
+		if part != nil && part.Text != "" {
+			textParts = append(textParts, part.Text)
+		}
+	}
+	return strings.Join(textParts, "\n")
+}
+
+// addUsage adds the token counts of u to total, which is allocated if nil.
+func addUsage(total, u *genai.GenerateContentResponseUsageMetadata) *genai.GenerateContentResponseUsageMetadata {
+	if u == nil {
+		return total
+	}
+	if total == nil {
+		total = &genai.GenerateContentResponseUsageMetadata{}
+	}
+	total.PromptTokenCount += u.PromptTokenCount
+	total.CandidatesTokenCount += u.CandidatesTokenCount
+	total.CachedContentTokenCount += u.CachedContentTokenCount
+	total.ThoughtsTokenCount += u.ThoughtsTokenCount
+	total.ToolUsePromptTokenCount += u.ToolUsePromptTokenCount
+	total.TotalTokenCount += u.TotalTokenCount
+	return total
+}
+
+var (
+	_ toolinternal.FunctionTool          = (*agentTool)(nil)
+	_ toolinternal.StreamingFunctionTool = (*streamingAgentTool)(nil)
+)
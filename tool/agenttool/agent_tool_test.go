@@ -15,6 +15,7 @@
 package agenttool_test
 
 import (
+	"iter"
 	"log"
 	"testing"
 
@@ -23,6 +24,8 @@ import (
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	iartifact "google.golang.org/adk/internal/artifact"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/testutil"
 	"google.golang.org/adk/internal/toolinternal"
@@ -366,3 +369,156 @@ func createToolContext(t *testing.T, testAgent agent.Agent) tool.Context {
 
 	return toolinternal.NewToolContext(ctx, "", &session.EventActions{}, nil)
 }
+
+// newRecordingAgent returns an agent which records the state, artifacts and
+// number of events of its session, saves the "out.txt" and "private.txt"
+// artifacts and yields a partial event, then a final event setting the
+// "answer" and "private" state keys.
+func newRecordingAgent(t *testing.T, seen map[string]any) agent.Agent {
+	t.Helper()
+	a, err := agent.New(agent.Config{
+		Name:        "recorder",
+		Description: "Records its context.",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				for k, v := range ctx.Session().State().All() {
+					seen["state:"+k] = v
+				}
+				seen["events"] = ctx.Session().Events().Len()
+				list, err := ctx.Artifacts().List(ctx)
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				seen["artifacts"] = list.FileNames
+				for _, name := range []string{"out.txt", "private.txt"} {
+					if _, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromText(name)); err != nil {
+						yield(nil, err)
+						return
+					}
+				}
+
+				partial := session.NewEvent(ctx.InvocationID())
+				partial.Author = "recorder"
+				partial.Partial = true
+				partial.LLMResponse.Content = genai.NewContentFromText("work", genai.RoleModel)
+				if !yield(partial, nil) {
+					return
+				}
+				final := session.NewEvent(ctx.InvocationID())
+				final.Author = "recorder"
+				final.LLMResponse.Content = genai.NewContentFromText("done", genai.RoleModel)
+				final.LLMResponse.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 3, CandidatesTokenCount: 2, TotalTokenCount: 5}
+				final.Actions.StateDelta = map[string]any{"answer": 42, "private": true}
+				final.Actions.ArtifactDelta = map[string]int64{"out.txt": 0, "private.txt": 0}
+				yield(final, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestAgentTool_Run_Propagation(t *testing.T) {
+	sessionService := session.InMemoryService()
+	created, err := sessionService.Create(t.Context(), &session.CreateRequest{
+		AppName:   "testApp",
+		UserID:    "testUser",
+		SessionID: "testSession",
+		State:     map[string]any{"shared": "yes", "secret": "no"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ev := range []*session.Event{
+		{Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("hello", genai.RoleUser)}},
+		{Author: "other", Branch: "root.other", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("hidden", genai.RoleModel)}},
+	} {
+		if err := sessionService.AppendEvent(t.Context(), created.Session, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	artifacts := &iartifact.Artifacts{Service: artifact.InMemoryService(), AppName: "testApp", UserID: "testUser", SessionID: "testSession"}
+	for _, name := range []string{"in.txt", "ignored.txt"} {
+		if _, err := artifacts.Save(t.Context(), name, genai.NewPartFromText(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	parent, err := agent.New(agent.Config{Name: "root"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Agent:     parent,
+		Session:   created.Session,
+		Artifacts: artifacts,
+		Branch:    "root",
+	})
+	actions := &session.EventActions{}
+	toolCtx := toolinternal.NewToolContext(invCtx, "call1", actions, nil)
+
+	seen := map[string]any{}
+	agentTool := agenttool.New(newRecordingAgent(t, seen), &agenttool.Config{
+		StateIn:             agenttool.Names("shared"),
+		StateOut:            agenttool.Names("answer"),
+		ArtifactsIn:         agenttool.Names("in.txt"),
+		ArtifactsOut:        agenttool.Names("out.txt"),
+		CopyParentHistory:   true,
+		StreamPartialEvents: true,
+		ReportUsage:         true,
+	})
+	toolImpl, ok := agentTool.(toolinternal.StreamingFunctionTool)
+	if !ok {
+		t.Fatal("agentTool does not implement StreamingFunctionTool")
+	}
+
+	var results []map[string]any
+	for result, err := range toolImpl.RunStream(toolCtx, map[string]any{"request": "go"}) {
+		if err != nil {
+			t.Fatalf("RunStream() failed: %v", err)
+		}
+		results = append(results, result)
+	}
+	want := []map[string]any{
+		{"author": "recorder", "text": "work"},
+		{"result": "done", agenttool.UsageKey: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 3, CandidatesTokenCount: 2, TotalTokenCount: 5}},
+	}
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Errorf("RunStream() results mismatch (-want +got):\n%s", diff)
+	}
+
+	// The sub-agent sees the selected state and artifacts, and the parent
+	// events of its branch followed by the request.
+	wantSeen := map[string]any{"state:shared": "yes", "artifacts": []string{"in.txt"}, "events": 2}
+	if diff := cmp.Diff(wantSeen, seen); diff != "" {
+		t.Errorf("sub-agent context mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"answer": 42}, actions.StateDelta); diff != "" {
+		t.Errorf("StateDelta mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]int64{"out.txt": 1}, actions.ArtifactDelta); diff != "" {
+		t.Errorf("ArtifactDelta mismatch (-want +got):\n%s", diff)
+	}
+
+	// By default, the state changes and artifacts of the sub-agent are
+	// discarded and the sub-agent runs in an isolated session.
+	seen = map[string]any{}
+	actions = &session.EventActions{}
+	toolCtx = toolinternal.NewToolContext(invCtx, "call2", actions, nil)
+	result, err := agenttool.New(newRecordingAgent(t, seen), nil).(toolinternal.FunctionTool).Run(toolCtx, map[string]any{"request": "go"})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"result": "done"}, result); diff != "" {
+		t.Errorf("Run() result mismatch (-want +got):\n%s", diff)
+	}
+	wantSeen = map[string]any{"state:shared": "yes", "state:secret": "no", "state:answer": 42, "artifacts": []string(nil), "events": 1}
+	if diff := cmp.Diff(wantSeen, seen); diff != "" {
+		t.Errorf("sub-agent context mismatch (-want +got):\n%s", diff)
+	}
+	if len(actions.StateDelta) != 0 || len(actions.ArtifactDelta) != 0 {
+		t.Errorf("Run() changed the parent: state delta %v, artifact delta %v", actions.StateDelta, actions.ArtifactDelta)
+	}
+}
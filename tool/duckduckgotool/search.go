@@ -29,7 +29,6 @@ package duckduckgotool
 
 import (
 	"fmt"
-	"html"
 	"io"
 	"net/http"
 	"net/url"
@@ -39,6 +38,7 @@ import (
 
 	"google.golang.org/genai"
 
+	"google.golang.org/adk/internal/htmltext"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
 )
@@ -239,10 +239,6 @@ var (
 	hrefRe = regexp.MustCompile(`href="([^"]*)"`)
 	// Extracts the actual URL from DuckDuckGo's redirect parameter.
 	uddgParamRe = regexp.MustCompile(`[?&]uddg=([^&]*)`)
-	// Matches HTML tags for stripping.
-	htmlTagRe = regexp.MustCompile(`<[^>]*>`)
-	// Collapses runs of whitespace.
-	whitespaceRe = regexp.MustCompile(`\s+`)
 )
 
 // parseResults extracts search results from DuckDuckGo HTML.
@@ -326,8 +322,5 @@ func extractURL(rawURL string) string {
 
 // stripHTML removes HTML tags and decodes HTML entities.
 func stripHTML(s string) string {
-	s = htmlTagRe.ReplaceAllString(s, "")
-	s = html.UnescapeString(s)
-	s = whitespaceRe.ReplaceAllString(s, " ")
-	return strings.TrimSpace(s)
+	return htmltext.Text(s)
 }
@@ -0,0 +1,247 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesystemtoolset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/adk/tool"
+)
+
+// defaultReadLimit is the number of lines read_file returns by default.
+const defaultReadLimit = 2000
+
+type readFileArgs struct {
+	Path   string `json:"path" jsonschema:"path of the file, relative to the root directory"`
+	Offset int    `json:"offset,omitempty" jsonschema:"line number to start reading from, starting at 1"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"maximum number of lines to read (default: 2000)"`
+}
+
+type readFileResult struct {
+	Path       string `json:"path"`
+	Content    string `json:"content"`
+	TotalLines int    `json:"total_lines"`
+	Truncated  bool   `json:"truncated,omitempty"`
+}
+
+func (f *filesystem) readFile(ctx tool.Context, args readFileArgs) (readFileResult, error) {
+	p, err := f.relPath(args.Path)
+	if err != nil {
+		return readFileResult{}, err
+	}
+	if args.Offset < 0 || args.Limit < 0 {
+		return readFileResult{}, errors.New("offset and limit must not be negative")
+	}
+	root, err := f.openRoot()
+	if err != nil {
+		return readFileResult{}, err
+	}
+	defer root.Close()
+
+	data, err := f.read(root, p)
+	if err != nil {
+		return readFileResult{}, err
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	start := max(args.Offset, 1) - 1
+	limit := args.Limit
+	if limit == 0 {
+		limit = defaultReadLimit
+	}
+	end := min(start+limit, len(lines))
+	start = min(start, end)
+	return readFileResult{
+		Path:       p,
+		Content:    strings.Join(lines[start:end], ""),
+		TotalLines: len(lines),
+		Truncated:  end < len(lines),
+	}, nil
+}
+
+// read returns the content of a text file no larger than the size limit.
+func (f *filesystem) read(root *os.Root, p string) ([]byte, error) {
+	file, err := root.Open(filepath.FromSlash(p))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %q: %w", p, unwrapPathError(err))
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %q: %w", p, unwrapPathError(err))
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%q is a directory", p)
+	}
+	if info.Size() > f.maxFileSize {
+		return nil, fmt.Errorf("%q is %d bytes, larger than the limit of %d bytes", p, info.Size(), f.maxFileSize)
+	}
+	data, err := io.ReadAll(io.LimitReader(file, f.maxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", p, unwrapPathError(err))
+	}
+	if int64(len(data)) > f.maxFileSize {
+		return nil, fmt.Errorf("%q is larger than the limit of %d bytes", p, f.maxFileSize)
+	}
+	if isBinary(data) {
+		return nil, fmt.Errorf("%q is a binary file", p)
+	}
+	return data, nil
+}
+
+type writeFileArgs struct {
+	Path    string `json:"path" jsonschema:"path of the file, relative to the root directory"`
+	Content string `json:"content" jsonschema:"the new content of the file"`
+}
+
+type writeFileResult struct {
+	Path         string `json:"path"`
+	BytesWritten int    `json:"bytes_written"`
+}
+
+func (f *filesystem) writeFile(ctx tool.Context, args writeFileArgs) (writeFileResult, error) {
+	p, err := f.relPath(args.Path)
+	if err != nil {
+		return writeFileResult{}, err
+	}
+	if p == "." {
+		return writeFileResult{}, errors.New("missing required parameter: path")
+	}
+	if int64(len(args.Content)) > f.maxFileSize {
+		return writeFileResult{}, fmt.Errorf("content is %d bytes, larger than the limit of %d bytes", len(args.Content), f.maxFileSize)
+	}
+	root, err := f.openRoot()
+	if err != nil {
+		return writeFileResult{}, err
+	}
+	defer root.Close()
+
+	if err := write(root, p, []byte(args.Content)); err != nil {
+		return writeFileResult{}, err
+	}
+	return writeFileResult{Path: p, BytesWritten: len(args.Content)}, nil
+}
+
+// write writes a file, creating its missing parent directories.
+func write(root *os.Root, p string, data []byte) error {
+	if err := mkdirAll(root, path.Dir(p)); err != nil {
+		return err
+	}
+	file, err := root.OpenFile(filepath.FromSlash(p), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", p, unwrapPathError(err))
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %q: %w", p, unwrapPathError(err))
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %q: %w", p, unwrapPathError(err))
+	}
+	return nil
+}
+
+func mkdirAll(root *os.Root, dir string) error {
+	if dir == "." {
+		return nil
+	}
+	var cur string
+	for _, elem := range strings.Split(dir, "/") {
+		cur = path.Join(cur, elem)
+		if err := root.Mkdir(filepath.FromSlash(cur), 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("failed to create directory %q: %w", cur, unwrapPathError(err))
+		}
+	}
+	return nil
+}
+
+type listDirArgs struct {
+	Path string `json:"path,omitempty" jsonschema:"path of the directory, relative to the root directory (default: the root directory)"`
+}
+
+type dirEntry struct {
+	Name string `json:"name"`
+	// Type is "file", "dir" or "symlink".
+	Type string `json:"type"`
+	Size int64  `json:"size,omitempty"`
+}
+
+type listDirResult struct {
+	Path      string     `json:"path"`
+	Entries   []dirEntry `json:"entries"`
+	Truncated bool       `json:"truncated,omitempty"`
+}
+
+func (f *filesystem) listDir(ctx tool.Context, args listDirArgs) (listDirResult, error) {
+	p, err := f.relPath(args.Path)
+	if err != nil {
+		return listDirResult{}, err
+	}
+	root, err := f.openRoot()
+	if err != nil {
+		return listDirResult{}, err
+	}
+	defer root.Close()
+
+	entries, err := fs.ReadDir(root.FS(), p)
+	if err != nil {
+		return listDirResult{}, fmt.Errorf("failed to list %q: %w", p, unwrapPathError(err))
+	}
+	result := listDirResult{Path: p, Entries: []dirEntry{}}
+	for _, e := range entries {
+		if len(result.Entries) == f.maxResults {
+			result.Truncated = true
+			break
+		}
+		entry := dirEntry{Name: e.Name(), Type: "file"}
+		switch {
+		case e.Type()&fs.ModeSymlink != 0:
+			entry.Type = "symlink"
+		case e.IsDir():
+			entry.Type = "dir"
+		default:
+			if info, err := e.Info(); err == nil {
+				entry.Size = info.Size()
+			}
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+	return result, nil
+}
+
+// isBinary reports whether data looks like the content of a binary file.
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0
+}
+
+// unwrapPathError removes the path of a *fs.PathError, which is absolute or
+// relative to the root depending on the call, from error messages.
+func unwrapPathError(err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+	return err
+}
@@ -0,0 +1,385 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesystemtoolset
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/adk/tool"
+)
+
+const devNull = "/dev/null"
+
+// filePatch is the diff of a file of a unified diff.
+type filePatch struct {
+	// oldPath and newPath are devNull for created and deleted files.
+	oldPath, newPath string
+	hunks            []hunk
+}
+
+type hunk struct {
+	oldStart int
+	// lines are the lines of the hunk with their ' ', '-' or '+' prefix.
+	lines []string
+	// noNewline reports whether the new file does not end with a newline.
+	noNewline bool
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// parsePatch parses a unified diff. It tolerates the missing space prefix
+// of empty context lines and wrong line counts in hunk headers, which are
+// common in diffs written by models; the context lines of the hunks are
+// checked when the patch is applied.
+func parsePatch(patch string) ([]filePatch, error) {
+	lines := strings.Split(strings.TrimSuffix(strings.ReplaceAll(patch, "\r\n", "\n"), "\n"), "\n")
+	var patches []filePatch
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "--- ") {
+			continue
+		}
+		if i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "+++ ") {
+			return nil, fmt.Errorf("line %d: missing +++ line after --- line", i+1)
+		}
+		fp := filePatch{
+			oldPath: diffPath(lines[i][4:]),
+			newPath: diffPath(lines[i+1][4:]),
+		}
+		if fp.oldPath == devNull && fp.newPath == devNull {
+			return nil, fmt.Errorf("line %d: both files are %s", i+1, devNull)
+		}
+		i += 2
+		for i < len(lines) && strings.HasPrefix(lines[i], "@@") {
+			m := hunkHeaderRe.FindStringSubmatch(lines[i])
+			if m == nil {
+				return nil, fmt.Errorf("line %d: invalid hunk header %q", i+1, lines[i])
+			}
+			oldStart, _ := strconv.Atoi(m[1])
+			oldCount, newCount := hunkCount(m[2]), hunkCount(m[4])
+			if oldCount == 0 {
+				// Hunks without old lines insert after the line oldStart.
+				oldStart++
+			}
+			h := hunk{oldStart: oldStart}
+			for i++; i < len(lines); i++ {
+				line := lines[i]
+				if oldCount <= 0 && newCount <= 0 && !strings.HasPrefix(line, `\`) {
+					break
+				}
+				if line == "" {
+					line = " "
+				}
+				switch line[0] {
+				case ' ':
+					oldCount--
+					newCount--
+				case '-':
+					oldCount--
+				case '+':
+					newCount--
+				case '\\':
+					// "\ No newline at end of file" after a line of the new
+					// file.
+					if n := len(h.lines); n > 0 && h.lines[n-1][0] != '-' {
+						h.noNewline = true
+					}
+					continue
+				}
+				if !strings.ContainsRune(" -+", rune(line[0])) {
+					break
+				}
+				h.lines = append(h.lines, line)
+			}
+			fp.hunks = append(fp.hunks, h)
+		}
+		if len(fp.hunks) == 0 && fp.newPath != devNull {
+			return nil, fmt.Errorf("patch of %q has no hunks", fp.newPath)
+		}
+		patches = append(patches, fp)
+		i--
+	}
+	if len(patches) == 0 {
+		return nil, errors.New("patch contains no file diffs in unified diff format")
+	}
+	return patches, nil
+}
+
+// diffPath returns the path of a ---/+++ line, without the a/ and b/
+// prefixes of git diffs and the timestamp of diff -u.
+func diffPath(s string) string {
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	if s == devNull {
+		return s
+	}
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		return s[2:]
+	}
+	return s
+}
+
+// hunkCount returns the line count of a hunk header, which is 1 if omitted.
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// apply applies the hunks to the content of a file.
+func (fp *filePatch) apply(content string) (string, error) {
+	crlf := strings.Contains(content, "\r\n")
+	if crlf {
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+	}
+	newline := content == "" || strings.HasSuffix(content, "\n")
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+
+	var out []string
+	// pos is the position in lines after the previous hunk.
+	pos := 0
+	for n, h := range fp.hunks {
+		var old, repl []string
+		for _, line := range h.lines {
+			if line[0] != '+' {
+				old = append(old, line[1:])
+			}
+			if line[0] != '-' {
+				repl = append(repl, line[1:])
+			}
+		}
+		at := findLines(lines, old, pos, max(h.oldStart-1, 0))
+		if at < 0 {
+			return "", fmt.Errorf("hunk %d (@@ -%d) does not match the content of the file", n+1, h.oldStart)
+		}
+		out = append(out, lines[pos:at]...)
+		out = append(out, repl...)
+		pos = at + len(old)
+		if h.noNewline {
+			newline = false
+		}
+	}
+	out = append(out, lines[pos:]...)
+
+	result := strings.Join(out, "\n")
+	if newline && len(out) > 0 {
+		result += "\n"
+	}
+	if crlf {
+		result = strings.ReplaceAll(result, "\n", "\r\n")
+	}
+	return result, nil
+}
+
+// findLines returns the position of old in lines at or after start which is
+// the closest to want, or -1 if it is not found. Lines are compared exactly
+// first, and then ignoring trailing whitespace.
+func findLines(lines, old []string, start, want int) int {
+	if len(old) == 0 {
+		return min(max(want, start), len(lines))
+	}
+	for _, equal := range []func(a, b string) bool{
+		func(a, b string) bool { return a == b },
+		func(a, b string) bool { return strings.TrimRight(a, " \t") == strings.TrimRight(b, " \t") },
+	} {
+		matches := func(at int) bool {
+			if at < start || at+len(old) > len(lines) {
+				return false
+			}
+			for i, line := range old {
+				if !equal(lines[at+i], line) {
+					return false
+				}
+			}
+			return true
+		}
+		for d := 0; want-d >= start || want+d < len(lines); d++ {
+			if matches(want - d) {
+				return want - d
+			}
+			if matches(want + d) {
+				return want + d
+			}
+		}
+	}
+	return -1
+}
+
+type applyPatchArgs struct {
+	Patch string `json:"patch" jsonschema:"the patch in unified diff format"`
+}
+
+type changedFile struct {
+	Path string `json:"path"`
+	// Operation is "created", "modified", "deleted" or "renamed".
+	Operation string `json:"operation"`
+	From      string `json:"from,omitempty"`
+}
+
+type applyPatchResult struct {
+	Files []changedFile `json:"files"`
+}
+
+func (f *filesystem) applyPatch(ctx tool.Context, args applyPatchArgs) (applyPatchResult, error) {
+	patches, err := parsePatch(args.Patch)
+	if err != nil {
+		return applyPatchResult{}, fmt.Errorf("invalid patch: %w", err)
+	}
+	root, err := f.openRoot()
+	if err != nil {
+		return applyPatchResult{}, err
+	}
+	defer root.Close()
+
+	// The new contents of the files are computed before any file is
+	// changed, so that a patch which does not apply changes nothing. A nil
+	// content deletes the file. The original contents are kept to restore
+	// the files if one of them cannot be written.
+	contents := map[string]*string{}
+	originals := map[string]*string{}
+	var order []string
+	var result applyPatchResult
+	current := func(p string) (string, bool, error) {
+		if c, ok := contents[p]; ok {
+			if c == nil {
+				return "", false, nil
+			}
+			return *c, true, nil
+		}
+		data, err := f.read(root, p)
+		if errors.Is(err, fs.ErrNotExist) {
+			originals[p] = nil
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, err
+		}
+		original := string(data)
+		originals[p] = &original
+		return original, true, nil
+	}
+	set := func(p string, content *string) {
+		if _, ok := contents[p]; !ok {
+			order = append(order, p)
+		}
+		contents[p] = content
+	}
+
+	for _, fp := range patches {
+		var oldPath, newPath string
+		if fp.oldPath != devNull {
+			if oldPath, err = f.relPath(fp.oldPath); err != nil {
+				return applyPatchResult{}, err
+			}
+		}
+		if fp.newPath != devNull {
+			if newPath, err = f.relPath(fp.newPath); err != nil {
+				return applyPatchResult{}, err
+			}
+		}
+
+		var content string
+		if oldPath != "" {
+			var exists bool
+			content, exists, err = current(oldPath)
+			if err != nil {
+				return applyPatchResult{}, err
+			}
+			if !exists {
+				return applyPatchResult{}, fmt.Errorf("file %q does not exist", oldPath)
+			}
+		} else if _, exists, err := current(newPath); err != nil || exists {
+			if err == nil {
+				err = fmt.Errorf("file %q already exists", newPath)
+			}
+			return applyPatchResult{}, err
+		}
+
+		patched, err := fp.apply(content)
+		if err != nil {
+			return applyPatchResult{}, fmt.Errorf("failed to patch %q: %w", cmp.Or(oldPath, newPath), err)
+		}
+		if int64(len(patched)) > f.maxFileSize {
+			return applyPatchResult{}, fmt.Errorf("patched %q is larger than the limit of %d bytes", newPath, f.maxFileSize)
+		}
+
+		switch {
+		case oldPath == "":
+			set(newPath, &patched)
+			result.Files = append(result.Files, changedFile{Path: newPath, Operation: "created"})
+		case newPath == "":
+			set(oldPath, nil)
+			result.Files = append(result.Files, changedFile{Path: oldPath, Operation: "deleted"})
+		case oldPath != newPath:
+			if _, exists, err := current(newPath); err != nil || exists {
+				if err == nil {
+					err = fmt.Errorf("cannot rename %q to the existing file %q", oldPath, newPath)
+				}
+				return applyPatchResult{}, err
+			}
+			set(oldPath, nil)
+			set(newPath, &patched)
+			result.Files = append(result.Files, changedFile{Path: newPath, Operation: "renamed", From: oldPath})
+		default:
+			set(newPath, &patched)
+			result.Files = append(result.Files, changedFile{Path: newPath, Operation: "modified"})
+		}
+	}
+
+	for i, p := range order {
+		if err := setContent(root, p, contents[p]); err != nil {
+			return applyPatchResult{}, errors.Join(err, restore(root, order[:i+1], originals))
+		}
+	}
+	return result, nil
+}
+
+// setContent writes the file p with content, or deletes it if content is
+// nil.
+func setContent(root *os.Root, p string, content *string) error {
+	if content != nil {
+		return write(root, p, []byte(*content))
+	}
+	if err := root.Remove(filepath.FromSlash(p)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete %q: %w", p, unwrapPathError(err))
+	}
+	return nil
+}
+
+// restore sets the files in paths back to their original contents after
+// a failed write.
+func restore(root *os.Root, paths []string, originals map[string]*string) error {
+	var errs []error
+	for _, p := range paths {
+		if err := setContent(root, p, originals[p]); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore %q: %w", p, err))
+		}
+	}
+	return errors.Join(errs...)
+}
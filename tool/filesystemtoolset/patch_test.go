@@ -0,0 +1,182 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesystemtoolset
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		patch     string
+		wantFiles map[string]string
+		want      map[string]any
+	}{
+		{
+			name:  "modify",
+			files: map[string]string{"main.go": "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n"},
+			patch: `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -2,4 +2,5 @@
+ 
+ func main() {
+-	println("hello")
++	println("hello,")
++	println("world")
+ }
+`,
+			wantFiles: map[string]string{"main.go": "package main\n\nfunc main() {\n\tprintln(\"hello,\")\n\tprintln(\"world\")\n}\n"},
+			want:      map[string]any{"files": []any{map[string]any{"path": "main.go", "operation": "modified"}}},
+		},
+		{
+			name:      "wrong line numbers and empty context line",
+			files:     map[string]string{"a.txt": "1\n2\n3\n\n4\n5\n"},
+			patch:     "--- a.txt\n+++ a.txt\n@@ -1,3 +1,3 @@\n 3\n\n-4\n+four\n",
+			wantFiles: map[string]string{"a.txt": "1\n2\n3\n\nfour\n5\n"},
+			want:      map[string]any{"files": []any{map[string]any{"path": "a.txt", "operation": "modified"}}},
+		},
+		{
+			name:      "insert without context",
+			files:     map[string]string{"a.txt": "1\n2\n"},
+			patch:     "--- a/a.txt\n+++ b/a.txt\n@@ -1,0 +2 @@\n+1.5\n",
+			wantFiles: map[string]string{"a.txt": "1\n1.5\n2\n"},
+			want:      map[string]any{"files": []any{map[string]any{"path": "a.txt", "operation": "modified"}}},
+		},
+		{
+			name:      "crlf",
+			files:     map[string]string{"a.txt": "1\r\n2\r\n"},
+			patch:     "--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,2 @@\n 1\n-2\n+two\n",
+			wantFiles: map[string]string{"a.txt": "1\r\ntwo\r\n"},
+			want:      map[string]any{"files": []any{map[string]any{"path": "a.txt", "operation": "modified"}}},
+		},
+		{
+			name:  "create delete rename",
+			files: map[string]string{"old.txt": "bye\n", "from.txt": "a\nb\n"},
+			patch: `--- /dev/null
++++ b/dir/new.txt
+@@ -0,0 +1,2 @@
++hello
++world
+\ No newline at end of file
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+--- a/from.txt
++++ b/to.txt
+@@ -1,2 +1,2 @@
+ a
+-b
++c
+`,
+			wantFiles: map[string]string{"dir/new.txt": "hello\nworld", "to.txt": "a\nc\n"},
+			want: map[string]any{"files": []any{
+				map[string]any{"path": "dir/new.txt", "operation": "created"},
+				map[string]any{"path": "old.txt", "operation": "deleted"},
+				map[string]any{"path": "to.txt", "operation": "renamed", "from": "from.txt"},
+			}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := newTestDir(t, tc.files)
+			ts := tools(t, Config{Root: dir})
+			got, err := run(t, ts["apply_patch"], map[string]any{"patch": tc.patch})
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantFiles, readDir(t, dir)); diff != "" {
+				t.Errorf("files mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyPatch_Errors(t *testing.T) {
+	files := map[string]string{"a.txt": "1\n2\n", "b.txt": "x\n"}
+	tests := []struct {
+		name    string
+		patch   string
+		wantErr string
+	}{
+		{name: "not a diff", patch: "hello", wantErr: "no file diffs"},
+		{name: "context mismatch", patch: "--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,2 @@\n 1\n-3\n+4\n", wantErr: "does not match"},
+		{name: "missing file", patch: "--- a/c.txt\n+++ b/c.txt\n@@ -1 +1 @@\n-1\n+2\n", wantErr: `"c.txt" does not exist`},
+		{name: "create existing", patch: "--- /dev/null\n+++ b/a.txt\n@@ -0,0 +1 @@\n+1\n", wantErr: `"a.txt" already exists`},
+		{name: "outside root", patch: "--- /dev/null\n+++ ../x.txt\n@@ -0,0 +1 @@\n+1\n", wantErr: "outside of the root directory"},
+		{
+			// The first file diff applies but the second does not, so no file
+			// is changed.
+			name:    "atomic",
+			patch:   "--- a/b.txt\n+++ b/b.txt\n@@ -1 +1 @@\n-x\n+y\n--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-9\n+8\n",
+			wantErr: "does not match",
+		},
+		{name: "rename onto existing", patch: "--- a/a.txt\n+++ b/b.txt\n@@ -1,2 +1,2 @@\n 1\n-2\n+3\n", wantErr: `existing file "b.txt"`},
+		{
+			// x/y.txt cannot be written as x is a file, so the files written
+			// before are restored.
+			name:    "write error",
+			patch:   "--- a/b.txt\n+++ b/b.txt\n@@ -1 +1 @@\n-x\n+y\n--- /dev/null\n+++ b/x\n@@ -0,0 +1 @@\n+1\n--- /dev/null\n+++ b/x/y.txt\n@@ -0,0 +1 @@\n+2\n",
+			wantErr: `failed to open "x/y.txt"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := newTestDir(t, files)
+			ts := tools(t, Config{Root: dir})
+			_, err := run(t, ts["apply_patch"], map[string]any{"patch": tc.patch})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Run() error = %v, want %q", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(files, readDir(t, dir)); diff != "" {
+				t.Errorf("files changed (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// readDir returns the content of the files of a directory by path.
+func readDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, p)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
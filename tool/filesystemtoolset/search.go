@@ -0,0 +1,228 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesystemtoolset
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+
+	"google.golang.org/adk/tool"
+)
+
+// maxLineLength limits the length of the lines returned by grep.
+const maxLineLength = 500
+
+type globArgs struct {
+	Pattern string `json:"pattern" jsonschema:"glob pattern of the paths relative to the root directory, like src/**/*.go"`
+}
+
+type globResult struct {
+	Pattern   string   `json:"pattern"`
+	Paths     []string `json:"paths"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+func (f *filesystem) glob(ctx tool.Context, args globArgs) (globResult, error) {
+	pattern, err := f.relPath(args.Pattern)
+	if err != nil {
+		return globResult{}, err
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return globResult{}, fmt.Errorf("invalid pattern %q: %w", args.Pattern, err)
+	}
+	root, err := f.openRoot()
+	if err != nil {
+		return globResult{}, err
+	}
+	defer root.Close()
+
+	patternElems := strings.Split(pattern, "/")
+	result := globResult{Pattern: args.Pattern, Paths: []string{}}
+	err = fs.WalkDir(root.FS(), staticPrefix(patternElems), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip the files which cannot be read.
+			return nil
+		}
+		if p != "." && d.IsDir() && d.Name() == ".git" {
+			return fs.SkipDir
+		}
+		if p == "." || !matchPath(patternElems, strings.Split(p, "/")) {
+			return nil
+		}
+		if len(result.Paths) == f.maxResults {
+			result.Truncated = true
+			return fs.SkipAll
+		}
+		result.Paths = append(result.Paths, p)
+		return nil
+	})
+	if err != nil {
+		return globResult{}, fmt.Errorf("failed to search files: %w", unwrapPathError(err))
+	}
+	return result, nil
+}
+
+// staticPrefix returns the leading directories of a pattern without
+// wildcards, where the search for matching paths starts.
+func staticPrefix(patternElems []string) string {
+	var prefix []string
+	for _, elem := range patternElems[:len(patternElems)-1] {
+		if strings.ContainsAny(elem, `*?[\`) {
+			break
+		}
+		prefix = append(prefix, elem)
+	}
+	if len(prefix) == 0 {
+		return "."
+	}
+	return path.Join(prefix...)
+}
+
+// matchPath reports whether the elements of a path match the elements of a
+// pattern, where "**" matches any number of elements.
+func matchPath(pattern, elems []string) bool {
+	if len(pattern) == 0 {
+		return len(elems) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(elems); i++ {
+			if matchPath(pattern[1:], elems[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(elems) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], elems[0])
+	return ok && matchPath(pattern[1:], elems[1:])
+}
+
+type grepArgs struct {
+	Pattern    string `json:"pattern" jsonschema:"regular expression in RE2 syntax"`
+	Path       string `json:"path,omitempty" jsonschema:"file or directory to search, relative to the root directory (default: the root directory)"`
+	Include    string `json:"include,omitempty" jsonschema:"glob pattern of the files to search, like *.go"`
+	IgnoreCase bool   `json:"ignore_case,omitempty" jsonschema:"whether the search is case-insensitive"`
+}
+
+type grepMatch struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+type grepResult struct {
+	Matches   []grepMatch `json:"matches"`
+	Truncated bool        `json:"truncated,omitempty"`
+}
+
+func (f *filesystem) grep(ctx tool.Context, args grepArgs) (grepResult, error) {
+	if args.Pattern == "" {
+		return grepResult{}, errors.New("missing required parameter: pattern")
+	}
+	expr := args.Pattern
+	if args.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return grepResult{}, fmt.Errorf("invalid pattern %q: %w", args.Pattern, err)
+	}
+	dir, err := f.relPath(args.Path)
+	if err != nil {
+		return grepResult{}, err
+	}
+	var include []string
+	if args.Include != "" {
+		if _, err := path.Match(args.Include, ""); err != nil {
+			return grepResult{}, fmt.Errorf("invalid include pattern %q: %w", args.Include, err)
+		}
+		include = strings.Split(path.Clean(args.Include), "/")
+	}
+	root, err := f.openRoot()
+	if err != nil {
+		return grepResult{}, err
+	}
+	defer root.Close()
+
+	fsys := root.FS()
+	result := grepResult{Matches: []grepMatch{}}
+	err = fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir {
+				return err
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if p != dir && d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !matchInclude(include, p) {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > f.maxFileSize {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil || isBinary(data) {
+			return nil
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, len(data)+1)
+		for line := 1; scanner.Scan(); line++ {
+			text := scanner.Text()
+			if !re.MatchString(text) {
+				continue
+			}
+			if len(result.Matches) == f.maxResults {
+				result.Truncated = true
+				return fs.SkipAll
+			}
+			if len(text) > maxLineLength {
+				text = strings.ToValidUTF8(text[:maxLineLength], "") + "..."
+			}
+			result.Matches = append(result.Matches, grepMatch{Path: p, Line: line, Text: text})
+		}
+		return nil
+	})
+	if err != nil {
+		return grepResult{}, fmt.Errorf("failed to search %q: %w", dir, unwrapPathError(err))
+	}
+	return result, nil
+}
+
+// matchInclude reports whether a file matches the include pattern. Patterns
+// without "/" match the file name, others the path.
+func matchInclude(include []string, p string) bool {
+	switch len(include) {
+	case 0:
+		return true
+	case 1:
+		ok, _ := path.Match(include[0], path.Base(p))
+		return ok
+	default:
+		return matchPath(include, strings.Split(p, "/"))
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filesystemtoolset provides a toolset reading, searching and
+// editing the files of a directory.
+//
+// The toolset exposes the read_file, list_dir, glob and grep tools, and the
+// write_file and apply_patch tools unless it is read-only. All paths are
+// relative to the configured root directory, and the tools cannot access
+// files outside of it, neither with ".." nor through symbolic links.
+//
+// Example:
+//
+//	files, err := filesystemtoolset.New(filesystemtoolset.Config{
+//		Root:                "/path/to/project",
+//		RequireConfirmation: true,
+//	})
+//	...
+//	llmagent.New(llmagent.Config{
+//		Name:     "coding_agent",
+//		Model:    model,
+//		Toolsets: []tool.Toolset{files},
+//	})
+package filesystemtoolset
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultMaxFileSize = 1 << 20
+	defaultMaxResults  = 200
+)
+
+// Config provides the configuration of a filesystem toolset.
+type Config struct {
+	// Root is the directory the tools are confined to. It must exist.
+	Root string
+	// ReadOnly removes the write_file and apply_patch tools.
+	ReadOnly bool
+	// MaxFileSize is the maximum size in bytes of the files the tools read
+	// and write (default: 1 MiB). grep skips larger files.
+	MaxFileSize int64
+	// MaxResults is the maximum number of entries, paths and matches
+	// returned by list_dir, glob and grep (default: 200).
+	MaxResults int
+	// RequireConfirmation flags whether the tools modifying files must ask
+	// for user confirmation before execution, using the Human-in-the-Loop
+	// confirmation flow of the ADK framework.
+	RequireConfirmation bool
+}
+
+// New returns a toolset for the files of the root directory.
+func New(cfg Config) (tool.Toolset, error) {
+	if cfg.Root == "" {
+		return nil, errors.New("root directory is required")
+	}
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve root directory: %w", err)
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access root directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("root %q is not a directory", root)
+	}
+
+	fsys := &filesystem{
+		root:        root,
+		maxFileSize: defaultMaxFileSize,
+		maxResults:  defaultMaxResults,
+	}
+	if cfg.MaxFileSize > 0 {
+		fsys.maxFileSize = cfg.MaxFileSize
+	}
+	if cfg.MaxResults > 0 {
+		fsys.maxResults = cfg.MaxResults
+	}
+
+	s := &set{}
+	add := func(t tool.Tool, err error) error {
+		if err != nil {
+			return err
+		}
+		s.tools = append(s.tools, t)
+		return nil
+	}
+	if err := errors.Join(
+		add(functiontool.New(functiontool.Config{
+			Name:        "read_file",
+			Description: "Reads a text file. Use offset and limit to read a range of lines of large files.",
+		}, fsys.readFile)),
+		add(functiontool.New(functiontool.Config{
+			Name:        "list_dir",
+			Description: "Lists the files and directories of a directory.",
+		}, fsys.listDir)),
+		add(functiontool.New(functiontool.Config{
+			Name:        "glob",
+			Description: `Finds the files whose paths match a glob pattern like "src/**/*.go", where "**" matches any number of directories.`,
+		}, fsys.glob)),
+		add(functiontool.New(functiontool.Config{
+			Name:        "grep",
+			Description: "Searches the lines of the files matching a regular expression (RE2 syntax).",
+		}, fsys.grep)),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create filesystem tools: %w", err)
+	}
+	if cfg.ReadOnly {
+		return s, nil
+	}
+	if err := errors.Join(
+		add(functiontool.New(functiontool.Config{
+			Name:                "write_file",
+			Description:         "Writes a text file, replacing its content. Missing parent directories are created.",
+			RequireConfirmation: cfg.RequireConfirmation,
+		}, fsys.writeFile)),
+		add(functiontool.New(functiontool.Config{
+			Name: "apply_patch",
+			Description: "Applies a patch in unified diff format, like the output of `git diff`, to the files. " +
+				"Files are created with /dev/null as the old file and deleted with /dev/null as the new file. " +
+				"Either all files of the patch are changed or none: a patch which does not apply changes nothing, " +
+				"and the changed files are restored if a file cannot be written. Renaming onto an existing file fails.",
+			RequireConfirmation: cfg.RequireConfirmation,
+		}, fsys.applyPatch)),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create filesystem tools: %w", err)
+	}
+	return s, nil
+}
+
+type set struct {
+	tools []tool.Tool
+}
+
+// Name implements tool.Toolset.
+func (s *set) Name() string {
+	return "filesystem_toolset"
+}
+
+// Tools implements tool.Toolset.
+func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	return s.tools, nil
+}
+
+// filesystem implements the tools. Every call opens the root directory with
+// os.OpenRoot, which rejects paths escaping it including through symbolic
+// links.
+type filesystem struct {
+	root        string
+	maxFileSize int64
+	maxResults  int
+}
+
+func (f *filesystem) openRoot() (*os.Root, error) {
+	r, err := os.OpenRoot(f.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open root directory: %w", err)
+	}
+	return r, nil
+}
+
+// relPath converts a path given to a tool to a slash-separated path
+// relative to the root. Absolute paths are accepted if they are within the
+// root.
+func (f *filesystem) relPath(p string) (string, error) {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ".", nil
+	}
+	if filepath.IsAbs(p) {
+		rel, err := filepath.Rel(f.root, filepath.Clean(p))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("path %q is outside of the root directory", p)
+		}
+		p = rel
+	}
+	p = filepath.ToSlash(filepath.Clean(p))
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("path %q is outside of the root directory", p)
+	}
+	return p, nil
+}
@@ -0,0 +1,375 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesystemtoolset
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+func createToolContext(t *testing.T, confirmation *toolconfirmation.ToolConfirmation) tool.Context {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a})
+	return toolinternal.NewToolContext(invCtx, "call1", nil, confirmation)
+}
+
+// newTestDir creates a directory with the given files.
+func newTestDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func tools(t *testing.T, cfg Config) map[string]toolinternal.FunctionTool {
+	t.Helper()
+	ts, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	list, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	m := map[string]toolinternal.FunctionTool{}
+	for _, tl := range list {
+		m[tl.Name()] = tl.(toolinternal.FunctionTool)
+	}
+	return m
+}
+
+func run(t *testing.T, tl toolinternal.FunctionTool, args map[string]any) (map[string]any, error) {
+	t.Helper()
+	return tl.Run(createToolContext(t, nil), args)
+}
+
+func TestNew(t *testing.T) {
+	dir := newTestDir(t, map[string]string{"file.txt": "text"})
+	tests := []struct {
+		name      string
+		cfg       Config
+		wantTools []string
+		wantErr   bool
+	}{
+		{name: "read write", cfg: Config{Root: dir}, wantTools: []string{"read_file", "list_dir", "glob", "grep", "write_file", "apply_patch"}},
+		{name: "read only", cfg: Config{Root: dir, ReadOnly: true}, wantTools: []string{"read_file", "list_dir", "glob", "grep"}},
+		{name: "missing root", cfg: Config{}, wantErr: true},
+		{name: "root not found", cfg: Config{Root: filepath.Join(dir, "missing")}, wantErr: true},
+		{name: "root is a file", cfg: Config{Root: filepath.Join(dir, "file.txt")}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, err := New(tc.cfg)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("New() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			list, err := ts.Tools(nil)
+			if err != nil {
+				t.Fatalf("Tools() failed: %v", err)
+			}
+			var got []string
+			for _, tl := range list {
+				got = append(got, tl.Name())
+			}
+			if diff := cmp.Diff(tc.wantTools, got); diff != "" {
+				t.Errorf("Tools() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir := newTestDir(t, map[string]string{
+		"lines.txt":  "one\ntwo\nthree\n",
+		"binary.bin": "\x00\x01",
+		"large.txt":  strings.Repeat("x", 100),
+	})
+	ts := tools(t, Config{Root: dir, MaxFileSize: 50})
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name: "whole file",
+			args: map[string]any{"path": "lines.txt"},
+			want: map[string]any{"path": "lines.txt", "content": "one\ntwo\nthree\n", "total_lines": float64(3)},
+		},
+		{
+			name: "absolute path",
+			args: map[string]any{"path": filepath.Join(dir, "lines.txt")},
+			want: map[string]any{"path": "lines.txt", "content": "one\ntwo\nthree\n", "total_lines": float64(3)},
+		},
+		{
+			name: "range",
+			args: map[string]any{"path": "lines.txt", "offset": 2, "limit": 1},
+			want: map[string]any{"path": "lines.txt", "content": "two\n", "total_lines": float64(3), "truncated": true},
+		},
+		{name: "binary", args: map[string]any{"path": "binary.bin"}, wantErr: "binary file"},
+		{name: "too large", args: map[string]any{"path": "large.txt"}, wantErr: "larger than the limit"},
+		{name: "directory", args: map[string]any{"path": "."}, wantErr: "is a directory"},
+		{name: "not found", args: map[string]any{"path": "missing.txt"}, wantErr: "no such file"},
+		{name: "parent", args: map[string]any{"path": "../lines.txt"}, wantErr: "outside of the root directory"},
+		{name: "absolute outside", args: map[string]any{"path": "/etc/passwd"}, wantErr: "outside of the root directory"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := run(t, ts["read_file"], tc.args)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("Run() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSymlinkEscape(t *testing.T) {
+	outside := newTestDir(t, map[string]string{"secret.txt": "secret"})
+	dir := newTestDir(t, nil)
+	if err := os.Symlink(filepath.Join(outside, "secret.txt"), filepath.Join(dir, "link.txt")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "linkdir")); err != nil {
+		t.Fatal(err)
+	}
+	ts := tools(t, Config{Root: dir})
+
+	if _, err := run(t, ts["read_file"], map[string]any{"path": "link.txt"}); err == nil {
+		t.Error("read_file of a symlink to a file outside of the root succeeded, want error")
+	}
+	if _, err := run(t, ts["write_file"], map[string]any{"path": "linkdir/new.txt", "content": "x"}); err == nil {
+		t.Error("write_file through a symlink to a directory outside of the root succeeded, want error")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "new.txt")); err == nil {
+		t.Error("write_file created a file outside of the root")
+	}
+	got, err := run(t, ts["grep"], map[string]any{"pattern": "secret"})
+	if err != nil {
+		t.Fatalf("grep failed: %v", err)
+	}
+	if matches := got["matches"].([]any); len(matches) != 0 {
+		t.Errorf("grep matches = %v, want none", matches)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := newTestDir(t, map[string]string{"old.txt": "old"})
+	ts := tools(t, Config{Root: dir, MaxFileSize: 10})
+
+	got, err := run(t, ts["write_file"], map[string]any{"path": "sub/dir/new.txt", "content": "new"})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"path": "sub/dir/new.txt", "bytes_written": float64(3)}, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := run(t, ts["write_file"], map[string]any{"path": "old.txt", "content": "replaced"}); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	for name, want := range map[string]string{"sub/dir/new.txt": "new", "old.txt": "replaced"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v, want %q", name, data, err, want)
+		}
+	}
+	if _, err := run(t, ts["write_file"], map[string]any{"path": "big.txt", "content": strings.Repeat("x", 11)}); err == nil {
+		t.Error("Run() with too large content succeeded, want error")
+	}
+}
+
+func TestWriteFile_RequireConfirmation(t *testing.T) {
+	dir := newTestDir(t, nil)
+	ts := tools(t, Config{Root: dir, RequireConfirmation: true})
+	args := map[string]any{"path": "new.txt", "content": "new"}
+
+	if _, err := ts["write_file"].Run(createToolContext(t, nil), args); err == nil || !strings.Contains(err.Error(), "requires confirmation") {
+		t.Errorf("Run() error = %v, want confirmation request", err)
+	}
+	if _, err := ts["write_file"].Run(createToolContext(t, &toolconfirmation.ToolConfirmation{Confirmed: false}), args); err == nil {
+		t.Error("Run() rejected by the user succeeded, want error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.txt")); err == nil {
+		t.Fatal("file was written without confirmation")
+	}
+	if _, err := ts["write_file"].Run(createToolContext(t, &toolconfirmation.ToolConfirmation{Confirmed: true}), args); err != nil {
+		t.Fatalf("Run() confirmed by the user failed: %v", err)
+	}
+	// Read-only tools do not require confirmation.
+	if _, err := run(t, ts["read_file"], map[string]any{"path": "new.txt"}); err != nil {
+		t.Errorf("read_file failed: %v", err)
+	}
+}
+
+func TestListDir(t *testing.T) {
+	dir := newTestDir(t, map[string]string{"b.txt": "bb", "a/c.txt": "c"})
+	ts := tools(t, Config{Root: dir})
+
+	got, err := run(t, ts["list_dir"], map[string]any{})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	want := map[string]any{
+		"path": ".",
+		"entries": []any{
+			map[string]any{"name": "a", "type": "dir"},
+			map[string]any{"name": "b.txt", "type": "file", "size": float64(2)},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGlob(t *testing.T) {
+	dir := newTestDir(t, map[string]string{
+		"main.go":          "",
+		"src/a.go":         "",
+		"src/pkg/b.go":     "",
+		"src/pkg/b.txt":    "",
+		".git/config.go":   "",
+		"docs/readme.md":   "",
+		"src/pkg/c/d.go":   "",
+		"other/src/e.go":   "",
+		"other/src/e.json": "",
+	})
+	ts := tools(t, Config{Root: dir})
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "*.go", want: []string{"main.go"}},
+		{pattern: "src/*.go", want: []string{"src/a.go"}},
+		{pattern: "src/**/*.go", want: []string{"src/a.go", "src/pkg/b.go", "src/pkg/c/d.go"}},
+		{pattern: "**/*.go", want: []string{"main.go", "other/src/e.go", "src/a.go", "src/pkg/b.go", "src/pkg/c/d.go"}},
+		{pattern: "**/src", want: []string{"other/src", "src"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.pattern, func(t *testing.T) {
+			got, err := run(t, ts["glob"], map[string]any{"pattern": tc.pattern})
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			var paths []string
+			for _, p := range got["paths"].([]any) {
+				paths = append(paths, p.(string))
+			}
+			if diff := cmp.Diff(tc.want, paths); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGrep(t *testing.T) {
+	dir := newTestDir(t, map[string]string{
+		"a.go":       "package a\n\nfunc Hello() {}\n",
+		"b/b.go":     "package b\n\n// hello world\n",
+		"b/b.txt":    "Hello text\n",
+		"binary.bin": "Hello\x00",
+	})
+	ts := tools(t, Config{Root: dir, MaxResults: 2})
+	tests := []struct {
+		name string
+		args map[string]any
+		want map[string]any
+	}{
+		{
+			name: "case sensitive",
+			args: map[string]any{"pattern": "Hello"},
+			want: map[string]any{"matches": []any{
+				map[string]any{"path": "a.go", "line": float64(3), "text": "func Hello() {}"},
+				map[string]any{"path": "b/b.txt", "line": float64(1), "text": "Hello text"},
+			}},
+		},
+		{
+			name: "include and ignore case",
+			args: map[string]any{"pattern": "hello", "include": "*.go", "ignore_case": true},
+			want: map[string]any{"matches": []any{
+				map[string]any{"path": "a.go", "line": float64(3), "text": "func Hello() {}"},
+				map[string]any{"path": "b/b.go", "line": float64(3), "text": "// hello world"},
+			}},
+		},
+		{
+			name: "path",
+			args: map[string]any{"pattern": "^package", "path": "b"},
+			want: map[string]any{"matches": []any{
+				map[string]any{"path": "b/b.go", "line": float64(1), "text": "package b"},
+			}},
+		},
+		{
+			name: "truncated",
+			args: map[string]any{"pattern": "."},
+			want: map[string]any{
+				"matches": []any{
+					map[string]any{"path": "a.go", "line": float64(1), "text": "package a"},
+					map[string]any{"path": "a.go", "line": float64(3), "text": "func Hello() {}"},
+				},
+				"truncated": true,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := run(t, ts["grep"], tc.args)
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := run(t, ts["grep"], map[string]any{"pattern": "("}); err == nil {
+		t.Error("Run() with invalid pattern succeeded, want error")
+	}
+}
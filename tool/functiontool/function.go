@@ -18,6 +18,7 @@ package functiontool
 import (
 	"errors"
 	"fmt"
+	"log"
 	"reflect"
 	"runtime/debug"
 
@@ -28,6 +29,7 @@ import (
 	"google.golang.org/adk/internal/typeutil"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolpolicy"
 )
 
 // FunctionTool: borrow implementation from MCP go.
@@ -64,6 +66,26 @@ type Config struct {
 	// where ToolArgs is the input type of your go function
 	// Returning true means confirmation is required.
 	RequireConfirmationProvider any
+
+	// Policy limits the executions of the tool: timeout, concurrency, rate
+	// limit and circuit breaker. The zero value does not limit them.
+	Policy toolpolicy.Policy
+
+	// OutputValidation is how strictly the results of the handler are
+	// validated against the output schema (default: OutputValidationStrict,
+	// failing the calls with invalid results).
+	OutputValidation OutputValidation
+	// RepairModel, if set, is asked to fix the arguments of the calls which
+	// do not match the input schema before the handler runs. A small model
+	// is enough. If the repair fails, or without repair model, the invalid
+	// fields are reported to the calling model as a ValidationError. The
+	// arguments of a tool which may require a confirmation are not
+	// repaired, so that the call which runs is the one confirmed.
+	RepairModel model.LLM
+	// Logger, if set, logs the results which do not match the output schema
+	// with OutputValidationWarn and the failures of the repair model
+	// (optional).
+	Logger *log.Logger
 }
 
 // Func represents a Go function that can be wrapped in a tool.
@@ -76,6 +98,17 @@ var ErrInvalidArgument = errors.New("invalid argument")
 // New creates a new tool with a name, description, and the provided handler.
 // Input schema is automatically inferred from the input and output types.
 func New[TArgs, TResults any](cfg Config, handler Func[TArgs, TResults]) (tool.Tool, error) {
+	f, err := newFunctionTool[TArgs, TResults](cfg)
+	if err != nil {
+		return nil, err
+	}
+	f.handler = handler
+	return f, nil
+}
+
+// newFunctionTool creates a function tool without handler, resolving the
+// schemas of its arguments and results.
+func newFunctionTool[TArgs, TResults any](cfg Config) (*functionTool[TArgs, TResults], error) {
 	// TODO: How can we improve UX for functions that does not require an argument, returns a simple type value, or returns a no result?
 	// https://github.com/modelcontextprotocol/go-sdk/discussions/37
 
@@ -108,13 +141,20 @@ func New[TArgs, TResults any](cfg Config, handler Func[TArgs, TResults]) (tool.T
 		confirmWrapper = fn
 	}
 
+	var limiter *toolpolicy.Limiter
+	if !cfg.Policy.IsZero() {
+		if limiter, err = toolpolicy.NewLimiter(cfg.Name, cfg.Policy); err != nil {
+			return nil, err
+		}
+	}
+
 	return &functionTool[TArgs, TResults]{
 		cfg:                         cfg,
 		inputSchema:                 ischema,
 		outputSchema:                oschema,
-		handler:                     handler,
 		requireConfirmation:         cfg.RequireConfirmation,
 		requireConfirmationProvider: confirmWrapper,
+		limiter:                     limiter,
 	}, nil
 }
 
@@ -133,6 +173,9 @@ type functionTool[TArgs, TResults any] struct {
 	requireConfirmation bool
 
 	requireConfirmationProvider func(TArgs) bool
+
+	// limiter enforces the policy of the tool if it has one.
+	limiter *toolpolicy.Limiter
 }
 
 // Description implements tool.Tool.
@@ -189,18 +232,53 @@ func (f *functionTool[TArgs, TResults]) Run(ctx tool.Context, args any) (result
 		}
 	}()
 
+	input, err := f.input(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if f.limiter != nil {
+		return f.limiter.Do(ctx, func(ctx tool.Context) (map[string]any, error) {
+			return f.run(ctx, input)
+		})
+	}
+	return f.run(ctx, input)
+}
+
+func (f *functionTool[TArgs, TResults]) run(ctx tool.Context, input TArgs) (map[string]any, error) {
+	output, err := f.handler(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return f.output(output)
+}
+
+// input converts the arguments of a call to the input of the handler, and
+// checks the confirmation of the call if it is required.
+func (f *functionTool[TArgs, TResults]) input(ctx tool.Context, args any) (TArgs, error) {
+	var zero TArgs
 	m, ok := args.(map[string]any)
 	if !ok {
-		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+		return zero, fmt.Errorf("unexpected args type, got: %T", args)
 	}
-	input, err := typeutil.ConvertToWithJSONSchema[map[string]any, TArgs](m, f.inputSchema)
+	input, err := f.convertArgs(m)
 	if err != nil {
-		return nil, err
+		var verr *ValidationError
+		if f.cfg.RepairModel == nil || f.mayRequireConfirmation() || !errors.As(err, &verr) {
+			return zero, err
+		}
+		repaired, repairErr := f.repairArgs(ctx, m, verr)
+		if repairErr != nil {
+			if f.cfg.Logger != nil && !errors.As(repairErr, new(*ValidationError)) {
+				f.cfg.Logger.Printf("failed to repair the arguments of tool %q: %v", f.Name(), repairErr)
+			}
+			return zero, err
+		}
+		input = repaired
 	}
 
 	if confirmation := ctx.ToolConfirmation(); confirmation != nil {
 		if !confirmation.Confirmed {
-			return nil, fmt.Errorf("error tool %q call is rejected", f.Name())
+			return zero, fmt.Errorf("error tool %q call is rejected", f.Name())
 		}
 	} else {
 		requireConfirmation := f.requireConfirmation
@@ -216,19 +294,28 @@ func (f *functionTool[TArgs, TResults]) Run(ctx tool.Context, args any) (result
 				fmt.Sprintf("Please approve or reject the tool call %s() by responding with a FunctionResponse with an expected ToolConfirmation payload.",
 					f.Name()), nil)
 			if err != nil {
-				return nil, err
+				return zero, err
 			}
 			ctx.Actions().SkipSummarization = true
-			return nil, fmt.Errorf("error tool %q requires confirmation, please approve or reject", f.Name())
+			return zero, fmt.Errorf("error tool %q requires confirmation, please approve or reject", f.Name())
 		}
 	}
+	return input, nil
+}
 
-	output, err := f.handler(ctx, input)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := typeutil.ConvertToWithJSONSchema[TResults, map[string]any](output, f.outputSchema)
-	if err == nil { // all good
+// mayRequireConfirmation reports whether calls of the tool may require a
+// confirmation.
+func (f *functionTool[TArgs, TResults]) mayRequireConfirmation() bool {
+	return f.requireConfirmation || f.requireConfirmationProvider != nil
+}
+
+// output converts a result of the handler to the response of the tool.
+func (f *functionTool[TArgs, TResults]) output(output TResults) (map[string]any, error) {
+	resp, err := typeutil.ConvertToWithJSONSchema[TResults, map[string]any](output, nil)
+	if err == nil {
+		if err := f.validateOutput(resp); err != nil {
+			return nil, err
+		}
 		return resp, nil
 	}
 
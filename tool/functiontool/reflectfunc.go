@@ -0,0 +1,230 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"google.golang.org/adk/tool"
+)
+
+// Param describes a parameter of a function wrapped by NewFunc.
+type Param struct {
+	// Name of the parameter in the arguments of the calls.
+	Name string
+	// Description tells the model what to pass.
+	Description string
+	// Optional parameters may be omitted by the model, in which case the
+	// function receives their zero value. Pointer parameters are always
+	// optional.
+	Optional bool
+}
+
+// FuncDoc documents a function wrapped by NewFunc. It is usually generated
+// from the doc comment of the function by the functooldoc command:
+//
+//	//go:generate go run google.golang.org/adk/cmd/functooldoc
+type FuncDoc struct {
+	// Description of the function, the default description of the tool.
+	Description string
+	// Params describe the parameters following the tool.Context.
+	Params []Param
+}
+
+// funcDocs are the registered documentations, by function name.
+var funcDocs sync.Map
+
+// RegisterFuncDoc registers the documentation of a top-level function,
+// used by NewFunc when no parameters are given.
+func RegisterFuncDoc(fn any, doc FuncDoc) {
+	funcDocs.Store(funcName(reflect.ValueOf(fn)), doc)
+}
+
+var (
+	contextType = reflect.TypeFor[tool.Context]()
+	errorType   = reflect.TypeFor[error]()
+)
+
+// NewFunc creates a tool from an ordinary Go function whose first parameter
+// is a tool.Context, like
+//
+//	func(ctx tool.Context, city string, days int) (string, error)
+//
+// The function returns a result and an error, only an error, or only a
+// result. Results which are not structs or maps are returned to the model
+// as {"result": value}.
+//
+// Reflection does not provide the names of the parameters, so params
+// describe the parameters following the context, in order. Without params,
+// the documentation registered for the function with RegisterFuncDoc is
+// used. The name of the tool defaults to the name of the function, and its
+// description to the registered one. The RequireConfirmationProvider of
+// cfg, if any, receives the arguments as a map[string]any.
+func NewFunc(cfg Config, fn any, params ...Param) (tool.Tool, error) {
+	fnValue := reflect.ValueOf(fn)
+	if !fnValue.IsValid() || fnValue.Kind() != reflect.Func {
+		return nil, fmt.Errorf("expected a function, got %T: %w", fn, ErrInvalidArgument)
+	}
+	if fnValue.IsNil() {
+		return nil, fmt.Errorf("function must not be nil: %w", ErrInvalidArgument)
+	}
+	fnType := fnValue.Type()
+	if fnType.NumIn() == 0 || fnType.In(0) != contextType || fnType.IsVariadic() {
+		return nil, fmt.Errorf("the first parameter of function %s must be a tool.Context and it must not be variadic: %w", fnType, ErrInvalidArgument)
+	}
+	numOut := fnType.NumOut()
+	hasErr := numOut > 0 && fnType.Out(numOut-1) == errorType
+	hasResult := numOut == 2 || (numOut == 1 && !hasErr)
+	if numOut > 2 || (numOut == 2 && !hasErr) {
+		return nil, fmt.Errorf("function %s must return a result and an error, a result or an error: %w", fnType, ErrInvalidArgument)
+	}
+
+	name := funcName(fnValue)
+	if v, ok := funcDocs.Load(name); ok {
+		doc := v.(FuncDoc)
+		if len(params) == 0 {
+			params = doc.Params
+		}
+		if cfg.Description == "" {
+			cfg.Description = doc.Description
+		}
+	} else if len(params) == 0 && fnType.NumIn() > 1 {
+		return nil, fmt.Errorf("the parameter names of function %s are unknown, pass them as params or generate its documentation with functooldoc", name)
+	}
+	if len(params) != fnType.NumIn()-1 {
+		return nil, fmt.Errorf("function %s has %d parameters after the context, got %d params: %w", name, fnType.NumIn()-1, len(params), ErrInvalidArgument)
+	}
+	if cfg.Name == "" {
+		cfg.Name = name[strings.LastIndex(name, ".")+1:]
+	}
+
+	resultType := reflect.Type(nil)
+	if hasResult {
+		resultType = fnType.Out(0)
+	}
+	wrapResult := resultType != nil && !isObjectType(resultType)
+	if cfg.InputSchema == nil {
+		schema, err := paramsSchema(fnType, params)
+		if err != nil {
+			return nil, err
+		}
+		cfg.InputSchema = schema
+	}
+	if cfg.OutputSchema == nil && resultType != nil {
+		schema, err := jsonschema.ForType(resultType, &jsonschema.ForOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to infer output schema: %w", err)
+		}
+		if wrapResult {
+			schema = &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{"result": schema},
+			}
+		}
+		cfg.OutputSchema = schema
+	}
+
+	f, err := newFunctionTool[map[string]any, any](cfg)
+	if err != nil {
+		return nil, err
+	}
+	f.handler = func(ctx tool.Context, args map[string]any) (any, error) {
+		in := make([]reflect.Value, fnType.NumIn())
+		in[0] = reflect.ValueOf(&ctx).Elem()
+		for i, p := range params {
+			v := reflect.New(fnType.In(i + 1))
+			if raw, ok := args[p.Name]; ok && raw != nil {
+				b, err := json.Marshal(raw)
+				if err != nil {
+					return nil, err
+				}
+				if err := json.Unmarshal(b, v.Interface()); err != nil {
+					return nil, &ValidationError{Tool: cfg.Name, Fields: []FieldError{{Field: p.Name, Message: err.Error()}}}
+				}
+			}
+			in[i+1] = v.Elem()
+		}
+		out := fnValue.Call(in)
+		if hasErr {
+			if errValue := out[len(out)-1]; !errValue.IsNil() {
+				return nil, errValue.Interface().(error)
+			}
+		}
+		if !hasResult {
+			return nil, nil
+		}
+		if wrapResult {
+			return map[string]any{"result": out[0].Interface()}, nil
+		}
+		return out[0].Interface(), nil
+	}
+	return f, nil
+}
+
+// paramsSchema returns the schema of the arguments of a function, an object
+// with a property per parameter.
+func paramsSchema(fnType reflect.Type, params []Param) (*jsonschema.Schema, error) {
+	schema := &jsonschema.Schema{
+		Type:                 "object",
+		Properties:           map[string]*jsonschema.Schema{},
+		AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+	}
+	for i, p := range params {
+		if p.Name == "" {
+			return nil, fmt.Errorf("parameter %d has no name: %w", i+1, ErrInvalidArgument)
+		}
+		if _, ok := schema.Properties[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate parameter %q: %w", p.Name, ErrInvalidArgument)
+		}
+		t := fnType.In(i + 1)
+		prop, err := jsonschema.ForType(t, &jsonschema.ForOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to infer the schema of parameter %q: %w", p.Name, err)
+		}
+		prop.Description = p.Description
+		schema.Properties[p.Name] = prop
+		if !p.Optional && t.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, p.Name)
+		}
+	}
+	return schema, nil
+}
+
+// isObjectType reports whether values of the type are encoded as JSON
+// objects.
+func isObjectType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct || t.Kind() == reflect.Map
+}
+
+// funcName returns the name of a function, like "example.com/pkg.getWeather".
+func funcName(fn reflect.Value) string {
+	if fn.Kind() != reflect.Func {
+		return ""
+	}
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func forecast(ctx tool.Context, city string, days int, unit *string) (string, error) {
+	if city == "" {
+		return "", errors.New("unknown city")
+	}
+	u := "C"
+	if unit != nil {
+		u = *unit
+	}
+	return fmt.Sprintf("%s: sunny for %d days, 20°%s", city, days, u), nil
+}
+
+func init() {
+	functiontool.RegisterFuncDoc(forecast, functiontool.FuncDoc{
+		Description: "Returns the weather forecast of a city.",
+		Params: []functiontool.Param{
+			{Name: "city", Description: "name of the city"},
+			{Name: "days", Description: "number of days", Optional: true},
+			{Name: "unit", Description: "C or F"},
+		},
+	})
+}
+
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func TestNewFunc(t *testing.T) {
+	t.Run("registered doc", func(t *testing.T) {
+		forecastTool, err := functiontool.NewFunc(functiontool.Config{}, forecast)
+		if err != nil {
+			t.Fatalf("NewFunc() failed: %v", err)
+		}
+		fn := forecastTool.(toolinternal.FunctionTool)
+		if got, want := fn.Name(), "forecast"; got != want {
+			t.Errorf("Name() = %q, want %q", got, want)
+		}
+		decl := fn.Declaration()
+		if got, want := decl.Description, "Returns the weather forecast of a city."; got != want {
+			t.Errorf("Description = %q, want %q", got, want)
+		}
+		wantParams := `{"type":"object","required":["city"],"properties":{"city":{"type":"string","description":"name of the city"},"days":{"type":"integer","description":"number of days"},"unit":{"type":["null","string"],"description":"C or F"}},"additionalProperties":false}`
+		if got := stringifyCompact(t, decl.ParametersJsonSchema); got != wantParams {
+			t.Errorf("ParametersJsonSchema = %s, want %s", got, wantParams)
+		}
+		wantResponse := `{"type":"object","properties":{"result":{"type":"string"}}}`
+		if got := stringifyCompact(t, decl.ResponseJsonSchema); got != wantResponse {
+			t.Errorf("ResponseJsonSchema = %s, want %s", got, wantResponse)
+		}
+
+		got, err := fn.Run(createToolContext(t), map[string]any{"city": "Paris", "days": 3, "unit": "F"})
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if diff := cmp.Diff(map[string]any{"result": "Paris: sunny for 3 days, 20°F"}, got); diff != "" {
+			t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+		}
+		got, err = fn.Run(createToolContext(t), map[string]any{"city": "Rome"})
+		if err != nil || got["result"] != "Rome: sunny for 0 days, 20°C" {
+			t.Errorf("Run() with omitted optional params = %v, %v", got, err)
+		}
+		if _, err := fn.Run(createToolContext(t), map[string]any{"city": ""}); err == nil || err.Error() != "unknown city" {
+			t.Errorf("Run() error = %v, want the function error", err)
+		}
+		var verr *functiontool.ValidationError
+		if _, err := fn.Run(createToolContext(t), map[string]any{"days": "two"}); !errors.As(err, &verr) {
+			t.Errorf("Run() with invalid args error = %v, want a ValidationError", err)
+		}
+	})
+
+	t.Run("params", func(t *testing.T) {
+		moveTool, err := functiontool.NewFunc(functiontool.Config{Name: "move", Description: "Moves a point."},
+			func(ctx tool.Context, p Point, dx int) Point { return Point{X: p.X + dx, Y: p.Y} },
+			functiontool.Param{Name: "point"}, functiontool.Param{Name: "dx"})
+		if err != nil {
+			t.Fatalf("NewFunc() failed: %v", err)
+		}
+		got, err := moveTool.(toolinternal.FunctionTool).Run(createToolContext(t), map[string]any{"point": map[string]any{"x": 1, "y": 2}, "dx": 3})
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		// Struct results are not wrapped.
+		if diff := cmp.Diff(map[string]any{"x": float64(4), "y": float64(2)}, got); diff != "" {
+			t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("no params", func(t *testing.T) {
+		called := false
+		pingTool, err := functiontool.NewFunc(functiontool.Config{Name: "ping"}, func(ctx tool.Context) error {
+			called = true
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("NewFunc() failed: %v", err)
+		}
+		if _, err := pingTool.(toolinternal.FunctionTool).Run(createToolContext(t), map[string]any{}); err != nil || !called {
+			t.Errorf("Run() = %v, called = %v, want a call", err, called)
+		}
+	})
+}
+
+func TestNewFunc_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		fn     any
+		params []functiontool.Param
+	}{
+		{name: "not a function", fn: 42},
+		{name: "no context", fn: func(s string) string { return s }, params: []functiontool.Param{{Name: "s"}}},
+		{name: "unknown names", fn: func(ctx tool.Context, s string) string { return s }},
+		{name: "params count", fn: func(ctx tool.Context, s string) string { return s }, params: []functiontool.Param{{Name: "a"}, {Name: "b"}}},
+		{name: "duplicate params", fn: func(ctx tool.Context, a, b string) string { return a }, params: []functiontool.Param{{Name: "a"}, {Name: "a"}}},
+		{name: "results", fn: func(ctx tool.Context) (string, int) { return "", 0 }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := functiontool.NewFunc(functiontool.Config{Name: "tool"}, tc.fn, tc.params...); err == nil {
+				t.Error("NewFunc() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestNewFunc_Nil(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		fn   any
+	}{
+		{name: "nil", fn: nil},
+		{name: "nil function", fn: (func(tool.Context) error)(nil)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := functiontool.NewFunc(functiontool.Config{Name: "tool"}, tc.fn); !errors.Is(err, functiontool.ErrInvalidArgument) {
+				t.Errorf("NewFunc() error = %v, want %v", err, functiontool.ErrInvalidArgument)
+			}
+		})
+	}
+}
+
+func stringifyCompact(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"fmt"
+	"iter"
+	"runtime/debug"
+
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// StreamingFunc represents a Go function that yields results while it runs.
+// Every result is delivered to the client as a partial event, while only the
+// last one is sent back to the model: a function whose intermediate results
+// are increments yields the aggregated result last.
+type StreamingFunc[TArgs, TResults any] func(tool.Context, TArgs) iter.Seq2[TResults, error]
+
+// NewStreaming creates a new tool with a name, description, and the provided
+// streaming handler, for long computations, like generating a report or
+// tailing logs, reporting their progress to the client.
+// Input schema is automatically inferred from the input and output types.
+func NewStreaming[TArgs, TResults any](cfg Config, handler StreamingFunc[TArgs, TResults]) (tool.Tool, error) {
+	f, err := newFunctionTool[TArgs, TResults](cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &streamingFunctionTool[TArgs, TResults]{functionTool: f, handler: handler}, nil
+}
+
+// streamingFunctionTool wraps a Go function yielding results.
+type streamingFunctionTool[TArgs, TResults any] struct {
+	*functionTool[TArgs, TResults]
+
+	// handler is the Go function.
+	handler StreamingFunc[TArgs, TResults]
+}
+
+// ProcessRequest packs the function tool's declaration into the LLM request.
+func (f *streamingFunctionTool[TArgs, TResults]) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolutils.PackTool(req, f)
+}
+
+// Run executes the tool and returns its last result.
+func (f *streamingFunctionTool[TArgs, TResults]) Run(ctx tool.Context, args any) (map[string]any, error) {
+	var last map[string]any
+	for result, err := range f.RunStream(ctx, args) {
+		if err != nil {
+			return nil, err
+		}
+		last = result
+	}
+	return last, nil
+}
+
+// RunStream executes the tool and yields its results. It stops at the first
+// error.
+func (f *streamingFunctionTool[TArgs, TResults]) RunStream(ctx tool.Context, args any) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		input, err := f.input(ctx, args)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		yieldResult := func(result map[string]any) bool { return yield(result, nil) }
+		if f.limiter == nil {
+			if err := f.stream(ctx, input, yieldResult); err != nil {
+				yield(nil, err)
+			}
+			return
+		}
+
+		execCtx, end, err := f.limiter.Begin(ctx)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		ended := false
+		defer func() {
+			// The consumer panicked.
+			if !ended {
+				end(nil)
+			}
+		}()
+		err = f.stream(execCtx, input, yieldResult)
+		ended = true
+		if err := end(err); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// stream passes the results of the handler to yield until it returns false,
+// and returns the error ending the stream.
+func (f *streamingFunctionTool[TArgs, TResults]) stream(ctx tool.Context, input TArgs, yield func(map[string]any) bool) (err error) {
+	// A panic of the handler is reported as an error, while a panic of the
+	// consumer is propagated.
+	var yielding bool
+	defer func() {
+		if yielding {
+			return
+		}
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in tool %q: %v\nstack: %s", f.Name(), r, debug.Stack())
+		}
+	}()
+	for output, err := range f.handler(ctx, input) {
+		if err != nil {
+			return err
+		}
+		resp, err := f.output(output)
+		if err != nil {
+			return err
+		}
+		yielding = true
+		ok := yield(resp)
+		yielding = false
+		if !ok {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool_test
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type countArgs struct {
+	N int `json:"n"`
+}
+
+type countResult struct {
+	Count int `json:"count"`
+}
+
+func count(ctx tool.Context, args countArgs) iter.Seq2[countResult, error] {
+	return func(yield func(countResult, error) bool) {
+		for i := 1; i <= args.N; i++ {
+			if !yield(countResult{Count: i}, nil) {
+				return
+			}
+		}
+	}
+}
+
+func newStreamingTool(t *testing.T, handler functiontool.StreamingFunc[countArgs, countResult]) toolinternal.StreamingFunctionTool {
+	t.Helper()
+	countTool, err := functiontool.NewStreaming(functiontool.Config{
+		Name:        "count",
+		Description: "counts up to n",
+	}, handler)
+	if err != nil {
+		t.Fatalf("NewStreaming() error = %v", err)
+	}
+	st, ok := countTool.(toolinternal.StreamingFunctionTool)
+	if !ok {
+		t.Fatalf("NewStreaming() = %T, want a StreamingFunctionTool", countTool)
+	}
+	return st
+}
+
+func newToolContext(t *testing.T) tool.Context {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a})
+	return toolinternal.NewToolContext(invCtx, "call1", &session.EventActions{}, nil)
+}
+
+func TestNewStreaming(t *testing.T) {
+	st := newStreamingTool(t, count)
+	ctx := newToolContext(t)
+
+	var got []map[string]any
+	for result, err := range st.RunStream(ctx, map[string]any{"n": 3}) {
+		if err != nil {
+			t.Fatalf("RunStream() error = %v", err)
+		}
+		got = append(got, result)
+	}
+	want := []map[string]any{{"count": 1.0}, {"count": 2.0}, {"count": 3.0}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RunStream() mismatch (-want +got):\n%s", diff)
+	}
+
+	result, err := st.Run(ctx, map[string]any{"n": 3})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"count": 3.0}, result); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewStreaming_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler functiontool.StreamingFunc[countArgs, countResult]
+		wantErr string
+	}{
+		{
+			name: "error",
+			handler: func(ctx tool.Context, args countArgs) iter.Seq2[countResult, error] {
+				return func(yield func(countResult, error) bool) {
+					if !yield(countResult{Count: 1}, nil) {
+						return
+					}
+					yield(countResult{}, errors.New("boom"))
+				}
+			},
+			wantErr: "boom",
+		},
+		{
+			name: "panic",
+			handler: func(ctx tool.Context, args countArgs) iter.Seq2[countResult, error] {
+				return func(yield func(countResult, error) bool) {
+					panic("boom")
+				}
+			},
+			wantErr: `panic in tool "count": boom`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st := newStreamingTool(t, tc.handler)
+			_, err := st.Run(newToolContext(t), map[string]any{"n": 1})
+			if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+				t.Errorf("Run() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewStreaming_ConsumerStops(t *testing.T) {
+	st := newStreamingTool(t, count)
+	var got int
+	for range st.RunStream(newToolContext(t), map[string]any{"n": 10}) {
+		got++
+		if got == 2 {
+			break
+		}
+	}
+	if got != 2 {
+		t.Errorf("got %d results, want 2", got)
+	}
+}
+
+func TestStreamingFunctionFlow(t *testing.T) {
+	mockModel := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("count", map[string]any{"n": 3}, genai.RoleModel),
+		genai.NewContentFromText("counted", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: mockModel,
+		Tools: []tool.Tool{newStreamingTool(t, count)},
+	})
+	if err != nil {
+		t.Fatalf("failed to create llm agent: %v", err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+
+	events, err := testutil.CollectEvents(runner.Run(t, "session", "count to 3"))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	var got []string
+	for _, ev := range events {
+		for _, part := range ev.Content.Parts {
+			switch {
+			case part.FunctionResponse != nil:
+				got = append(got, fmt.Sprintf("partial=%t %v", ev.Partial, part.FunctionResponse.Response))
+			case part.Text != "":
+				got = append(got, part.Text)
+			}
+		}
+	}
+	want := []string{
+		"partial=true map[count:1]",
+		"partial=true map[count:2]",
+		"partial=true map[count:3]",
+		"partial=false map[count:3]",
+		"counted",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", diff)
+	}
+
+	// Only the final result is sent to the model.
+	req := mockModel.Requests[len(mockModel.Requests)-1]
+	var responses []map[string]any
+	for _, content := range req.Contents {
+		for _, part := range content.Parts {
+			if part.FunctionResponse != nil {
+				responses = append(responses, part.FunctionResponse.Response)
+			}
+		}
+	}
+	if diff := cmp.Diff([]map[string]any{{"count": 3.0}}, responses); diff != "" {
+		t.Errorf("function responses sent to the model mismatch (-want +got):\n%s", diff)
+	}
+}
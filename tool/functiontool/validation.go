@@ -0,0 +1,292 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/typeutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// OutputValidation is how strictly the results of a tool are validated
+// against its output schema.
+type OutputValidation int
+
+const (
+	// OutputValidationStrict fails the calls whose result does not match
+	// the output schema.
+	OutputValidationStrict OutputValidation = iota
+	// OutputValidationWarn logs the results which do not match the output
+	// schema with the logger of the Config, if any, and returns them to the
+	// model.
+	OutputValidationWarn
+	// OutputValidationOff returns the results without validating them.
+	OutputValidationOff
+)
+
+// FieldError is an argument of a call which does not match the input
+// schema of the tool.
+type FieldError struct {
+	// Field is the path of the argument, like "address.city" or
+	// "items[2]", or empty if the arguments as a whole are invalid.
+	Field string `json:"field,omitempty"`
+	// Message describes the problem.
+	Message string `json:"error"`
+}
+
+// ValidationError is returned by the calls whose arguments do not match the
+// input schema of the tool. The invalid fields are reported to the model so
+// that it can fix them.
+type ValidationError struct {
+	// Tool is the name of the tool.
+	Tool string
+	// Fields are the invalid arguments.
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var fields []string
+	for _, f := range e.Fields {
+		if f.Field == "" {
+			fields = append(fields, f.Message)
+		} else {
+			fields = append(fields, f.Field+": "+f.Message)
+		}
+	}
+	return fmt.Sprintf("invalid arguments for tool %q: %s", e.Tool, strings.Join(fields, "; "))
+}
+
+// Unwrap returns ErrInvalidArgument.
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidArgument
+}
+
+// ErrorDetails returns the invalid fields, reported to the model with the
+// error.
+func (e *ValidationError) ErrorDetails() map[string]any {
+	fields := make([]any, len(e.Fields))
+	for i, f := range e.Fields {
+		field := map[string]any{"error": f.Message}
+		if f.Field != "" {
+			field["field"] = f.Field
+		}
+		fields[i] = field
+	}
+	return map[string]any{
+		"invalid_arguments": fields,
+		"hint":              "Fix the invalid arguments according to the parameters schema of the tool and call it again.",
+	}
+}
+
+// convertArgs converts the arguments of a call to the input of the handler,
+// reporting the arguments not matching the schema as a ValidationError.
+func (f *functionTool[TArgs, TResults]) convertArgs(args map[string]any) (TArgs, error) {
+	input, err := typeutil.ConvertToWithJSONSchema[map[string]any, TArgs](args, f.inputSchema)
+	if err == nil {
+		return input, nil
+	}
+	verr := &ValidationError{Tool: f.Name()}
+	if f.inputSchema != nil {
+		// Validate the JSON form of the arguments, like the conversion.
+		var value any
+		if b, err := json.Marshal(args); err == nil && json.Unmarshal(b, &value) == nil {
+			verr.Fields = fieldErrors(f.inputSchema.Schema(), value, "")
+		}
+	}
+	if len(verr.Fields) == 0 {
+		verr.Fields = []FieldError{{Message: validationMessage(err)}}
+	}
+	return input, verr
+}
+
+// repairArgs asks the repair model to fix the arguments of a call which do
+// not match the input schema, and returns the converted fixed arguments.
+func (f *functionTool[TArgs, TResults]) repairArgs(ctx tool.Context, args map[string]any, verr *ValidationError) (TArgs, error) {
+	var zero TArgs
+	argsJSON, err := json.Marshal(args)
+	if err != nil {
+		return zero, err
+	}
+	errorsJSON, err := json.Marshal(verr.Fields)
+	if err != nil {
+		return zero, err
+	}
+	prompt := fmt.Sprintf("The arguments of a call of the tool %q do not match its parameters schema. "+
+		"Fix them, changing as little as possible and keeping the intent of the call. "+
+		"Respond with the fixed arguments only, as a JSON object.\n\n"+
+		"Tool description: %s\n\nArguments: %s\n\nErrors: %s", f.Name(), f.Description(), argsJSON, errorsJSON)
+	config := &genai.GenerateContentConfig{ResponseMIMEType: "application/json"}
+	if f.inputSchema != nil {
+		config.ResponseJsonSchema = f.inputSchema.Schema()
+	}
+	req := &model.LLMRequest{
+		Model:    f.cfg.RepairModel.Name(),
+		Contents: []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)},
+		Config:   config,
+	}
+	var resp *model.LLMResponse
+	for r, err := range f.cfg.RepairModel.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return zero, err
+		}
+		resp = r
+	}
+	if resp == nil || resp.Content == nil {
+		return zero, errors.New("repair model returned no content")
+	}
+	var text strings.Builder
+	for _, p := range resp.Content.Parts {
+		if !p.Thought {
+			text.WriteString(p.Text)
+		}
+	}
+	var fixed map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(text.String())), &fixed); err != nil {
+		return zero, fmt.Errorf("repair model returned invalid JSON: %w", err)
+	}
+	return f.convertArgs(fixed)
+}
+
+// validateOutput validates a response of the tool against its output
+// schema according to the configured strictness.
+func (f *functionTool[TArgs, TResults]) validateOutput(resp map[string]any) error {
+	if f.outputSchema == nil || f.cfg.OutputValidation == OutputValidationOff {
+		return nil
+	}
+	err := f.outputSchema.Validate(resp)
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("result of tool %q does not match its output schema: %s", f.Name(), validationMessage(err))
+	if f.cfg.OutputValidation == OutputValidationWarn {
+		if f.cfg.Logger != nil {
+			f.cfg.Logger.Print(err)
+		}
+		return nil
+	}
+	return err
+}
+
+// fieldErrors returns the errors of the fields of the value which do not
+// match the schema, descending into the properties of objects and the items
+// of arrays.
+func fieldErrors(schema *jsonschema.Schema, value any, path string) []FieldError {
+	if schema == nil {
+		return nil
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		if schema.Properties == nil && schema.AdditionalProperties == nil {
+			break
+		}
+		var errs []FieldError
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				errs = append(errs, FieldError{Field: joinPath(path, name), Message: "missing required field"})
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			prop, ok := schema.Properties[name]
+			if !ok {
+				prop = schema.AdditionalProperties
+			}
+			if isFalseSchema(prop) {
+				errs = append(errs, FieldError{Field: joinPath(path, name), Message: "unexpected field"})
+				continue
+			}
+			errs = append(errs, fieldErrors(prop, v[name], joinPath(path, name))...)
+		}
+		// Other constraints of the object, like its number of properties.
+		if len(errs) == 0 {
+			errs = leafErrors(&jsonschema.Schema{
+				Type: schema.Type, MinProperties: schema.MinProperties, MaxProperties: schema.MaxProperties,
+			}, value, path)
+		}
+		return errs
+	case []any:
+		if schema.Items == nil {
+			break
+		}
+		var errs []FieldError
+		for i, item := range v {
+			errs = append(errs, fieldErrors(schema.Items, item, path+"["+strconv.Itoa(i)+"]")...)
+		}
+		if len(errs) == 0 {
+			errs = leafErrors(&jsonschema.Schema{
+				Type: schema.Type, MinItems: schema.MinItems, MaxItems: schema.MaxItems, UniqueItems: schema.UniqueItems,
+			}, value, path)
+		}
+		return errs
+	}
+	return leafErrors(schema, value, path)
+}
+
+// leafErrors validates the value against the schema as a whole.
+func leafErrors(schema *jsonschema.Schema, value any, path string) []FieldError {
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		// Like schemas with references to definitions of the root.
+		return nil
+	}
+	if err := resolved.Validate(value); err != nil {
+		return []FieldError{{Field: path, Message: validationMessage(err)}}
+	}
+	return nil
+}
+
+// isFalseSchema reports whether the schema is the false schema, which
+// matches no value.
+func isFalseSchema(s *jsonschema.Schema) bool {
+	if s == nil {
+		return false
+	}
+	b, err := json.Marshal(s)
+	return err == nil && string(b) == "false"
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// validationMessage returns the message of a validation error without the
+// location prefixes of jsonschema, like "validating root: ".
+func validationMessage(err error) string {
+	msg := err.Error()
+	for {
+		i := strings.Index(msg, ": ")
+		if !strings.HasPrefix(msg, "validating ") || i < 0 {
+			return msg
+		}
+		msg = msg[i+2:]
+	}
+}
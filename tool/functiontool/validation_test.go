@@ -0,0 +1,202 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool_test
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type Address struct {
+	City string `json:"city"`
+	Zip  int    `json:"zip"`
+}
+
+type OrderArgs struct {
+	Item     string    `json:"item"`
+	Quantity int       `json:"quantity"`
+	Address  Address   `json:"address"`
+	Tags     []Address `json:"tags,omitempty"`
+}
+
+func newOrderTool(t *testing.T, cfg functiontool.Config, got *OrderArgs) toolinternal.FunctionTool {
+	t.Helper()
+	cfg.Name = "order"
+	cfg.Description = "Orders an item."
+	orderTool, err := functiontool.New(cfg, func(ctx tool.Context, args OrderArgs) (map[string]any, error) {
+		*got = args
+		return map[string]any{"ok": true}, nil
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	return orderTool.(toolinternal.FunctionTool)
+}
+
+func TestFunctionTool_ValidationError(t *testing.T) {
+	var got OrderArgs
+	orderTool := newOrderTool(t, functiontool.Config{}, &got)
+
+	_, err := orderTool.Run(createToolContext(t), map[string]any{
+		"item":    "book",
+		"address": map[string]any{"city": 1, "zip": 75001},
+		"tags":    []any{map[string]any{"city": "Paris"}},
+		"color":   "red",
+	})
+	var verr *functiontool.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Run() error = %v, want a ValidationError", err)
+	}
+	if !errors.Is(err, functiontool.ErrInvalidArgument) {
+		t.Errorf("Run() error = %v, want ErrInvalidArgument", err)
+	}
+	want := []functiontool.FieldError{
+		{Field: "quantity", Message: "missing required field"},
+		{Field: "address.city", Message: `type: 1 has type "integer", want "string"`},
+		{Field: "color", Message: "unexpected field"},
+		{Field: "tags[0].zip", Message: "missing required field"},
+	}
+	if diff := cmp.Diff(want, verr.Fields); diff != "" {
+		t.Errorf("ValidationError fields mismatch (-want +got):\n%s", diff)
+	}
+	details := verr.ErrorDetails()
+	if fields, ok := details["invalid_arguments"].([]any); !ok || len(fields) != 4 {
+		t.Errorf("ErrorDetails() = %v, want the 4 invalid arguments", details)
+	}
+}
+
+func TestFunctionTool_RepairModel(t *testing.T) {
+	invalid := map[string]any{"item": "book", "quantity": "2", "address": map[string]any{"city": "Paris", "zip": 75001}}
+
+	t.Run("repaired", func(t *testing.T) {
+		repairModel := &testutil.MockModel{Responses: []*genai.Content{
+			genai.NewContentFromText(`{"item": "book", "quantity": 2, "address": {"city": "Paris", "zip": 75001}}`, genai.RoleModel),
+		}}
+		var got OrderArgs
+		orderTool := newOrderTool(t, functiontool.Config{RepairModel: repairModel}, &got)
+		if _, err := orderTool.Run(createToolContext(t), invalid); err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if want := (OrderArgs{Item: "book", Quantity: 2, Address: Address{City: "Paris", Zip: 75001}}); !cmp.Equal(want, got) {
+			t.Errorf("handler args = %+v, want %+v", got, want)
+		}
+		if len(repairModel.Requests) != 1 {
+			t.Fatalf("repair model got %d requests, want 1", len(repairModel.Requests))
+		}
+		req := repairModel.Requests[0]
+		if req.Config.ResponseJsonSchema == nil || req.Config.ResponseMIMEType != "application/json" {
+			t.Errorf("repair request config = %+v, want JSON with the input schema", req.Config)
+		}
+		if prompt := req.Contents[0].Parts[0].Text; !strings.Contains(prompt, `"field":"quantity"`) {
+			t.Errorf("repair prompt = %q, want the invalid fields", prompt)
+		}
+	})
+
+	t.Run("still invalid", func(t *testing.T) {
+		repairModel := &testutil.MockModel{Responses: []*genai.Content{
+			genai.NewContentFromText(`{"item": "book"}`, genai.RoleModel),
+		}}
+		var got OrderArgs
+		orderTool := newOrderTool(t, functiontool.Config{RepairModel: repairModel}, &got)
+		_, err := orderTool.Run(createToolContext(t), invalid)
+		var verr *functiontool.ValidationError
+		if !errors.As(err, &verr) || len(verr.Fields) != 1 || verr.Fields[0].Field != "quantity" {
+			t.Errorf("Run() error = %v, want the original validation error", err)
+		}
+	})
+
+	// The arguments are not repaired, as the repaired call would differ
+	// from the one confirmed.
+	for _, tc := range []struct {
+		name string
+		cfg  functiontool.Config
+	}{
+		{name: "confirmation required", cfg: functiontool.Config{RequireConfirmation: true}},
+		{name: "confirmation provider", cfg: functiontool.Config{RequireConfirmationProvider: func(OrderArgs) bool { return false }}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repairModel := &testutil.MockModel{Responses: []*genai.Content{
+				genai.NewContentFromText(`{"item": "book", "quantity": 2, "address": {"city": "Paris", "zip": 75001}}`, genai.RoleModel),
+			}}
+			tc.cfg.RepairModel = repairModel
+			var got OrderArgs
+			orderTool := newOrderTool(t, tc.cfg, &got)
+			_, err := orderTool.Run(createToolContext(t), invalid)
+			if !errors.As(err, new(*functiontool.ValidationError)) {
+				t.Errorf("Run() error = %v, want a validation error", err)
+			}
+			if len(repairModel.Requests) != 0 {
+				t.Errorf("repair model got %d requests, want none", len(repairModel.Requests))
+			}
+		})
+	}
+}
+
+func TestFunctionTool_OutputValidation(t *testing.T) {
+	outputSchema := &jsonschema.Schema{
+		Type:       "object",
+		Properties: map[string]*jsonschema.Schema{"count": {Type: "integer"}},
+		Required:   []string{"count"},
+	}
+	for _, tc := range []struct {
+		name       string
+		validation functiontool.OutputValidation
+		wantErr    bool
+	}{
+		{name: "strict", validation: functiontool.OutputValidationStrict, wantErr: true},
+		{name: "warn", validation: functiontool.OutputValidationWarn},
+		{name: "off", validation: functiontool.OutputValidationOff},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs strings.Builder
+			countTool, err := functiontool.New(functiontool.Config{
+				Name:             "count",
+				OutputSchema:     outputSchema,
+				OutputValidation: tc.validation,
+				Logger:           log.New(&logs, "", 0),
+			}, func(ctx tool.Context, args map[string]any) (map[string]any, error) {
+				return map[string]any{"count": "many"}, nil
+			})
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			got, err := countTool.(toolinternal.FunctionTool).Run(createToolContext(t), map[string]any{})
+			if tc.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "does not match its output schema") {
+					t.Errorf("Run() = %v, %v, want an output validation error", got, err)
+				}
+				return
+			}
+			if err != nil || got["count"] != "many" {
+				t.Errorf("Run() = %v, %v, want the result", got, err)
+			}
+			logged := strings.Contains(logs.String(), "does not match its output schema")
+			if want := tc.validation == functiontool.OutputValidationWarn; logged != want {
+				t.Errorf("logged the invalid result = %v, want %v (logs: %q)", logged, want, logs.String())
+			}
+		})
+	}
+}
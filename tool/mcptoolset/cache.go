@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/tool"
+)
+
+// toolCache caches the tool listing of a server until it expires or the
+// server notifies of a list change.
+type toolCache struct {
+	mu sync.Mutex
+	// generation is incremented by every invalidation, so that results
+	// fetched concurrently with one are not cached.
+	generation uint64
+	tools      []*mcp.Tool
+	valid      bool
+	// expires is the expiry of the listing, zero if it does not expire.
+	expires time.Time
+}
+
+func (c *toolCache) cached(now time.Time) ([]*mcp.Tool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.valid || (!c.expires.IsZero() && !now.Before(c.expires)) {
+		return nil, false
+	}
+	return c.tools, true
+}
+
+func (c *toolCache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+func (c *toolCache) store(tools []*mcp.Tool, generation uint64, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.tools, c.valid, c.expires = tools, true, expires
+	}
+}
+
+// invalidate drops the cached listing after the server reported a change
+// of the tool list or the session was lost.
+func (c *toolCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.tools, c.valid, c.expires = nil, false, time.Time{}
+}
+
+// listExpiry reports whether a listing of the server can be cached and
+// until when, according to toolListTTL. Listings are only cached if
+// toolListTTL is positive.
+func (c *connectionRefresher) listExpiry(now time.Time) (time.Time, bool) {
+	if c.toolListTTL <= 0 {
+		return time.Time{}, false
+	}
+	return now.Add(c.toolListTTL), true
+}
+
+// maxMemoizedInvocations bounds the number of invocations the tools of a
+// toolset are memoised for.
+const maxMemoizedInvocations = 64
+
+// invocationTools memoises the tools of a toolset per invocation, so that
+// the tools stay the same across the LLM requests of an invocation and the
+// server is not asked for them on each request.
+type invocationTools struct {
+	mu sync.Mutex
+	// order holds the memoised invocation IDs, oldest first.
+	order []string
+	tools map[string][]tool.Tool
+}
+
+func (m *invocationTools) get(invocationID string) ([]tool.Tool, bool) {
+	if invocationID == "" {
+		return nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tools, ok := m.tools[invocationID]
+	return tools, ok
+}
+
+func (m *invocationTools) put(invocationID string, tools []tool.Tool) {
+	if invocationID == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tools == nil {
+		m.tools = map[string][]tool.Tool{}
+	}
+	if _, ok := m.tools[invocationID]; !ok {
+		m.order = append(m.order, invocationID)
+	}
+	m.tools[invocationID] = tools
+	for len(m.order) > maxMemoizedInvocations {
+		delete(m.tools, m.order[0])
+		m.order = m.order[1:]
+	}
+}
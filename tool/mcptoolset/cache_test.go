@@ -0,0 +1,305 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/mcptoolset"
+	"google.golang.org/adk/tool/toolpolicy"
+)
+
+// countingServer returns a server with a single echo tool, counting the
+// tool list requests it receives.
+func countingServer(t *testing.T, lists *atomic.Int32) *mcp.Server {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test_server", Version: "v1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "echo", InputSchema: &jsonschema.Schema{Type: "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "echo"}}}, nil
+	})
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "tools/list" {
+				lists.Add(1)
+			}
+			return next(ctx, method, req)
+		}
+	})
+	return server
+}
+
+func connectToolset(t *testing.T, server *mcp.Server, cfg mcptoolset.Config) tool.Toolset {
+	t.Helper()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Transport = clientTransport
+	ts, err := mcptoolset.New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create MCP tool set: %v", err)
+	}
+	return ts
+}
+
+func newInvocation(t *testing.T) agent.InvocationContext {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a})
+}
+
+func toolNames(t *testing.T, ts tool.Toolset, invCtx agent.InvocationContext) []string {
+	t.Helper()
+	tools, err := ts.Tools(icontext.NewReadonlyContext(invCtx))
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	var names []string
+	for _, tl := range tools {
+		names = append(names, tl.Name())
+	}
+	return names
+}
+
+func TestMCPToolSet_ToolListInvalidatedOnChange(t *testing.T) {
+	var lists atomic.Int32
+	server := countingServer(t, &lists)
+	ts := connectToolset(t, server, mcptoolset.Config{ToolListTTL: time.Hour})
+
+	inv := newInvocation(t)
+	toolNames(t, ts, inv)
+	toolNames(t, ts, inv)
+	toolNames(t, ts, newInvocation(t))
+	if got := lists.Load(); got != 1 {
+		t.Fatalf("server got %d tool list requests, want 1", got)
+	}
+
+	server.AddTool(&mcp.Tool{Name: "added", InputSchema: &jsonschema.Schema{Type: "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	// The tools of an invocation do not change.
+	if got := toolNames(t, ts, inv); len(got) != 1 {
+		t.Errorf("Tools() in the same invocation = %v, want the memoised tools", got)
+	}
+	// The list change notification is delivered asynchronously.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if got := toolNames(t, ts, newInvocation(t)); len(got) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Tools() did not return the added tool after the list change notification")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMCPToolSet_ToolListTTL(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		ttl       time.Duration
+		wantLists int32
+	}{
+		{name: "disabled", wantLists: 2},
+		{name: "expired", ttl: time.Nanosecond, wantLists: 2},
+		{name: "valid", ttl: time.Hour, wantLists: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var lists atomic.Int32
+			ts := connectToolset(t, countingServer(t, &lists), mcptoolset.Config{ToolListTTL: tc.ttl})
+			toolNames(t, ts, newInvocation(t))
+			time.Sleep(time.Millisecond)
+			toolNames(t, ts, newInvocation(t))
+			if got := lists.Load(); got != tc.wantLists {
+				t.Errorf("server got %d tool list requests, want %d", got, tc.wantLists)
+			}
+		})
+	}
+}
+
+func TestMCPToolSet_ToolNamePrefix(t *testing.T) {
+	var lists atomic.Int32
+	server := countingServer(t, &lists)
+	server.AddResource(&mcp.Resource{URI: "file:///readme.txt", Name: "readme"}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "readme"}}}, nil
+	})
+	ts := connectToolset(t, server, mcptoolset.Config{ToolNamePrefix: "docs", IncludeResources: true})
+
+	inv := newInvocation(t)
+	tools, err := ts.Tools(icontext.NewReadonlyContext(inv))
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	if len(tools) != 2 || tools[0].Name() != "docs_echo" || tools[1].Name() != "docs_"+mcptoolset.LoadResourceToolName {
+		t.Fatalf("Tools() = %v, want the prefixed echo and resource tools", tools)
+	}
+	echo := tools[0].(toolinternal.FunctionTool)
+	if got := echo.Declaration().Name; got != "docs_echo" {
+		t.Errorf("Declaration().Name = %q, want %q", got, "docs_echo")
+	}
+	result, err := echo.Run(toolinternal.NewToolContext(inv, "call1", nil, nil), map[string]any{})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if got := result["output"]; got != "echo" {
+		t.Errorf("Run() output = %v, want %q", got, "echo")
+	}
+}
+
+func TestMCPToolSet_CallTimeoutAndRetries(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		retries      int
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{name: "retried", retries: 1, wantAttempts: 2},
+		{name: "no retries", retries: 0, wantAttempts: 1, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := mcp.NewServer(&mcp.Implementation{Name: "slow_server", Version: "v1.0.0"}, nil)
+			server.AddTool(&mcp.Tool{Name: "slow", InputSchema: &jsonschema.Schema{Type: "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				if attempts.Add(1) == 1 {
+					// The first attempt hangs until it is cancelled.
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil
+			})
+			var logs strings.Builder
+			fnTool, invCtx := newTestTool(t, server, mcptoolset.Config{CallTimeout: 50 * time.Millisecond, CallRetries: tc.retries, Logger: log.New(&logs, "", 0)})
+
+			result, err := fnTool.Run(toolinternal.NewToolContext(invCtx, "call1", nil, nil), map[string]any{})
+			if tc.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "timed out") {
+					t.Errorf("Run() error = %v, want a timeout", err)
+				}
+			} else if err != nil || result["output"] != "done" {
+				t.Errorf("Run() = %v, %v, want output %q", result, err, "done")
+			}
+			if got := attempts.Load(); got != tc.wantAttempts {
+				t.Errorf("server got %d calls, want %d", got, tc.wantAttempts)
+			}
+			if got, want := strings.Count(logs.String(), "retrying call"), int(tc.wantAttempts)-1; got != want {
+				t.Errorf("logged %d retries, want %d (logs: %q)", got, want, logs.String())
+			}
+		})
+	}
+}
+
+func TestMCPToolSet_Policy(t *testing.T) {
+	var calls atomic.Int32
+	server := mcp.NewServer(&mcp.Implementation{Name: "failing_server", Version: "v1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "fail", InputSchema: &jsonschema.Schema{Type: "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "failed"}}}, nil
+	})
+	ts := connectToolset(t, server, mcptoolset.Config{Policy: toolpolicy.Policy{
+		CircuitBreaker: &toolpolicy.CircuitBreaker{FailureThreshold: 2, Cooldown: time.Hour},
+	}})
+
+	// The tools are converted again for each invocation, the circuit
+	// breaker must count the failures of all of them.
+	run := func() error {
+		inv := newInvocation(t)
+		tools, err := ts.Tools(icontext.NewReadonlyContext(inv))
+		if err != nil {
+			t.Fatalf("Tools() failed: %v", err)
+		}
+		_, err = tools[0].(toolinternal.FunctionTool).Run(toolinternal.NewToolContext(inv, "call1", nil, nil), map[string]any{})
+		return err
+	}
+	for range 2 {
+		if err := run(); err == nil {
+			t.Fatal("Run() succeeded, want the tool error")
+		}
+	}
+	var policyErr *toolpolicy.Error
+	if err := run(); !errors.As(err, &policyErr) || policyErr.Reason != toolpolicy.ReasonCircuitOpen {
+		t.Errorf("Run() error = %v, want an open circuit", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server got %d calls, want 2", got)
+	}
+}
+
+func TestNew_InvalidPolicy(t *testing.T) {
+	if _, err := mcptoolset.New(mcptoolset.Config{Policy: toolpolicy.Policy{MaxConcurrency: -1}}); err == nil {
+		t.Error("New() with a negative concurrency succeeded, want error")
+	}
+}
+
+func TestMCPToolSet_ResourceListCached(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		ttl       time.Duration
+		wantLists int32
+	}{
+		// The resources are still listed once per invocation.
+		{name: "disabled", wantLists: 2},
+		{name: "valid", ttl: time.Hour, wantLists: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var toolLists, lists atomic.Int32
+			server := countingServer(t, &toolLists)
+			server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+				return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+					if method == "resources/list" {
+						lists.Add(1)
+					}
+					return next(ctx, method, req)
+				}
+			})
+			server.AddResource(&mcp.Resource{URI: "file:///readme.txt", Name: "readme"}, func(context.Context, *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+				return &mcp.ReadResourceResult{}, nil
+			})
+			ts := connectToolset(t, server, mcptoolset.Config{IncludeResources: true, ToolListTTL: tc.ttl})
+			for range 2 {
+				inv := newInvocation(t)
+				tools, err := ts.Tools(icontext.NewReadonlyContext(inv))
+				if err != nil {
+					t.Fatalf("Tools() failed: %v", err)
+				}
+				resourceTool := tools[len(tools)-1].(toolinternal.RequestProcessor)
+				for range 2 {
+					if err := resourceTool.ProcessRequest(toolinternal.NewToolContext(inv, "", nil, nil), &model.LLMRequest{}); err != nil {
+						t.Fatalf("ProcessRequest() failed: %v", err)
+					}
+				}
+			}
+			if got := lists.Load(); got != tc.wantLists {
+				t.Errorf("server got %d resource list requests, want %d", got, tc.wantLists)
+			}
+		})
+	}
+}
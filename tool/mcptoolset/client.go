@@ -22,6 +22,7 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -40,9 +41,19 @@ type MCPClient interface {
 type connectionRefresher struct {
 	client    *mcp.Client
 	transport mcp.Transport
+	// notified reports whether client delivers list change and resource
+	// notifications to the refresher, which is required to subscribe to
+	// resources.
+	notified bool
+	// toolListTTL is how long the tool listing is cached; see
+	// Config.ToolListTTL.
+	toolListTTL time.Duration
 
 	mu      sync.Mutex
 	session *mcp.ClientSession
+
+	tools     toolCache
+	resources resourceCache
 }
 
 // refreshableErrors is a list of errors that should trigger a connection refresh.
@@ -53,15 +64,32 @@ var refreshableErrors = []error{
 }
 
 // newConnectionRefresher creates a new connectionRefresher with the given client and transport.
-// If client is nil, a default MCP client will be created.
-func newConnectionRefresher(client *mcp.Client, transport mcp.Transport) *connectionRefresher {
-	if client == nil {
-		client = mcp.NewClient(&mcp.Implementation{Name: "adk-mcp-client", Version: version.Version}, nil)
-	}
-	return &connectionRefresher{
+// If client is nil, a default MCP client will be created with the given
+// options, which invalidates the cached tools and resources on change
+// notifications.
+func newConnectionRefresher(client *mcp.Client, transport mcp.Transport, opts *mcp.ClientOptions) *connectionRefresher {
+	c := &connectionRefresher{
 		client:    client,
 		transport: transport,
 	}
+	if client == nil {
+		var clientOpts mcp.ClientOptions
+		if opts != nil {
+			clientOpts = *opts
+		}
+		clientOpts.ToolListChangedHandler = func(context.Context, *mcp.ToolListChangedRequest) {
+			c.tools.invalidate()
+		}
+		clientOpts.ResourceListChangedHandler = func(context.Context, *mcp.ResourceListChangedRequest) {
+			c.resources.invalidate()
+		}
+		clientOpts.ResourceUpdatedHandler = func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			c.resources.updated(req.Params.URI)
+		}
+		c.client = mcp.NewClient(&mcp.Implementation{Name: "adk-mcp-client", Version: version.Version}, &clientOpts)
+		c.notified = true
+	}
+	return c
 }
 
 // CallTool calls a tool on the MCP server, automatically reconnecting if needed.
@@ -73,40 +101,70 @@ func (c *connectionRefresher) CallTool(ctx context.Context, params *mcp.CallTool
 }
 
 // ListTools lists all available tools from the MCP server, handling pagination
-// and automatically reconnecting if needed. Per MCP spec, cursors do not persist
-// across sessions, so pagination restarts from scratch after reconnection.
+// and automatically reconnecting if needed. The listing is cached according
+// to toolListTTL.
 func (c *connectionRefresher) ListTools(ctx context.Context) ([]*mcp.Tool, error) {
-	var tools []*mcp.Tool
+	if tools, ok := c.tools.cached(time.Now()); ok {
+		return tools, nil
+	}
+	generation := c.tools.currentGeneration()
+
+	tools, err := listAll(ctx, c, func(session *mcp.ClientSession, cursor string) ([]*mcp.Tool, string, error) {
+		resp, err := session.ListTools(ctx, &mcp.ListToolsParams{Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Tools, resp.NextCursor, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP tools: %w", err)
+	}
+	if expires, ok := c.listExpiry(time.Now()); ok {
+		c.tools.store(tools, generation, expires)
+	}
+	return tools, nil
+}
+
+// listAll collects all pages of a paginated MCP list operation, automatically
+// reconnecting if needed. Per MCP spec, cursors do not persist across
+// sessions, so pagination restarts from scratch after reconnection.
+func listAll[T any](ctx context.Context, c *connectionRefresher, list func(session *mcp.ClientSession, cursor string) ([]T, string, error)) ([]T, error) {
+	var items []T
 	cursor := ""
 	hasReconnected := false
 
 	for {
-		resp, reconnected, err := withRetry(ctx, c, func(session *mcp.ClientSession) (*mcp.ListToolsResult, error) {
-			return session.ListTools(ctx, &mcp.ListToolsParams{Cursor: cursor})
+		type page struct {
+			items      []T
+			nextCursor string
+		}
+		resp, reconnected, err := withRetry(ctx, c, func(session *mcp.ClientSession) (page, error) {
+			items, nextCursor, err := list(session, cursor)
+			return page{items, nextCursor}, err
 		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to list MCP tools: %w", err)
+			return nil, err
 		}
 		if reconnected {
 			if hasReconnected {
-				return nil, fmt.Errorf("failed to list MCP tools: connection lost again after reconnection")
+				return nil, fmt.Errorf("connection lost again after reconnection")
 			}
 			// On reconnection, restart pagination from scratch per MCP spec.
 			hasReconnected = true
 			cursor = ""
-			tools = nil
+			items = nil
 			continue
 		}
 
-		tools = append(tools, resp.Tools...)
+		items = append(items, resp.items...)
 
-		if resp.NextCursor == "" {
+		if resp.nextCursor == "" {
 			break
 		}
-		cursor = resp.NextCursor
+		cursor = resp.nextCursor
 	}
 
-	return tools, nil
+	return items, nil
 }
 
 // withRetry executes fn with the current session, and if it fails, attempts to refresh
@@ -180,6 +238,10 @@ func (c *connectionRefresher) refreshConnection(ctx context.Context) (*mcp.Clien
 			log.Printf("failed to close MCP session: %v", err)
 		}
 		c.session = nil
+		// Subscriptions do not survive the session and notifications may
+		// have been missed, so the caches are no longer reliable.
+		c.tools.invalidate()
+		c.resources.reset()
 	}
 
 	session, err := c.client.Connect(ctx, c.transport, nil)
@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	artifactinternal "google.golang.org/adk/internal/artifact"
+	"google.golang.org/adk/tool"
+)
+
+// DefaultMaxInlineDataSize is the default for [Config.MaxInlineDataSize].
+const DefaultMaxInlineDataSize = 1 << 20
+
+// attachmentsKey is the tool response key listing the non-text contents of
+// an MCP tool result.
+const attachmentsKey = "attachments"
+
+// contentConverter converts the MCP contents returned by the tool named
+// toolName.
+type contentConverter struct {
+	toolName          string
+	maxInlineDataSize int
+}
+
+// toolContent is an MCP tool result converted for the model.
+type toolContent struct {
+	text        strings.Builder
+	attachments []map[string]any
+}
+
+// convertContent converts the contents of an MCP tool result. Text is
+// concatenated; images, audio and binary resources are attached to the
+// response with [tool.AttachParts], or saved as artifacts when larger than
+// the inline data limit; other resources are described in the attachments.
+func (cc *contentConverter) convertContent(ctx tool.Context, contents []mcp.Content) (*toolContent, error) {
+	out := &toolContent{}
+	for i, c := range contents {
+		switch c := c.(type) {
+		case *mcp.TextContent:
+			out.text.WriteString(c.Text)
+		case *mcp.ImageContent:
+			if err := cc.addBinary(ctx, out, i, "image", c.MIMEType, c.Data, ""); err != nil {
+				return nil, err
+			}
+		case *mcp.AudioContent:
+			if err := cc.addBinary(ctx, out, i, "audio", c.MIMEType, c.Data, ""); err != nil {
+				return nil, err
+			}
+		case *mcp.EmbeddedResource:
+			r := c.Resource
+			if r == nil {
+				continue
+			}
+			if r.Blob != nil {
+				if err := cc.addBinary(ctx, out, i, "resource", r.MIMEType, r.Blob, r.URI); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			out.attachments = append(out.attachments, withoutEmpty(map[string]any{
+				"type":     "resource",
+				"uri":      r.URI,
+				"mimeType": r.MIMEType,
+				"text":     r.Text,
+			}))
+		case *mcp.ResourceLink:
+			out.attachments = append(out.attachments, withoutEmpty(map[string]any{
+				"type":        "resource_link",
+				"uri":         c.URI,
+				"name":        c.Name,
+				"description": c.Description,
+				"mimeType":    c.MIMEType,
+			}))
+		}
+	}
+	return out, nil
+}
+
+// addBinary adds binary content to out, saving it as an artifact if it
+// exceeds the inline data limit and an artifact service is available.
+func (cc *contentConverter) addBinary(ctx tool.Context, out *toolContent, index int, kind, mimeType string, data []byte, uri string) error {
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	attachment := withoutEmpty(map[string]any{
+		"type":     kind,
+		"mimeType": mimeType,
+		"uri":      uri,
+	})
+	if len(data) > cc.maxInlineDataSize && ctx.Artifacts() != nil {
+		name := artifactinternal.FileName(fmt.Sprintf("%s_%s_%d", cc.toolName, ctx.FunctionCallID(), index), mimeType)
+		resp, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(data, mimeType))
+		if err != nil {
+			return fmt.Errorf("failed to save %s content of MCP tool %q as artifact: %w", kind, cc.toolName, err)
+		}
+		attachment["artifact"] = name
+		attachment["version"] = resp.Version
+	} else {
+		// The attached parts are passed to the model with the function
+		// response, never in its JSON where they would be stored in the
+		// session.
+		if err := tool.AttachParts(ctx, genai.NewPartFromBytes(data, mimeType)); err != nil {
+			return fmt.Errorf("failed to attach %s content of MCP tool %q: %w", kind, cc.toolName, err)
+		}
+		attachment["inline"] = true
+	}
+	out.attachments = append(out.attachments, attachment)
+	return nil
+}
+
+func withoutEmpty(m map[string]any) map[string]any {
+	maps.DeleteFunc(m, func(_ string, v any) bool { return v == "" })
+	return m
+}
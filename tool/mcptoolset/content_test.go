@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	artifactinternal "google.golang.org/adk/internal/artifact"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool/mcptoolset"
+)
+
+func TestMCPToolSet_BinaryContent(t *testing.T) {
+	smallImage := []byte("small png")
+	largeImage := bytes.Repeat([]byte("x"), 64)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "chart_server", Version: "v1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "draw_chart", InputSchema: &jsonschema.Schema{Type: "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{
+			&mcp.TextContent{Text: "Here is your chart."},
+			&mcp.ImageContent{Data: smallImage, MIMEType: "image/png"},
+			&mcp.ImageContent{Data: largeImage, MIMEType: "image/png"},
+			&mcp.ResourceLink{URI: "file:///data.csv", Name: "data.csv", MIMEType: "text/csv"},
+		}}, nil
+	})
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := mcptoolset.New(mcptoolset.Config{Transport: clientTransport, MaxInlineDataSize: 32})
+	if err != nil {
+		t.Fatalf("Failed to create MCP tool set: %v", err)
+	}
+
+	a, err := agent.New(agent.Config{Name: "charts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifacts := &artifactinternal.Artifacts{Service: artifact.InMemoryService(), AppName: "app", UserID: "user", SessionID: "session"}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a, Artifacts: artifacts})
+	toolCtx := toolinternal.NewToolContext(invCtx, "call1", nil, nil)
+
+	tools, err := ts.Tools(icontext.NewReadonlyContext(invCtx))
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	fnTool := tools[0].(toolinternal.FunctionTool)
+	result, err := fnTool.Run(toolCtx, map[string]any{})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	wantAttachments := []map[string]any{
+		{"type": "image", "mimeType": "image/png", "inline": true},
+		{"type": "image", "mimeType": "image/png", "artifact": "draw_chart_call1_2.png", "version": int64(1)},
+		{"type": "resource_link", "uri": "file:///data.csv", "name": "data.csv", "mimeType": "text/csv"},
+	}
+	if diff := cmp.Diff(wantAttachments, result["attachments"]); diff != "" {
+		t.Errorf("Run() attachments mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := result["output"], "Here is your chart."; got != want {
+		t.Errorf("Run() output = %v, want %q", got, want)
+	}
+	saved, err := artifacts.Load(t.Context(), "draw_chart_call1_2.png")
+	if err != nil {
+		t.Fatalf("Load() of saved artifact failed: %v", err)
+	}
+	if !bytes.Equal(saved.Part.InlineData.Data, largeImage) {
+		t.Errorf("saved artifact = %q, want large image", saved.Part.InlineData.Data)
+	}
+	if got := toolCtx.Actions().ArtifactDelta; got["draw_chart_call1_2.png"] != 1 {
+		t.Errorf("ArtifactDelta = %v, want the saved artifact", got)
+	}
+
+	// The inline image is attached to the response rather than put in its
+	// JSON, which is stored in the session.
+	if _, ok := result["inline_data"]; ok {
+		t.Error("Run() put inline data in the function response")
+	}
+	wantParts := []*genai.Part{genai.NewPartFromBytes(smallImage, "image/png")}
+	if diff := cmp.Diff(wantParts, toolinternal.AttachedParts(toolCtx)); diff != "" {
+		t.Errorf("Run() attached parts mismatch (-want +got):\n%s", diff)
+	}
+}
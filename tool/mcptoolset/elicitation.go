@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+// elicitations maps the elicitation requests of an MCP server onto the tool
+// confirmation flow.
+//
+// MCP servers elicit information while handling a tool call and wait for
+// the answer, while tool confirmations are answered in a later invocation
+// which runs the tool again. So the first elicitation of a call is cancelled
+// and turned into a confirmation request; when the tool runs again with the
+// confirmation, the repeated elicitation is answered with it.
+type elicitations struct {
+	logger *log.Logger
+
+	mu    sync.Mutex
+	calls map[*pendingCall]bool
+}
+
+// pendingCall is a tool call in progress.
+type pendingCall struct {
+	toolName string
+	ctx      tool.Context
+	// answer is the confirmation the tool runs with, if any.
+	answer *toolconfirmation.ToolConfirmation
+	// answered reports whether answer was used for an elicitation.
+	answered bool
+	// requested reports whether a confirmation was requested for an
+	// elicitation.
+	requested bool
+}
+
+// begin registers a tool call in progress.
+func (e *elicitations) begin(toolName string, ctx tool.Context, answer *toolconfirmation.ToolConfirmation) *pendingCall {
+	call := &pendingCall{toolName: toolName, ctx: ctx, answer: answer}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.calls == nil {
+		e.calls = map[*pendingCall]bool{}
+	}
+	e.calls[call] = true
+	return call
+}
+
+// end unregisters a tool call and reports whether a confirmation was
+// requested during the call.
+func (e *elicitations) end(call *pendingCall) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.calls, call)
+	return call.requested
+}
+
+// handle answers an elicitation request of the server. Elicitations cannot
+// be related to a tool call by the protocol, so they are only handled while
+// a single tool call of the toolset is in progress and declined otherwise.
+func (e *elicitations) handle(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.calls) != 1 {
+		if e.logger != nil {
+			e.logger.Printf("declining MCP elicitation %q: %d tool calls in progress", req.Params.Message, len(e.calls))
+		}
+		return &mcp.ElicitResult{Action: "decline"}, nil
+	}
+	var call *pendingCall
+	for c := range e.calls {
+		call = c
+	}
+
+	switch {
+	case call.answer != nil && !call.answered:
+		call.answered = true
+		if !call.answer.Confirmed {
+			return &mcp.ElicitResult{Action: "decline"}, nil
+		}
+		content, _ := call.answer.Payload.(map[string]any)
+		return &mcp.ElicitResult{Action: "accept", Content: content}, nil
+	case call.answer == nil && !call.requested:
+		payload := map[string]any{"requestedSchema": req.Params.RequestedSchema}
+		if err := call.ctx.RequestConfirmation(req.Params.Message, payload); err != nil {
+			return nil, err
+		}
+		call.requested = true
+		return &mcp.ElicitResult{Action: "cancel"}, nil
+	default:
+		// Only a single elicitation per tool call can be answered.
+		return &mcp.ElicitResult{Action: "decline"}, nil
+	}
+}
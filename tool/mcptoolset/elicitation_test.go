@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool/mcptoolset"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+func TestMCPToolSet_Elicitation(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "weather_server", Version: "v1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "get_weather", InputSchema: &jsonschema.Schema{Type: "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		res, err := req.Session.Elicit(ctx, &mcp.ElicitParams{
+			Message: "Which city?",
+			RequestedSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{"city": {Type: "string"}},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		text := "no city: " + res.Action
+		if res.Action == "accept" {
+			text = "Sunny in " + res.Content["city"].(string)
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil
+	})
+	fnTool, invCtx := newTestTool(t, server, mcptoolset.Config{HandleElicitation: true})
+
+	// The elicitation is turned into a confirmation request.
+	toolCtx := toolinternal.NewToolContext(invCtx, "call1", nil, nil)
+	if _, err := fnTool.Run(toolCtx, map[string]any{}); err == nil {
+		t.Fatal("Run() succeeded, want confirmation required error")
+	}
+	requested, ok := toolCtx.Actions().RequestedToolConfirmations["call1"]
+	if !ok {
+		t.Fatalf("Run() did not request a confirmation, actions: %+v", toolCtx.Actions())
+	}
+	if requested.Hint != "Which city?" {
+		t.Errorf("requested confirmation hint = %q, want the elicitation message", requested.Hint)
+	}
+
+	for _, tc := range []struct {
+		name         string
+		confirmation *toolconfirmation.ToolConfirmation
+		want         string
+	}{
+		{
+			name:         "confirmed",
+			confirmation: &toolconfirmation.ToolConfirmation{Confirmed: true, Payload: map[string]any{"city": "Paris"}},
+			want:         "Sunny in Paris",
+		},
+		{
+			name:         "rejected",
+			confirmation: &toolconfirmation.ToolConfirmation{Confirmed: false},
+			want:         "no city: decline",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			toolCtx := toolinternal.NewToolContext(invCtx, "call1", nil, tc.confirmation)
+			result, err := fnTool.Run(toolCtx, map[string]any{})
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if got := result["output"]; got != tc.want {
+				t.Errorf("Run() output = %v, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMCPToolSet_ElicitationDeclinedWithConcurrentCalls(t *testing.T) {
+	var arrived, answered sync.WaitGroup
+	arrived.Add(2)
+	answered.Add(2)
+	server := mcp.NewServer(&mcp.Implementation{Name: "weather_server", Version: "v1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "get_weather", InputSchema: &jsonschema.Schema{Type: "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Both calls are in progress when the elicitations are sent.
+		arrived.Done()
+		arrived.Wait()
+		res, err := req.Session.Elicit(ctx, &mcp.ElicitParams{Message: "Which city?"})
+		answered.Done()
+		answered.Wait()
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: res.Action}}}, nil
+	})
+	var logs strings.Builder
+	fnTool, invCtx := newTestTool(t, server, mcptoolset.Config{HandleElicitation: true, Logger: log.New(&logs, "", 0)})
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"call1", "call2"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := fnTool.Run(toolinternal.NewToolContext(invCtx, id, nil, nil), map[string]any{})
+			if err != nil {
+				t.Errorf("Run() failed: %v", err)
+				return
+			}
+			if got := result["output"]; got != "decline" {
+				t.Errorf("Run() output = %v, want the elicitation declined", got)
+			}
+		}()
+	}
+	wg.Wait()
+	if !strings.Contains(logs.String(), "2 tool calls in progress") {
+		t.Errorf("logs = %q, want the declined elicitations", logs.String())
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+)
+
+// PromptConfig provides the configuration of an instruction provider backed
+// by an MCP prompt.
+type PromptConfig struct {
+	// Client is an optional custom MCP client to use. If nil, a default client will be created.
+	Client *mcp.Client
+	// Transport that will be used to connect to MCP server. The connection
+	// is separate from the one of an MCP ToolSet, so the transport must not
+	// be shared with one.
+	Transport mcp.Transport
+	// Name of the MCP prompt.
+	Name string
+	// Arguments returns the arguments of the prompt for the current
+	// invocation. If nil, the prompt is requested without arguments.
+	Arguments func(agent.ReadonlyContext) (map[string]string, error)
+}
+
+// NewInstructionProvider returns an instruction provider that gets the
+// instruction from an MCP prompt. The text of the prompt messages is joined
+// by blank lines; other contents are ignored.
+//
+// Example:
+//
+//	instruction, err := mcptoolset.NewInstructionProvider(mcptoolset.PromptConfig{
+//		Transport: &mcp.CommandTransport{Command: exec.Command("myserver")},
+//		Name:      "code_review",
+//	})
+//	...
+//	llmagent.New(llmagent.Config{
+//		Name:                "reviewer",
+//		Model:               model,
+//		InstructionProvider: instruction,
+//	})
+func NewInstructionProvider(cfg PromptConfig) (llmagent.InstructionProvider, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("prompt name is required")
+	}
+	if cfg.Transport == nil {
+		return nil, errors.New("transport is required")
+	}
+	client := newConnectionRefresher(cfg.Client, cfg.Transport, nil)
+	return func(ctx agent.ReadonlyContext) (string, error) {
+		var args map[string]string
+		if cfg.Arguments != nil {
+			var err error
+			if args, err = cfg.Arguments(ctx); err != nil {
+				return "", fmt.Errorf("failed to get arguments of MCP prompt %q: %w", cfg.Name, err)
+			}
+		}
+		res, err := client.GetPrompt(ctx, &mcp.GetPromptParams{Name: cfg.Name, Arguments: args})
+		if err != nil {
+			return "", err
+		}
+		return promptText(res), nil
+	}, nil
+}
+
+// GetPrompt gets a prompt from the MCP server, automatically reconnecting if needed.
+func (c *connectionRefresher) GetPrompt(ctx context.Context, params *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
+	res, _, err := withRetry(ctx, c, func(session *mcp.ClientSession) (*mcp.GetPromptResult, error) {
+		return session.GetPrompt(ctx, params)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP prompt %q: %w", params.Name, err)
+	}
+	return res, nil
+}
+
+// promptText returns the text contents of the prompt messages.
+func promptText(res *mcp.GetPromptResult) string {
+	var texts []string
+	for _, m := range res.Messages {
+		if m == nil {
+			continue
+		}
+		switch c := m.Content.(type) {
+		case *mcp.TextContent:
+			texts = append(texts, c.Text)
+		case *mcp.EmbeddedResource:
+			if c.Resource != nil && c.Resource.Text != "" {
+				texts = append(texts, c.Resource.Text)
+			}
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
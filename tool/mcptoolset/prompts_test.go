@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/tool/mcptoolset"
+)
+
+func TestNewInstructionProvider(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "prompt_server", Version: "v1.0.0"}, nil)
+	server.AddPrompt(&mcp.Prompt{Name: "code_review", Arguments: []*mcp.PromptArgument{{Name: "language", Required: true}}}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{Messages: []*mcp.PromptMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: "You review " + req.Params.Arguments["language"] + " code."}},
+			{Role: "user", Content: &mcp.ImageContent{Data: []byte("png"), MIMEType: "image/png"}},
+			{Role: "user", Content: &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: "file:///style.md", Text: "Follow the style guide."}}},
+		}}, nil
+	})
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := mcptoolset.NewInstructionProvider(mcptoolset.PromptConfig{
+		Transport: clientTransport,
+		Name:      "code_review",
+		Arguments: func(agent.ReadonlyContext) (map[string]string, error) {
+			return map[string]string{"language": "Go"}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewInstructionProvider() failed: %v", err)
+	}
+
+	got, err := provider(icontext.NewReadonlyContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{})))
+	if err != nil {
+		t.Fatalf("provider() failed: %v", err)
+	}
+	if want := "You review Go code.\n\nFollow the style guide."; got != want {
+		t.Errorf("provider() = %q, want %q", got, want)
+	}
+}
+
+func TestNewInstructionProvider_Errors(t *testing.T) {
+	clientTransport, _ := mcp.NewInMemoryTransports()
+	if _, err := mcptoolset.NewInstructionProvider(mcptoolset.PromptConfig{Transport: clientTransport}); err == nil {
+		t.Error("NewInstructionProvider() without name succeeded, want error")
+	}
+	if _, err := mcptoolset.NewInstructionProvider(mcptoolset.PromptConfig{Name: "code_review"}); err == nil {
+		t.Error("NewInstructionProvider() without transport succeeded, want error")
+	}
+}
@@ -0,0 +1,430 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// LoadResourceToolName is the name of the tool loading MCP resources, added
+// to the toolset when [Config.IncludeResources] is set. It is prefixed with
+// [Config.ToolNamePrefix] like the other tools of the toolset.
+const LoadResourceToolName = "load_mcp_resource"
+
+// resourceClient is implemented by MCP clients supporting resources.
+type resourceClient interface {
+	// ResourceCapabilities returns the resource capabilities of the server,
+	// or nil if the server does not provide resources.
+	ResourceCapabilities(context.Context) (*mcp.ResourceCapabilities, error)
+	ListResources(context.Context) (*resourceListing, error)
+	ReadResource(ctx context.Context, uri string, subscribe bool) (*mcp.ReadResourceResult, error)
+}
+
+// resourceListing holds the resources and resource templates of a server.
+type resourceListing struct {
+	resources []*mcp.Resource
+	templates []*mcp.ResourceTemplate
+}
+
+// resourceCache caches the resource listing of a server like the tool
+// listing, and the contents of the resources subscribed to.
+type resourceCache struct {
+	mu sync.Mutex
+	// generation is incremented by every invalidation, so that results
+	// fetched concurrently with one are not cached.
+	generation uint64
+	listing    *resourceListing
+	// expires is the expiry of the listing, zero if it does not expire.
+	expires    time.Time
+	subscribed map[string]bool
+	contents   map[string]*mcp.ReadResourceResult
+}
+
+func (r *resourceCache) currentGeneration() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.generation
+}
+
+func (r *resourceCache) cachedListing(now time.Time) *resourceListing {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.expires.IsZero() && !now.Before(r.expires) {
+		return nil
+	}
+	return r.listing
+}
+
+func (r *resourceCache) storeListing(listing *resourceListing, generation uint64, expires time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.generation == generation {
+		r.listing, r.expires = listing, expires
+	}
+}
+
+func (r *resourceCache) cachedContent(uri string) *mcp.ReadResourceResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.contents[uri]
+}
+
+func (r *resourceCache) storeContent(uri string, res *mcp.ReadResourceResult, generation uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.generation != generation {
+		return
+	}
+	if r.contents == nil {
+		r.contents = map[string]*mcp.ReadResourceResult{}
+	}
+	r.contents[uri] = res
+}
+
+func (r *resourceCache) isSubscribed(uri string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.subscribed[uri]
+}
+
+func (r *resourceCache) markSubscribed(uri string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.subscribed == nil {
+		r.subscribed = map[string]bool{}
+	}
+	r.subscribed[uri] = true
+}
+
+// invalidate drops the cached listing after the server reported a change
+// of the resource list.
+func (r *resourceCache) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generation++
+	r.listing, r.expires = nil, time.Time{}
+}
+
+// updated drops the cached listing and the cached contents of the resource
+// with the given URI, which may be a sub-resource of one subscribed to.
+func (r *resourceCache) updated(uri string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generation++
+	r.listing, r.expires = nil, time.Time{}
+	for cached := range r.contents {
+		if strings.HasPrefix(uri, cached) {
+			delete(r.contents, cached)
+		}
+	}
+}
+
+// reset drops the whole cache, including the subscriptions.
+func (r *resourceCache) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generation++
+	r.listing, r.expires = nil, time.Time{}
+	r.subscribed = nil
+	r.contents = nil
+}
+
+// ResourceCapabilities returns the resource capabilities of the server, or
+// nil if the server does not provide resources.
+func (c *connectionRefresher) ResourceCapabilities(ctx context.Context) (*mcp.ResourceCapabilities, error) {
+	session, err := c.getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if res := session.InitializeResult(); res != nil && res.Capabilities != nil {
+		return res.Capabilities.Resources, nil
+	}
+	return nil, nil
+}
+
+// ListResources lists the resources and resource templates of the MCP server,
+// automatically reconnecting if needed. The listing is cached like the tool
+// listing, according to toolListTTL.
+func (c *connectionRefresher) ListResources(ctx context.Context) (*resourceListing, error) {
+	if listing := c.resources.cachedListing(time.Now()); listing != nil {
+		return listing, nil
+	}
+	generation := c.resources.currentGeneration()
+
+	resources, err := listAll(ctx, c, func(session *mcp.ClientSession, cursor string) ([]*mcp.Resource, string, error) {
+		resp, err := session.ListResources(ctx, &mcp.ListResourcesParams{Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Resources, resp.NextCursor, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP resources: %w", err)
+	}
+	templates, err := listAll(ctx, c, func(session *mcp.ClientSession, cursor string) ([]*mcp.ResourceTemplate, string, error) {
+		resp, err := session.ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.ResourceTemplates, resp.NextCursor, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP resource templates: %w", err)
+	}
+	listing := &resourceListing{resources: resources, templates: templates}
+
+	if expires, ok := c.listExpiry(time.Now()); ok {
+		c.resources.storeListing(listing, generation, expires)
+	}
+	return listing, nil
+}
+
+// ReadResource reads the resource with the given URI, automatically
+// reconnecting if needed. If subscribe is set and the server supports it,
+// the client subscribes to the resource and caches its contents until the
+// server reports an update.
+func (c *connectionRefresher) ReadResource(ctx context.Context, uri string, subscribe bool) (*mcp.ReadResourceResult, error) {
+	if res := c.resources.cachedContent(uri); res != nil {
+		return res, nil
+	}
+	generation := c.resources.currentGeneration()
+
+	cacheable := false
+	if subscribe && c.notified {
+		caps, err := c.ResourceCapabilities(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if caps != nil && caps.Subscribe {
+			if err := c.subscribe(ctx, uri); err != nil {
+				return nil, err
+			}
+			cacheable = true
+		}
+	}
+
+	res, _, err := withRetry(ctx, c, func(session *mcp.ClientSession) (*mcp.ReadResourceResult, error) {
+		return session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MCP resource %q: %w", uri, err)
+	}
+	if cacheable {
+		c.resources.storeContent(uri, res, generation)
+	}
+	return res, nil
+}
+
+func (c *connectionRefresher) subscribe(ctx context.Context, uri string) error {
+	if c.resources.isSubscribed(uri) {
+		return nil
+	}
+	_, _, err := withRetry(ctx, c, func(session *mcp.ClientSession) (struct{}, error) {
+		return struct{}{}, session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to MCP resource %q: %w", uri, err)
+	}
+	c.resources.markSubscribed(uri)
+	return nil
+}
+
+var _ resourceClient = (*connectionRefresher)(nil)
+
+func newResourceTool(name string, client resourceClient, subscribe bool, maxInlineDataSize int) *resourceTool {
+	return &resourceTool{
+		name:      name,
+		client:    client,
+		subscribe: subscribe,
+		contentConverter: contentConverter{
+			toolName:          name,
+			maxInlineDataSize: maxInlineDataSize,
+		},
+	}
+}
+
+// resourceTool loads the contents of MCP resources. A resourceTool is
+// created each time the tools of the toolset are listed, which is once per
+// invocation if the tool listing is cached.
+type resourceTool struct {
+	name      string
+	client    resourceClient
+	subscribe bool
+
+	// listing memoises the resource listing of the tool, so that the
+	// server is not asked for it on each LLM request of an invocation.
+	listingMu sync.Mutex
+	listing   *resourceListing
+
+	contentConverter
+}
+
+// Name implements the tool.Tool.
+func (t *resourceTool) Name() string {
+	return t.name
+}
+
+// Description implements the tool.Tool.
+func (t *resourceTool) Description() string {
+	return "Loads the contents of a resource provided by the MCP server. " +
+		"Resources are identified by their URI; URIs matching one of the resource templates can be loaded as well."
+}
+
+// IsLongRunning implements the tool.Tool.
+func (t *resourceTool) IsLongRunning() bool {
+	return false
+}
+
+func (t *resourceTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        t.Name(),
+		Description: t.Description(),
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"uri": {
+					Type:        "STRING",
+					Description: "URI of the resource to load.",
+				},
+			},
+			Required: []string{"uri"},
+		},
+	}
+}
+
+func (t *resourceTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	if err := toolutils.PackTool(req, t); err != nil {
+		return err
+	}
+	listing, err := t.resourceListing(ctx)
+	if err != nil {
+		return err
+	}
+	if instructions := resourceInstructions(t.name, listing); instructions != "" {
+		utils.AppendInstructions(req, instructions)
+	}
+	return nil
+}
+
+// resourceListing returns the resource listing of the server, listing the
+// resources once per tool.
+func (t *resourceTool) resourceListing(ctx context.Context) (*resourceListing, error) {
+	t.listingMu.Lock()
+	defer t.listingMu.Unlock()
+	if t.listing != nil {
+		return t.listing, nil
+	}
+	listing, err := t.client.ListResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.listing = listing
+	return listing, nil
+}
+
+// resourceInstructions describes the available resources to the model.
+func resourceInstructions(toolName string, listing *resourceListing) string {
+	if len(listing.resources) == 0 && len(listing.templates) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "You can load the following resources with the `%s` function:\n", toolName)
+	for _, r := range listing.resources {
+		writeResourceLine(&sb, r.URI, r.Name, r.Description, r.MIMEType)
+	}
+	if len(listing.templates) > 0 {
+		sb.WriteString("\nYou can also load resources whose URI matches one of the following RFC 6570 URI templates:\n")
+		for _, r := range listing.templates {
+			writeResourceLine(&sb, r.URITemplate, r.Name, r.Description, r.MIMEType)
+		}
+	}
+	return sb.String()
+}
+
+func writeResourceLine(sb *strings.Builder, uri, name, description, mimeType string) {
+	fmt.Fprintf(sb, "- %s", uri)
+	if name != "" {
+		fmt.Fprintf(sb, " (%s)", name)
+	}
+	if mimeType != "" {
+		fmt.Fprintf(sb, " [%s]", mimeType)
+	}
+	if description != "" {
+		fmt.Fprintf(sb, ": %s", description)
+	}
+	sb.WriteString("\n")
+}
+
+func (t *resourceTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	}
+	uri, _ := m["uri"].(string)
+	if uri == "" {
+		return nil, fmt.Errorf("%s requires a uri argument", t.name)
+	}
+
+	res, err := t.client.ReadResource(ctx, uri, t.subscribe)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &toolContent{}
+	var contents []map[string]any
+	for i, rc := range res.Contents {
+		if rc == nil {
+			continue
+		}
+		if rc.Blob != nil {
+			if err := t.addBinary(ctx, out, i, "resource", rc.MIMEType, rc.Blob, rc.URI); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		contents = append(contents, withoutEmpty(map[string]any{
+			"uri":      rc.URI,
+			"mimeType": rc.MIMEType,
+			"text":     rc.Text,
+		}))
+	}
+
+	result := map[string]any{"uri": uri}
+	if len(contents) > 0 {
+		result["contents"] = contents
+	}
+	if len(out.attachments) > 0 {
+		result[attachmentsKey] = out.attachments
+	}
+	return result, nil
+}
+
+var (
+	_ toolinternal.FunctionTool     = (*resourceTool)(nil)
+	_ toolinternal.RequestProcessor = (*resourceTool)(nil)
+)
@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool/mcptoolset"
+)
+
+func TestMCPToolSet_Resources(t *testing.T) {
+	var mu sync.Mutex
+	readme := "v1"
+	readmeReads := 0
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "docs_server", Version: "v1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler:   func(context.Context, *mcp.SubscribeRequest) error { return nil },
+		UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error { return nil },
+	})
+	server.AddResource(&mcp.Resource{URI: "file:///readme.txt", Name: "readme", Description: "Project overview", MIMEType: "text/plain"}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		readmeReads++
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, MIMEType: "text/plain", Text: readme}}}, nil
+	})
+	server.AddResource(&mcp.Resource{URI: "file:///logo.png", Name: "logo", MIMEType: "image/png"}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, MIMEType: "image/png", Blob: []byte("png")}}}, nil
+	})
+	server.AddResourceTemplate(&mcp.ResourceTemplate{URITemplate: "file:///notes/{name}", Name: "note"}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "note " + strings.TrimPrefix(req.Params.URI, "file:///notes/")}}}, nil
+	})
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := mcptoolset.New(mcptoolset.Config{Transport: clientTransport, IncludeResources: true, SubscribeResources: true})
+	if err != nil {
+		t.Fatalf("Failed to create MCP tool set: %v", err)
+	}
+	a, err := agent.New(agent.Config{Name: "docs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a})
+	toolCtx := toolinternal.NewToolContext(invCtx, "call1", nil, nil)
+
+	tools, err := ts.Tools(icontext.NewReadonlyContext(invCtx))
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name() != mcptoolset.LoadResourceToolName {
+		t.Fatalf("Tools() = %v, want only the %s tool", tools, mcptoolset.LoadResourceToolName)
+	}
+	resourceTool := tools[0].(toolinternal.FunctionTool)
+
+	req := &model.LLMRequest{}
+	if err := resourceTool.(toolinternal.RequestProcessor).ProcessRequest(toolCtx, req); err != nil {
+		t.Fatalf("ProcessRequest() failed: %v", err)
+	}
+	instructions := req.Config.SystemInstruction.Parts[0].Text
+	for _, want := range []string{
+		"- file:///readme.txt (readme) [text/plain]: Project overview",
+		"- file:///logo.png (logo) [image/png]",
+		"- file:///notes/{name} (note)",
+	} {
+		if !strings.Contains(instructions, want) {
+			t.Errorf("ProcessRequest() instructions = %q, want to contain %q", instructions, want)
+		}
+	}
+
+	load := func(uri string) map[string]any {
+		t.Helper()
+		result, err := resourceTool.Run(toolCtx, map[string]any{"uri": uri})
+		if err != nil {
+			t.Fatalf("Run(%q) failed: %v", uri, err)
+		}
+		return result
+	}
+	readmeResult := func(text string) map[string]any {
+		return map[string]any{
+			"uri":      "file:///readme.txt",
+			"contents": []map[string]any{{"uri": "file:///readme.txt", "mimeType": "text/plain", "text": text}},
+		}
+	}
+
+	if diff := cmp.Diff(readmeResult("v1"), load("file:///readme.txt")); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	// The subscribed resource is served from the cache until it is updated.
+	if diff := cmp.Diff(readmeResult("v1"), load("file:///readme.txt")); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	mu.Lock()
+	if readmeReads != 1 {
+		t.Errorf("server got %d reads, want 1", readmeReads)
+	}
+	readme = "v2"
+	mu.Unlock()
+
+	if err := server.ResourceUpdated(t.Context(), &mcp.ResourceUpdatedNotificationParams{URI: "file:///readme.txt"}); err != nil {
+		t.Fatalf("ResourceUpdated() failed: %v", err)
+	}
+	// Notifications are delivered asynchronously.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := load("file:///readme.txt")
+		if cmp.Equal(readmeResult("v2"), got) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Run() = %v after update, want %v", got, readmeResult("v2"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	wantNote := map[string]any{
+		"uri":      "file:///notes/todo",
+		"contents": []map[string]any{{"uri": "file:///notes/todo", "text": "note todo"}},
+	}
+	if diff := cmp.Diff(wantNote, load("file:///notes/todo")); diff != "" {
+		t.Errorf("Run() of templated resource mismatch (-want +got):\n%s", diff)
+	}
+
+	wantLogo := map[string]any{
+		"uri":         "file:///logo.png",
+		"attachments": []map[string]any{{"type": "resource", "uri": "file:///logo.png", "mimeType": "image/png", "inline": true}},
+	}
+	if diff := cmp.Diff(wantLogo, load("file:///logo.png")); diff != "" {
+		t.Errorf("Run() of binary resource mismatch (-want +got):\n%s", diff)
+	}
+	wantParts := []*genai.Part{genai.NewPartFromBytes([]byte("png"), "image/png")}
+	if diff := cmp.Diff(wantParts, toolinternal.AttachedParts(toolCtx)); diff != "" {
+		t.Errorf("Run() of binary resource attached parts mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMCPToolSet_ResourcesNotProvided(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "weather_server", Version: "v1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "get_weather"}, weatherFunc)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := mcptoolset.New(mcptoolset.Config{Transport: clientTransport, IncludeResources: true})
+	if err != nil {
+		t.Fatalf("Failed to create MCP tool set: %v", err)
+	}
+	tools, err := ts.Tools(icontext.NewReadonlyContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{})))
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name() != "get_weather" {
+		t.Errorf("Tools() = %v, want only get_weather", tools)
+	}
+}
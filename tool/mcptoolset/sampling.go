@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// SamplingConfig routes the sampling requests of an MCP server, which asks
+// the client to generate a message, to a model.
+type SamplingConfig struct {
+	// Model generates the messages requested by the server.
+	Model model.LLM
+	// MaxTokens caps the number of tokens the server can request for a
+	// message. Zero means no cap.
+	MaxTokens int64
+	// Policy is called before a sampling request is sent to the model, for
+	// example to ask for approval or enforce a budget. It can modify the
+	// request and rejects it by returning an error. If nil, all requests
+	// are sent to the model.
+	Policy func(ctx context.Context, params *mcp.CreateMessageParams) error
+}
+
+// createMessage handles the sampling requests of the server.
+func (c *SamplingConfig) createMessage(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	params := *req.Params
+	if c.MaxTokens > 0 && (params.MaxTokens <= 0 || params.MaxTokens > c.MaxTokens) {
+		params.MaxTokens = c.MaxTokens
+	}
+	if c.Policy != nil {
+		if err := c.Policy(ctx, &params); err != nil {
+			return nil, fmt.Errorf("sampling request rejected: %w", err)
+		}
+	}
+
+	llmReq, err := samplingRequest(&params)
+	if err != nil {
+		return nil, err
+	}
+	var resp *model.LLMResponse
+	for r, err := range c.Model.GenerateContent(ctx, llmReq, false) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate sampled message: %w", err)
+		}
+		resp = r
+	}
+	if resp == nil || resp.Content == nil {
+		return nil, errors.New("model returned no content for sampling request")
+	}
+	if resp.ErrorCode != "" || resp.ErrorMessage != "" {
+		return nil, fmt.Errorf("model failed to generate sampled message (code: %q, message: %q)", resp.ErrorCode, resp.ErrorMessage)
+	}
+	content, err := samplingContent(resp.Content)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.CreateMessageResult{
+		Content:    content,
+		Model:      c.Model.Name(),
+		Role:       "assistant",
+		StopReason: stopReason(resp.FinishReason),
+	}, nil
+}
+
+// samplingRequest converts a sampling request to a model request.
+func samplingRequest(params *mcp.CreateMessageParams) (*model.LLMRequest, error) {
+	config := &genai.GenerateContentConfig{
+		StopSequences: params.StopSequences,
+	}
+	if params.SystemPrompt != "" {
+		config.SystemInstruction = genai.NewContentFromText(params.SystemPrompt, genai.RoleUser)
+	}
+	if params.MaxTokens > 0 {
+		config.MaxOutputTokens = int32(params.MaxTokens)
+	}
+	if params.Temperature != 0 {
+		config.Temperature = genai.Ptr(float32(params.Temperature))
+	}
+
+	req := &model.LLMRequest{Config: config}
+	for _, m := range params.Messages {
+		if m == nil {
+			continue
+		}
+		role := genai.RoleUser
+		if m.Role == "assistant" {
+			role = genai.RoleModel
+		}
+		var part *genai.Part
+		switch c := m.Content.(type) {
+		case *mcp.TextContent:
+			part = genai.NewPartFromText(c.Text)
+		case *mcp.ImageContent:
+			part = genai.NewPartFromBytes(c.Data, c.MIMEType)
+		case *mcp.AudioContent:
+			part = genai.NewPartFromBytes(c.Data, c.MIMEType)
+		default:
+			return nil, fmt.Errorf("unsupported sampling message content %T", m.Content)
+		}
+		// Consecutive messages of the same role form a single content.
+		if n := len(req.Contents); n > 0 && req.Contents[n-1].Role == role {
+			req.Contents[n-1].Parts = append(req.Contents[n-1].Parts, part)
+			continue
+		}
+		req.Contents = append(req.Contents, &genai.Content{Role: role, Parts: []*genai.Part{part}})
+	}
+	if len(req.Contents) == 0 {
+		return nil, errors.New("sampling request has no messages")
+	}
+	return req, nil
+}
+
+// samplingContent converts the model response to the content of a sampled
+// message, which is either text or a single image or audio.
+func samplingContent(content *genai.Content) (mcp.Content, error) {
+	var texts []string
+	var blob *genai.Blob
+	for _, part := range content.Parts {
+		switch {
+		case part.Text != "" && !part.Thought:
+			texts = append(texts, part.Text)
+		case part.InlineData != nil && blob == nil:
+			blob = part.InlineData
+		}
+	}
+	switch {
+	case len(texts) > 0:
+		return &mcp.TextContent{Text: strings.Join(texts, "")}, nil
+	case blob != nil && strings.HasPrefix(blob.MIMEType, "image/"):
+		return &mcp.ImageContent{Data: blob.Data, MIMEType: blob.MIMEType}, nil
+	case blob != nil && strings.HasPrefix(blob.MIMEType, "audio/"):
+		return &mcp.AudioContent{Data: blob.Data, MIMEType: blob.MIMEType}, nil
+	}
+	return nil, errors.New("model returned no text, image or audio for sampling request")
+}
+
+// stopReason converts a finish reason to an MCP stop reason.
+func stopReason(reason genai.FinishReason) string {
+	switch reason {
+	case "":
+		return ""
+	case genai.FinishReasonStop:
+		return "endTurn"
+	case genai.FinishReasonMaxTokens:
+		return "maxTokens"
+	default:
+		return strings.ToLower(string(reason))
+	}
+}
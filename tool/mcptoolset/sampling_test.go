@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool/mcptoolset"
+)
+
+// newTestTool connects the toolset to the server and returns its only tool.
+func newTestTool(t *testing.T, server *mcp.Server, cfg mcptoolset.Config) (toolinternal.FunctionTool, agent.InvocationContext) {
+	t.Helper()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Transport = clientTransport
+	ts, err := mcptoolset.New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create MCP tool set: %v", err)
+	}
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a})
+	tools, err := ts.Tools(icontext.NewReadonlyContext(invCtx))
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	if len(tools) != 1 {
+		t.Fatalf("Tools() returned %d tools, want 1", len(tools))
+	}
+	return tools[0].(toolinternal.FunctionTool), invCtx
+}
+
+func TestMCPToolSet_Sampling(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "summary_server", Version: "v1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "summarize", InputSchema: &jsonschema.Schema{Type: "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		res, err := req.Session.CreateMessage(ctx, &mcp.CreateMessageParams{
+			SystemPrompt: "You summarize documents.",
+			MaxTokens:    1000,
+			Messages:     []*mcp.SamplingMessage{{Role: "user", Content: &mcp.TextContent{Text: "Summarize: a long text"}}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{res.Content}}, nil
+	})
+
+	llm := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("A text.", genai.RoleModel)}}
+	var policyTokens int64
+	fnTool, invCtx := newTestTool(t, server, mcptoolset.Config{Sampling: &mcptoolset.SamplingConfig{
+		Model:     llm,
+		MaxTokens: 100,
+		Policy: func(ctx context.Context, params *mcp.CreateMessageParams) error {
+			policyTokens = params.MaxTokens
+			return nil
+		},
+	}})
+
+	result, err := fnTool.Run(toolinternal.NewToolContext(invCtx, "call1", nil, nil), map[string]any{})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if got, want := result["output"], "A text."; got != want {
+		t.Errorf("Run() output = %v, want %q", got, want)
+	}
+	if policyTokens != 100 {
+		t.Errorf("Policy got MaxTokens = %d, want the cap 100", policyTokens)
+	}
+	if len(llm.Requests) != 1 {
+		t.Fatalf("model got %d requests, want 1", len(llm.Requests))
+	}
+	req := llm.Requests[0]
+	if diff := cmp.Diff([]*genai.Content{genai.NewContentFromText("Summarize: a long text", genai.RoleUser)}, req.Contents); diff != "" {
+		t.Errorf("model request contents mismatch (-want +got):\n%s", diff)
+	}
+	if got := req.Config.SystemInstruction.Parts[0].Text; got != "You summarize documents." {
+		t.Errorf("model request system instruction = %q", got)
+	}
+	if req.Config.MaxOutputTokens != 100 {
+		t.Errorf("model request MaxOutputTokens = %d, want 100", req.Config.MaxOutputTokens)
+	}
+}
+
+func TestMCPToolSet_SamplingRejected(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "summary_server", Version: "v1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "summarize", InputSchema: &jsonschema.Schema{Type: "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, err := req.Session.CreateMessage(ctx, &mcp.CreateMessageParams{
+			Messages: []*mcp.SamplingMessage{{Role: "user", Content: &mcp.TextContent{Text: "Hi"}}},
+		})
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "sampled"}}}, nil
+	})
+
+	llm := &testutil.MockModel{}
+	fnTool, invCtx := newTestTool(t, server, mcptoolset.Config{Sampling: &mcptoolset.SamplingConfig{
+		Model: llm,
+		Policy: func(context.Context, *mcp.CreateMessageParams) error {
+			return errors.New("sampling is not allowed")
+		},
+	}})
+
+	_, err := fnTool.Run(toolinternal.NewToolContext(invCtx, "call1", nil, nil), map[string]any{})
+	if err == nil || !strings.Contains(err.Error(), "sampling is not allowed") {
+		t.Errorf("Run() error = %v, want the policy error", err)
+	}
+	if len(llm.Requests) != 0 {
+		t.Errorf("model got %d requests, want none", len(llm.Requests))
+	}
+}
+
+func TestMCPToolSet_SamplingRequiresDefaultClient(t *testing.T) {
+	clientTransport, _ := mcp.NewInMemoryTransports()
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v1.0.0"}, nil)
+	if _, err := mcptoolset.New(mcptoolset.Config{Client: client, Transport: clientTransport, HandleElicitation: true}); err == nil {
+		t.Error("New() with custom client and elicitation handling succeeded, want error")
+	}
+	if _, err := mcptoolset.New(mcptoolset.Config{Transport: clientTransport, Sampling: &mcptoolset.SamplingConfig{}}); err == nil {
+		t.Error("New() with sampling without model succeeded, want error")
+	}
+}
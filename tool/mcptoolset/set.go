@@ -16,12 +16,18 @@
 package mcptoolset
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"log"
+	"sync"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolpolicy"
 )
 
 // New returns MCP ToolSet.
@@ -47,11 +53,47 @@ import (
 //		},
 //	})
 func New(cfg Config) (tool.Toolset, error) {
+	if cfg.Client != nil && (cfg.Sampling != nil || cfg.HandleElicitation) {
+		return nil, errors.New("sampling and elicitation handling require the default client, Client must be nil")
+	}
+	if cfg.Sampling != nil && cfg.Sampling.Model == nil {
+		return nil, errors.New("sampling requires a model")
+	}
+	if cfg.CallRetries < 0 {
+		return nil, errors.New("call retries must not be negative")
+	}
+	// Validate the policy once rather than on every tool conversion.
+	if _, err := toolpolicy.NewLimiter("", cfg.Policy); err != nil {
+		return nil, err
+	}
+
+	var elicit *elicitations
+	var clientOpts mcp.ClientOptions
+	if cfg.Sampling != nil {
+		clientOpts.CreateMessageHandler = cfg.Sampling.createMessage
+	}
+	if cfg.HandleElicitation {
+		elicit = &elicitations{logger: cfg.Logger}
+		clientOpts.ElicitationHandler = elicit.handle
+	}
+	client := newConnectionRefresher(cfg.Client, cfg.Transport, &clientOpts)
+	client.toolListTTL = cfg.ToolListTTL
 	return &set{
-		mcpClient:                   newConnectionRefresher(cfg.Client, cfg.Transport),
+		elicitations:                elicit,
+		mcpClient:                   client,
+		resourceClient:              client,
+		includeResources:            cfg.IncludeResources,
+		subscribeResources:          cfg.SubscribeResources,
 		toolFilter:                  cfg.ToolFilter,
 		requireConfirmation:         cfg.RequireConfirmation,
 		requireConfirmationProvider: cfg.RequireConfirmationProvider,
+		maxInlineDataSize:           cmp.Or(cfg.MaxInlineDataSize, DefaultMaxInlineDataSize),
+		memoizeTools:                cfg.ToolListTTL > 0,
+		toolNamePrefix:              cfg.ToolNamePrefix,
+		callTimeout:                 cfg.CallTimeout,
+		callRetries:                 cfg.CallRetries,
+		policy:                      cfg.Policy,
+		logger:                      cfg.Logger,
 	}, nil
 }
 
@@ -83,6 +125,73 @@ type Config struct {
 	// func(name string, toolInput any) bool
 	// Returning true means confirmation is required.
 	RequireConfirmationProvider ConfirmationProvider
+
+	// MaxInlineDataSize is the size in bytes above which image, audio and
+	// binary resource contents of tool results are saved as artifacts, with
+	// a reference returned to the model, instead of being passed inline.
+	// Contents are always passed inline if no artifact service is
+	// configured. Zero means DefaultMaxInlineDataSize.
+	MaxInlineDataSize int
+
+	// IncludeResources adds the load_mcp_resource tool to the toolset if
+	// the server provides resources. The tool lists the resources and
+	// resource templates of the server in the instructions and loads them
+	// by URI.
+	//
+	// The resource listing is cached like the tool listing, according to
+	// ToolListTTL.
+	IncludeResources bool
+	// SubscribeResources subscribes to the resources loaded by the
+	// load_mcp_resource tool, if the server supports it, and caches their
+	// contents until the server reports an update. It requires Client to
+	// be nil and has no effect otherwise.
+	SubscribeResources bool
+
+	// Sampling routes the sampling requests of the server, which asks the
+	// client to generate a message with a model, to a model. If nil, the
+	// client does not support sampling. It requires Client to be nil.
+	Sampling *SamplingConfig
+	// HandleElicitation answers the elicitation requests the server sends
+	// while calling a tool through the tool confirmation flow: the tool
+	// call requests a confirmation with the elicitation message as hint and
+	// the requested schema in the payload, and when confirmed runs again,
+	// answering the elicitation with the confirmation payload. A rejected
+	// confirmation declines the elicitation. If the tool requires a
+	// confirmation anyway, that confirmation answers the elicitation.
+	//
+	// Only a single elicitation per tool call is supported, and
+	// elicitations are declined while several tool calls of the toolset
+	// are in progress. It requires Client to be nil.
+	HandleElicitation bool
+
+	// ToolListTTL is how long the tool and resource listings of the server
+	// are cached if positive. The cache is also invalidated when the server
+	// notifies of a list change, if Client is nil, or the connection is
+	// lost. Zero disables the cache.
+	//
+	// If the cache is enabled, the tools and resources are also listed
+	// only once per invocation, so they stay the same across the LLM
+	// requests of an invocation.
+	ToolListTTL time.Duration
+	// ToolNamePrefix is prepended to the names of the tools, separated by
+	// an underscore, to avoid name collisions between the tools of several
+	// MCP toolsets. The tools are still called by their original names on
+	// the server.
+	ToolNamePrefix string
+	// CallTimeout limits each attempt to call a tool if positive.
+	CallTimeout time.Duration
+	// CallRetries is the number of times a tool call is retried after it
+	// timed out or the connection failed, with exponential backoff. Only
+	// enable retries for servers whose tools are safe to call again.
+	CallRetries int
+	// Policy limits the executions of each tool of the toolset: timeout,
+	// concurrency, rate limit and circuit breaker. Every tool has its own
+	// limits. The zero value does not limit them.
+	Policy toolpolicy.Policy
+	// Logger, if set, logs the retries of the tool calls and the
+	// elicitations declined because several tool calls are in progress
+	// (optional).
+	Logger *log.Logger
 }
 
 type set struct {
@@ -90,6 +199,29 @@ type set struct {
 	toolFilter                  tool.Predicate
 	requireConfirmation         bool
 	requireConfirmationProvider ConfirmationProvider
+	maxInlineDataSize           int
+
+	resourceClient     resourceClient
+	includeResources   bool
+	subscribeResources bool
+
+	elicitations *elicitations
+
+	toolNamePrefix string
+	callTimeout    time.Duration
+	callRetries    int
+	logger         *log.Logger
+
+	// policy limits the executions of each tool, with limiters kept by
+	// tool name since the tools are converted again for each invocation.
+	policy     toolpolicy.Policy
+	limitersMu sync.Mutex
+	limiters   map[string]*toolpolicy.Limiter
+
+	// memoizeTools memoises the tools per invocation, which is enabled
+	// with the tool list cache.
+	memoizeTools    bool
+	invocationTools invocationTools
 }
 
 func (*set) Name() string {
@@ -105,7 +237,25 @@ func (*set) IsLongRunning() bool {
 }
 
 // Tools fetch MCP tools from the server, convert to adk tool.Tool and filter by name.
+// The tools are memoised per invocation if the tool list cache is enabled.
 func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	if !s.memoizeTools {
+		return s.listTools(ctx)
+	}
+	if tools, ok := s.invocationTools.get(ctx.InvocationID()); ok {
+		return tools, nil
+	}
+	tools, err := s.listTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.invocationTools.put(ctx.InvocationID(), tools)
+	return tools, nil
+}
+
+// listTools lists the tools of the server and converts them to ADK tools.
+func (s *set) listTools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+
 	mcpTools, err := s.mcpClient.ListTools(ctx)
 	if err != nil {
 		return nil, err
@@ -113,7 +263,7 @@ func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
 
 	var adkTools []tool.Tool
 	for _, mcpTool := range mcpTools {
-		t, err := convertTool(mcpTool, s.mcpClient, s.requireConfirmation, s.requireConfirmationProvider)
+		t, err := s.convertTool(mcpTool)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert MCP tool %q to adk tool: %w", mcpTool.Name, err)
 		}
@@ -125,9 +275,52 @@ func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
 		adkTools = append(adkTools, t)
 	}
 
+	if s.includeResources {
+		caps, err := s.resourceClient.ResourceCapabilities(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if caps != nil {
+			t := newResourceTool(s.toolName(LoadResourceToolName), s.resourceClient, s.subscribeResources, s.maxInlineDataSize)
+			if s.toolFilter == nil || s.toolFilter(ctx, t) {
+				adkTools = append(adkTools, t)
+			}
+		}
+	}
+
 	return adkTools, nil
 }
 
+// limiter returns the limiter of the tool named name, or nil if the
+// toolset has no policy.
+func (s *set) limiter(name string) (*toolpolicy.Limiter, error) {
+	if s.policy.IsZero() {
+		return nil, nil
+	}
+	s.limitersMu.Lock()
+	defer s.limitersMu.Unlock()
+	if l, ok := s.limiters[name]; ok {
+		return l, nil
+	}
+	l, err := toolpolicy.NewLimiter(name, s.policy)
+	if err != nil {
+		return nil, err
+	}
+	if s.limiters == nil {
+		s.limiters = map[string]*toolpolicy.Limiter{}
+	}
+	s.limiters[name] = l
+	return l, nil
+}
+
+// toolName returns the name of the ADK tool for a tool of the server.
+func (s *set) toolName(name string) string {
+	if s.toolNamePrefix == "" {
+		return name
+	}
+	return s.toolNamePrefix + "_" + name
+}
+
 // ConfirmationProvider defines a function that dynamically determines whether
 // a specific tool execution requires user confirmation.
 //
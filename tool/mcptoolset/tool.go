@@ -15,9 +15,12 @@
 package mcptoolset
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/genai"
@@ -26,19 +29,37 @@ import (
 	"google.golang.org/adk/internal/toolinternal/toolutils"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolpolicy"
 )
 
-func convertTool(t *mcp.Tool, client MCPClient, requireConfirmation bool, requireConfirmationProvider ConfirmationProvider) (tool.Tool, error) {
+// convertTool converts an MCP tool of the server to an ADK tool named with
+// the tool name prefix of the set.
+func (s *set) convertTool(t *mcp.Tool) (tool.Tool, error) {
+	name := s.toolName(t.Name)
+	limiter, err := s.limiter(name)
+	if err != nil {
+		return nil, err
+	}
 	mcp := &mcpTool{
-		name:        t.Name,
+		name:        name,
+		mcpName:     t.Name,
 		description: t.Description,
 		funcDeclaration: &genai.FunctionDeclaration{
-			Name:        t.Name,
+			Name:        name,
 			Description: t.Description,
 		},
-		mcpClient:                   client,
-		requireConfirmation:         requireConfirmation,
-		requireConfirmationProvider: requireConfirmationProvider,
+		mcpClient:                   s.mcpClient,
+		requireConfirmation:         s.requireConfirmation,
+		requireConfirmationProvider: s.requireConfirmationProvider,
+		elicitations:                s.elicitations,
+		callTimeout:                 s.callTimeout,
+		callRetries:                 s.callRetries,
+		logger:                      s.logger,
+		limiter:                     limiter,
+		contentConverter: contentConverter{
+			toolName:          name,
+			maxInlineDataSize: s.maxInlineDataSize,
+		},
 	}
 
 	// Since t.InputSchema and t.OutputSchema are pointers (*jsonschema.Schema) and the destination ResponseJsonSchema
@@ -56,7 +77,10 @@ func convertTool(t *mcp.Tool, client MCPClient, requireConfirmation bool, requir
 }
 
 type mcpTool struct {
-	name            string
+	name string
+	// mcpName is the name of the tool on the server, which differs from
+	// name if the toolset has a tool name prefix.
+	mcpName         string
 	description     string
 	funcDeclaration *genai.FunctionDeclaration
 
@@ -65,6 +89,21 @@ type mcpTool struct {
 	requireConfirmation bool
 
 	requireConfirmationProvider ConfirmationProvider
+
+	// elicitations handles the elicitation requests of the server if set.
+	elicitations *elicitations
+
+	// callTimeout limits each attempt to call the tool if positive.
+	callTimeout time.Duration
+	// callRetries is the number of times a call is retried after it timed
+	// out or the connection failed.
+	callRetries int
+	// logger logs the retries if set.
+	logger *log.Logger
+	// limiter enforces the policy of the toolset if it has one.
+	limiter *toolpolicy.Limiter
+
+	contentConverter
 }
 
 // Name implements the tool.Tool.
@@ -91,36 +130,38 @@ func (t *mcpTool) Declaration() *genai.FunctionDeclaration {
 }
 
 func (t *mcpTool) Run(ctx tool.Context, args any) (map[string]any, error) {
-	if confirmation := ctx.ToolConfirmation(); confirmation != nil {
-		if !confirmation.Confirmed {
+	confirmation := ctx.ToolConfirmation()
+	if confirmation != nil {
+		// With elicitation handling, a rejected confirmation of a tool not
+		// requiring one declines the elicitation of the server.
+		if !confirmation.Confirmed && (t.elicitations == nil || t.confirmationRequired(args)) {
 			return nil, fmt.Errorf("error tool %q call is rejected", t.Name())
 		}
-	} else {
-		requireConfirmation := t.requireConfirmation
-
-		// Only run the potentially expensive provider if the static flag didn't already trigger it
-		// Provider takes precedence/overrides:
-		if t.requireConfirmationProvider != nil {
-			requireConfirmation = t.requireConfirmationProvider(t.Name(), args)
-		}
-
-		if requireConfirmation {
-			err := ctx.RequestConfirmation(
-				fmt.Sprintf("Please approve or reject the tool call %s() by responding with a FunctionResponse with an expected ToolConfirmation payload.",
-					t.Name()), nil)
-			if err != nil {
-				return nil, err
-			}
-			ctx.Actions().SkipSummarization = true
-			return nil, fmt.Errorf("error tool %q requires confirmation, please approve or reject", t.Name())
+	} else if t.confirmationRequired(args) {
+		err := ctx.RequestConfirmation(
+			fmt.Sprintf("Please approve or reject the tool call %s() by responding with a FunctionResponse with an expected ToolConfirmation payload.",
+				t.Name()), nil)
+		if err != nil {
+			return nil, err
 		}
+		ctx.Actions().SkipSummarization = true
+		return nil, fmt.Errorf("error tool %q requires confirmation, please approve or reject", t.Name())
 	}
 
+	var call *pendingCall
+	if t.elicitations != nil {
+		call = t.elicitations.begin(t.name, ctx, confirmation)
+	}
 	// TODO: add auth
-	res, err := t.mcpClient.CallTool(ctx, &mcp.CallToolParams{
-		Name:      t.name,
+	res, err := t.callWithPolicy(ctx, &mcp.CallToolParams{
+		Name:      t.mcpName,
 		Arguments: args,
 	})
+	if call != nil && t.elicitations.end(call) {
+		// The server elicited information, which is requested from the
+		// user; the result of the cancelled elicitation is discarded.
+		return nil, fmt.Errorf("error tool %q requires confirmation of the MCP server request %q, please approve or reject", t.Name(), ctx.Actions().RequestedToolConfirmations[ctx.FunctionCallID()].Hint)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to call MCP tool %q with err: %w", t.name, err)
 	}
@@ -145,32 +186,95 @@ func (t *mcpTool) Run(ctx tool.Context, args any) (map[string]any, error) {
 		return nil, errors.New(errMsg)
 	}
 
+	content, err := t.convertContent(ctx, res.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]any{}
 	if res.StructuredContent != nil {
-		return map[string]any{
-			"output": res.StructuredContent,
-		}, nil
+		result["output"] = res.StructuredContent
+	} else if content.text.Len() > 0 {
+		result["output"] = content.text.String()
+	}
+	if len(content.attachments) > 0 {
+		result[attachmentsKey] = content.attachments
 	}
 
-	textResponse := strings.Builder{}
+	if len(result) == 0 {
+		return nil, errors.New("no content in tool response")
+	}
+	return result, nil
+}
 
-	for _, c := range res.Content {
-		textContent, ok := c.(*mcp.TextContent)
-		if !ok {
-			continue
-		}
+// callRetryBackoff is the delay before the first retry of a tool call,
+// doubled for each further retry.
+const callRetryBackoff = 200 * time.Millisecond
 
-		if _, err := textResponse.WriteString(textContent.Text); err != nil {
-			return nil, fmt.Errorf("failed to write text response: %w", err)
+// callTool calls the tool on the server, limiting each attempt to
+// callTimeout and retrying up to callRetries times after a timeout or a
+// connection failure.
+func (t *mcpTool) callTool(ctx context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	backoff := callRetryBackoff
+	for attempt := 0; ; attempt++ {
+		callCtx, cancel := ctx, context.CancelFunc(func() {})
+		if t.callTimeout > 0 {
+			callCtx, cancel = context.WithTimeout(ctx, t.callTimeout)
+		}
+		res, err := t.mcpClient.CallTool(callCtx, params)
+		timedOut := callCtx.Err() != nil && ctx.Err() == nil
+		cancel()
+		if err == nil {
+			return res, nil
+		}
+		if timedOut {
+			err = fmt.Errorf("%w (timed out after %v)", err, t.callTimeout)
+		}
+		if attempt >= t.callRetries || ctx.Err() != nil || !(timedOut || shouldRefreshConnection(err)) {
+			return nil, err
 		}
+		if t.logger != nil {
+			t.logger.Printf("retrying call of MCP tool %q after error: %v", t.mcpName, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
 	}
+}
 
-	if textResponse.Len() == 0 {
-		return nil, errors.New("no text content in tool response")
+// callWithPolicy calls the tool under the policy of the toolset, if any. A
+// result reporting an error counts as a failure for the circuit breaker.
+func (t *mcpTool) callWithPolicy(ctx tool.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	if t.limiter == nil {
+		return t.callTool(ctx, params)
 	}
+	execCtx, end, err := t.limiter.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.callTool(execCtx, params)
+	if err != nil {
+		return nil, end(err)
+	}
+	if res.IsError {
+		end(errors.New("tool execution failed"))
+	} else {
+		end(nil)
+	}
+	return res, nil
+}
 
-	return map[string]any{
-		"output": textResponse.String(),
-	}, nil
+// confirmationRequired reports whether the call of the tool with args
+// requires a confirmation.
+func (t *mcpTool) confirmationRequired(args any) bool {
+	// Provider takes precedence/overrides the static flag.
+	if t.requireConfirmationProvider != nil {
+		return t.requireConfirmationProvider(t.Name(), args)
+	}
+	return t.requireConfirmation
 }
 
 var (
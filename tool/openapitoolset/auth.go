@@ -0,0 +1,139 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapitoolset
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// Credential holds the credential for a security scheme declared in the
+// spec. Only the fields matching the type of the scheme are used.
+type Credential struct {
+	// APIKey is sent for apiKey schemes, in the header, query parameter or
+	// cookie declared by the scheme.
+	APIKey string
+	// Username and Password are sent for http basic schemes.
+	Username string
+	Password string
+	// Token is sent as bearer token for http bearer, oauth2 and
+	// openIdConnect schemes.
+	Token string
+	// TokenSource provides the bearer token if Token is empty.
+	TokenSource oauth2.TokenSource
+}
+
+// authenticator applies the credentials to the requests of the operations.
+type authenticator struct {
+	schemes     map[string]*securityScheme
+	credentials map[string]Credential
+}
+
+// apply authenticates req for the first security requirement of the
+// operation which all credentials are configured for. An empty list of
+// requirements or an empty requirement means no authentication.
+func (a *authenticator) apply(req *http.Request, requirements []securityRequirement) error {
+	if len(requirements) == 0 {
+		return nil
+	}
+	var missing []string
+	for _, requirement := range requirements {
+		if unsatisfied := a.unsatisfied(requirement); len(unsatisfied) > 0 {
+			missing = append(missing, unsatisfied...)
+			continue
+		}
+		for name := range requirement {
+			if err := a.authenticate(req, a.schemes[name], a.credentials[name]); err != nil {
+				return fmt.Errorf("failed to apply security scheme %q: %w", name, err)
+			}
+		}
+		return nil
+	}
+	slices.Sort(missing)
+	return fmt.Errorf("no credentials configured for the security schemes %s of the operation", strings.Join(slices.Compact(missing), ", "))
+}
+
+// unsatisfied returns the schemes of the requirement which cannot be
+// applied.
+func (a *authenticator) unsatisfied(requirement securityRequirement) []string {
+	var names []string
+	for name := range requirement {
+		_, ok := a.credentials[name]
+		if scheme := a.schemes[name]; !ok || scheme == nil || !supported(scheme) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// supported reports whether requests can be authenticated for the scheme.
+func supported(scheme *securityScheme) bool {
+	switch scheme.Type {
+	case "apiKey", "oauth2", "openIdConnect":
+		return true
+	case "http":
+		s := strings.ToLower(scheme.Scheme)
+		return s == "basic" || s == "bearer"
+	default:
+		return false
+	}
+}
+
+func (a *authenticator) authenticate(req *http.Request, scheme *securityScheme, cred Credential) error {
+	switch scheme.Type {
+	case "apiKey":
+		switch scheme.In {
+		case "header":
+			req.Header.Set(scheme.Name, cred.APIKey)
+		case "query":
+			q := req.URL.Query()
+			q.Set(scheme.Name, cred.APIKey)
+			req.URL.RawQuery = q.Encode()
+		case "cookie":
+			req.AddCookie(&http.Cookie{Name: scheme.Name, Value: cred.APIKey})
+		default:
+			return fmt.Errorf("unsupported API key location %q", scheme.In)
+		}
+	case "http":
+		if strings.EqualFold(scheme.Scheme, "basic") {
+			req.SetBasicAuth(cred.Username, cred.Password)
+			return nil
+		}
+		return setBearer(req, cred)
+	default:
+		return setBearer(req, cred)
+	}
+	return nil
+}
+
+func setBearer(req *http.Request, cred Credential) error {
+	token := cred.Token
+	if token == "" && cred.TokenSource != nil {
+		t, err := cred.TokenSource.Token()
+		if err != nil {
+			return fmt.Errorf("failed to get token: %w", err)
+		}
+		token = t.AccessToken
+	}
+	if token == "" {
+		return fmt.Errorf("no token configured")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openapitoolset provides a toolset calling the operations of a REST
+// API described by an OpenAPI 3 spec.
+//
+// Each operation of the spec becomes a tool named after its operationId,
+// whose parameters are the path, query, header and cookie parameters of the
+// operation plus a "body" parameter for the request body. The tools send the
+// HTTP request, authenticated with the security schemes declared in the spec,
+// and return the response.
+//
+// Example:
+//
+//	spec, err := os.ReadFile("petstore.yaml")
+//	...
+//	petstore, err := openapitoolset.New(openapitoolset.Config{
+//		Spec: spec,
+//		Credentials: map[string]openapitoolset.Credential{
+//			"api_key": {APIKey: os.Getenv("PETSTORE_API_KEY")},
+//		},
+//	})
+//	...
+//	llmagent.New(llmagent.Config{
+//		Name:     "petstore_agent",
+//		Model:    model,
+//		Toolsets: []tool.Toolset{petstore},
+//	})
+package openapitoolset
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	// maxToolNameLength is the maximum length of function names accepted
+	// by the models.
+	maxToolNameLength = 64
+)
+
+// Config provides the configuration of an OpenAPI toolset.
+type Config struct {
+	// Name of the toolset. If empty, it is derived from the title of the
+	// spec.
+	Name string
+	// Spec is the OpenAPI 3 spec in JSON or YAML.
+	Spec []byte
+	// ServerURL is the base URL of the API. If empty, the first server of
+	// the spec is used, which must then have an absolute URL.
+	ServerURL string
+	// HTTPClient sends the requests of the tools. If nil, a client with a
+	// timeout of 30 seconds is used.
+	HTTPClient *http.Client
+	// Credentials maps the names of the security schemes of the spec to
+	// their credentials. Operations requiring security schemes without
+	// credentials fail.
+	Credentials map[string]Credential
+	// Headers are added to all requests.
+	Headers map[string]string
+}
+
+// New returns a toolset with a tool per operation of the OpenAPI spec.
+func New(cfg Config) (tool.Toolset, error) {
+	doc, err := parseDocument(cfg.Spec)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, err := serverURL(cfg.ServerURL, doc.Servers)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	s := &set{name: cmp.Or(cfg.Name, snakeCase(doc.Info.Title), "openapi_toolset")}
+	caller := &caller{
+		baseURL:    baseURL,
+		httpClient: httpClient,
+		headers:    cfg.Headers,
+		auth: &authenticator{
+			schemes:     doc.Components.SecuritySchemes,
+			credentials: cfg.Credentials,
+		},
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	names := map[string]bool{}
+	for _, path := range paths {
+		item, err := doc.resolvePathItem(doc.Paths[path])
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range item.operations() {
+			t, err := newOperationTool(doc, caller, path, o.method, item, o.op)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert operation %s %s: %w", o.method, path, err)
+			}
+			if names[t.name] {
+				return nil, fmt.Errorf("duplicate tool name %q for operation %s %s", t.name, o.method, path)
+			}
+			names[t.name] = true
+			s.tools = append(s.tools, t)
+		}
+	}
+	return s, nil
+}
+
+type set struct {
+	name  string
+	tools []tool.Tool
+}
+
+// Name implements tool.Toolset.
+func (s *set) Name() string {
+	return s.name
+}
+
+// Tools implements tool.Toolset.
+func (s *set) Tools(agent.ReadonlyContext) ([]tool.Tool, error) {
+	return s.tools, nil
+}
+
+// serverVariableRe matches the variables of server URLs like "{version}".
+var serverVariableRe = regexp.MustCompile(`\{([^}]+)\}`)
+
+// serverURL returns the base URL of the API.
+func serverURL(override string, servers []server) (*url.URL, error) {
+	raw := override
+	if raw == "" {
+		if len(servers) == 0 {
+			return nil, errors.New("spec declares no servers, ServerURL is required")
+		}
+		srv := servers[0]
+		raw = serverVariableRe.ReplaceAllStringFunc(srv.URL, func(v string) string {
+			return srv.Variables[strings.Trim(v, "{}")].Default
+		})
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL %q: %w", raw, err)
+	}
+	if !u.IsAbs() {
+		return nil, fmt.Errorf("server URL %q is not absolute, ServerURL is required", raw)
+	}
+	return u, nil
+}
+
+var (
+	snakeBoundaryRe = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	nonNameRe       = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// snakeCase converts an identifier like "listPets" or "List pets" to
+// snake case.
+func snakeCase(s string) string {
+	s = snakeBoundaryRe.ReplaceAllString(s, "${1}_${2}")
+	s = nonNameRe.ReplaceAllString(strings.ToLower(s), "_")
+	return strings.Trim(s, "_")
+}
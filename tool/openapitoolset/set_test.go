@@ -0,0 +1,301 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapitoolset_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/openapitoolset"
+)
+
+func newToolset(t *testing.T, cfg openapitoolset.Config) (tool.Toolset, map[string]toolinternal.FunctionTool) {
+	t.Helper()
+	if cfg.Spec == nil {
+		spec, err := os.ReadFile("testdata/petstore.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.Spec = spec
+	}
+	ts, err := openapitoolset.New(cfg)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	byName := map[string]toolinternal.FunctionTool{}
+	for _, tl := range tools {
+		byName[tl.Name()] = tl.(toolinternal.FunctionTool)
+	}
+	return ts, byName
+}
+
+func newToolContext(t *testing.T) tool.Context {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a})
+	return toolinternal.NewToolContext(invCtx, "call1", nil, nil)
+}
+
+func TestNew_Declarations(t *testing.T) {
+	ts, tools := newToolset(t, openapitoolset.Config{})
+
+	if got, want := ts.Name(), "pet_store"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+	var names []string
+	for name := range tools {
+		names = append(names, name)
+	}
+	wantNames := []string{"create_pet", "delete_pets_pet_id", "list_pets", "show_pet_by_id"}
+	slices.Sort(names)
+	if diff := cmp.Diff(wantNames, names); diff != "" {
+		t.Errorf("tool names mismatch (-want +got):\n%s", diff)
+	}
+
+	if got, want := tools["show_pet_by_id"].Description(), "Info for a specific pet."; got != want {
+		t.Errorf("Description() = %q, want %q", got, want)
+	}
+
+	wantCreate := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"body": map[string]any{
+				"type":     "object",
+				"required": []any{"name"},
+				"properties": map[string]any{
+					"name":   map[string]any{"type": "string"},
+					"tag":    map[string]any{"type": []any{"string", "null"}},
+					"parent": map[string]any{"type": "object"},
+				},
+			},
+		},
+		"required": []string{"body"},
+	}
+	if diff := cmp.Diff(wantCreate, tools["create_pet"].Declaration().ParametersJsonSchema); diff != "" {
+		t.Errorf("create_pet parameters mismatch (-want +got):\n%s", diff)
+	}
+
+	wantList := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"tags":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Tags to filter by."},
+			"limit": map[string]any{"type": "integer"},
+		},
+	}
+	if diff := cmp.Diff(wantList, tools["list_pets"].Declaration().ParametersJsonSchema); diff != "" {
+		t.Errorf("list_pets parameters mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// recordedRequest is a request received by the test server.
+type recordedRequest struct {
+	Method, Path, Query, APIKey, Authorization, RequestID, Body string
+}
+
+func newServer(t *testing.T, status int, response string) (*httptest.Server, *recordedRequest) {
+	t.Helper()
+	got := &recordedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*got = recordedRequest{
+			Method:        r.Method,
+			Path:          r.URL.Path,
+			Query:         r.URL.RawQuery,
+			APIKey:        r.Header.Get("X-API-Key"),
+			Authorization: r.Header.Get("Authorization"),
+			RequestID:     r.Header.Get("X-Request-Id"),
+			Body:          string(body),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+	return server, got
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		tool     string
+		args     map[string]any
+		response string
+		want     recordedRequest
+		wantBody any
+	}{
+		{
+			name:     "query parameters",
+			tool:     "list_pets",
+			args:     map[string]any{"tags": []any{"cat", "dog"}, "limit": 5.0},
+			response: `[{"name":"Tom"}]`,
+			want:     recordedRequest{Method: "GET", Path: "/v1/pets", Query: "limit=5&tags=cat&tags=dog", APIKey: "secret"},
+			wantBody: []any{map[string]any{"name": "Tom"}},
+		},
+		{
+			name:     "path and header parameters",
+			tool:     "show_pet_by_id",
+			args:     map[string]any{"petId": 7.0, "X-Request-Id": "r1"},
+			response: `{"name":"Tom"}`,
+			want:     recordedRequest{Method: "GET", Path: "/v1/pets/7", APIKey: "secret", RequestID: "r1"},
+			wantBody: map[string]any{"name": "Tom"},
+		},
+		{
+			name:     "request body",
+			tool:     "create_pet",
+			args:     map[string]any{"body": map[string]any{"name": "Rex"}},
+			response: `{"name":"Rex"}`,
+			want:     recordedRequest{Method: "POST", Path: "/v1/pets", Authorization: "Bearer token", Body: `{"name":"Rex"}`},
+			wantBody: map[string]any{"name": "Rex"},
+		},
+		{
+			name: "no security",
+			tool: "delete_pets_pet_id",
+			args: map[string]any{"petId": 7.0},
+			want: recordedRequest{Method: "DELETE", Path: "/v1/pets/7"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server, got := newServer(t, http.StatusOK, tc.response)
+			_, tools := newToolset(t, openapitoolset.Config{
+				ServerURL: server.URL + "/v1",
+				Credentials: map[string]openapitoolset.Credential{
+					"api_key": {APIKey: "secret"},
+					"bearer":  {Token: "token"},
+				},
+			})
+
+			result, err := tools[tc.tool].Run(newToolContext(t), tc.args)
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, *got); diff != "" {
+				t.Errorf("request mismatch (-want +got):\n%s", diff)
+			}
+			if result["status_code"] != http.StatusOK {
+				t.Errorf("Run() status_code = %v, want 200", result["status_code"])
+			}
+			if diff := cmp.Diff(tc.wantBody, result["body"]); diff != "" {
+				t.Errorf("Run() body mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		credentials map[string]openapitoolset.Credential
+		args        map[string]any
+		wantErr     string
+	}{
+		{
+			name:        "error status",
+			status:      http.StatusNotFound,
+			credentials: map[string]openapitoolset.Credential{"api_key": {APIKey: "secret"}},
+			args:        map[string]any{"petId": 1.0},
+			wantErr:     `GET /pets/{petId} returned status 404: {"error":"not found"}`,
+		},
+		{
+			name:    "missing credentials",
+			status:  http.StatusOK,
+			args:    map[string]any{"petId": 1.0},
+			wantErr: "no credentials configured for the security schemes api_key",
+		},
+		{
+			name:        "missing path parameter",
+			status:      http.StatusOK,
+			credentials: map[string]openapitoolset.Credential{"api_key": {APIKey: "secret"}},
+			args:        map[string]any{},
+			wantErr:     `missing required parameter "petId"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server, _ := newServer(t, tc.status, `{"error":"not found"}`)
+			_, tools := newToolset(t, openapitoolset.Config{ServerURL: server.URL, Credentials: tc.credentials})
+
+			_, err := tools["show_pet_by_id"].Run(newToolContext(t), tc.args)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Run() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_JSONSpec(t *testing.T) {
+	server, got := newServer(t, http.StatusOK, `{"status":"ok"}`)
+	spec, err := json.Marshal(map[string]any{
+		"openapi": "3.1.0",
+		"info":    map[string]any{"title": "Status"},
+		"servers": []any{map[string]any{"url": server.URL}},
+		"paths": map[string]any{
+			"/status": map[string]any{"get": map[string]any{"operationId": "getStatus"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, tools := newToolset(t, openapitoolset.Config{Name: "status", Spec: spec})
+
+	result, err := tools["get_status"].Run(newToolContext(t), map[string]any{})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if got.Path != "/status" {
+		t.Errorf("request path = %q, want /status", got.Path)
+	}
+	if diff := cmp.Diff(map[string]any{"status": "ok"}, result["body"]); diff != "" {
+		t.Errorf("Run() body mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNew_InvalidSpec(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec string
+	}{
+		{name: "swagger 2", spec: `{"swagger": "2.0"}`},
+		{name: "relative server", spec: "openapi: 3.0.0\nservers:\n  - url: /v1\npaths: {}\n"},
+		{name: "no server", spec: "openapi: 3.0.0\npaths: {}\n"},
+		{name: "unresolvable reference", spec: "openapi: 3.0.0\nservers:\n  - url: https://example.com\npaths:\n  /a:\n    get:\n      parameters:\n        - $ref: '#/components/parameters/missing'\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := openapitoolset.New(openapitoolset.Config{Spec: []byte(tc.spec)}); err == nil {
+				t.Error("New() succeeded, want error")
+			}
+		})
+	}
+}
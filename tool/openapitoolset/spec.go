@@ -0,0 +1,338 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapitoolset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// document is the subset of an OpenAPI 3 document used by the toolset.
+type document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       info                  `json:"info"`
+	Servers    []server              `json:"servers"`
+	Paths      map[string]*pathItem  `json:"paths"`
+	Components components            `json:"components"`
+	Security   []securityRequirement `json:"security"`
+
+	// raw is the whole document, used to resolve schema references.
+	raw map[string]any
+}
+
+type info struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type server struct {
+	URL       string                    `json:"url"`
+	Variables map[string]serverVariable `json:"variables"`
+}
+
+type serverVariable struct {
+	Default string `json:"default"`
+}
+
+type pathItem struct {
+	Ref         string       `json:"$ref"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Parameters  []*parameter `json:"parameters"`
+	Get         *operation   `json:"get"`
+	Put         *operation   `json:"put"`
+	Post        *operation   `json:"post"`
+	Delete      *operation   `json:"delete"`
+	Options     *operation   `json:"options"`
+	Head        *operation   `json:"head"`
+	Patch       *operation   `json:"patch"`
+	Trace       *operation   `json:"trace"`
+}
+
+// operations returns the operations of the path item by HTTP method, in a
+// stable order.
+func (p *pathItem) operations() []struct {
+	method string
+	op     *operation
+} {
+	all := []struct {
+		method string
+		op     *operation
+	}{
+		{"GET", p.Get}, {"PUT", p.Put}, {"POST", p.Post}, {"DELETE", p.Delete},
+		{"OPTIONS", p.Options}, {"HEAD", p.Head}, {"PATCH", p.Patch}, {"TRACE", p.Trace},
+	}
+	ops := all[:0]
+	for _, o := range all {
+		if o.op != nil {
+			ops = append(ops, o)
+		}
+	}
+	return ops
+}
+
+type operation struct {
+	OperationID string       `json:"operationId"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Parameters  []*parameter `json:"parameters"`
+	RequestBody *requestBody `json:"requestBody"`
+	// Security is nil if the operation does not override the security
+	// requirements of the document.
+	Security *[]securityRequirement `json:"security"`
+}
+
+type parameter struct {
+	Ref         string         `json:"$ref"`
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description"`
+	Required    bool           `json:"required"`
+	Schema      map[string]any `json:"schema"`
+	Explode     *bool          `json:"explode"`
+}
+
+type requestBody struct {
+	Ref         string               `json:"$ref"`
+	Description string               `json:"description"`
+	Required    bool                 `json:"required"`
+	Content     map[string]mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema map[string]any `json:"schema"`
+}
+
+type components struct {
+	Parameters      map[string]*parameter      `json:"parameters"`
+	RequestBodies   map[string]*requestBody    `json:"requestBodies"`
+	SecuritySchemes map[string]*securityScheme `json:"securitySchemes"`
+}
+
+// securityRequirement maps the names of security schemes to the required
+// scopes. All schemes of a requirement must be satisfied.
+type securityRequirement map[string][]string
+
+type securityScheme struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	In     string `json:"in"`
+	Scheme string `json:"scheme"`
+}
+
+// parseDocument parses an OpenAPI 3 document in JSON or YAML.
+func parseDocument(spec []byte) (*document, error) {
+	var raw map[string]any
+	if trimmed := bytes.TrimSpace(spec); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse OpenAPI spec as JSON: %w", err)
+		}
+	} else {
+		var v any
+		if err := yaml.Unmarshal(spec, &v); err != nil {
+			return nil, fmt.Errorf("failed to parse OpenAPI spec as YAML: %w", err)
+		}
+		var ok bool
+		if raw, ok = normalizeYAML(v).(map[string]any); !ok {
+			return nil, fmt.Errorf("OpenAPI spec is not an object")
+		}
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert OpenAPI spec: %w", err)
+	}
+	doc := &document{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, want 3.x", doc.OpenAPI)
+	}
+	doc.raw = raw
+	return doc, nil
+}
+
+// normalizeYAML converts the maps with non-string keys decoded from YAML,
+// like response status codes, to maps with string keys.
+func normalizeYAML(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = normalizeYAML(e)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = normalizeYAML(e)
+		}
+		return m
+	case []any:
+		for i, e := range v {
+			v[i] = normalizeYAML(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// resolveParameter returns the parameter a reference points to.
+func (d *document) resolveParameter(p *parameter) (*parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	name, ok := strings.CutPrefix(p.Ref, "#/components/parameters/")
+	if !ok || d.Components.Parameters[name] == nil {
+		return nil, fmt.Errorf("unresolvable parameter reference %q", p.Ref)
+	}
+	return d.resolveParameter(d.Components.Parameters[name])
+}
+
+// resolveRequestBody returns the request body a reference points to.
+func (d *document) resolveRequestBody(b *requestBody) (*requestBody, error) {
+	if b.Ref == "" {
+		return b, nil
+	}
+	name, ok := strings.CutPrefix(b.Ref, "#/components/requestBodies/")
+	if !ok || d.Components.RequestBodies[name] == nil {
+		return nil, fmt.Errorf("unresolvable request body reference %q", b.Ref)
+	}
+	return d.resolveRequestBody(d.Components.RequestBodies[name])
+}
+
+// resolvePathItem returns the path item a reference points to.
+func (d *document) resolvePathItem(p *pathItem) (*pathItem, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	target, err := d.lookup(p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(target)
+	if err != nil {
+		return nil, err
+	}
+	resolved := &pathItem{}
+	if err := json.Unmarshal(data, resolved); err != nil {
+		return nil, fmt.Errorf("invalid path item %q: %w", p.Ref, err)
+	}
+	return resolved, nil
+}
+
+// lookup returns the value a local reference like
+// "#/components/schemas/Pet" points to.
+func (d *document) lookup(ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %q, only local references are supported", ref)
+	}
+	var v any = d.raw
+	for _, token := range strings.Split(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable reference %q", ref)
+		}
+		if v, ok = m[token]; !ok {
+			return nil, fmt.Errorf("unresolvable reference %q", ref)
+		}
+	}
+	return v, nil
+}
+
+// openAPIOnlyKeywords are the OpenAPI schema keywords which are not part of
+// JSON Schema and are dropped from the function declarations.
+var openAPIOnlyKeywords = map[string]bool{
+	"example": true, "xml": true, "externalDocs": true, "discriminator": true, "nullable": true,
+}
+
+// resolveSchema returns a copy of an OpenAPI schema as JSON Schema, with
+// references inlined. Recursive references are replaced by an unconstrained
+// object schema.
+func (d *document) resolveSchema(schema map[string]any, seen map[string]bool) (map[string]any, error) {
+	if ref, ok := schema["$ref"].(string); ok {
+		if seen[ref] {
+			return map[string]any{"type": "object"}, nil
+		}
+		target, err := d.lookup(ref)
+		if err != nil {
+			return nil, err
+		}
+		targetSchema, ok := target.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("reference %q is not a schema", ref)
+		}
+		seen[ref] = true
+		defer delete(seen, ref)
+		return d.resolveSchema(targetSchema, seen)
+	}
+
+	out := make(map[string]any, len(schema))
+	for k, v := range schema {
+		if openAPIOnlyKeywords[k] {
+			continue
+		}
+		var err error
+		switch k {
+		case "items", "additionalProperties", "not":
+			if sub, ok := v.(map[string]any); ok {
+				v, err = d.resolveSchema(sub, seen)
+			}
+		case "properties", "patternProperties":
+			if subs, ok := v.(map[string]any); ok {
+				props := make(map[string]any, len(subs))
+				for name, sub := range subs {
+					if subSchema, ok := sub.(map[string]any); ok {
+						if sub, err = d.resolveSchema(subSchema, seen); err != nil {
+							return nil, err
+						}
+					}
+					props[name] = sub
+				}
+				v = props
+			}
+		case "allOf", "anyOf", "oneOf":
+			if subs, ok := v.([]any); ok {
+				list := make([]any, len(subs))
+				for i, sub := range subs {
+					if subSchema, ok := sub.(map[string]any); ok {
+						if sub, err = d.resolveSchema(subSchema, seen); err != nil {
+							return nil, err
+						}
+					}
+					list[i] = sub
+				}
+				v = list
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		out[k] = v
+	}
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		if t, ok := schema["type"].(string); ok {
+			out["type"] = []any{t, "null"}
+		}
+	}
+	return out, nil
+}
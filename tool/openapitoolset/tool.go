@@ -0,0 +1,417 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapitoolset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+const (
+	// maxResponseSize limits the size of the responses read by the tools.
+	maxResponseSize = 10 << 20
+	// maxErrorDetails limits the response text included in errors.
+	maxErrorDetails = 1000
+)
+
+// operationTool calls an operation of the API.
+type operationTool struct {
+	name        string
+	description string
+	method      string
+	path        string
+	caller      *caller
+
+	// params maps the argument names to the parameters of the operation.
+	params map[string]*parameter
+	// bodyArg is the name of the argument holding the request body, empty
+	// if the operation has no request body.
+	bodyArg     string
+	contentType string
+	security    []securityRequirement
+
+	funcDeclaration *genai.FunctionDeclaration
+}
+
+func newOperationTool(doc *document, caller *caller, path, method string, item *pathItem, op *operation) (*operationTool, error) {
+	name := snakeCase(op.OperationID)
+	if name == "" {
+		name = snakeCase(strings.ToLower(method) + " " + strings.NewReplacer("{", "", "}", "").Replace(path))
+	}
+	if len(name) > maxToolNameLength {
+		name = name[:maxToolNameLength]
+	}
+
+	var descriptions []string
+	for _, d := range []string{op.Summary, op.Description} {
+		if d = strings.TrimSpace(d); d != "" {
+			descriptions = append(descriptions, d)
+		}
+	}
+	if len(descriptions) == 0 {
+		descriptions = append(descriptions, method+" "+path)
+	}
+
+	t := &operationTool{
+		name:        name,
+		description: strings.Join(descriptions, "\n\n"),
+		method:      method,
+		path:        path,
+		caller:      caller,
+		params:      map[string]*parameter{},
+		security:    doc.Security,
+	}
+	if op.Security != nil {
+		t.security = *op.Security
+	}
+
+	params, err := operationParameters(doc, item.Parameters, op.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	properties := map[string]any{}
+	var required []string
+	for _, p := range params {
+		argName := p.Name
+		if _, ok := t.params[argName]; ok {
+			argName = p.In + "_" + p.Name
+		}
+		schema := map[string]any{"type": "string"}
+		if p.Schema != nil {
+			if schema, err = doc.resolveSchema(p.Schema, map[string]bool{}); err != nil {
+				return nil, fmt.Errorf("invalid schema of parameter %q: %w", p.Name, err)
+			}
+		}
+		if p.Description != "" {
+			schema["description"] = p.Description
+		}
+		t.params[argName] = p
+		properties[argName] = schema
+		if p.Required || p.In == "path" {
+			required = append(required, argName)
+		}
+	}
+
+	if op.RequestBody != nil {
+		body, err := doc.resolveRequestBody(op.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		contentType, media := bodyMediaType(body.Content)
+		schema := map[string]any{}
+		if media.Schema != nil {
+			if schema, err = doc.resolveSchema(media.Schema, map[string]bool{}); err != nil {
+				return nil, fmt.Errorf("invalid schema of request body: %w", err)
+			}
+		}
+		if body.Description != "" {
+			schema["description"] = body.Description
+		}
+		t.bodyArg = "body"
+		if _, ok := t.params[t.bodyArg]; ok {
+			t.bodyArg = "request_body"
+		}
+		t.contentType = contentType
+		properties[t.bodyArg] = schema
+		if body.Required {
+			required = append(required, t.bodyArg)
+		}
+	}
+
+	parameters := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		parameters["required"] = required
+	}
+	t.funcDeclaration = &genai.FunctionDeclaration{
+		Name:                 t.name,
+		Description:          t.description,
+		ParametersJsonSchema: parameters,
+	}
+	return t, nil
+}
+
+// operationParameters returns the parameters of an operation, which override
+// the parameters of its path with the same name and location.
+func operationParameters(doc *document, pathParams, opParams []*parameter) ([]*parameter, error) {
+	var params []*parameter
+	for _, p := range slices.Concat(pathParams, opParams) {
+		p, err := doc.resolveParameter(p)
+		if err != nil {
+			return nil, err
+		}
+		if i := slices.IndexFunc(params, func(q *parameter) bool { return q.Name == p.Name && q.In == p.In }); i >= 0 {
+			params[i] = p
+			continue
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// bodyMediaType selects the media type the request body is sent as,
+// preferring JSON.
+func bodyMediaType(content map[string]mediaType) (string, mediaType) {
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	for _, preferred := range []func(string) bool{
+		func(t string) bool { return t == "application/json" },
+		isJSON,
+		func(t string) bool { return t == "application/x-www-form-urlencoded" },
+	} {
+		if i := slices.IndexFunc(types, preferred); i >= 0 {
+			return types[i], content[types[i]]
+		}
+	}
+	if len(types) > 0 {
+		return types[0], content[types[0]]
+	}
+	return "application/json", mediaType{}
+}
+
+func isJSON(contentType string) bool {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	return strings.HasSuffix(strings.TrimSpace(contentType), "json")
+}
+
+// Name implements tool.Tool.
+func (t *operationTool) Name() string {
+	return t.name
+}
+
+// Description implements tool.Tool.
+func (t *operationTool) Description() string {
+	return t.description
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *operationTool) IsLongRunning() bool {
+	return false
+}
+
+// Declaration returns the function declaration of the operation.
+func (t *operationTool) Declaration() *genai.FunctionDeclaration {
+	return t.funcDeclaration
+}
+
+// ProcessRequest adds the tool to the LLM request.
+func (t *operationTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolutils.PackTool(req, t)
+}
+
+// Run calls the operation with the given arguments.
+func (t *operationTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]any, got %T", args)
+	}
+	return t.caller.call(ctx, t, m)
+}
+
+// caller sends the requests of the operations.
+type caller struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	headers    map[string]string
+	auth       *authenticator
+}
+
+func (c *caller) call(ctx context.Context, t *operationTool, args map[string]any) (map[string]any, error) {
+	req, err := c.newRequest(ctx, t, args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request %s %s failed: %w", t.method, t.path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		details := string(data)
+		if len(details) > maxErrorDetails {
+			details = details[:maxErrorDetails] + "..."
+		}
+		return nil, fmt.Errorf("%s %s returned status %d: %s", t.method, t.path, resp.StatusCode, details)
+	}
+
+	result := map[string]any{"status_code": resp.StatusCode}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return result, nil
+	}
+	var body any
+	if err := json.Unmarshal(data, &body); err == nil {
+		result["body"] = body
+	} else {
+		result["body"] = string(data)
+	}
+	return result, nil
+}
+
+// newRequest maps the arguments to the parameters and body of the request.
+func (c *caller) newRequest(ctx context.Context, t *operationTool, args map[string]any) (*http.Request, error) {
+	path := t.path
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+
+	argNames := make([]string, 0, len(t.params))
+	for name := range t.params {
+		argNames = append(argNames, name)
+	}
+	slices.Sort(argNames)
+	for _, name := range argNames {
+		p := t.params[name]
+		v, ok := args[name]
+		if !ok || v == nil {
+			if p.Required || p.In == "path" {
+				return nil, fmt.Errorf("missing required parameter %q", name)
+			}
+			continue
+		}
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(formatValue(v)))
+		case "query":
+			if list, ok := v.([]any); ok && (p.Explode == nil || *p.Explode) {
+				for _, e := range list {
+					query.Add(p.Name, formatValue(e))
+				}
+				continue
+			}
+			query.Add(p.Name, formatValue(v))
+		case "header":
+			header.Set(p.Name, formatValue(v))
+		case "cookie":
+			cookies = append(cookies, &http.Cookie{Name: p.Name, Value: formatValue(v)})
+		}
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(c.baseURL.String(), "/") + path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request URL: %w", err)
+	}
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+
+	var body io.Reader
+	if v, ok := args[t.bodyArg]; ok && t.bodyArg != "" && v != nil {
+		data, err := encodeBody(t.contentType, v)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+		header.Set("Content-Type", t.contentType)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, t.method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	if err := c.auth.apply(req, t.security); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// encodeBody encodes the request body for the content type.
+func encodeBody(contentType string, v any) ([]byte, error) {
+	switch {
+	case isJSON(contentType):
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		return data, nil
+	case contentType == "application/x-www-form-urlencoded":
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("form request body must be an object, got %T", v)
+		}
+		form := url.Values{}
+		for k, e := range m {
+			form.Set(k, formatValue(e))
+		}
+		return []byte(form.Encode()), nil
+	default:
+		return []byte(formatValue(v)), nil
+	}
+}
+
+// formatValue formats an argument value for a parameter. Arrays are joined
+// by commas and objects are encoded as JSON.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		parts := make([]string, len(v))
+		for i, e := range v {
+			parts[i] = formatValue(e)
+		}
+		return strings.Join(parts, ",")
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+var (
+	_ toolinternal.FunctionTool     = (*operationTool)(nil)
+	_ toolinternal.RequestProcessor = (*operationTool)(nil)
+)
@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/genai"
+)
+
+const defaultEmbeddingModel = "gemini-embedding-001"
+
+// NewGeminiEmbedder returns an embedder computing the embeddings with a
+// Gemini embedding model (default: "gemini-embedding-001").
+func NewGeminiEmbedder(client *genai.Client, model string) Embedder {
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+	return &geminiEmbedder{client: client, model: model}
+}
+
+type geminiEmbedder struct {
+	client *genai.Client
+	model  string
+}
+
+func (e *geminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	contents := make([]*genai.Content, len(texts))
+	for i, text := range texts {
+		contents[i] = genai.NewContentFromText(text, genai.RoleUser)
+	}
+	resp, err := e.client.Models.EmbedContent(ctx, e.model, contents, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("model %q returned %d embeddings for %d texts", e.model, len(resp.Embeddings), len(texts))
+	}
+	vectors := make([][]float32, len(resp.Embeddings))
+	for i, emb := range resp.Embeddings {
+		vectors[i] = emb.Values
+	}
+	return vectors, nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+)
+
+// defaultExtensions are the extensions of the files loaded by
+// LoadDirectory by default.
+var defaultExtensions = []string{".md", ".markdown", ".txt"}
+
+// LoadDirectory returns the documents of the files of the directory tree
+// at root with one of the extensions, like ".md" (default: Markdown and
+// text files). Hidden files and directories are skipped.
+//
+// The ID of a document is the slash separated path of its file relative to
+// root, its source the path of the file and its title the first Markdown
+// heading, or the file name.
+func LoadDirectory(root string, extensions ...string) ([]Document, error) {
+	if len(extensions) == 0 {
+		extensions = defaultExtensions
+	}
+	var docs []Document
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !slices.Contains(extensions, strings.ToLower(filepath.Ext(p))) {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, Document{
+			ID:     filepath.ToSlash(rel),
+			Title:  title(string(data), d.Name()),
+			Source: p,
+			Text:   string(data),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the documents of %q: %w", root, err)
+	}
+	return docs, nil
+}
+
+// LoadArtifacts returns the documents of the text artifacts with the names,
+// or of all the text artifacts of the session if no name is given. The ID
+// and source of a document are the name of its artifact.
+func LoadArtifacts(ctx context.Context, artifacts agent.Artifacts, names ...string) ([]Document, error) {
+	all := len(names) == 0
+	if all {
+		resp, err := artifacts.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the artifacts: %w", err)
+		}
+		names = resp.FileNames
+	}
+	var docs []Document
+	for _, name := range names {
+		resp, err := artifacts.Load(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load artifact %q: %w", name, err)
+		}
+		text, ok := partText(resp.Part)
+		if !ok {
+			if all {
+				continue
+			}
+			return nil, fmt.Errorf("artifact %q is not a text document", name)
+		}
+		docs = append(docs, Document{
+			ID:     name,
+			Title:  title(text, name),
+			Source: name,
+			Text:   text,
+		})
+	}
+	return docs, nil
+}
+
+// partText returns the text of a text or text file part.
+func partText(part *genai.Part) (string, bool) {
+	switch {
+	case part == nil:
+		return "", false
+	case part.Text != "":
+		return part.Text, true
+	case part.InlineData != nil:
+		mimeType, _, _ := mime.ParseMediaType(part.InlineData.MIMEType)
+		if strings.HasPrefix(mimeType, "text/") || mimeType == "application/json" {
+			return string(part.InlineData.Data), true
+		}
+	}
+	return "", false
+}
+
+// title returns the first Markdown heading of the text, or the name without
+// its extension.
+func title(text, name string) string {
+	for _, line := range strings.SplitN(text, "\n", 50) {
+		if m := headingRE.FindStringSubmatch(line); m != nil {
+			return m[2]
+		}
+	}
+	return strings.TrimSuffix(name, path.Ext(name))
+}
@@ -0,0 +1,434 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retrievaltool provides a retrieval tool answering questions from
+// a corpus of documents (retrieval augmented generation).
+//
+// Documents are split into chunks, embedded with an [Embedder] and stored
+// in a [VectorStore]. The "retrieve" tool embeds the query of the model and
+// returns the most similar chunks with their sources, which the model cites
+// in its answer.
+//
+// Usage:
+//
+//	r, err := retrievaltool.New(retrievaltool.Config{
+//	    Embedder: retrievaltool.NewGeminiEmbedder(client, ""),
+//	    Store:    retrievaltool.NewInMemoryStore(),
+//	    Splitter: retrievaltool.MarkdownSplitter{},
+//	})
+//	docs, err := retrievaltool.LoadDirectory("docs", ".md")
+//	err = r.Ingest(ctx, docs...)
+//	agent, err := llmagent.New(llmagent.Config{
+//	    Tools:               []tool.Tool{r.Tool()},
+//	    AfterModelCallbacks: []llmagent.AfterModelCallback{r.CitationCallback()},
+//	})
+package retrievaltool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultTopK = 4
+	// embedBatchSize is the maximum number of chunks embedded in a single
+	// call of the embedder.
+	embedBatchSize = 64
+)
+
+// Document is a text document of the corpus.
+type Document struct {
+	// ID identifies the document in the store. Ingesting a document again
+	// replaces its chunks.
+	ID string
+	// Title of the document, cited with its chunks.
+	Title string
+	// Source is the location of the document, like a URL, a file path or
+	// an artifact name, cited with its chunks.
+	Source string
+	// Text is the content of the document.
+	Text string
+}
+
+// Chunk is a part of a document, the unit of retrieval.
+type Chunk struct {
+	// ID identifies the chunk, like "guide.md#3".
+	ID string
+	// DocumentID is the ID of the document of the chunk.
+	DocumentID string
+	// Index is the position of the chunk in its document, starting at 0.
+	Index int
+	// Title and Source are those of the document.
+	Title  string
+	Source string
+	// Heading is the path of the Markdown headings of the section of the
+	// chunk, like "Install > Linux", if the splitter knows it.
+	Heading string
+	// Text is the content of the chunk.
+	Text string
+	// Embedding is the vector of the text, set during ingestion.
+	Embedding []float32
+}
+
+// ScoredChunk is a chunk returned by a search with its similarity to the
+// query.
+type ScoredChunk struct {
+	Chunk
+	// Score is the cosine similarity of the chunk to the query, between -1
+	// and 1.
+	Score float64
+}
+
+// Embedder computes the embedding vectors of texts.
+type Embedder interface {
+	// Embed returns the vectors of the texts, in the same order. All the
+	// vectors must have the same dimension.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// VectorStore stores the chunks of the documents with their embeddings.
+type VectorStore interface {
+	// Upsert adds the chunks, replacing the chunks with the same IDs.
+	Upsert(ctx context.Context, chunks []Chunk) error
+	// DeleteDocument deletes the chunks of a document. Deleting a missing
+	// document is not an error.
+	DeleteDocument(ctx context.Context, documentID string) error
+	// Search returns at most k chunks, most similar to the embedding first.
+	Search(ctx context.Context, embedding []float32, k int) ([]ScoredChunk, error)
+}
+
+// Config holds configuration for the retriever.
+type Config struct {
+	// Name of the tool (default: "retrieve").
+	Name string
+	// Description of the tool, which should tell the model what the corpus
+	// is about (default: a generic description).
+	Description string
+	// Embedder computes the embeddings of the chunks and queries. Required.
+	Embedder Embedder
+	// Store stores the chunks. Required.
+	Store VectorStore
+	// Splitter splits the ingested documents into chunks (default:
+	// TokenSplitter{}).
+	Splitter Splitter
+	// TopK is the number of chunks returned by the tool (default: 4).
+	TopK int
+	// MinScore filters out chunks whose similarity to the query is below
+	// it (optional).
+	MinScore float64
+}
+
+// Retriever ingests documents into a vector store and retrieves the chunks
+// relevant to the queries of the model.
+type Retriever struct {
+	tool     tool.Tool
+	embedder Embedder
+	store    VectorStore
+	splitter Splitter
+	topK     int
+	minScore float64
+
+	// mu serializes the updates of the citations of the invocations, kept
+	// in the temporary state of the session until they are attached to a
+	// final response.
+	mu sync.Mutex
+}
+
+// New creates a new retriever.
+func New(cfg Config) (*Retriever, error) {
+	if cfg.Embedder == nil {
+		return nil, errors.New("an embedder is required")
+	}
+	if cfg.Store == nil {
+		return nil, errors.New("a vector store is required")
+	}
+	if cfg.TopK < 0 {
+		return nil, errors.New("top K must not be negative")
+	}
+	r := &Retriever{
+		embedder: cfg.Embedder,
+		store:    cfg.Store,
+		splitter: cfg.Splitter,
+		topK:     defaultTopK,
+		minScore: cfg.MinScore,
+	}
+	if r.splitter == nil {
+		r.splitter = TokenSplitter{}
+	}
+	if cfg.TopK > 0 {
+		r.topK = cfg.TopK
+	}
+	name := "retrieve"
+	if cfg.Name != "" {
+		name = cfg.Name
+	}
+	description := "Searches the documents of the knowledge base and returns the passages most relevant to the query, with their sources. Cite the sources in your answer."
+	if cfg.Description != "" {
+		description = cfg.Description
+	}
+	t, err := functiontool.New(functiontool.Config{
+		Name:        name,
+		Description: description,
+	}, r.retrieve)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the retrieval tool: %w", err)
+	}
+	r.tool = t
+	return r, nil
+}
+
+// Ingest splits the documents into chunks, embeds them and stores them,
+// replacing the chunks previously ingested for the same document IDs.
+func (r *Retriever) Ingest(ctx context.Context, docs ...Document) error {
+	for _, doc := range docs {
+		if doc.ID == "" {
+			return errors.New("document ID must not be empty")
+		}
+		chunks := r.splitter.Split(doc)
+		for start := 0; start < len(chunks); start += embedBatchSize {
+			batch := chunks[start:min(start+embedBatchSize, len(chunks))]
+			texts := make([]string, len(batch))
+			for i, c := range batch {
+				texts[i] = embeddingText(c)
+			}
+			vectors, err := r.embedder.Embed(ctx, texts)
+			if err != nil {
+				return fmt.Errorf("failed to embed document %q: %w", doc.ID, err)
+			}
+			if len(vectors) != len(batch) {
+				return fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(batch))
+			}
+			for i := range batch {
+				batch[i].Embedding = vectors[i]
+			}
+		}
+		if err := r.store.DeleteDocument(ctx, doc.ID); err != nil {
+			return fmt.Errorf("failed to delete the chunks of document %q: %w", doc.ID, err)
+		}
+		if len(chunks) == 0 {
+			continue
+		}
+		if err := r.store.Upsert(ctx, chunks); err != nil {
+			return fmt.Errorf("failed to store the chunks of document %q: %w", doc.ID, err)
+		}
+	}
+	return nil
+}
+
+// embeddingText returns the text embedded for a chunk: its heading and
+// title give context to passages which do not repeat them.
+func embeddingText(c Chunk) string {
+	var prefix []string
+	if c.Title != "" {
+		prefix = append(prefix, c.Title)
+	}
+	if c.Heading != "" {
+		prefix = append(prefix, c.Heading)
+	}
+	if len(prefix) == 0 {
+		return c.Text
+	}
+	return strings.Join(prefix, "\n") + "\n\n" + c.Text
+}
+
+// Retrieve returns the k chunks most similar to the query, or the
+// configured top K if k is not positive.
+func (r *Retriever) Retrieve(ctx context.Context, query string, k int) ([]ScoredChunk, error) {
+	if k <= 0 {
+		k = r.topK
+	}
+	vectors, err := r.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed the query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embedder returned %d vectors for 1 text", len(vectors))
+	}
+	chunks, err := r.store.Search(ctx, vectors[0], k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the vector store: %w", err)
+	}
+	return slices.DeleteFunc(chunks, func(c ScoredChunk) bool { return c.Score < r.minScore }), nil
+}
+
+type retrieveArgs struct {
+	Query string `json:"query" jsonschema:"the question or keywords to search the documents for"`
+	TopK  int    `json:"top_k,omitempty" jsonschema:"number of passages to return (optional)"`
+}
+
+type retrieveResult struct {
+	Query    string    `json:"query"`
+	Passages []passage `json:"passages"`
+}
+
+type passage struct {
+	// Citation is the number of the source of the passage, to cite as
+	// "[1]" in the answer.
+	Citation int     `json:"citation"`
+	Text     string  `json:"text"`
+	Title    string  `json:"title,omitempty"`
+	Source   string  `json:"source,omitempty"`
+	Heading  string  `json:"heading,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+// Tool returns the tool retrieving the passages relevant to a query.
+func (r *Retriever) Tool() tool.Tool {
+	return r.tool
+}
+
+func (r *Retriever) retrieve(ctx tool.Context, args retrieveArgs) (retrieveResult, error) {
+	if strings.TrimSpace(args.Query) == "" {
+		return retrieveResult{}, errors.New("missing required parameter: query")
+	}
+	chunks, err := r.Retrieve(ctx, args.Query, args.TopK)
+	if err != nil {
+		return retrieveResult{}, err
+	}
+	citations := CitationMetadata(chunks)
+	result := retrieveResult{Query: args.Query, Passages: make([]passage, len(chunks))}
+	for i, c := range chunks {
+		result.Passages[i] = passage{
+			Citation: slices.IndexFunc(citations.Citations, func(cit *genai.Citation) bool { return sameSource(cit, c.Chunk) }) + 1,
+			Text:     c.Text,
+			Title:    c.Title,
+			Source:   c.Source,
+			Heading:  c.Heading,
+			Score:    c.Score,
+		}
+	}
+	if err := r.addCitations(ctx, citations.Citations); err != nil {
+		return retrieveResult{}, err
+	}
+	return result, nil
+}
+
+// CitationMetadata returns the citations of the distinct sources of the
+// chunks, in the order of the chunks.
+func CitationMetadata(chunks []ScoredChunk) *genai.CitationMetadata {
+	metadata := &genai.CitationMetadata{}
+	for _, c := range chunks {
+		if slices.ContainsFunc(metadata.Citations, func(cit *genai.Citation) bool { return sameSource(cit, c.Chunk) }) {
+			continue
+		}
+		metadata.Citations = append(metadata.Citations, &genai.Citation{
+			Title: cmp.Or(c.Title, c.DocumentID),
+			URI:   c.Source,
+		})
+	}
+	return metadata
+}
+
+func sameSource(cit *genai.Citation, c Chunk) bool {
+	return cit.URI == c.Source && cit.Title == cmp.Or(c.Title, c.DocumentID)
+}
+
+// invocationCitations are the sources retrieved during an invocation. They
+// are kept in the temporary state of the session, which is only discarded
+// after the invocation by some session services, so they are tied to the
+// invocation.
+type invocationCitations struct {
+	invocationID string
+	citations    []*genai.Citation
+}
+
+// citationsKey is the temporary state key of the citations of the retriever.
+func (r *Retriever) citationsKey() string {
+	return session.KeyPrefixTemp + r.tool.Name() + "_citations"
+}
+
+// invocationCitations returns the citations of the invocation in state.
+func (r *Retriever) invocationCitations(state session.State, invocationID string) []*genai.Citation {
+	v, err := state.Get(r.citationsKey())
+	if err != nil {
+		return nil
+	}
+	c, ok := v.(*invocationCitations)
+	if !ok || c.invocationID != invocationID {
+		return nil
+	}
+	return c.citations
+}
+
+func (r *Retriever) addCitations(ctx tool.Context, citations []*genai.Citation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := slices.Clone(r.invocationCitations(ctx.State(), ctx.InvocationID()))
+	for _, c := range citations {
+		if !slices.ContainsFunc(current, func(cit *genai.Citation) bool { return *cit == *c }) {
+			current = append(current, c)
+		}
+	}
+	return ctx.State().Set(r.citationsKey(), &invocationCitations{invocationID: ctx.InvocationID(), citations: current})
+}
+
+// CitationCallback returns an after model callback attaching the sources
+// retrieved during the invocation to the CitationMetadata of the final
+// response of the model, the response with text and no function calls.
+// Citations already set by the model are kept.
+func (r *Retriever) CitationCallback() llmagent.AfterModelCallback {
+	return func(ctx agent.CallbackContext, resp *model.LLMResponse, respErr error) (*model.LLMResponse, error) {
+		if respErr != nil || resp == nil || resp.Partial || !isFinalResponse(resp.Content) {
+			return nil, nil
+		}
+		r.mu.Lock()
+		citations := r.invocationCitations(ctx.State(), ctx.InvocationID())
+		var err error
+		if citations != nil {
+			err = ctx.State().Set(r.citationsKey(), nil)
+		}
+		r.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		if len(citations) == 0 {
+			return nil, nil
+		}
+		if resp.CitationMetadata == nil {
+			resp.CitationMetadata = &genai.CitationMetadata{}
+		}
+		resp.CitationMetadata.Citations = append(resp.CitationMetadata.Citations, citations...)
+		return nil, nil
+	}
+}
+
+func isFinalResponse(content *genai.Content) bool {
+	if content == nil {
+		return false
+	}
+	hasText := false
+	for _, p := range content.Parts {
+		if p.FunctionCall != nil {
+			return false
+		}
+		if p.Text != "" && !p.Thought {
+			hasText = true
+		}
+	}
+	return hasText
+}
@@ -0,0 +1,215 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"context"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	iartifact "google.golang.org/adk/internal/artifact"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// wordEmbedder embeds texts as bags of words hashed into a small vector.
+type wordEmbedder struct{}
+
+func (e *wordEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := make([]float32, 64)
+		for _, w := range strings.Fields(strings.ToLower(text)) {
+			h := fnv.New32a()
+			h.Write([]byte(strings.Trim(w, ".,#")))
+			v[h.Sum32()%64]++
+		}
+		vectors[i] = v
+	}
+	return vectors, nil
+}
+
+func newSession(t *testing.T) session.Session {
+	t.Helper()
+	resp, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Session
+}
+
+func newInvocation(t *testing.T, sess session.Session) agent.InvocationContext {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a, Session: sess})
+}
+
+func TestRetriever(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pets/cats.md": "# Cats\n\nCats sleep most of the day.",
+		"pets/dogs.md": "# Dogs\n\nDogs need a daily walk.",
+		"notes.txt":    "Unrelated shopping list: bread, milk.",
+		"image.png":    "not a document",
+		".hidden/x.md": "# Hidden\n\nCats sleep.",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	docs, err := LoadDirectory(dir)
+	if err != nil {
+		t.Fatalf("LoadDirectory() failed: %v", err)
+	}
+	var ids []string
+	for _, d := range docs {
+		ids = append(ids, d.ID+" "+d.Title)
+	}
+	if diff := cmp.Diff([]string{"notes.txt notes", "pets/cats.md Cats", "pets/dogs.md Dogs"}, ids); diff != "" {
+		t.Errorf("LoadDirectory() documents mismatch (-want +got):\n%s", diff)
+	}
+
+	r, err := New(Config{Embedder: &wordEmbedder{}, Store: NewInMemoryStore(), Splitter: MarkdownSplitter{}, TopK: 2})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := r.Ingest(t.Context(), docs...); err != nil {
+		t.Fatalf("Ingest() failed: %v", err)
+	}
+	// Ingesting a document again replaces its chunks.
+	if err := r.Ingest(t.Context(), docs[1]); err != nil {
+		t.Fatalf("Ingest() failed: %v", err)
+	}
+
+	sess := newSession(t)
+	inv := newInvocation(t, sess)
+	retrieve := r.Tool().(toolinternal.FunctionTool)
+	got, err := retrieve.Run(toolinternal.NewToolContext(inv, "call1", nil, nil), map[string]any{"query": "how long do cats sleep"})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	passages := got["passages"].([]any)
+	if len(passages) != 2 {
+		t.Fatalf("Run() returned %d passages, want 2: %v", len(passages), got)
+	}
+	first := passages[0].(map[string]any)
+	if first["source"] != filepath.Join(dir, "pets/cats.md") || first["citation"] != float64(1) || first["heading"] != "Cats" {
+		t.Errorf("first passage = %v, want the cats passage with citation 1", first)
+	}
+	if second := passages[1].(map[string]any); second["citation"] != float64(2) {
+		t.Errorf("second passage = %v, want citation 2", second)
+	}
+
+	// The final response of the model cites the retrieved sources.
+	callback := r.CitationCallback()
+	call := &model.LLMResponse{Content: genai.NewContentFromFunctionCall("retrieve", nil, genai.RoleModel)}
+	if _, err := callback(icontext.NewCallbackContext(inv), call, nil); err != nil || call.CitationMetadata != nil {
+		t.Errorf("callback on a function call = %v, %v, want no citations", call.CitationMetadata, err)
+	}
+	answer := &model.LLMResponse{Content: genai.NewContentFromText("Cats sleep a lot [1].", genai.RoleModel)}
+	if _, err := callback(icontext.NewCallbackContext(inv), answer, nil); err != nil {
+		t.Fatalf("callback failed: %v", err)
+	}
+	if answer.CitationMetadata == nil || len(answer.CitationMetadata.Citations) != 2 {
+		t.Fatalf("CitationMetadata = %v, want the 2 sources", answer.CitationMetadata)
+	}
+	if c := answer.CitationMetadata.Citations[0]; c.Title != "Cats" || c.URI != filepath.Join(dir, "pets/cats.md") {
+		t.Errorf("first citation = %+v, want the cats document", c)
+	}
+	// The citations are attached once.
+	again := &model.LLMResponse{Content: genai.NewContentFromText("Anything else?", genai.RoleModel)}
+	if _, err := callback(icontext.NewCallbackContext(inv), again, nil); err != nil || again.CitationMetadata != nil {
+		t.Errorf("second callback = %v, %v, want no citations", again.CitationMetadata, err)
+	}
+
+	if _, err := retrieve.Run(toolinternal.NewToolContext(inv, "call2", nil, nil), map[string]any{"query": " "}); err == nil {
+		t.Error("Run() with an empty query succeeded, want error")
+	}
+
+	// The citations of an invocation without a final response are not
+	// attached to the final response of the next one.
+	if _, err := retrieve.Run(toolinternal.NewToolContext(inv, "call3", nil, nil), map[string]any{"query": "dogs"}); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	next := &model.LLMResponse{Content: genai.NewContentFromText("Hello.", genai.RoleModel)}
+	if _, err := callback(icontext.NewCallbackContext(newInvocation(t, sess)), next, nil); err != nil || next.CitationMetadata != nil {
+		t.Errorf("callback of the next invocation = %v, %v, want no citations", next.CitationMetadata, err)
+	}
+}
+
+func TestRetriever_MinScore(t *testing.T) {
+	r, err := New(Config{Embedder: &wordEmbedder{}, Store: NewInMemoryStore(), MinScore: 0.5})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := r.Ingest(t.Context(), Document{ID: "a", Text: "alpha beta"}, Document{ID: "b", Text: "gamma delta"}); err != nil {
+		t.Fatalf("Ingest() failed: %v", err)
+	}
+	got, err := r.Retrieve(t.Context(), "alpha beta", 0)
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].DocumentID != "a" {
+		t.Errorf("Retrieve() = %v, want only document a", got)
+	}
+}
+
+func TestLoadArtifacts(t *testing.T) {
+	ctx := t.Context()
+	artifacts := &iartifact.Artifacts{Service: artifact.InMemoryService(), AppName: "app", UserID: "user", SessionID: "session"}
+	for name, part := range map[string]*genai.Part{
+		"notes.md":  genai.NewPartFromText("# Notes\n\nSome notes."),
+		"data.csv":  genai.NewPartFromBytes([]byte("a,b"), "text/csv"),
+		"image.png": genai.NewPartFromBytes([]byte{0x89}, "image/png"),
+	} {
+		if _, err := artifacts.Save(ctx, name, part); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	docs, err := LoadArtifacts(ctx, artifacts)
+	if err != nil {
+		t.Fatalf("LoadArtifacts() failed: %v", err)
+	}
+	want := []Document{
+		{ID: "data.csv", Title: "data", Source: "data.csv", Text: "a,b"},
+		{ID: "notes.md", Title: "Notes", Source: "notes.md", Text: "# Notes\n\nSome notes."},
+	}
+	if diff := cmp.Diff(want, docs); diff != "" {
+		t.Errorf("LoadArtifacts() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := LoadArtifacts(ctx, artifacts, "image.png"); err == nil {
+		t.Error("LoadArtifacts() of an image succeeded, want error")
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultChunkSize  = 256
+	defaultOverlap    = 32
+	defaultMaxSection = 512
+)
+
+// Splitter splits documents into chunks.
+type Splitter interface {
+	// Split returns the chunks of the document, without embeddings.
+	Split(doc Document) []Chunk
+}
+
+// TokenSplitter splits documents into chunks of a fixed number of tokens,
+// overlapping so that a passage cut at the end of a chunk is whole in the
+// next one. Tokens are the words separated by white space, which
+// approximates the tokens of the models well enough to size chunks.
+type TokenSplitter struct {
+	// ChunkSize is the maximum number of tokens of a chunk (default: 256).
+	ChunkSize int
+	// Overlap is the number of tokens repeated at the start of the next
+	// chunk (default: 32). It is reduced to half the chunk size if larger.
+	Overlap int
+}
+
+// Split implements Splitter.
+func (s TokenSplitter) Split(doc Document) []Chunk {
+	var chunks []Chunk
+	for _, text := range s.splitText(doc.Text) {
+		chunks = append(chunks, newChunk(doc, len(chunks), "", text))
+	}
+	return chunks
+}
+
+func (s TokenSplitter) splitText(text string) []string {
+	size, overlap := s.ChunkSize, s.Overlap
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+	if overlap <= 0 {
+		overlap = defaultOverlap
+	}
+	overlap = min(overlap, size/2)
+
+	words := wordSpans(text)
+	var parts []string
+	for start := 0; start < len(words); start += size - overlap {
+		end := min(start+size, len(words))
+		// The text of the chunk keeps the white space between the words.
+		parts = append(parts, text[words[start][0]:words[end-1][1]])
+		if end == len(words) {
+			break
+		}
+	}
+	return parts
+}
+
+// wordSpans returns the start and end offsets of the words of the text.
+func wordSpans(text string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range text {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
+
+// MarkdownSplitter splits Markdown documents at their headings, so that a
+// chunk is a section with the path of its headings. Sections longer than
+// the maximum size are split further by tokens.
+type MarkdownSplitter struct {
+	// MaxTokens is the maximum number of tokens of a chunk (default: 512).
+	MaxTokens int
+	// Overlap is the number of tokens repeated between the chunks of a
+	// long section (default: 32).
+	Overlap int
+}
+
+var (
+	headingRE = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	fenceRE   = regexp.MustCompile("^ {0,3}(```|~~~)")
+)
+
+// Split implements Splitter.
+func (s MarkdownSplitter) Split(doc Document) []Chunk {
+	tokens := TokenSplitter{ChunkSize: s.MaxTokens, Overlap: s.Overlap}
+	if tokens.ChunkSize <= 0 {
+		tokens.ChunkSize = defaultMaxSection
+	}
+
+	var chunks []Chunk
+	var headings []string // headings[i] is the current heading of level i+1.
+	var section []string
+	sectionHeading := ""
+	hasBody := false
+	flush := func() {
+		if hasBody {
+			for _, text := range tokens.splitText(strings.Join(section, "\n")) {
+				chunks = append(chunks, newChunk(doc, len(chunks), sectionHeading, text))
+			}
+		}
+		section, hasBody = nil, false
+	}
+
+	fence := ""
+	for _, line := range strings.Split(doc.Text, "\n") {
+		if m := fenceRE.FindStringSubmatch(line); m != nil {
+			switch fence {
+			case "":
+				fence = m[1]
+			case m[1]:
+				fence = ""
+			}
+		} else if m := headingRE.FindStringSubmatch(line); m != nil && fence == "" {
+			flush()
+			level := len(m[1])
+			for len(headings) < level {
+				headings = append(headings, "")
+			}
+			headings = append(headings[:level-1], m[2])
+			sectionHeading = joinHeadings(headings)
+			section = append(section, line)
+			continue
+		}
+		section = append(section, line)
+		if strings.TrimSpace(line) != "" {
+			hasBody = true
+		}
+	}
+	flush()
+	return chunks
+}
+
+// joinHeadings returns the path of the headings, skipping the missing
+// levels.
+func joinHeadings(headings []string) string {
+	var path []string
+	for _, h := range headings {
+		if h != "" {
+			path = append(path, h)
+		}
+	}
+	return strings.Join(path, " > ")
+}
+
+func newChunk(doc Document, index int, heading, text string) Chunk {
+	return Chunk{
+		ID:         fmt.Sprintf("%s#%d", doc.ID, index),
+		DocumentID: doc.ID,
+		Index:      index,
+		Title:      doc.Title,
+		Source:     doc.Source,
+		Heading:    heading,
+		Text:       text,
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTokenSplitter(t *testing.T) {
+	doc := Document{ID: "doc", Title: "Doc", Source: "doc.txt", Text: "one two  three\nfour five six seven"}
+	got := TokenSplitter{ChunkSize: 3, Overlap: 1}.Split(doc)
+	want := []Chunk{
+		{ID: "doc#0", DocumentID: "doc", Index: 0, Title: "Doc", Source: "doc.txt", Text: "one two  three"},
+		{ID: "doc#1", DocumentID: "doc", Index: 1, Title: "Doc", Source: "doc.txt", Text: "three\nfour five"},
+		{ID: "doc#2", DocumentID: "doc", Index: 2, Title: "Doc", Source: "doc.txt", Text: "five six seven"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Split() mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := (TokenSplitter{}).Split(Document{ID: "empty", Text: " \n "}); len(got) != 0 {
+		t.Errorf("Split() of a blank document = %v, want no chunks", got)
+	}
+}
+
+func TestMarkdownSplitter(t *testing.T) {
+	text := `Preamble.
+
+# Guide
+
+## Install
+Run the installer.
+` + "```sh\n# not a heading\n```" + `
+
+### Linux
+Use the package.
+
+## Usage
+w1 w2 w3 w4 w5 w6 w7 w8 w9 w10 w11 w12
+`
+	var got []string
+	var headings []string
+	for _, c := range (MarkdownSplitter{MaxTokens: 12, Overlap: 1}).Split(Document{ID: "guide.md", Text: text}) {
+		got = append(got, c.Text)
+		headings = append(headings, c.Heading)
+	}
+	want := []string{
+		"Preamble.",
+		"## Install\nRun the installer.\n```sh\n# not a heading\n```",
+		"### Linux\nUse the package.",
+		"## Usage\nw1 w2 w3 w4 w5 w6 w7 w8 w9 w10",
+		"w10 w11 w12",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Split() texts mismatch (-want +got):\n%s", diff)
+	}
+	wantHeadings := []string{"", "Guide > Install", "Guide > Install > Linux", "Guide > Usage", "Guide > Usage"}
+	if diff := cmp.Diff(wantHeadings, headings); diff != "" {
+		t.Errorf("Split() headings mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// SQLiteStore is a vector store persisting the chunks in a SQLite
+// database. Searches compare the query with all the chunks, like the
+// in-memory store, without keeping them in memory.
+type SQLiteStore struct {
+	db *gorm.DB
+}
+
+// chunkRecord is a chunk stored in the database.
+type chunkRecord struct {
+	ID         string `gorm:"primaryKey"`
+	DocumentID string `gorm:"index"`
+	ChunkIndex int
+	Title      string
+	Source     string
+	Heading    string
+	Text       string
+	// Embedding is the vector encoded as little endian float32 values.
+	Embedding []byte
+}
+
+func (chunkRecord) TableName() string {
+	return "retrieval_chunks"
+}
+
+// NewSQLiteStore opens or creates the SQLite database at path, like
+// "chunks.db" or ":memory:", and creates the table of the chunks if needed.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the SQLite database: %w", err)
+	}
+	if err := db.AutoMigrate(&chunkRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to create the chunks table: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close closes the database.
+func (s *SQLiteStore) Close() error {
+	db, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// Upsert implements VectorStore.
+func (s *SQLiteStore) Upsert(ctx context.Context, chunks []Chunk) error {
+	records := make([]chunkRecord, len(chunks))
+	for i, c := range chunks {
+		records[i] = chunkRecord{
+			ID:         c.ID,
+			DocumentID: c.DocumentID,
+			ChunkIndex: c.Index,
+			Title:      c.Title,
+			Source:     c.Source,
+			Heading:    c.Heading,
+			Text:       c.Text,
+			Embedding:  encodeEmbedding(c.Embedding),
+		}
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(records, 100).Error
+}
+
+// DeleteDocument implements VectorStore.
+func (s *SQLiteStore) DeleteDocument(ctx context.Context, documentID string) error {
+	return s.db.WithContext(ctx).Where("document_id = ?", documentID).Delete(&chunkRecord{}).Error
+}
+
+// Search implements VectorStore.
+func (s *SQLiteStore) Search(ctx context.Context, embedding []float32, k int) ([]ScoredChunk, error) {
+	db := s.db.WithContext(ctx)
+	rows, err := db.Model(&chunkRecord{}).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var top topChunks
+	for rows.Next() {
+		var r chunkRecord
+		if err := db.ScanRows(rows, &r); err != nil {
+			return nil, err
+		}
+		c := Chunk{
+			ID:         r.ID,
+			DocumentID: r.DocumentID,
+			Index:      r.ChunkIndex,
+			Title:      r.Title,
+			Source:     r.Source,
+			Heading:    r.Heading,
+			Text:       r.Text,
+			Embedding:  decodeEmbedding(r.Embedding),
+		}
+		score, err := cosine(embedding, c.Embedding)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %q: %w", c.ID, err)
+		}
+		top.add(ScoredChunk{Chunk: c, Score: score}, k)
+	}
+	return top, rows.Err()
+}
+
+func encodeEmbedding(v []float32) []byte {
+	b := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(f))
+	}
+	return b
+}
+
+func decodeEmbedding(b []byte) []float32 {
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return v
+}
+
+var _ VectorStore = (*SQLiteStore)(nil)
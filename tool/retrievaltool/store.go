@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+)
+
+// NewInMemoryStore returns a vector store keeping the chunks in memory and
+// searching them by brute force, which suits corpora of up to tens of
+// thousands of chunks.
+func NewInMemoryStore() VectorStore {
+	return &inMemoryStore{documents: map[string][]Chunk{}}
+}
+
+type inMemoryStore struct {
+	mu sync.RWMutex
+	// documents are the chunks of each document, by document ID.
+	documents map[string][]Chunk
+}
+
+func (s *inMemoryStore) Upsert(ctx context.Context, chunks []Chunk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range chunks {
+		c.Embedding = slices.Clone(c.Embedding)
+		doc := s.documents[c.DocumentID]
+		if i := slices.IndexFunc(doc, func(old Chunk) bool { return old.ID == c.ID }); i >= 0 {
+			doc[i] = c
+			continue
+		}
+		s.documents[c.DocumentID] = append(doc, c)
+	}
+	return nil
+}
+
+func (s *inMemoryStore) DeleteDocument(ctx context.Context, documentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.documents, documentID)
+	return nil
+}
+
+func (s *inMemoryStore) Search(ctx context.Context, embedding []float32, k int) ([]ScoredChunk, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var top topChunks
+	for _, doc := range s.documents {
+		for _, c := range doc {
+			score, err := cosine(embedding, c.Embedding)
+			if err != nil {
+				return nil, fmt.Errorf("chunk %q: %w", c.ID, err)
+			}
+			top.add(ScoredChunk{Chunk: c, Score: score}, k)
+		}
+	}
+	return top, nil
+}
+
+// cosine returns the cosine similarity of two vectors, 0 if one of them is
+// null.
+func cosine(a, b []float32) (float64, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("embedding dimensions differ: %d and %d", len(a), len(b))
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0, nil
+	}
+	return dot / math.Sqrt(normA*normB), nil
+}
+
+// topChunks keeps the k best chunks of a search, best first.
+type topChunks []ScoredChunk
+
+func (t *topChunks) add(c ScoredChunk, k int) {
+	i, _ := slices.BinarySearchFunc(*t, c, compareChunks)
+	if i >= k {
+		return
+	}
+	*t = slices.Insert(*t, i, c)
+	if len(*t) > k {
+		*t = (*t)[:k]
+	}
+}
+
+// compareChunks orders the chunks by decreasing score, then by document
+// and position for a deterministic order.
+func compareChunks(a, b ScoredChunk) int {
+	switch {
+	case a.Score > b.Score:
+		return -1
+	case a.Score < b.Score:
+		return 1
+	}
+	return cmp.Or(cmp.Compare(a.DocumentID, b.DocumentID), cmp.Compare(a.Index, b.Index))
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVectorStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) VectorStore{
+		"in memory": func(t *testing.T) VectorStore { return NewInMemoryStore() },
+		"sqlite": func(t *testing.T) VectorStore {
+			s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "chunks.db"))
+			if err != nil {
+				t.Fatalf("NewSQLiteStore() failed: %v", err)
+			}
+			t.Cleanup(func() { s.Close() })
+			return s
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			s := newStore(t)
+			chunks := []Chunk{
+				{ID: "a#0", DocumentID: "a", Index: 0, Title: "A", Source: "a.md", Heading: "H", Text: "x", Embedding: []float32{1, 0}},
+				{ID: "a#1", DocumentID: "a", Index: 1, Text: "xy", Embedding: []float32{1, 1}},
+				{ID: "b#0", DocumentID: "b", Index: 0, Text: "y", Embedding: []float32{0, 1}},
+			}
+			if err := s.Upsert(ctx, chunks); err != nil {
+				t.Fatalf("Upsert() failed: %v", err)
+			}
+
+			got, err := s.Search(ctx, []float32{2, 0}, 2)
+			if err != nil {
+				t.Fatalf("Search() failed: %v", err)
+			}
+			want := []ScoredChunk{{Chunk: chunks[0], Score: 1}, {Chunk: chunks[1], Score: 0.7071}}
+			if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b float64) bool { return a-b < 1e-4 && b-a < 1e-4 })); diff != "" {
+				t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+			}
+
+			updated := chunks[2]
+			updated.Embedding = []float32{1, 0}
+			if err := s.Upsert(ctx, []Chunk{updated}); err != nil {
+				t.Fatalf("Upsert() failed: %v", err)
+			}
+			if err := s.DeleteDocument(ctx, "a"); err != nil {
+				t.Fatalf("DeleteDocument() failed: %v", err)
+			}
+			got, err = s.Search(ctx, []float32{1, 0}, 5)
+			if err != nil {
+				t.Fatalf("Search() failed: %v", err)
+			}
+			if len(got) != 1 || got[0].ID != "b#0" || got[0].Score != 1 {
+				t.Errorf("Search() after update and delete = %v, want the updated chunk", got)
+			}
+
+			if _, err := s.Search(ctx, []float32{1, 0, 0}, 5); err == nil {
+				t.Error("Search() with another dimension succeeded, want error")
+			}
+		})
+	}
+}
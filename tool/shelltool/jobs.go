@@ -0,0 +1,176 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shelltool
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// add starts waiting for a background command and returns its job ID.
+func (j *jobs) add(command string, p *process) string {
+	jb := &job{
+		command: command,
+		started: time.Now(),
+		process: p,
+		done:    make(chan struct{}),
+	}
+	go func() {
+		jb.result = p.wait()
+		close(jb.done)
+	}()
+
+	id := uuid.NewString()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.byID[id] = jb
+	j.order = append(j.order, id)
+	for len(j.order) > maxJobs {
+		i := slices.IndexFunc(j.order, func(id string) bool { return j.byID[id].completed() })
+		if i < 0 {
+			break
+		}
+		delete(j.byID, j.order[i])
+		j.order = slices.Delete(j.order, i, i+1)
+	}
+	return id
+}
+
+func (j *jobs) get(id string) (*job, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	jb, ok := j.byID[id]
+	return jb, ok
+}
+
+func (jb *job) completed() bool {
+	select {
+	case <-jb.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// status returns the progress of the job: its output so far while it is
+// running, and its result once completed.
+func (jb *job) status() map[string]any {
+	var status map[string]any
+	if jb.completed() {
+		status = maps.Clone(jb.result)
+		status["status"] = "completed"
+	} else {
+		status = jb.process.output()
+		status["status"] = "running"
+	}
+	status["command"] = jb.command
+	status["elapsed_seconds"] = int(time.Since(jb.started).Seconds())
+	return status
+}
+
+// outputTool is the get_command_output tool.
+type outputTool struct {
+	jobs *jobs
+}
+
+// Name implements tool.Tool.
+func (t *outputTool) Name() string {
+	return "get_command_output"
+}
+
+// Description implements tool.Tool.
+func (t *outputTool) Description() string {
+	return "Returns the status and the output so far of a command started by run_shell_command, " +
+		"and its exit code once completed."
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *outputTool) IsLongRunning() bool {
+	return false
+}
+
+// Declaration returns the function declaration of the tool.
+func (t *outputTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        t.Name(),
+		Description: t.Description(),
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"job_id": {
+					Type:        "STRING",
+					Description: "The job ID returned by run_shell_command.",
+				},
+			},
+			Required: []string{"job_id"},
+		},
+	}
+}
+
+// ProcessRequest packs the tool into the LLM request.
+func (t *outputTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolutils.PackTool(req, t)
+}
+
+// Run returns the status of the job.
+func (t *outputTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	}
+	id, ok := m["job_id"].(string)
+	if !ok || strings.TrimSpace(id) == "" {
+		return nil, errors.New("missing required parameter: job_id")
+	}
+	jb, ok := t.jobs.get(id)
+	if !ok {
+		return nil, fmt.Errorf("unknown job %q", id)
+	}
+	status := jb.status()
+	status["job_id"] = id
+	return status, nil
+}
+
+// jobs holds the commands running in the background.
+type jobs struct {
+	mu    sync.Mutex
+	byID  map[string]*job
+	order []string
+}
+
+// maxJobs is the number of jobs kept; the oldest completed jobs are removed
+// first.
+const maxJobs = 100
+
+type job struct {
+	command string
+	started time.Time
+	process *process
+	done    chan struct{}
+	// result is set once done is closed.
+	result map[string]any
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shelltool
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// outputBuffer keeps the beginning and the end of the output of a command
+// up to a size limit. It is safe for concurrent use, so that the output of
+// running commands can be read.
+type outputBuffer struct {
+	mu    sync.Mutex
+	limit int
+	head  []byte
+	// tail is a ring buffer of the last bytes, starting at tailStart once
+	// full.
+	tail      []byte
+	tailStart int
+	total     int64
+}
+
+func newOutputBuffer(limit int) *outputBuffer {
+	return &outputBuffer{limit: limit}
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := len(p)
+	b.total += int64(n)
+	headLimit := b.limit - b.limit/2
+	if len(b.head) < headLimit {
+		k := min(headLimit-len(b.head), len(p))
+		b.head = append(b.head, p[:k]...)
+		p = p[k:]
+	}
+	tailLimit := b.limit / 2
+	if tailLimit == 0 {
+		return n, nil
+	}
+	if len(p) >= tailLimit {
+		b.tail = append(b.tail[:0], p[len(p)-tailLimit:]...)
+		b.tailStart = 0
+		return n, nil
+	}
+	for _, c := range p {
+		if len(b.tail) < tailLimit {
+			b.tail = append(b.tail, c)
+			continue
+		}
+		b.tail[b.tailStart] = c
+		b.tailStart = (b.tailStart + 1) % tailLimit
+	}
+	return n, nil
+}
+
+// String returns the output, with a marker replacing the omitted middle
+// part if it exceeds the limit.
+func (b *outputBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var sb strings.Builder
+	sb.Write(b.head)
+	if omitted := b.total - int64(len(b.head)) - int64(len(b.tail)); omitted > 0 {
+		fmt.Fprintf(&sb, "\n... [%d bytes truncated] ...\n", omitted)
+	}
+	sb.Write(b.tail[b.tailStart:])
+	sb.Write(b.tail[:b.tailStart])
+	return strings.ToValidUTF8(sb.String(), "�")
+}
+
+// truncated reports whether part of the output was omitted.
+func (b *outputBuffer) truncated() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.total > int64(b.limit)
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shelltool
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Decision is the decision of a policy about a command.
+type Decision int
+
+const (
+	// Confirm requires the user to confirm the command before it runs.
+	Confirm Decision = iota
+	// Allow runs the command without confirmation.
+	Allow
+	// Deny refuses to run the command.
+	Deny
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Confirm:
+		return "confirm"
+	case Allow:
+		return "allow"
+	case Deny:
+		return "deny"
+	default:
+		return fmt.Sprintf("Decision(%d)", int(d))
+	}
+}
+
+// Policy decides whether a command runs, is denied or requires user
+// confirmation. Rules are evaluated in order: denied patterns, then allowed
+// prefixes, then the default decision.
+type Policy struct {
+	// DeniedPatterns are regular expressions (RE2 syntax) of the commands
+	// which are denied, like `\brm\s+-rf\b`. They are matched against the
+	// whole command.
+	DeniedPatterns []string
+	// AllowedPrefixes are the commands which run without confirmation,
+	// like "ls" or "go test". A command matches a prefix if it is the
+	// prefix or starts with the prefix followed by a space. Commands
+	// containing shell operators like ";", "|", "&&", "$(" or redirections
+	// never match, since they could run other commands.
+	AllowedPrefixes []string
+	// Default is the decision for the other commands (default: Confirm).
+	Default Decision
+}
+
+// compiledPolicy is a Policy with compiled patterns.
+type compiledPolicy struct {
+	denied   []*regexp.Regexp
+	prefixes []string
+	fallback Decision
+}
+
+func compilePolicy(p *Policy) (*compiledPolicy, error) {
+	if p == nil {
+		return &compiledPolicy{fallback: Confirm}, nil
+	}
+	if p.Default < Confirm || p.Default > Deny {
+		return nil, fmt.Errorf("invalid default decision %v", p.Default)
+	}
+	cp := &compiledPolicy{fallback: p.Default}
+	for _, pattern := range p.DeniedPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid denied pattern %q: %w", pattern, err)
+		}
+		cp.denied = append(cp.denied, re)
+	}
+	for _, prefix := range p.AllowedPrefixes {
+		if prefix = strings.Join(strings.Fields(prefix), " "); prefix != "" {
+			cp.prefixes = append(cp.prefixes, prefix)
+		}
+	}
+	return cp, nil
+}
+
+// decide returns the decision about a command with its reason.
+func (p *compiledPolicy) decide(command string) (Decision, string) {
+	for _, re := range p.denied {
+		if re.MatchString(command) {
+			return Deny, fmt.Sprintf("command matches denied pattern %q", re.String())
+		}
+	}
+	if !hasShellOperators(command) {
+		normalized := strings.Join(strings.Fields(command), " ")
+		for _, prefix := range p.prefixes {
+			if normalized == prefix || strings.HasPrefix(normalized, prefix+" ") {
+				return Allow, fmt.Sprintf("command matches allowed prefix %q", prefix)
+			}
+		}
+	}
+	return p.fallback, "default decision"
+}
+
+// hasShellOperators reports whether a command contains shell syntax which
+// could run other commands or write files.
+func hasShellOperators(command string) bool {
+	return strings.ContainsAny(command, ";&|<>`\n\r") || strings.Contains(command, "$(")
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shelltool
+
+import (
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	policy, err := compilePolicy(&Policy{
+		DeniedPatterns:  []string{`\bsudo\b`, `\brm\s+-rf\b`},
+		AllowedPrefixes: []string{"ls", "go  test", "git status"},
+	})
+	if err != nil {
+		t.Fatalf("compilePolicy() failed: %v", err)
+	}
+	tests := []struct {
+		command string
+		want    Decision
+	}{
+		{command: "ls", want: Allow},
+		{command: "ls -la dir", want: Allow},
+		{command: "go test ./...", want: Allow},
+		{command: "  go   test  ./pkg", want: Allow},
+		{command: "git status", want: Allow},
+		{command: "lsof", want: Confirm},
+		{command: "git push", want: Confirm},
+		{command: "ls; curl example.com", want: Confirm},
+		{command: "ls && make", want: Confirm},
+		{command: "ls | sh", want: Confirm},
+		{command: "ls > out.txt", want: Confirm},
+		{command: "ls $(cat dirs)", want: Confirm},
+		{command: "ls `cat dirs`", want: Confirm},
+		{command: "ls\nmake", want: Confirm},
+		{command: "sudo ls", want: Deny},
+		{command: "ls && rm  -rf /", want: Deny},
+	}
+	for _, tc := range tests {
+		t.Run(tc.command, func(t *testing.T) {
+			if got, reason := policy.decide(tc.command); got != tc.want {
+				t.Errorf("decide(%q) = %v (%s), want %v", tc.command, got, reason, tc.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_Default(t *testing.T) {
+	for _, tc := range []struct {
+		policy *Policy
+		want   Decision
+	}{
+		{policy: nil, want: Confirm},
+		{policy: &Policy{}, want: Confirm},
+		{policy: &Policy{Default: Allow}, want: Allow},
+		{policy: &Policy{Default: Deny}, want: Deny},
+	} {
+		policy, err := compilePolicy(tc.policy)
+		if err != nil {
+			t.Fatalf("compilePolicy() failed: %v", err)
+		}
+		if got, _ := policy.decide("make"); got != tc.want {
+			t.Errorf("decide() with %+v = %v, want %v", tc.policy, got, tc.want)
+		}
+	}
+}
+
+func TestPolicy_Invalid(t *testing.T) {
+	if _, err := compilePolicy(&Policy{DeniedPatterns: []string{"("}}); err == nil {
+		t.Error("compilePolicy() with invalid pattern succeeded, want error")
+	}
+	if _, err := compilePolicy(&Policy{Default: Decision(7)}); err == nil {
+		t.Error("compilePolicy() with invalid default succeeded, want error")
+	}
+}
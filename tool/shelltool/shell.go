@@ -0,0 +1,365 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shelltool provides a toolset running shell commands.
+//
+// The run_shell_command tool runs a command with the shell in a working
+// directory, with a timeout, a limit on the size of the returned output and
+// only the allowed environment variables. A Policy decides for each command
+// whether it runs, is denied, or requires the confirmation of the user
+// through the Human-in-the-Loop confirmation flow of the ADK framework.
+//
+// With LongRunning, the commands run in the background: run_shell_command
+// is a long-running tool returning a job ID, and the get_command_output
+// tool reports the output of the command so far and its exit code once
+// completed.
+//
+// Example:
+//
+//	shell, err := shelltool.New(shelltool.Config{
+//		WorkDir: "/path/to/project",
+//		Policy: &shelltool.Policy{
+//			AllowedPrefixes: []string{"ls", "git status", "go test"},
+//			DeniedPatterns:  []string{`\bsudo\b`, `\brm\s+-rf\b`},
+//		},
+//	})
+//	...
+//	llmagent.New(llmagent.Config{
+//		Name:     "coding_agent",
+//		Model:    model,
+//		Toolsets: []tool.Toolset{shell},
+//	})
+package shelltool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+const (
+	defaultTimeout       = 60 * time.Second
+	defaultMaxOutputSize = 32 << 10
+	// waitDelay is how long to wait for the output of the processes started
+	// by a command after it exits or is killed.
+	waitDelay = 2 * time.Second
+)
+
+// defaultAllowedEnv are the environment variables passed to the commands by
+// default.
+var defaultAllowedEnv = []string{
+	"PATH", "HOME", "USER", "LANG", "LC_ALL", "TMPDIR", "TEMP", "TMP", "SYSTEMROOT", "USERPROFILE",
+}
+
+// Config provides the configuration of a shell toolset.
+type Config struct {
+	// WorkDir is the working directory of the commands (default: the
+	// working directory of the process).
+	WorkDir string
+	// Shell is the command running the commands, which are appended to it
+	// (default: "/bin/sh -c", or "cmd /C" on Windows).
+	Shell []string
+	// Timeout limits the duration of the commands (default: 60 seconds).
+	Timeout time.Duration
+	// MaxOutputSize is the maximum size in bytes of the standard output
+	// and the standard error returned to the model (default: 32 KiB). The
+	// middle of larger outputs is omitted.
+	MaxOutputSize int
+	// AllowedEnv are the names of the environment variables of the process
+	// passed to the commands (default: PATH, HOME, USER, LANG, LC_ALL,
+	// TMPDIR, TEMP, TMP, SYSTEMROOT and USERPROFILE).
+	AllowedEnv []string
+	// Env are environment variables set for the commands.
+	Env map[string]string
+	// Policy decides whether the commands run, are denied or require
+	// confirmation. If nil, all commands require confirmation.
+	Policy *Policy
+	// LongRunning runs the commands in the background and adds the
+	// get_command_output tool reporting their progress.
+	LongRunning bool
+}
+
+// New returns a toolset running shell commands.
+func New(cfg Config) (tool.Toolset, error) {
+	policy, err := compilePolicy(cfg.Policy)
+	if err != nil {
+		return nil, err
+	}
+	r := &runner{
+		workDir:       cfg.WorkDir,
+		shell:         cfg.Shell,
+		timeout:       cfg.Timeout,
+		maxOutputSize: cfg.MaxOutputSize,
+		env:           environment(cfg.AllowedEnv, cfg.Env),
+	}
+	if len(r.shell) == 0 {
+		r.shell = []string{"/bin/sh", "-c"}
+		if runtime.GOOS == "windows" {
+			r.shell = []string{"cmd", "/C"}
+		}
+	}
+	if r.timeout <= 0 {
+		r.timeout = defaultTimeout
+	}
+	if r.maxOutputSize <= 0 {
+		r.maxOutputSize = defaultMaxOutputSize
+	}
+	if r.workDir != "" {
+		info, err := os.Stat(r.workDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to access working directory: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("working directory %q is not a directory", r.workDir)
+		}
+	}
+
+	run := &runTool{runner: r, policy: policy}
+	s := &set{tools: []tool.Tool{run}}
+	if cfg.LongRunning {
+		run.jobs = &jobs{byID: map[string]*job{}}
+		s.tools = append(s.tools, &outputTool{jobs: run.jobs})
+	}
+	return s, nil
+}
+
+// environment returns the environment of the commands.
+func environment(allowed []string, extra map[string]string) []string {
+	if allowed == nil {
+		allowed = defaultAllowedEnv
+	}
+	var env []string
+	for _, name := range allowed {
+		if _, ok := extra[name]; ok {
+			continue
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	for name, v := range extra {
+		env = append(env, name+"="+v)
+	}
+	return env
+}
+
+type set struct {
+	tools []tool.Tool
+}
+
+// Name implements tool.Toolset.
+func (s *set) Name() string {
+	return "shell_toolset"
+}
+
+// Tools implements tool.Toolset.
+func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	return s.tools, nil
+}
+
+// runner runs commands.
+type runner struct {
+	workDir       string
+	shell         []string
+	timeout       time.Duration
+	maxOutputSize int
+	env           []string
+}
+
+// process is a started command.
+type process struct {
+	cmd            *exec.Cmd
+	cancel         context.CancelFunc
+	stdout, stderr *outputBuffer
+	ctx            context.Context
+}
+
+// start starts a command, which is killed when ctx is done or after the
+// timeout.
+func (r *runner) start(ctx context.Context, command string) (*process, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	args := append(append([]string{}, r.shell[1:]...), command)
+	cmd := exec.CommandContext(ctx, r.shell[0], args...)
+	cmd.Dir = r.workDir
+	cmd.Env = r.env
+	cmd.WaitDelay = waitDelay
+	killProcessGroup(cmd)
+	p := &process{
+		cmd:    cmd,
+		cancel: cancel,
+		stdout: newOutputBuffer(r.maxOutputSize),
+		stderr: newOutputBuffer(r.maxOutputSize),
+		ctx:    ctx,
+	}
+	cmd.Stdout, cmd.Stderr = p.stdout, p.stderr
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start command: %w", err)
+	}
+	return p, nil
+}
+
+// wait waits for the command to exit and returns its result.
+func (p *process) wait() map[string]any {
+	defer p.cancel()
+	err := p.cmd.Wait()
+	result := p.output()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(p.ctx.Err(), context.DeadlineExceeded):
+		result["timed_out"] = true
+		result["exit_code"] = -1
+	case err == nil || errors.As(err, &exitErr):
+		result["exit_code"] = p.cmd.ProcessState.ExitCode()
+	default:
+		result["exit_code"] = -1
+		result["error"] = err.Error()
+	}
+	return result
+}
+
+// output returns the output of the command so far.
+func (p *process) output() map[string]any {
+	result := map[string]any{
+		"stdout": p.stdout.String(),
+		"stderr": p.stderr.String(),
+	}
+	if p.stdout.truncated() || p.stderr.truncated() {
+		result["truncated"] = true
+	}
+	return result
+}
+
+// runTool is the run_shell_command tool.
+type runTool struct {
+	runner *runner
+	policy *compiledPolicy
+	// jobs holds the commands running in the background if the tool is
+	// long-running.
+	jobs *jobs
+}
+
+// Name implements tool.Tool.
+func (t *runTool) Name() string {
+	return "run_shell_command"
+}
+
+// Description implements tool.Tool.
+func (t *runTool) Description() string {
+	desc := "Runs a shell command and returns its exit code, standard output and standard error."
+	if t.runner.workDir != "" {
+		desc += fmt.Sprintf(" The working directory is %s.", t.runner.workDir)
+	}
+	desc += fmt.Sprintf(" Commands are killed after %s.", t.runner.timeout)
+	if t.jobs != nil {
+		desc += " The command runs in the background: use get_command_output with the returned job_id " +
+			"to get its output and exit code.\n\n" +
+			"NOTE: This is a long-running operation. Do not call this tool again if it has already returned some intermediate or pending status."
+	}
+	return desc
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *runTool) IsLongRunning() bool {
+	return t.jobs != nil
+}
+
+// Declaration returns the function declaration of the tool.
+func (t *runTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        t.Name(),
+		Description: t.Description(),
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"command": {
+					Type:        "STRING",
+					Description: "The shell command to run.",
+				},
+			},
+			Required: []string{"command"},
+		},
+	}
+}
+
+// ProcessRequest packs the tool into the LLM request.
+func (t *runTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolutils.PackTool(req, t)
+}
+
+// Run runs the command if the policy allows it or the user confirmed it.
+func (t *runTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	}
+	command, ok := m["command"].(string)
+	if !ok || strings.TrimSpace(command) == "" {
+		return nil, errors.New("missing required parameter: command")
+	}
+
+	decision, reason := t.policy.decide(command)
+	switch decision {
+	case Deny:
+		return nil, fmt.Errorf("command %q is denied by the policy: %s", command, reason)
+	case Confirm:
+		if confirmation := ctx.ToolConfirmation(); confirmation != nil {
+			if !confirmation.Confirmed {
+				return nil, fmt.Errorf("error tool %q call is rejected", t.Name())
+			}
+			break
+		}
+		err := ctx.RequestConfirmation(
+			fmt.Sprintf("Please approve or reject running the shell command: %s", command),
+			map[string]any{"command": command})
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error tool %q requires confirmation, please approve or reject", t.Name())
+	}
+
+	if t.jobs != nil {
+		// The command outlives the invocation.
+		p, err := t.runner.start(context.WithoutCancel(ctx), command)
+		if err != nil {
+			return nil, err
+		}
+		id := t.jobs.add(command, p)
+		return map[string]any{
+			"command": command,
+			"job_id":  id,
+			"status":  "running",
+		}, nil
+	}
+
+	p, err := t.runner.start(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+	result := p.wait()
+	result["command"] = command
+	return result, nil
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shelltool
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+func createToolContext(t *testing.T, actions *session.EventActions, confirmation *toolconfirmation.ToolConfirmation) tool.Context {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a})
+	return toolinternal.NewToolContext(invCtx, "call1", actions, confirmation)
+}
+
+func newTools(t *testing.T, cfg Config) map[string]toolinternal.FunctionTool {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the tests use a POSIX shell")
+	}
+	ts, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	list, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	m := map[string]toolinternal.FunctionTool{}
+	for _, tl := range list {
+		m[tl.Name()] = tl.(toolinternal.FunctionTool)
+	}
+	return m
+}
+
+var allowAll = &Policy{Default: Allow}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("SHELLTOOL_SECRET", "secret")
+	t.Setenv("SHELLTOOL_ALLOWED", "allowed")
+	tests := []struct {
+		name    string
+		cfg     Config
+		command string
+		want    map[string]any
+	}{
+		{
+			name:    "output and exit code",
+			cfg:     Config{Policy: allowAll},
+			command: "echo out; echo err >&2; exit 3",
+			want:    map[string]any{"command": "echo out; echo err >&2; exit 3", "stdout": "out\n", "stderr": "err\n", "exit_code": 3},
+		},
+		{
+			name:    "working directory",
+			cfg:     Config{Policy: allowAll, WorkDir: dir},
+			command: "pwd",
+			want:    map[string]any{"command": "pwd", "stdout": dir + "\n", "stderr": "", "exit_code": 0},
+		},
+		{
+			name:    "environment",
+			cfg:     Config{Policy: allowAll, AllowedEnv: []string{"PATH", "SHELLTOOL_ALLOWED"}, Env: map[string]string{"EXTRA": "extra"}},
+			command: `echo "$SHELLTOOL_ALLOWED $SHELLTOOL_SECRET $EXTRA"`,
+			want:    map[string]any{"command": `echo "$SHELLTOOL_ALLOWED $SHELLTOOL_SECRET $EXTRA"`, "stdout": "allowed  extra\n", "stderr": "", "exit_code": 0},
+		},
+		{
+			name:    "truncated output",
+			cfg:     Config{Policy: allowAll, MaxOutputSize: 8},
+			command: "printf 0123456789abcdef",
+			want:    map[string]any{"command": "printf 0123456789abcdef", "stdout": "0123\n... [8 bytes truncated] ...\ncdef", "stderr": "", "exit_code": 0, "truncated": true},
+		},
+		{
+			name:    "timeout",
+			cfg:     Config{Policy: allowAll, Timeout: 100 * time.Millisecond},
+			command: "echo started; sleep 10",
+			want:    map[string]any{"command": "echo started; sleep 10", "stdout": "started\n", "stderr": "", "exit_code": -1, "timed_out": true},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newTools(t, tc.cfg)
+			start := time.Now()
+			got, err := ts["run_shell_command"].Run(createToolContext(t, nil, nil), map[string]any{"command": tc.command})
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Run() took %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestRun_Policy(t *testing.T) {
+	dir := t.TempDir()
+	ts := newTools(t, Config{WorkDir: dir, Policy: &Policy{
+		AllowedPrefixes: []string{"echo"},
+		DeniedPatterns:  []string{`\brm\b`},
+	}})
+	run := ts["run_shell_command"]
+
+	if _, err := run.Run(createToolContext(t, nil, nil), map[string]any{"command": "echo hi"}); err != nil {
+		t.Errorf("Run() of an allowed command failed: %v", err)
+	}
+	if _, err := run.Run(createToolContext(t, nil, &toolconfirmation.ToolConfirmation{Confirmed: true}), map[string]any{"command": "rm -f x"}); err == nil || !strings.Contains(err.Error(), "denied by the policy") {
+		t.Errorf("Run() of a denied command error = %v, want denied", err)
+	}
+
+	actions := &session.EventActions{}
+	_, err := run.Run(createToolContext(t, actions, nil), map[string]any{"command": "touch file"})
+	if err == nil || !strings.Contains(err.Error(), "requires confirmation") {
+		t.Fatalf("Run() error = %v, want confirmation request", err)
+	}
+	want := toolconfirmation.ToolConfirmation{
+		Hint:    "Please approve or reject running the shell command: touch file",
+		Payload: map[string]any{"command": "touch file"},
+	}
+	if diff := cmp.Diff(want, actions.RequestedToolConfirmations["call1"]); diff != "" {
+		t.Errorf("requested confirmation mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := run.Run(createToolContext(t, nil, &toolconfirmation.ToolConfirmation{Confirmed: false}), map[string]any{"command": "touch file"}); err == nil {
+		t.Error("Run() rejected by the user succeeded, want error")
+	}
+	got, err := run.Run(createToolContext(t, nil, &toolconfirmation.ToolConfirmation{Confirmed: true}), map[string]any{"command": "touch file && echo done"})
+	if err != nil {
+		t.Fatalf("Run() confirmed by the user failed: %v", err)
+	}
+	if got["stdout"] != "done\n" {
+		t.Errorf("Run() stdout = %q, want %q", got["stdout"], "done\n")
+	}
+}
+
+func TestRun_LongRunning(t *testing.T) {
+	ts := newTools(t, Config{Policy: allowAll, LongRunning: true})
+	run, output := ts["run_shell_command"], ts["get_command_output"]
+	if !run.IsLongRunning() {
+		t.Error("IsLongRunning() = false, want true")
+	}
+
+	dir := t.TempDir()
+	command := "echo first; while [ ! -f " + dir + "/go ]; do sleep 0.01; done; echo second"
+	got, err := run.Run(createToolContext(t, nil, nil), map[string]any{"command": command})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if got["status"] != "running" {
+		t.Fatalf("Run() = %v, want running status", got)
+	}
+	id := got["job_id"].(string)
+
+	status := waitFor(t, func() map[string]any {
+		status, err := output.Run(createToolContext(t, nil, nil), map[string]any{"job_id": id})
+		if err != nil {
+			t.Fatalf("get_command_output failed: %v", err)
+		}
+		return status
+	}, func(status map[string]any) bool { return status["stdout"] == "first\n" })
+	if status["status"] != "running" {
+		t.Errorf("status = %v, want running", status["status"])
+	}
+
+	if _, err := run.Run(createToolContext(t, nil, nil), map[string]any{"command": "touch " + dir + "/go"}); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	status = waitFor(t, func() map[string]any {
+		status, err := output.Run(createToolContext(t, nil, nil), map[string]any{"job_id": id})
+		if err != nil {
+			t.Fatalf("get_command_output failed: %v", err)
+		}
+		return status
+	}, func(status map[string]any) bool { return status["status"] == "completed" })
+	delete(status, "elapsed_seconds")
+	want := map[string]any{
+		"job_id":    id,
+		"command":   command,
+		"status":    "completed",
+		"stdout":    "first\nsecond\n",
+		"stderr":    "",
+		"exit_code": 0,
+	}
+	if diff := cmp.Diff(want, status); diff != "" {
+		t.Errorf("get_command_output mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := output.Run(createToolContext(t, nil, nil), map[string]any{"job_id": "unknown"}); err == nil {
+		t.Error("get_command_output of an unknown job succeeded, want error")
+	}
+}
+
+func waitFor(t *testing.T, get func() map[string]any, done func(map[string]any) bool) map[string]any {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		status := get()
+		if done(status) {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting, last status: %v", status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestOutputBuffer(t *testing.T) {
+	b := newOutputBuffer(10)
+	for _, s := range []string{"01", "234", "56789", "abc", "d", "ef"} {
+		b.Write([]byte(s))
+	}
+	if got, want := b.String(), "01234\n... [6 bytes truncated] ...\nbcdef"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !b.truncated() {
+		t.Error("truncated() = false, want true")
+	}
+
+	b = newOutputBuffer(10)
+	b.Write([]byte("0123456789"))
+	if got := b.String(); got != "0123456789" || b.truncated() {
+		t.Errorf("String() = %q, truncated() = %v, want the whole output", got, b.truncated())
+	}
+}
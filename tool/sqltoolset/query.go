@@ -0,0 +1,343 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqltoolset
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/tool"
+)
+
+// schemaQueries are the queries listing the schema of a dialect.
+type schemaQueries struct {
+	// listTables returns the name and type of the tables and views.
+	listTables string
+	// describeTable returns the name, type, nullability, default value and
+	// primary key membership of the columns of the table given as the
+	// parameter.
+	describeTable string
+}
+
+var schemaQueriesByDialect = map[Dialect]schemaQueries{
+	SQLite: {
+		listTables: `SELECT name, type FROM sqlite_master
+			WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`,
+		describeTable: `SELECT name, type, "notnull" = 0, dflt_value, pk > 0
+			FROM pragma_table_info(?) ORDER BY cid`,
+	},
+	PostgreSQL: {
+		listTables: `SELECT table_name, table_type FROM information_schema.tables
+			WHERE table_schema = current_schema() ORDER BY table_name`,
+		describeTable: `SELECT c.column_name, c.data_type, c.is_nullable = 'YES', c.column_default,
+			EXISTS (SELECT 1 FROM information_schema.table_constraints tc
+				JOIN information_schema.key_column_usage k
+				ON k.constraint_name = tc.constraint_name AND k.table_schema = tc.table_schema
+				WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema
+				AND tc.table_name = c.table_name AND k.column_name = c.column_name)
+			FROM information_schema.columns c
+			WHERE c.table_schema = current_schema() AND c.table_name = $1 ORDER BY c.ordinal_position`,
+	},
+	MySQL: {
+		listTables: `SELECT table_name, table_type FROM information_schema.tables
+			WHERE table_schema = DATABASE() ORDER BY table_name`,
+		describeTable: `SELECT column_name, column_type, is_nullable = 'YES', column_default, column_key = 'PRI'
+			FROM information_schema.columns
+			WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position`,
+	},
+}
+
+// database implements the tools.
+type database struct {
+	db              *sql.DB
+	queries         schemaQueries
+	allowWrites     bool
+	timeout         time.Duration
+	maxRows         int
+	maxResultSize   int
+	maxArtifactRows int
+}
+
+type listTablesArgs struct{}
+
+type listTablesResult struct {
+	Tables []table `json:"tables"`
+}
+
+type table struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func (d *database) listTables(ctx tool.Context, args listTablesArgs) (listTablesResult, error) {
+	tables, err := d.tables(ctx)
+	if err != nil {
+		return listTablesResult{}, err
+	}
+	return listTablesResult{Tables: tables}, nil
+}
+
+func (d *database) tables(ctx context.Context) ([]table, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	rows, err := d.db.QueryContext(ctx, d.queries.listTables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the tables: %w", err)
+	}
+	defer rows.Close()
+	tables := []table{}
+	for rows.Next() {
+		var t table
+		if err := rows.Scan(&t.Name, &t.Type); err != nil {
+			return nil, fmt.Errorf("failed to list the tables: %w", err)
+		}
+		t.Type = strings.ToLower(strings.TrimPrefix(t.Type, "BASE "))
+		tables = append(tables, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list the tables: %w", err)
+	}
+	return tables, nil
+}
+
+type describeTableArgs struct {
+	Table string `json:"table" jsonschema:"name of the table or view"`
+}
+
+type describeTableResult struct {
+	Table   string   `json:"table"`
+	Columns []column `json:"columns"`
+}
+
+type column struct {
+	Name       string  `json:"name"`
+	Type       string  `json:"type"`
+	Nullable   bool    `json:"nullable"`
+	Default    *string `json:"default,omitempty"`
+	PrimaryKey bool    `json:"primary_key,omitempty"`
+}
+
+func (d *database) describeTable(ctx tool.Context, args describeTableArgs) (describeTableResult, error) {
+	tables, err := d.tables(ctx)
+	if err != nil {
+		return describeTableResult{}, err
+	}
+	if !slices.ContainsFunc(tables, func(t table) bool { return t.Name == args.Table }) {
+		return describeTableResult{}, fmt.Errorf("table %q does not exist, use list_tables to list the tables", args.Table)
+	}
+
+	qctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	rows, err := d.db.QueryContext(qctx, d.queries.describeTable, args.Table)
+	if err != nil {
+		return describeTableResult{}, fmt.Errorf("failed to describe table %q: %w", args.Table, err)
+	}
+	defer rows.Close()
+	result := describeTableResult{Table: args.Table, Columns: []column{}}
+	for rows.Next() {
+		var c column
+		var def sql.NullString
+		if err := rows.Scan(&c.Name, &c.Type, &c.Nullable, &def, &c.PrimaryKey); err != nil {
+			return describeTableResult{}, fmt.Errorf("failed to describe table %q: %w", args.Table, err)
+		}
+		if def.Valid {
+			c.Default = &def.String
+		}
+		result.Columns = append(result.Columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return describeTableResult{}, fmt.Errorf("failed to describe table %q: %w", args.Table, err)
+	}
+	return result, nil
+}
+
+type runQueryArgs struct {
+	Query string `json:"query" jsonschema:"the SQL statement to run"`
+}
+
+type runQueryResult struct {
+	Columns []string `json:"columns,omitempty"`
+	Rows    [][]any  `json:"rows,omitempty"`
+	// RowCount is the number of rows returned.
+	RowCount int `json:"row_count"`
+	// Truncated reports that the query returned more rows than returned
+	// to the model.
+	Truncated bool `json:"truncated,omitempty"`
+	// TotalRows is the number of rows of a truncated result, if all of
+	// them were read.
+	TotalRows int `json:"total_rows,omitempty"`
+	// Artifact is the name of the CSV artifact with the rows of a
+	// truncated result, and ArtifactRows the number of rows it contains.
+	Artifact     string `json:"artifact,omitempty"`
+	ArtifactRows int    `json:"artifact_rows,omitempty"`
+	// RowsAffected is the number of rows affected by a statement which is
+	// not a query.
+	RowsAffected *int64 `json:"rows_affected,omitempty"`
+}
+
+func (d *database) runQuery(ctx tool.Context, args runQueryArgs) (runQueryResult, error) {
+	readOnly, err := isReadOnly(args.Query)
+	if err != nil {
+		return runQueryResult{}, err
+	}
+	if !readOnly && !d.allowWrites {
+		return runQueryResult{}, errors.New("only read-only queries like SELECT are allowed")
+	}
+
+	qctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	var result runQueryResult
+	if readOnly {
+		result, err = d.query(qctx, ctx, args.Query)
+	} else {
+		result, err = d.exec(qctx, args.Query)
+	}
+	if err != nil && errors.Is(qctx.Err(), context.DeadlineExceeded) {
+		return runQueryResult{}, fmt.Errorf("the statement timed out after %v", d.timeout)
+	}
+	return result, err
+}
+
+func (d *database) exec(ctx context.Context, query string) (runQueryResult, error) {
+	res, err := d.db.ExecContext(ctx, query)
+	if err != nil {
+		return runQueryResult{}, err
+	}
+	var result runQueryResult
+	if n, err := res.RowsAffected(); err == nil {
+		result.RowsAffected = &n
+	}
+	return result, nil
+}
+
+// query runs a read-only query in a read-only transaction. The rows of a
+// truncated result are saved as a CSV artifact.
+func (d *database) query(ctx context.Context, toolCtx tool.Context, query string) (runQueryResult, error) {
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return runQueryResult{}, err
+	}
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return runQueryResult{}, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return runQueryResult{}, err
+	}
+
+	var csvBuf bytes.Buffer
+	var csvWriter *csv.Writer
+	if d.maxArtifactRows > 0 && toolCtx.Artifacts() != nil {
+		csvWriter = csv.NewWriter(&csvBuf)
+		if err := csvWriter.Write(columns); err != nil {
+			return runQueryResult{}, err
+		}
+	}
+
+	result := runQueryResult{Columns: columns}
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	total, size, complete := 0, 0, true
+	for rows.Next() {
+		if result.Truncated && (d.maxArtifactRows <= 0 || total == d.maxArtifactRows) {
+			complete = false
+			break
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return runQueryResult{}, err
+		}
+		total++
+		row := make([]any, len(values))
+		for i, v := range values {
+			row[i] = jsonValue(v)
+		}
+		if !result.Truncated {
+			b, err := json.Marshal(row)
+			if err != nil {
+				return runQueryResult{}, err
+			}
+			if len(result.Rows) < d.maxRows && size+len(b) <= d.maxResultSize {
+				result.Rows = append(result.Rows, row)
+				size += len(b)
+			} else {
+				result.Truncated = true
+			}
+		}
+		if csvWriter != nil {
+			record := make([]string, len(row))
+			for i, v := range row {
+				if v != nil {
+					record[i] = fmt.Sprint(v)
+				}
+			}
+			if err := csvWriter.Write(record); err != nil {
+				return runQueryResult{}, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return runQueryResult{}, err
+	}
+	result.RowCount = len(result.Rows)
+	if !result.Truncated {
+		return result, nil
+	}
+	if complete {
+		result.TotalRows = total
+	}
+	if csvWriter != nil {
+		csvWriter.Flush()
+		name := "query_result_" + strings.NewReplacer("/", "_", `\`, "_").Replace(toolCtx.FunctionCallID()) + ".csv"
+		if _, err := toolCtx.Artifacts().Save(toolCtx, name, genai.NewPartFromBytes(csvBuf.Bytes(), "text/csv")); err != nil {
+			return runQueryResult{}, fmt.Errorf("failed to save the result as artifact %q: %w", name, err)
+		}
+		result.Artifact = name
+		result.ArtifactRows = total
+	}
+	return result, nil
+}
+
+// jsonValue converts a value scanned from a row to a value encoded in JSON
+// as the model expects it.
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return base64.StdEncoding.EncodeToString(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return v
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqltoolset provides a toolset querying a SQL database through
+// database/sql.
+//
+// The list_tables and describe_table tools let the model discover the
+// schema, and run_query runs a statement. By default, only read-only
+// statements run: a statement is parsed to check that it is a single query
+// like SELECT, and runs in a read-only transaction. With AllowWrites, the
+// other statements run too, with the confirmation of the user through the
+// Human-in-the-Loop confirmation flow if ConfirmWrites is set.
+//
+// The parsing of the statements is a safeguard against mistakes of the
+// model, not a security boundary: functions with side effects can be called
+// from a SELECT. Connect with a database user having only the required
+// privileges.
+//
+// The results are limited in rows, size and duration. When a result has
+// more rows than returned to the model and an artifact service is
+// configured, all the rows are saved as a CSV artifact.
+//
+// Example:
+//
+//	db, err := sql.Open("pgx", dsn)
+//	...
+//	sqlTools, err := sqltoolset.New(sqltoolset.Config{DB: db})
+//	...
+//	llmagent.New(llmagent.Config{
+//		Name:     "analyst",
+//		Model:    model,
+//		Toolsets: []tool.Toolset{sqlTools},
+//	})
+package sqltoolset
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultTimeout         = 30 * time.Second
+	defaultMaxRows         = 100
+	defaultMaxResultSize   = 32 << 10
+	defaultMaxArtifactRows = 10000
+)
+
+// Dialect is the SQL dialect of a database, which determines how the schema
+// is listed.
+type Dialect string
+
+// The supported dialects.
+const (
+	SQLite     Dialect = "sqlite"
+	PostgreSQL Dialect = "postgresql"
+	MySQL      Dialect = "mysql"
+)
+
+// Config provides the configuration of a SQL toolset.
+type Config struct {
+	// DB is the database queried by the tools. Required.
+	//
+	// Unless AllowWrites is set, connect with the credentials of a database
+	// user having only read privileges: the check of the statements does
+	// not detect the functions with side effects called from a query.
+	DB *sql.DB
+	// Dialect of the database (default: detected from the driver of DB).
+	Dialect Dialect
+	// AllowWrites allows running statements other than queries, like
+	// INSERT or CREATE TABLE.
+	AllowWrites bool
+	// ConfirmWrites requires the confirmation of the user to run the
+	// statements which are not read-only, if AllowWrites is set.
+	ConfirmWrites bool
+	// Timeout limits the duration of the statements (default: 30 seconds).
+	Timeout time.Duration
+	// MaxRows is the maximum number of rows returned to the model (default:
+	// 100).
+	MaxRows int
+	// MaxResultSize is the maximum size in bytes of the JSON encoded rows
+	// returned to the model (default: 32 KiB).
+	MaxResultSize int
+	// MaxArtifactRows is the maximum number of rows of a truncated result
+	// read to save them as a CSV artifact and to count them (default:
+	// 10000). A negative value disables the artifacts.
+	MaxArtifactRows int
+}
+
+// New returns a toolset querying a SQL database.
+func New(cfg Config) (tool.Toolset, error) {
+	if cfg.DB == nil {
+		return nil, errors.New("a database is required")
+	}
+	d := cfg.Dialect
+	if d == "" {
+		d = detectDialect(cfg.DB)
+		if d == "" {
+			return nil, fmt.Errorf("cannot detect the SQL dialect of driver %T, set Dialect", cfg.DB.Driver())
+		}
+	}
+	queries, ok := schemaQueriesByDialect[d]
+	if !ok {
+		return nil, fmt.Errorf("unsupported SQL dialect %q", d)
+	}
+	db := &database{
+		db:              cfg.DB,
+		queries:         queries,
+		allowWrites:     cfg.AllowWrites,
+		timeout:         cfg.Timeout,
+		maxRows:         cfg.MaxRows,
+		maxResultSize:   cfg.MaxResultSize,
+		maxArtifactRows: cfg.MaxArtifactRows,
+	}
+	if db.timeout <= 0 {
+		db.timeout = defaultTimeout
+	}
+	if db.maxRows <= 0 {
+		db.maxRows = defaultMaxRows
+	}
+	if db.maxResultSize <= 0 {
+		db.maxResultSize = defaultMaxResultSize
+	}
+	if db.maxArtifactRows == 0 {
+		db.maxArtifactRows = defaultMaxArtifactRows
+	}
+
+	s := &set{}
+	add := func(t tool.Tool, err error) error {
+		if err != nil {
+			return err
+		}
+		s.tools = append(s.tools, t)
+		return nil
+	}
+	runQueryDescription := "Runs a read-only SQL query, like SELECT, and returns the rows. Use list_tables and describe_table to learn the schema first."
+	if cfg.AllowWrites {
+		runQueryDescription = "Runs a SQL statement and returns the rows of a query or the number of rows affected by other statements. Use list_tables and describe_table to learn the schema first."
+	}
+	if err := errors.Join(
+		add(functiontool.New(functiontool.Config{
+			Name:        "list_tables",
+			Description: "Lists the tables and views of the database.",
+		}, db.listTables)),
+		add(functiontool.New(functiontool.Config{
+			Name:        "describe_table",
+			Description: "Describes the columns of a table or view: their names, types, whether they are nullable and part of the primary key.",
+		}, db.describeTable)),
+		add(functiontool.New(functiontool.Config{
+			Name:        "run_query",
+			Description: fmt.Sprintf("%s Only one statement is allowed per call. The %s dialect is used.", runQueryDescription, d),
+			RequireConfirmationProvider: func(args runQueryArgs) bool {
+				if !cfg.AllowWrites || !cfg.ConfirmWrites {
+					return false
+				}
+				readOnly, err := isReadOnly(args.Query)
+				// Invalid statements fail without confirmation.
+				return err == nil && !readOnly
+			},
+		}, db.runQuery)),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create SQL tools: %w", err)
+	}
+	return s, nil
+}
+
+// detectDialect returns the dialect of the driver of the database, from the
+// package path of the driver, or "" if unknown.
+func detectDialect(db *sql.DB) Dialect {
+	t := reflect.TypeOf(db.Driver())
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	pkg := strings.ToLower(t.PkgPath())
+	switch {
+	case strings.Contains(pkg, "sqlite"):
+		return SQLite
+	case strings.Contains(pkg, "pgx"), strings.Contains(pkg, "lib/pq"), strings.Contains(pkg, "postgres"):
+		return PostgreSQL
+	case strings.Contains(pkg, "mysql"):
+		return MySQL
+	}
+	return ""
+}
+
+type set struct {
+	tools []tool.Tool
+}
+
+// Name implements tool.Toolset.
+func (s *set) Name() string {
+	return "sql_toolset"
+}
+
+// Tools implements tool.Toolset.
+func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	return s.tools, nil
+}
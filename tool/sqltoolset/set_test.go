@@ -0,0 +1,224 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqltoolset
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	// Registers the "sqlite" database/sql driver.
+	_ "github.com/glebarez/sqlite"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	iartifact "google.golang.org/adk/internal/artifact"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	for _, stmt := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT DEFAULT 'none')`,
+		`CREATE VIEW user_names AS SELECT name FROM users`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i <= 5; i++ {
+		if _, err := db.Exec(`INSERT INTO users (id, name) VALUES (?, ?)`, i, fmt.Sprintf("user%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func newTools(t *testing.T, cfg Config) map[string]toolinternal.FunctionTool {
+	t.Helper()
+	ts, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	list, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	m := map[string]toolinternal.FunctionTool{}
+	for _, tl := range list {
+		m[tl.Name()] = tl.(toolinternal.FunctionTool)
+	}
+	return m
+}
+
+func createToolContext(t *testing.T, artifacts agent.Artifacts, actions *session.EventActions, confirmation *toolconfirmation.ToolConfirmation) tool.Context {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a, Artifacts: artifacts})
+	return toolinternal.NewToolContext(invCtx, "call1", actions, confirmation)
+}
+
+func TestSchemaTools(t *testing.T) {
+	tools := newTools(t, Config{DB: openDB(t)})
+
+	got, err := tools["list_tables"].Run(createToolContext(t, nil, nil, nil), map[string]any{})
+	if err != nil {
+		t.Fatalf("list_tables failed: %v", err)
+	}
+	want := map[string]any{"tables": []any{
+		map[string]any{"name": "user_names", "type": "view"},
+		map[string]any{"name": "users", "type": "table"},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("list_tables mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = tools["describe_table"].Run(createToolContext(t, nil, nil, nil), map[string]any{"table": "users"})
+	if err != nil {
+		t.Fatalf("describe_table failed: %v", err)
+	}
+	want = map[string]any{"table": "users", "columns": []any{
+		map[string]any{"name": "id", "type": "INTEGER", "nullable": true, "primary_key": true},
+		map[string]any{"name": "name", "type": "TEXT", "nullable": false},
+		map[string]any{"name": "email", "type": "TEXT", "nullable": true, "default": "'none'"},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("describe_table mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := tools["describe_table"].Run(createToolContext(t, nil, nil, nil), map[string]any{"table": "missing"}); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("describe_table of a missing table error = %v, want not exist", err)
+	}
+}
+
+func TestRunQuery(t *testing.T) {
+	db := openDB(t)
+	tools := newTools(t, Config{DB: db})
+	run := tools["run_query"]
+
+	got, err := run.Run(createToolContext(t, nil, nil, nil), map[string]any{"query": "SELECT id, name, NULL AS x FROM users WHERE id <= 2 ORDER BY id"})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	want := map[string]any{
+		"columns":   []any{"id", "name", "x"},
+		"rows":      []any{[]any{float64(1), "user1", nil}, []any{float64(2), "user2", nil}},
+		"row_count": float64(2),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("run_query mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, query := range []string{"DELETE FROM users", "SELECT 1; DELETE FROM users"} {
+		if _, err := run.Run(createToolContext(t, nil, nil, nil), map[string]any{"query": query}); err == nil {
+			t.Errorf("run_query(%q) succeeded, want error", query)
+		}
+	}
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&n); err != nil || n != 5 {
+		t.Errorf("users count = %d, %v, want 5", n, err)
+	}
+}
+
+func TestRunQuery_Truncated(t *testing.T) {
+	tools := newTools(t, Config{DB: openDB(t), MaxRows: 2, MaxArtifactRows: 4})
+	artifacts := &iartifact.Artifacts{Service: artifact.InMemoryService(), AppName: "app", UserID: "user", SessionID: "session"}
+	ctx := createToolContext(t, artifacts, nil, nil)
+
+	got, err := tools["run_query"].Run(ctx, map[string]any{"query": "SELECT id, name FROM users ORDER BY id"})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	want := map[string]any{
+		"columns":       []any{"id", "name"},
+		"rows":          []any{[]any{float64(1), "user1"}, []any{float64(2), "user2"}},
+		"row_count":     float64(2),
+		"truncated":     true,
+		"artifact":      "query_result_call1.csv",
+		"artifact_rows": float64(4),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("run_query mismatch (-want +got):\n%s", diff)
+	}
+	resp, err := artifacts.Load(t.Context(), "query_result_call1.csv")
+	if err != nil {
+		t.Fatalf("failed to load the artifact: %v", err)
+	}
+	if got, want := string(resp.Part.InlineData.Data), "id,name\n1,user1\n2,user2\n3,user3\n4,user4\n"; got != want {
+		t.Errorf("artifact = %q, want %q", got, want)
+	}
+
+	// Without artifact service, all the rows are counted.
+	tools = newTools(t, Config{DB: openDB(t), MaxResultSize: 20})
+	got, err = tools["run_query"].Run(createToolContext(t, nil, nil, nil), map[string]any{"query": "SELECT id, name FROM users ORDER BY id"})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if got["row_count"] != float64(1) || got["truncated"] != true || got["total_rows"] != float64(5) {
+		t.Errorf("run_query = %v, want 1 of 5 rows", got)
+	}
+}
+
+func TestRunQuery_Writes(t *testing.T) {
+	db := openDB(t)
+	run := newTools(t, Config{DB: db, AllowWrites: true, ConfirmWrites: true})["run_query"]
+
+	if _, err := run.Run(createToolContext(t, nil, nil, nil), map[string]any{"query": "SELECT COUNT(*) FROM users"}); err != nil {
+		t.Errorf("read-only query failed: %v", err)
+	}
+
+	actions := &session.EventActions{}
+	query := map[string]any{"query": "DELETE FROM users WHERE id > 3"}
+	if _, err := run.Run(createToolContext(t, nil, actions, nil), query); err == nil {
+		t.Fatal("write without confirmation succeeded, want confirmation request")
+	}
+	if _, ok := actions.RequestedToolConfirmations["call1"]; !ok {
+		t.Errorf("requested confirmations = %v, want a confirmation of call1", actions.RequestedToolConfirmations)
+	}
+	if _, err := run.Run(createToolContext(t, nil, nil, &toolconfirmation.ToolConfirmation{Confirmed: false}), query); err == nil {
+		t.Error("rejected write succeeded, want error")
+	}
+	got, err := run.Run(createToolContext(t, nil, nil, &toolconfirmation.ToolConfirmation{Confirmed: true}), query)
+	if err != nil {
+		t.Fatalf("confirmed write failed: %v", err)
+	}
+	if got["rows_affected"] != float64(2) {
+		t.Errorf("rows_affected = %v, want 2", got["rows_affected"])
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Error("New() without database succeeded, want error")
+	}
+	if _, err := New(Config{DB: openDB(t), Dialect: "oracle"}); err == nil {
+		t.Error("New() with an unsupported dialect succeeded, want error")
+	}
+}
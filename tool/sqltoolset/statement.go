@@ -0,0 +1,171 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqltoolset
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// readOnlyStarts are the first keywords of the read-only statements.
+var readOnlyStarts = map[string]bool{
+	"SELECT": true, "WITH": true, "VALUES": true, "EXPLAIN": true,
+	"SHOW": true, "DESCRIBE": true, "DESC": true,
+}
+
+// writeKeywords are the keywords of the statements modifying the database
+// or the session, which may be nested in read-only statements like
+// "WITH d AS (DELETE ...) SELECT" or "SELECT ... INTO t".
+var writeKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "UPSERT": true,
+	"REPLACE": true, "INTO": true, "CREATE": true, "DROP": true, "ALTER": true,
+	"TRUNCATE": true, "RENAME": true, "GRANT": true, "REVOKE": true, "ATTACH": true,
+	"DETACH": true, "VACUUM": true, "REINDEX": true, "COPY": true, "CALL": true,
+	"EXEC": true, "EXECUTE": true, "DO": true, "LOCK": true, "SET": true,
+	"PRAGMA": true, "LOAD": true, "COMMENT": true, "REFRESH": true, "CLUSTER": true,
+}
+
+// isReadOnly reports whether the text is a single read-only statement.
+//
+// It is only a best-effort pre-filter on the keywords of the statement, not
+// a security check. Functions with side effects called from a query, like
+// pg_terminate_backend, lo_import or nextval in PostgreSQL, are not
+// detected. Conversely, read-only statements using keywords of writes are
+// rejected, like SELECT ... FOR UPDATE or the unquoted identifiers named
+// like a keyword of writes, as a column named "set" or "comment". The
+// read-only transaction of the statement and the privileges of the database
+// user are what prevent the writes.
+func isReadOnly(text string) (bool, error) {
+	statements, err := keywords(text)
+	if err != nil {
+		return false, err
+	}
+	switch len(statements) {
+	case 0:
+		return false, errors.New("the statement is empty")
+	case 1:
+	default:
+		return false, errors.New("only one statement is allowed per call")
+	}
+	words := statements[0]
+	if !readOnlyStarts[words[0]] {
+		return false, nil
+	}
+	for _, w := range words[1:] {
+		if writeKeywords[w] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// keywords returns the upper-cased words of the statements of the text,
+// without the comments, string literals and quoted identifiers. Empty
+// statements are omitted.
+func keywords(text string) ([][]string, error) {
+	var statements [][]string
+	var words []string
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == ';':
+			if len(words) > 0 {
+				statements = append(statements, words)
+				words = nil
+			}
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := indexRunes(runes, i+2, []rune("*/"))
+			if end < 0 {
+				return nil, errors.New("unterminated comment")
+			}
+			i = end + 2
+		case r == '\'' || r == '"' || r == '`':
+			// Quotes are escaped by doubling them.
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == r {
+					if j+1 < len(runes) && runes[j+1] == r {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j == len(runes) {
+				return nil, errors.New("unterminated quoted string")
+			}
+			i = j + 1
+		case r == '$' && dollarTag(runes[i:]) != nil:
+			// PostgreSQL dollar-quoted string, like $$text$$ or $tag$text$tag$.
+			tag := dollarTag(runes[i:])
+			end := indexRunes(runes, i+len(tag), tag)
+			if end < 0 {
+				return nil, errors.New("unterminated dollar-quoted string")
+			}
+			i = end + len(tag)
+		case isWordRune(r):
+			j := i
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+			words = append(words, strings.ToUpper(string(runes[i:j])))
+			i = j
+		default:
+			i++
+		}
+	}
+	if len(words) > 0 {
+		statements = append(statements, words)
+	}
+	return statements, nil
+}
+
+// dollarTag returns the dollar quote tag at the start of the runes, like
+// "$$" or "$tag$", or nil if there is none.
+func dollarTag(runes []rune) []rune {
+	for j := 1; j < len(runes); j++ {
+		if runes[j] == '$' {
+			return runes[:j+1]
+		}
+		if !isWordRune(runes[j]) || (j == 1 && unicode.IsDigit(runes[j])) {
+			// "$1" is a parameter.
+			return nil
+		}
+	}
+	return nil
+}
+
+// indexRunes returns the index of the first occurrence of sub in runes
+// from the index from, or -1.
+func indexRunes(runes []rune, from int, sub []rune) int {
+	for i := from; i+len(sub) <= len(runes); i++ {
+		if slices.Equal(runes[i:i+len(sub)], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
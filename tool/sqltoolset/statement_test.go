@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqltoolset
+
+import "testing"
+
+func TestIsReadOnly(t *testing.T) {
+	tests := []struct {
+		query    string
+		readOnly bool
+		wantErr  bool
+	}{
+		{query: "SELECT * FROM users", readOnly: true},
+		{query: "  select name from users where id = 1;  ", readOnly: true},
+		{query: "WITH t AS (SELECT 1) SELECT * FROM t", readOnly: true},
+		{query: "EXPLAIN SELECT 1", readOnly: true},
+		{query: "SELECT 'DELETE FROM users; DROP TABLE x' AS s", readOnly: true},
+		{query: `SELECT "update" FROM t -- DELETE`, readOnly: true},
+		{query: "SELECT /* ; INSERT */ 1", readOnly: true},
+		{query: "SELECT $$ DROP $$, $tag$ ; $tag$, $1", readOnly: true},
+		{query: "SELECT 'it''s'", readOnly: true},
+		{query: "INSERT INTO users VALUES (1)"},
+		{query: "update users set name = 'x'"},
+		{query: "WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d"},
+		{query: "SELECT * INTO backup FROM users"},
+		{query: "SELECT * FROM users FOR UPDATE"},
+		{query: "PRAGMA journal_mode = WAL"},
+		// The functions with side effects are not detected, the read-only
+		// transaction and the privileges of the user reject them.
+		{query: "SELECT pg_terminate_backend(1234)", readOnly: true},
+		{query: "SELECT lo_import('/etc/passwd')", readOnly: true},
+		{query: "SELECT nextval('users_id_seq')", readOnly: true},
+		// Identifiers named like keywords are rejected unless quoted.
+		{query: "SELECT set FROM t"},
+		{query: "SELECT comment FROM t"},
+		{query: `SELECT "comment" FROM t`, readOnly: true},
+		{query: "SELECT 1; DROP TABLE users", wantErr: true},
+		{query: "SELECT 1; SELECT 2", wantErr: true},
+		{query: " ; -- nothing", wantErr: true},
+		{query: "SELECT 'unterminated", wantErr: true},
+		{query: "SELECT 1 /* unterminated", wantErr: true},
+	}
+	for _, tc := range tests {
+		readOnly, err := isReadOnly(tc.query)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("isReadOnly(%q) error = %v, want error %v", tc.query, err, tc.wantErr)
+			continue
+		}
+		if readOnly != tc.readOnly {
+			t.Errorf("isReadOnly(%q) = %v, want %v", tc.query, readOnly, tc.readOnly)
+		}
+	}
+}
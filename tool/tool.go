@@ -19,6 +19,9 @@ package tool
 
 import (
 	"context"
+	"fmt"
+
+	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/memory"
@@ -89,6 +92,33 @@ type Context interface {
 	RequestConfirmation(hint string, payload any) error
 }
 
+// PartsAttacher is implemented by the contexts which let a tool attach media
+// parts to its response. Tools should call [AttachParts] rather than use it
+// directly.
+type PartsAttacher interface {
+	AttachParts(parts ...*genai.Part)
+}
+
+// AttachParts attaches media parts, like a chart or a screenshot rendered by
+// the tool, to the response of the tool alongside the structured result. The
+// parts must have inline or file data and be attached before the tool
+// returns.
+//
+// The inline data is saved as artifacts when an artifact service is
+// configured, and the parts are sent to the model with the function
+// response, so that the model sees them as media rather than as base64
+// encoded JSON.
+//
+// AttachParts returns an error if ctx does not support attaching parts.
+func AttachParts(ctx Context, parts ...*genai.Part) error {
+	attacher, ok := ctx.(PartsAttacher)
+	if !ok {
+		return fmt.Errorf("tool context %T does not support attaching parts", ctx)
+	}
+	attacher.AttachParts(parts...)
+	return nil
+}
+
 // Toolset is an interface for a collection of tools. It allows grouping
 // related tools together and providing them to an agent.
 type Toolset interface {
@@ -0,0 +1,428 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package toolpolicy limits the executions of tools with declarative
+// policies: timeouts, concurrency limits, rate limits and circuit breakers.
+//
+// Policies are set on functiontool.Config and mcptoolset.Config, or applied
+// to any function tool with [Wrap]. The executions rejected or stopped by a
+// policy fail with an [*Error], whose details are reported to the model.
+package toolpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+)
+
+// DefaultCooldown is the default for [CircuitBreaker.Cooldown].
+const DefaultCooldown = 30 * time.Second
+
+// Policy limits the executions of a tool. The zero value does not limit
+// them.
+type Policy struct {
+	// Timeout limits each execution of the tool if positive. The context
+	// of the execution is canceled when it expires, and the call fails even
+	// if the tool ignores the cancellation.
+	Timeout time.Duration
+	// MaxConcurrency limits the number of executions of the tool running
+	// at the same time, across all sessions, if positive.
+	MaxConcurrency int
+	// RateLimit limits the number of executions of the tool per second if
+	// positive, allowing bursts of Burst executions.
+	RateLimit float64
+	// Burst is the number of executions allowed at once by the rate limit.
+	// Zero means 1.
+	Burst int
+	// MaxWait is how long an execution waits for the concurrency and rate
+	// limits before it is rejected. Zero rejects it immediately.
+	MaxWait time.Duration
+	// CircuitBreaker rejects the executions of the tool for a while after
+	// consecutive failures if not nil.
+	CircuitBreaker *CircuitBreaker
+}
+
+// CircuitBreaker configures the circuit breaker of a Policy. After
+// FailureThreshold consecutive failed executions the circuit opens and the
+// executions are rejected during Cooldown. Then a single trial execution is
+// allowed: the circuit closes if it succeeds, and opens again otherwise.
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failures opening the
+	// circuit. Zero means 1.
+	FailureThreshold int
+	// Cooldown is how long the circuit stays open. Zero means
+	// DefaultCooldown.
+	Cooldown time.Duration
+}
+
+// IsZero reports whether p does not limit the executions.
+func (p Policy) IsZero() bool {
+	return p.Timeout <= 0 && p.MaxConcurrency <= 0 && p.RateLimit <= 0 && p.CircuitBreaker == nil
+}
+
+func (p Policy) validate() error {
+	if p.Timeout < 0 || p.MaxConcurrency < 0 || p.RateLimit < 0 || p.Burst < 0 || p.MaxWait < 0 {
+		return errors.New("policy values must not be negative")
+	}
+	if cb := p.CircuitBreaker; cb != nil && (cb.FailureThreshold < 0 || cb.Cooldown < 0) {
+		return errors.New("circuit breaker values must not be negative")
+	}
+	return nil
+}
+
+// Reason is the reason of a policy violation.
+type Reason string
+
+const (
+	// ReasonTimeout reports an execution which did not complete within
+	// the timeout.
+	ReasonTimeout Reason = "timeout"
+	// ReasonConcurrencyLimit reports an execution rejected because too
+	// many executions of the tool were running.
+	ReasonConcurrencyLimit Reason = "concurrency_limit"
+	// ReasonRateLimit reports an execution rejected by the rate limit.
+	ReasonRateLimit Reason = "rate_limit"
+	// ReasonCircuitOpen reports an execution rejected because the tool
+	// failed repeatedly.
+	ReasonCircuitOpen Reason = "circuit_open"
+)
+
+// Error is returned by the executions of a tool rejected or stopped by its
+// policy.
+type Error struct {
+	// Tool is the name of the tool.
+	Tool string
+	// Reason is the violated limit.
+	Reason Reason
+	// Timeout is the timeout of the policy, for ReasonTimeout.
+	Timeout time.Duration
+	// RetryAfter is how long to wait before calling the tool again, if
+	// known.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	var msg string
+	switch e.Reason {
+	case ReasonTimeout:
+		msg = fmt.Sprintf("tool %q timed out after %v", e.Tool, e.Timeout)
+	case ReasonConcurrencyLimit:
+		msg = fmt.Sprintf("tool %q has too many executions in progress", e.Tool)
+	case ReasonRateLimit:
+		msg = fmt.Sprintf("tool %q is rate limited", e.Tool)
+	case ReasonCircuitOpen:
+		msg = fmt.Sprintf("tool %q is temporarily disabled after repeated failures", e.Tool)
+	default:
+		msg = fmt.Sprintf("tool %q violated its policy: %s", e.Tool, e.Reason)
+	}
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %v", e.RetryAfter)
+	}
+	return msg
+}
+
+// ErrorDetails returns the details of the error reported to the model
+// alongside the error message.
+func (e *Error) ErrorDetails() map[string]any {
+	details := map[string]any{"policy_violation": string(e.Reason)}
+	if e.RetryAfter > 0 {
+		details["retry_after_seconds"] = math.Ceil(e.RetryAfter.Seconds())
+	}
+	return details
+}
+
+// Limiter enforces a policy on the executions of a tool. It is safe for
+// concurrent use, and shared by the executions of the tool in all sessions.
+type Limiter struct {
+	tool   string
+	policy Policy
+	// slots holds a token per running execution if the concurrency is
+	// limited.
+	slots chan struct{}
+	rate  *rate.Limiter
+
+	mu sync.Mutex
+	// failures is the number of consecutive failures.
+	failures int
+	// openUntil is when the open circuit allows a trial execution.
+	openUntil time.Time
+	// trial reports whether a trial execution is running.
+	trial bool
+}
+
+// NewLimiter returns a limiter enforcing p on the executions of the tool
+// named toolName.
+func NewLimiter(toolName string, p Policy) (*Limiter, error) {
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy of tool %q: %w", toolName, err)
+	}
+	l := &Limiter{tool: toolName, policy: p}
+	if p.MaxConcurrency > 0 {
+		l.slots = make(chan struct{}, p.MaxConcurrency)
+	}
+	if p.RateLimit > 0 {
+		l.rate = rate.NewLimiter(rate.Limit(p.RateLimit), max(p.Burst, 1))
+	}
+	return l, nil
+}
+
+// Begin waits for the policy to allow an execution of the tool. It returns
+// the context of the execution, canceled when the timeout expires, and end,
+// which must be called with the error of the execution when it ends. end
+// returns the error of the call: an [*Error] if the execution timed out,
+// and err otherwise.
+//
+// Unlike Do, the call lasts until the execution returns, even if it
+// ignores the cancellation of its context.
+func (l *Limiter) Begin(ctx tool.Context) (execCtx tool.Context, end func(err error) error, err error) {
+	execCtx, cancel, release, trial, err := l.begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	end = func(err error) error {
+		err = l.callError(ctx, execCtx, err)
+		cancel()
+		release()
+		l.record(ctx, trial, err)
+		return err
+	}
+	return execCtx, end, nil
+}
+
+// Do runs fn under the policy. If the timeout expires, Do returns an
+// [*Error] without waiting for fn, which keeps its concurrency slot until
+// it returns. fn then runs with a copy of ctx recording its state changes,
+// actions and attached parts, which are applied to ctx only if fn returns
+// before the timeout, so that an abandoned execution does not change ctx
+// concurrently with its caller. If ctx cannot be copied, Do waits for fn
+// like Begin.
+func (l *Limiter) Do(ctx tool.Context, fn func(ctx tool.Context) (map[string]any, error)) (map[string]any, error) {
+	detached, merge, ok := toolinternal.Detach(ctx)
+	if !ok || l.policy.Timeout <= 0 {
+		execCtx, end, err := l.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result, err := l.call(execCtx, fn)
+		if err := end(err); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	execCtx, cancel, release, trial, err := l.begin(detached)
+	if err != nil {
+		return nil, err
+	}
+	type outcome struct {
+		result map[string]any
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer release()
+		result, err := l.call(execCtx, fn)
+		done <- outcome{result, err}
+	}()
+
+	var o outcome
+	select {
+	case o = <-done:
+		if o.err == nil {
+			o.err = merge()
+		}
+	case <-execCtx.Done():
+		o.err = execCtx.Err()
+	}
+	cancel()
+	o.err = l.callError(ctx, execCtx, o.err)
+	l.record(ctx, trial, o.err)
+	if o.err != nil {
+		return nil, o.err
+	}
+	return o.result, nil
+}
+
+// call calls fn, returning the panics of fn as errors.
+func (l *Limiter) call(ctx tool.Context, fn func(ctx tool.Context) (map[string]any, error)) (result map[string]any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in tool %q: %v", l.tool, r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// begin waits for the policy to allow an execution, and returns its
+// context, the function canceling it, the function releasing its
+// concurrency slot, and whether it is the trial execution of an open
+// circuit.
+func (l *Limiter) begin(ctx tool.Context) (execCtx tool.Context, cancel context.CancelFunc, release func(), trial bool, err error) {
+	trial, err = l.allow()
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	release, err = l.acquire(ctx)
+	if err != nil {
+		if trial {
+			l.mu.Lock()
+			l.trial = false
+			l.mu.Unlock()
+		}
+		return nil, nil, nil, false, err
+	}
+	if l.policy.Timeout <= 0 {
+		return ctx, func() {}, release, trial, nil
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, l.policy.Timeout)
+	return &execContext{Context: ctx, ctx: timeoutCtx}, cancel, release, trial, nil
+}
+
+// allow checks the circuit breaker. It reports whether the execution is the
+// trial execution of an open circuit.
+func (l *Limiter) allow() (trial bool, err error) {
+	if l.policy.CircuitBreaker == nil {
+		return false, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.openUntil.IsZero() {
+		return false, nil
+	}
+	if wait := time.Until(l.openUntil); wait > 0 {
+		return false, &Error{Tool: l.tool, Reason: ReasonCircuitOpen, RetryAfter: wait}
+	}
+	if l.trial {
+		return false, &Error{Tool: l.tool, Reason: ReasonCircuitOpen, RetryAfter: l.cooldown()}
+	}
+	l.trial = true
+	return true, nil
+}
+
+// acquire waits for the rate and concurrency limits, and returns the
+// function releasing the concurrency slot of the execution.
+func (l *Limiter) acquire(ctx context.Context) (release func(), err error) {
+	deadline := time.Now().Add(l.policy.MaxWait)
+	if l.rate != nil {
+		r := l.rate.Reserve()
+		delay := r.Delay()
+		if !r.OK() || delay > l.policy.MaxWait {
+			r.Cancel()
+			return nil, &Error{Tool: l.tool, Reason: ReasonRateLimit, RetryAfter: delay}
+		}
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				r.Cancel()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	release = func() { <-l.slots }
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+	wait := time.Until(deadline)
+	if wait <= 0 {
+		return nil, &Error{Tool: l.tool, Reason: ReasonConcurrencyLimit}
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, &Error{Tool: l.tool, Reason: ReasonConcurrencyLimit}
+	}
+}
+
+// callError returns the error of a call whose execution ended with err: an
+// [*Error] if the execution timed out.
+func (l *Limiter) callError(ctx, execCtx context.Context, err error) error {
+	if err != nil && ctx.Err() == nil && errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+		return &Error{Tool: l.tool, Reason: ReasonTimeout, Timeout: l.policy.Timeout}
+	}
+	return err
+}
+
+// record records the outcome of an execution in the circuit breaker. The
+// executions interrupted by the cancellation of the call do not count.
+func (l *Limiter) record(ctx context.Context, trial bool, err error) {
+	cb := l.policy.CircuitBreaker
+	if cb == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if trial {
+		l.trial = false
+	}
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	if err == nil {
+		l.failures = 0
+		l.openUntil = time.Time{}
+		return
+	}
+	l.failures++
+	if trial || l.failures >= max(cb.FailureThreshold, 1) {
+		l.openUntil = time.Now().Add(l.cooldown())
+	}
+}
+
+func (l *Limiter) cooldown() time.Duration {
+	if l.policy.CircuitBreaker.Cooldown > 0 {
+		return l.policy.CircuitBreaker.Cooldown
+	}
+	return DefaultCooldown
+}
+
+// execContext is the context of an execution with a timeout.
+type execContext struct {
+	tool.Context
+	ctx context.Context
+}
+
+func (c *execContext) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c *execContext) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c *execContext) Err() error                  { return c.ctx.Err() }
+func (c *execContext) Value(key any) any           { return c.ctx.Value(key) }
+
+// AttachParts implements tool.PartsAttacher for the contexts supporting it,
+// which all contexts of the framework do.
+func (c *execContext) AttachParts(parts ...*genai.Part) {
+	if attacher, ok := c.Context.(tool.PartsAttacher); ok {
+		attacher.AttachParts(parts...)
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolpolicy_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/toolpolicy"
+)
+
+func createToolContext(t *testing.T) tool.Context {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a})
+	return toolinternal.NewToolContext(invCtx, "call1", &session.EventActions{}, nil)
+}
+
+func newLimiter(t *testing.T, p toolpolicy.Policy) *toolpolicy.Limiter {
+	t.Helper()
+	l, err := toolpolicy.NewLimiter("tool", p)
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+	return l
+}
+
+func ok(ctx tool.Context) (map[string]any, error) {
+	return map[string]any{"ok": true}, nil
+}
+
+func fail(ctx tool.Context) (map[string]any, error) {
+	return nil, errors.New("boom")
+}
+
+func wantReason(t *testing.T, err error, want toolpolicy.Reason) *toolpolicy.Error {
+	t.Helper()
+	var policyErr *toolpolicy.Error
+	if !errors.As(err, &policyErr) || policyErr.Reason != want {
+		t.Fatalf("error = %v, want a %s policy error", err, want)
+	}
+	return policyErr
+}
+
+func TestLimiter_Timeout(t *testing.T) {
+	l := newLimiter(t, toolpolicy.Policy{Timeout: 20 * time.Millisecond})
+	ctx := createToolContext(t)
+
+	release := make(chan struct{})
+	defer close(release)
+	// The tool ignores the cancellation of its context.
+	_, err := l.Do(ctx, func(ctx tool.Context) (map[string]any, error) {
+		<-release
+		return nil, nil
+	})
+	policyErr := wantReason(t, err, toolpolicy.ReasonTimeout)
+	if policyErr.Timeout != 20*time.Millisecond {
+		t.Errorf("Timeout = %v, want 20ms", policyErr.Timeout)
+	}
+
+	var deadline bool
+	if _, err := l.Do(ctx, func(ctx tool.Context) (map[string]any, error) {
+		_, deadline = ctx.Deadline()
+		return ok(ctx)
+	}); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if !deadline {
+		t.Error("the context of the execution has no deadline")
+	}
+}
+
+func TestLimiter_TimeoutDetachesContext(t *testing.T) {
+	l := newLimiter(t, toolpolicy.Policy{Timeout: 20 * time.Millisecond})
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a, Session: created.Session})
+	actions := &session.EventActions{}
+	ctx := toolinternal.NewToolContext(invCtx, "call1", actions, nil)
+
+	// An execution abandoned on timeout changes nothing once it resumes.
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	_, err = l.Do(ctx, func(ctx tool.Context) (map[string]any, error) {
+		defer close(finished)
+		<-release
+		if err := ctx.State().Set("late", true); err != nil {
+			t.Errorf("State().Set() error = %v", err)
+		}
+		ctx.Actions().Escalate = true
+		return nil, nil
+	})
+	wantReason(t, err, toolpolicy.ReasonTimeout)
+	close(release)
+	<-finished
+	if _, err := ctx.State().Get("late"); err == nil {
+		t.Error("the state set after the timeout is visible in the context")
+	}
+	if actions.Escalate {
+		t.Error("the actions set after the timeout are applied to the context")
+	}
+
+	// An execution finishing in time has its changes applied.
+	if _, err := l.Do(ctx, func(ctx tool.Context) (map[string]any, error) {
+		if err := ctx.State().Set("key", "value"); err != nil {
+			return nil, err
+		}
+		ctx.Actions().SkipSummarization = true
+		return ok(ctx)
+	}); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if got, _ := ctx.State().Get("key"); got != "value" {
+		t.Errorf("state[key] = %v, want %q", got, "value")
+	}
+	if got, _ := created.Session.State().Get("key"); got != "value" {
+		t.Errorf("session state[key] = %v, want %q", got, "value")
+	}
+	if diff := cmp.Diff(map[string]any{"key": "value"}, actions.StateDelta); diff != "" {
+		t.Errorf("StateDelta mismatch (-want +got):\n%s", diff)
+	}
+	if !actions.SkipSummarization {
+		t.Error("SkipSummarization = false, want the action applied")
+	}
+}
+
+func TestLimiter_Concurrency(t *testing.T) {
+	l := newLimiter(t, toolpolicy.Policy{MaxConcurrency: 1})
+	ctx := createToolContext(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		l.Do(ctx, func(ctx tool.Context) (map[string]any, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+	}()
+	<-started
+	_, err := l.Do(ctx, ok)
+	wantReason(t, err, toolpolicy.ReasonConcurrencyLimit)
+	close(release)
+	wg.Wait()
+
+	if _, err := l.Do(ctx, ok); err != nil {
+		t.Errorf("Do() after the execution ended error = %v", err)
+	}
+}
+
+func TestLimiter_ConcurrencyWait(t *testing.T) {
+	l := newLimiter(t, toolpolicy.Policy{MaxConcurrency: 1, MaxWait: time.Second})
+	ctx := createToolContext(t)
+
+	execCtx, end, err := l.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		end(nil)
+	}()
+	if _, err := l.Do(execCtx, ok); err != nil {
+		t.Errorf("Do() error = %v, want it to wait for the running execution", err)
+	}
+}
+
+func TestLimiter_RateLimit(t *testing.T) {
+	l := newLimiter(t, toolpolicy.Policy{RateLimit: 1, Burst: 2})
+	ctx := createToolContext(t)
+
+	for range 2 {
+		if _, err := l.Do(ctx, ok); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+	_, err := l.Do(ctx, ok)
+	policyErr := wantReason(t, err, toolpolicy.ReasonRateLimit)
+	if policyErr.RetryAfter <= 0 {
+		t.Errorf("RetryAfter = %v, want positive", policyErr.RetryAfter)
+	}
+	if diff := cmp.Diff(map[string]any{"policy_violation": "rate_limit", "retry_after_seconds": 1.0}, policyErr.ErrorDetails()); diff != "" {
+		t.Errorf("ErrorDetails() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLimiter_CircuitBreaker(t *testing.T) {
+	l := newLimiter(t, toolpolicy.Policy{CircuitBreaker: &toolpolicy.CircuitBreaker{
+		FailureThreshold: 2,
+		Cooldown:         20 * time.Millisecond,
+	}})
+	ctx := createToolContext(t)
+
+	for range 2 {
+		if _, err := l.Do(ctx, fail); err == nil || err.Error() != "boom" {
+			t.Fatalf("Do() error = %v, want boom", err)
+		}
+	}
+	_, err := l.Do(ctx, ok)
+	wantReason(t, err, toolpolicy.ReasonCircuitOpen)
+
+	// After the cooldown, a failed trial opens the circuit again.
+	time.Sleep(25 * time.Millisecond)
+	if _, err := l.Do(ctx, fail); err == nil || err.Error() != "boom" {
+		t.Fatalf("trial Do() error = %v, want boom", err)
+	}
+	_, err = l.Do(ctx, ok)
+	wantReason(t, err, toolpolicy.ReasonCircuitOpen)
+
+	// A successful trial closes it.
+	time.Sleep(25 * time.Millisecond)
+	for range 2 {
+		if _, err := l.Do(ctx, ok); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+}
+
+func TestLimiter_CanceledCallsDoNotCount(t *testing.T) {
+	l := newLimiter(t, toolpolicy.Policy{CircuitBreaker: &toolpolicy.CircuitBreaker{FailureThreshold: 1}})
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+	invCtx := icontext.NewInvocationContext(canceled, icontext.InvocationContextParams{Agent: a})
+	ctx := toolinternal.NewToolContext(invCtx, "call1", &session.EventActions{}, nil)
+
+	if _, err := l.Do(ctx, func(ctx tool.Context) (map[string]any, error) {
+		return nil, ctx.Err()
+	}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do() error = %v, want context.Canceled", err)
+	}
+	if _, err := l.Do(createToolContext(t), ok); err != nil {
+		t.Errorf("Do() error = %v, want the circuit closed", err)
+	}
+}
+
+func TestNewLimiter_Invalid(t *testing.T) {
+	if _, err := toolpolicy.NewLimiter("tool", toolpolicy.Policy{Timeout: -1}); err == nil {
+		t.Error("NewLimiter() with a negative timeout succeeded, want error")
+	}
+}
+
+type echoArgs struct {
+	Text string `json:"text"`
+}
+
+func TestWrap(t *testing.T) {
+	echo, err := functiontool.New(functiontool.Config{Name: "echo", Description: "echoes"}, func(ctx tool.Context, args echoArgs) (map[string]any, error) {
+		time.Sleep(50 * time.Millisecond)
+		return map[string]any{"text": args.Text}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := toolpolicy.Wrap(echo, toolpolicy.Policy{Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+
+	req := &model.LLMRequest{}
+	ctx := createToolContext(t)
+	if err := wrapped.(toolinternal.RequestProcessor).ProcessRequest(ctx, req); err != nil {
+		t.Fatalf("ProcessRequest() error = %v", err)
+	}
+	if req.Tools["echo"] != wrapped {
+		t.Errorf("request tool = %T, want the wrapped tool", req.Tools["echo"])
+	}
+	if got := req.Config.Tools[0].FunctionDeclarations[0].Name; got != "echo" {
+		t.Errorf("declaration name = %q, want echo", got)
+	}
+
+	_, err = wrapped.(toolinternal.FunctionTool).Run(ctx, map[string]any{"text": "hi"})
+	wantReason(t, err, toolpolicy.ReasonTimeout)
+
+	if _, err := toolpolicy.Wrap(fakeTool{}, toolpolicy.Policy{}); err == nil {
+		t.Error("Wrap() of a tool which is not a function tool succeeded, want error")
+	}
+}
+
+type fakeTool struct{}
+
+func (fakeTool) Name() string        { return "fake" }
+func (fakeTool) Description() string { return "fake" }
+func (fakeTool) IsLongRunning() bool { return false }
+
+func TestFunctionToolPolicy(t *testing.T) {
+	calls := 0
+	flaky, err := functiontool.New(functiontool.Config{
+		Name:        "flaky",
+		Description: "fails",
+		Policy: toolpolicy.Policy{
+			CircuitBreaker: &toolpolicy.CircuitBreaker{FailureThreshold: 1, Cooldown: time.Minute},
+		},
+	}, func(ctx tool.Context, args echoArgs) (map[string]any, error) {
+		calls++
+		return nil, errors.New("boom")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := createToolContext(t)
+	run := flaky.(toolinternal.FunctionTool).Run
+	if _, err := run(ctx, map[string]any{"text": "a"}); err == nil || err.Error() != "boom" {
+		t.Fatalf("Run() error = %v, want boom", err)
+	}
+	_, err = run(ctx, map[string]any{"text": "a"})
+	wantReason(t, err, toolpolicy.ReasonCircuitOpen)
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
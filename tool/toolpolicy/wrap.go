@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolpolicy
+
+import (
+	"fmt"
+	"iter"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// Wrap returns a tool enforcing p on the executions of t, which must be a
+// function tool, like the tools of functiontool or mcptoolset.
+//
+// The timeout of a streaming tool cancels the context of its execution but
+// does not interrupt the stream, as its results are delivered while it runs.
+func Wrap(t tool.Tool, p Policy) (tool.Tool, error) {
+	ft, ok := t.(toolinternal.FunctionTool)
+	if !ok {
+		return nil, fmt.Errorf("tool %q of type %T is not a function tool", t.Name(), t)
+	}
+	l, err := NewLimiter(t.Name(), p)
+	if err != nil {
+		return nil, err
+	}
+	w := &policyTool{FunctionTool: ft, limiter: l}
+	if st, ok := ft.(toolinternal.StreamingFunctionTool); ok {
+		return &streamingPolicyTool{policyTool: w, stream: st}, nil
+	}
+	return w, nil
+}
+
+// policyTool is a function tool whose executions are limited by a policy.
+type policyTool struct {
+	toolinternal.FunctionTool
+	limiter *Limiter
+}
+
+// ProcessRequest processes the request with the wrapped tool, registering
+// the policy tool as the tool to call.
+func (t *policyTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return processRequest(ctx, req, t.FunctionTool, t)
+}
+
+// Run runs the wrapped tool under the policy.
+func (t *policyTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	return t.limiter.Do(ctx, func(ctx tool.Context) (map[string]any, error) {
+		return t.FunctionTool.Run(ctx, args)
+	})
+}
+
+// streamingPolicyTool is a streaming function tool whose executions are
+// limited by a policy.
+type streamingPolicyTool struct {
+	*policyTool
+	stream toolinternal.StreamingFunctionTool
+}
+
+// ProcessRequest processes the request with the wrapped tool, registering
+// the policy tool as the tool to call.
+func (t *streamingPolicyTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return processRequest(ctx, req, t.FunctionTool, t)
+}
+
+// RunStream runs the wrapped tool under the policy.
+func (t *streamingPolicyTool) RunStream(ctx tool.Context, args any) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		execCtx, end, err := t.limiter.Begin(ctx)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		var runErr error
+		ended := false
+		defer func() {
+			// The consumer stopped the stream, or panicked.
+			if !ended {
+				end(nil)
+			}
+		}()
+		for result, err := range t.stream.RunStream(execCtx, args) {
+			if err != nil {
+				runErr = err
+				break
+			}
+			if !yield(result, nil) {
+				return
+			}
+		}
+		ended = true
+		if err := end(runErr); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// processRequest processes req with the wrapped tool, and registers w in
+// place of the wrapped tool, so that the flow calls w.
+func processRequest(ctx tool.Context, req *model.LLMRequest, wrapped toolinternal.FunctionTool, w toolinternal.FunctionTool) error {
+	p, ok := wrapped.(toolinternal.RequestProcessor)
+	if !ok {
+		return toolutils.PackTool(req, w)
+	}
+	if err := p.ProcessRequest(ctx, req); err != nil {
+		return err
+	}
+	if _, ok := req.Tools[w.Name()]; ok {
+		req.Tools[w.Name()] = w
+	}
+	return nil
+}
+
+var (
+	_ toolinternal.FunctionTool          = (*policyTool)(nil)
+	_ toolinternal.RequestProcessor      = (*policyTool)(nil)
+	_ toolinternal.StreamingFunctionTool = (*streamingPolicyTool)(nil)
+	_ toolinternal.RequestProcessor      = (*streamingPolicyTool)(nil)
+)
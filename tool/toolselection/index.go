@@ -0,0 +1,224 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolselection
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"google.golang.org/adk/internal/toolinternal"
+)
+
+// BM25 parameters of the keyword ranking.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// rrfK is the constant of the reciprocal rank fusion of the keyword and
+// embedding rankings, damping the weight of the first ranks.
+const rrfK = 60
+
+// index ranks tools by the relevance of their names and descriptions to a
+// query.
+type index struct {
+	docs []document
+	// df holds the number of documents containing each term.
+	df        map[string]int
+	avgLength float64
+}
+
+// document is the indexed text of a tool.
+type document struct {
+	name        string
+	description string
+	// text is the text embedded for the tool.
+	text string
+	// tf holds the frequency of each term of the tool.
+	tf     map[string]int
+	length int
+}
+
+func newIndex(tools []toolinternal.FunctionTool) *index {
+	idx := &index{docs: make([]document, len(tools)), df: map[string]int{}}
+	total := 0
+	for i, t := range tools {
+		description := t.Description()
+		if description == "" {
+			if decl := t.Declaration(); decl != nil {
+				description = decl.Description
+			}
+		}
+		d := document{
+			name:        t.Name(),
+			description: description,
+			text:        t.Name() + ": " + description,
+			tf:          map[string]int{},
+		}
+		// Terms of the name weigh twice as much as the ones of the
+		// description.
+		words := terms(t.Name())
+		words = append(words, words...)
+		words = append(words, terms(description)...)
+		for _, term := range words {
+			if d.tf[term] == 0 {
+				idx.df[term]++
+			}
+			d.tf[term]++
+		}
+		d.length = len(words)
+		total += d.length
+		idx.docs[i] = d
+	}
+	if len(tools) > 0 {
+		idx.avgLength = float64(total) / float64(len(tools))
+	}
+	return idx
+}
+
+// bm25 returns the BM25 score of each document for query.
+func (idx *index) bm25(query string) []float64 {
+	scores := make([]float64, len(idx.docs))
+	n := float64(len(idx.docs))
+	queryTerms := terms(query)
+	slices.Sort(queryTerms)
+	for _, term := range slices.Compact(queryTerms) {
+		df := float64(idx.df[term])
+		if df == 0 {
+			continue
+		}
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for i, d := range idx.docs {
+			tf := float64(d.tf[term])
+			if tf == 0 {
+				continue
+			}
+			norm := 1 - bm25B + bm25B*float64(d.length)/idx.avgLength
+			scores[i] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+	}
+	return scores
+}
+
+// rank returns the indices of the n documents most relevant to query, most
+// relevant first. Without similarities, the documents are ranked by their
+// BM25 score and only documents matching a term of the query are returned.
+// With the similarities of the embeddings of the documents with the query,
+// both rankings are combined with reciprocal rank fusion.
+func (idx *index) rank(query string, similarities []float64, n int) []int {
+	keyword := idx.bm25(query)
+	scores := keyword
+	if similarities != nil {
+		scores = make([]float64, len(idx.docs))
+		for rank, i := range order(keyword) {
+			if keyword[i] > 0 {
+				scores[i] += 1 / float64(rrfK+rank+1)
+			}
+		}
+		for rank, i := range order(similarities) {
+			scores[i] += 1 / float64(rrfK+rank+1)
+		}
+	}
+	var ranked []int
+	for _, i := range order(scores) {
+		if len(ranked) == n || scores[i] <= 0 {
+			break
+		}
+		ranked = append(ranked, i)
+	}
+	return ranked
+}
+
+// order returns the indices of scores by decreasing score, keeping the
+// order of equal scores.
+func order(scores []float64) []int {
+	indices := make([]int, len(scores))
+	for i := range indices {
+		indices[i] = i
+	}
+	slices.SortStableFunc(indices, func(a, b int) int {
+		return cmp.Compare(scores[b], scores[a])
+	})
+	return indices
+}
+
+// stopWords are the common English words left out of the terms.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "can": true, "do": true, "for": true, "from": true,
+	"i": true, "in": true, "is": true, "it": true, "me": true, "my": true,
+	"of": true, "on": true, "or": true, "please": true, "that": true,
+	"the": true, "this": true, "to": true, "with": true, "you": true,
+}
+
+// terms splits s into lowercase terms at non alphanumeric characters and
+// camel case boundaries, leaving out stop words, with a naive removal of
+// plurals.
+func terms(s string) []string {
+	var terms []string
+	var word []rune
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		term := strings.ToLower(string(word))
+		word = word[:0]
+		if stopWords[term] {
+			return
+		}
+		if len(term) > 3 && strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss") &&
+			!strings.HasSuffix(term, "us") && !strings.HasSuffix(term, "is") {
+			term = term[:len(term)-1]
+		}
+		terms = append(terms, term)
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			// Split "listFiles" and "HTTPServer" before the upper case
+			// letter starting a word.
+			flush()
+		}
+		word = append(word, r)
+	}
+	flush()
+	return terms
+}
+
+// cosine returns the cosine similarity of a and b.
+func cosine(a, b []float32) (float64, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("embedding dimensions differ: %d and %d", len(a), len(b))
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0, nil
+	}
+	return dot / math.Sqrt(normA*normB), nil
+}
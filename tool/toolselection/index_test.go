@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolselection
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/internal/toolinternal"
+)
+
+func TestTerms(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []string
+	}{
+		{in: "list_files", want: []string{"list", "file"}},
+		{in: "getHTTPStatus", want: []string{"get", "http", "status"}},
+		{in: "Creates a GitHub issue, with labels.", want: []string{"create", "git", "hub", "issue", "label"}},
+		{in: "", want: nil},
+	} {
+		if diff := cmp.Diff(tc.want, terms(tc.in)); diff != "" {
+			t.Errorf("terms(%q) mismatch (-want +got):\n%s", tc.in, diff)
+		}
+	}
+}
+
+func TestIndexRank(t *testing.T) {
+	idx := newIndex([]toolinternal.FunctionTool{
+		newTool(t, "create_issue", "Creates an issue in a repository."),
+		newTool(t, "list_files", "Lists the files of a directory."),
+		newTool(t, "get_weather", "Returns the weather forecast of a city."),
+		newTool(t, "read_file", "Reads a file."),
+	})
+
+	if diff := cmp.Diff([]int{3, 1}, idx.rank("read the file", nil, 5)); diff != "" {
+		t.Errorf("rank() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{3}, idx.rank("read the file", nil, 1)); diff != "" {
+		t.Errorf("rank() with limit mismatch (-want +got):\n%s", diff)
+	}
+	if got := idx.rank("unrelated", nil, 5); len(got) != 0 {
+		t.Errorf("rank() = %v, want no match", got)
+	}
+	// The similarities rank the tools without matching keywords too.
+	if diff := cmp.Diff([]int{2, 0, 1, 3}, idx.rank("forecast", []float64{0.5, 0.1, 0.9, 0}, 5)); diff != "" {
+		t.Errorf("rank() with similarities mismatch (-want +got):\n%s", diff)
+	}
+}
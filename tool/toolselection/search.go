@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolselection
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// SearchToolName is the name of the tool searching the tools not declared
+// to the model.
+const SearchToolName = "search_tools"
+
+// searchTool finds the tools relevant to a query and declares them to the
+// model in the following requests of the invocation.
+type searchTool struct {
+	set *set
+	// index holds the candidate tools listed with the tool.
+	index *index
+}
+
+// Name implements the tool.Tool.
+func (t *searchTool) Name() string {
+	return SearchToolName
+}
+
+// Description implements the tool.Tool.
+func (t *searchTool) Description() string {
+	return "Searches the available tools by name and description. " +
+		"The tools found can be called after this call."
+}
+
+// IsLongRunning implements the tool.Tool.
+func (t *searchTool) IsLongRunning() bool {
+	return false
+}
+
+func (t *searchTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        t.Name(),
+		Description: t.Description(),
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"query": {
+					Type:        "STRING",
+					Description: "Description of the task to find tools for, or keywords.",
+				},
+			},
+			Required: []string{"query"},
+		},
+	}
+}
+
+func (t *searchTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	if err := toolutils.PackTool(req, t); err != nil {
+		return err
+	}
+	sel, err := t.set.selection(ctx, t.index)
+	if err != nil {
+		return err
+	}
+	if hidden := sel.hidden(t.index); hidden > 0 {
+		utils.AppendInstructions(req, fmt.Sprintf(
+			"Only the tools most relevant to the request are declared; %d more tools are available. "+
+				"If none of the declared tools fits the task, call the `%s` function with a description of the task "+
+				"to find other tools, which can be called afterwards.", hidden, t.Name()))
+	}
+	return nil
+}
+
+type foundTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+func (t *searchTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	}
+	query, _ := m["query"].(string)
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("missing required parameter: query")
+	}
+
+	var found []foundTool
+	var names []string
+	for _, i := range t.set.rank(ctx, t.index, query, t.set.maxSearchResults) {
+		d := t.index.docs[i]
+		found = append(found, foundTool{Name: d.name, Description: d.description})
+		names = append(names, d.name)
+	}
+	if err := t.set.expose(ctx, t.index, names...); err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return map[string]any{"tools": []foundTool{}, "message": "No tools found, try other keywords."}, nil
+	}
+	return map[string]any{"tools": found}, nil
+}
+
+var (
+	_ toolinternal.FunctionTool     = (*searchTool)(nil)
+	_ toolinternal.RequestProcessor = (*searchTool)(nil)
+)
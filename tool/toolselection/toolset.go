@@ -0,0 +1,377 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package toolselection exposes only the tools relevant to a request out of
+// large toolsets, like the tools of several MCP servers or OpenAPI specs, to
+// keep the requests to the model small.
+//
+// The toolset returned by [New] indexes the names and descriptions of the
+// tools of the wrapped toolsets. For each invocation, only the tools most
+// relevant to the user content are declared to the model, along with the
+// search_tools tool the model calls to discover more tools, which are
+// declared in the following requests of the invocation.
+package toolselection
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+	"maps"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/retrievaltool"
+)
+
+const (
+	// DefaultMaxTools is the default for [Config.MaxTools].
+	DefaultMaxTools = 10
+	// DefaultMaxSearchResults is the default for [Config.MaxSearchResults].
+	DefaultMaxSearchResults = 5
+)
+
+// Config configures the toolset returned by [New].
+type Config struct {
+	// Name is the name of the toolset. Default: "tool_selection".
+	Name string
+	// Toolsets are the toolsets whose tools are selected.
+	Toolsets []tool.Toolset
+	// Tools are additional tools to select from.
+	Tools []tool.Tool
+
+	// MaxTools is the maximum number of tools declared to the model for an
+	// invocation, besides the tools always included and the tools found
+	// with search_tools. Zero means DefaultMaxTools.
+	MaxTools int
+	// MaxSearchResults is the maximum number of tools returned by a call
+	// of search_tools. Zero means DefaultMaxSearchResults.
+	MaxSearchResults int
+	// AlwaysInclude selects the tools always declared to the model, like
+	// tool.StringPredicate with the names of essential tools. Tools which
+	// are not function tools, like the tools of geminitool, are always
+	// included.
+	AlwaysInclude tool.Predicate
+	// Embedder, if set, ranks the tools by the similarity of the embeddings
+	// of their names and descriptions with the query, combined with the
+	// keyword ranking. The embeddings of the tools are cached. If
+	// embedding fails, the tools are ranked by keywords only.
+	Embedder retrievaltool.Embedder
+	// Logger, if set, logs the failures of the embedder (optional).
+	Logger *log.Logger
+}
+
+// New returns a toolset exposing the tools of cfg relevant to each
+// invocation, and the search_tools tool.
+//
+// Example:
+//
+//	tools, err := toolselection.New(toolselection.Config{
+//		Toolsets: []tool.Toolset{githubTools, jiraTools, openAPITools},
+//		MaxTools: 8,
+//	})
+//	...
+//	llmagent.New(llmagent.Config{
+//		...
+//		Toolsets: []tool.Toolset{tools},
+//	})
+func New(cfg Config) (tool.Toolset, error) {
+	if len(cfg.Toolsets) == 0 && len(cfg.Tools) == 0 {
+		return nil, errors.New("no tools to select from")
+	}
+	for _, ts := range cfg.Toolsets {
+		if ts == nil {
+			return nil, errors.New("toolset must not be nil")
+		}
+	}
+	if cfg.MaxTools < 0 {
+		return nil, errors.New("max tools must not be negative")
+	}
+	if cfg.MaxSearchResults < 0 {
+		return nil, errors.New("max search results must not be negative")
+	}
+	return &set{
+		name:             cmp.Or(cfg.Name, "tool_selection"),
+		toolsets:         cfg.Toolsets,
+		tools:            cfg.Tools,
+		maxTools:         cmp.Or(cfg.MaxTools, DefaultMaxTools),
+		maxSearchResults: cmp.Or(cfg.MaxSearchResults, DefaultMaxSearchResults),
+		alwaysInclude:    cfg.AlwaysInclude,
+		embedder:         cfg.Embedder,
+		logger:           cfg.Logger,
+	}, nil
+}
+
+type set struct {
+	name             string
+	toolsets         []tool.Toolset
+	tools            []tool.Tool
+	maxTools         int
+	maxSearchResults int
+	alwaysInclude    tool.Predicate
+	embedder         retrievaltool.Embedder
+	logger           *log.Logger
+
+	// embeddings caches the embeddings of the tool texts.
+	embeddingsMu sync.Mutex
+	embeddings   map[string][]float32
+
+	// selectionMu serializes the updates of the selections of the
+	// invocations, kept in the temporary state of the session.
+	selectionMu sync.Mutex
+}
+
+func (s *set) Name() string {
+	return s.name
+}
+
+// Tools returns the tools always included, every candidate tool, declared
+// to the model only if selected for the invocation, and search_tools.
+func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	all := append([]tool.Tool(nil), s.tools...)
+	for _, ts := range s.toolsets {
+		tools, err := ts.Tools(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract tools from the tool set %q: %w", ts.Name(), err)
+		}
+		all = append(all, tools...)
+	}
+
+	var result []tool.Tool
+	var candidates []toolinternal.FunctionTool
+	var selectable []*selectableTool
+	for _, t := range all {
+		ft, ok := t.(toolinternal.FunctionTool)
+		if !ok || (s.alwaysInclude != nil && s.alwaysInclude(ctx, t)) {
+			result = append(result, t)
+			continue
+		}
+		candidates = append(candidates, ft)
+		w := &selectableTool{FunctionTool: ft, set: s}
+		selectable = append(selectable, w)
+		if st, ok := ft.(toolinternal.StreamingFunctionTool); ok {
+			result = append(result, &streamingSelectableTool{selectableTool: w, stream: st})
+		} else {
+			result = append(result, w)
+		}
+	}
+
+	idx := newIndex(candidates)
+	for _, w := range selectable {
+		w.index = idx
+	}
+	return append(result, &searchTool{set: s, index: idx}), nil
+}
+
+// selectionKey is the temporary state key of the selection of the tools of
+// the invocation.
+func (s *set) selectionKey() string {
+	return session.KeyPrefixTemp + s.name + "_selection"
+}
+
+// selection returns the selection of the tools of idx for the invocation,
+// selecting the tools most relevant to the user content on the first call
+// of the invocation, so that the user content is embedded once.
+func (s *set) selection(ctx tool.Context, idx *index) (*selection, error) {
+	s.selectionMu.Lock()
+	defer s.selectionMu.Unlock()
+	return s.selectionLocked(ctx, idx)
+}
+
+func (s *set) selectionLocked(ctx tool.Context, idx *index) (*selection, error) {
+	if sel := s.currentSelection(ctx); sel != nil {
+		return sel, nil
+	}
+	sel := &selection{invocationID: ctx.InvocationID(), exposed: map[string]bool{}}
+	if query := contentText(ctx.UserContent()); query != "" {
+		for _, i := range s.rank(ctx, idx, query, s.maxTools) {
+			sel.exposed[idx.docs[i].name] = true
+		}
+	}
+	if err := ctx.State().Set(s.selectionKey(), sel); err != nil {
+		return nil, err
+	}
+	return sel, nil
+}
+
+// currentSelection returns the selection of the invocation in its state,
+// or nil if there is none yet.
+func (s *set) currentSelection(ctx tool.Context) *selection {
+	v, err := ctx.State().Get(s.selectionKey())
+	if err != nil {
+		return nil
+	}
+	sel, ok := v.(*selection)
+	if !ok || sel.invocationID != ctx.InvocationID() {
+		return nil
+	}
+	return sel
+}
+
+// expose declares the tools named names to the model in the following
+// requests of the invocation.
+func (s *set) expose(ctx tool.Context, idx *index, names ...string) error {
+	s.selectionMu.Lock()
+	defer s.selectionMu.Unlock()
+	sel, err := s.selectionLocked(ctx, idx)
+	if err != nil {
+		return err
+	}
+	exposed := maps.Clone(sel.exposed)
+	for _, name := range names {
+		exposed[name] = true
+	}
+	return ctx.State().Set(s.selectionKey(), &selection{invocationID: sel.invocationID, exposed: exposed})
+}
+
+// rank returns the indices of the n tools of idx most relevant to query,
+// most relevant first.
+func (s *set) rank(ctx agent.ReadonlyContext, idx *index, query string, n int) []int {
+	var similarities []float64
+	if s.embedder != nil {
+		var err error
+		similarities, err = s.similarities(ctx, idx, query)
+		if err != nil && s.logger != nil {
+			s.logger.Printf("ranking tools by keywords only, embedding failed: %v", err)
+		}
+	}
+	return idx.rank(query, similarities, n)
+}
+
+// similarities returns the similarities of the embeddings of the tools of
+// idx with the embedding of query, embedding the tools not embedded yet.
+func (s *set) similarities(ctx agent.ReadonlyContext, idx *index, query string) ([]float64, error) {
+	s.embeddingsMu.Lock()
+	texts := []string{query}
+	for _, d := range idx.docs {
+		if _, ok := s.embeddings[d.text]; !ok {
+			texts = append(texts, d.text)
+		}
+	}
+	s.embeddingsMu.Unlock()
+
+	vectors, err := s.embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+
+	s.embeddingsMu.Lock()
+	defer s.embeddingsMu.Unlock()
+	if s.embeddings == nil {
+		s.embeddings = map[string][]float32{}
+	}
+	for i, text := range texts[1:] {
+		s.embeddings[text] = vectors[i+1]
+	}
+	similarities := make([]float64, len(idx.docs))
+	for i, d := range idx.docs {
+		similarities[i], err = cosine(vectors[0], s.embeddings[d.text])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return similarities, nil
+}
+
+// selectableTool is a function tool declared to the model only if selected
+// for the invocation. Calls are dispatched to the wrapped tool, which
+// registers itself in the request.
+type selectableTool struct {
+	toolinternal.FunctionTool
+	set *set
+	// index holds the candidate tools listed with the tool.
+	index *index
+}
+
+// ProcessRequest processes the request with the wrapped tool if it is
+// selected for the invocation.
+func (t *selectableTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	sel, err := t.set.selection(ctx, t.index)
+	if err != nil {
+		return err
+	}
+	if !sel.exposed[t.Name()] {
+		return nil
+	}
+	if p, ok := t.FunctionTool.(toolinternal.RequestProcessor); ok {
+		return p.ProcessRequest(ctx, req)
+	}
+	return toolutils.PackTool(req, t.FunctionTool)
+}
+
+// streamingSelectableTool is a streaming function tool declared to the
+// model only if selected for the invocation.
+type streamingSelectableTool struct {
+	*selectableTool
+	stream toolinternal.StreamingFunctionTool
+}
+
+// RunStream runs the wrapped tool.
+func (t *streamingSelectableTool) RunStream(ctx tool.Context, args any) iter.Seq2[map[string]any, error] {
+	return t.stream.RunStream(ctx, args)
+}
+
+// selection holds the tools of an invocation declared to the model. It is
+// kept in the temporary state of the session, which is only discarded after
+// the invocation by some session services, so it is tied to the invocation.
+// A selection is not modified once stored.
+type selection struct {
+	invocationID string
+	// exposed holds the names of the tools declared to the model.
+	exposed map[string]bool
+}
+
+// hidden returns the number of tools of idx not declared to the model.
+func (sel *selection) hidden(idx *index) int {
+	n := 0
+	for _, d := range idx.docs {
+		if !sel.exposed[d.name] {
+			n++
+		}
+	}
+	return n
+}
+
+// contentText returns the text of the parts of c.
+func contentText(c *genai.Content) string {
+	if c == nil {
+		return ""
+	}
+	var texts []string
+	for _, p := range c.Parts {
+		if p != nil && p.Text != "" && !p.Thought {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n"))
+}
+
+var (
+	_ toolinternal.FunctionTool          = (*selectableTool)(nil)
+	_ toolinternal.RequestProcessor      = (*selectableTool)(nil)
+	_ toolinternal.StreamingFunctionTool = (*streamingSelectableTool)(nil)
+	_ toolinternal.RequestProcessor      = (*streamingSelectableTool)(nil)
+)
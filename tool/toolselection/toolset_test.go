@@ -0,0 +1,277 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolselection
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type args struct {
+	Input string `json:"input"`
+}
+
+func newTool(t *testing.T, name, description string) toolinternal.FunctionTool {
+	t.Helper()
+	ft, err := functiontool.New(functiontool.Config{Name: name, Description: description},
+		func(ctx tool.Context, a args) (string, error) { return name + ":" + a.Input, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ft.(toolinternal.FunctionTool)
+}
+
+type staticToolset struct {
+	tools []tool.Tool
+}
+
+func (s *staticToolset) Name() string { return "static" }
+
+func (s *staticToolset) Tools(agent.ReadonlyContext) ([]tool.Tool, error) { return s.tools, nil }
+
+func newSession(t *testing.T) session.Session {
+	t.Helper()
+	resp, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Session
+}
+
+func newInvocation(t *testing.T, sess session.Session, id, userText string) agent.InvocationContext {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Agent:        a,
+		Session:      sess,
+		InvocationID: id,
+		UserContent:  genai.NewContentFromText(userText, genai.RoleUser),
+	})
+}
+
+// declared returns the names of the tools declared to the model in a
+// request and the request.
+func declared(t *testing.T, ctx agent.InvocationContext, tools []tool.Tool) ([]string, *model.LLMRequest) {
+	t.Helper()
+	req := &model.LLMRequest{}
+	for _, tl := range tools {
+		toolCtx := toolinternal.NewToolContext(ctx, "", &session.EventActions{}, nil)
+		if err := tl.(toolinternal.RequestProcessor).ProcessRequest(toolCtx, req); err != nil {
+			t.Fatalf("ProcessRequest(%q) failed: %v", tl.Name(), err)
+		}
+	}
+	var names []string
+	for _, decl := range req.Config.Tools[0].FunctionDeclarations {
+		names = append(names, decl.Name)
+	}
+	slices.Sort(names)
+	return names, req
+}
+
+func TestToolset(t *testing.T) {
+	weather := newTool(t, "get_weather", "Returns the weather forecast of a city.")
+	ts, err := New(Config{
+		Toolsets: []tool.Toolset{&staticToolset{tools: []tool.Tool{
+			newTool(t, "create_issue", "Creates an issue in a repository."),
+			newTool(t, "list_files", "Lists the files of a directory."),
+			weather,
+			newTool(t, "read_file", "Reads a file."),
+		}}},
+		Tools:            []tool.Tool{newTool(t, "echo", "Repeats the input.")},
+		MaxTools:         1,
+		MaxSearchResults: 2,
+		AlwaysInclude:    tool.StringPredicate([]string{"echo"}),
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	sess := newSession(t)
+	ctx := newInvocation(t, sess, "inv1", "Please read the file notes.txt")
+	tools, err := ts.Tools(icontext.NewReadonlyContext(ctx))
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	if len(tools) != 6 {
+		t.Fatalf("Tools() returned %d tools, want 6", len(tools))
+	}
+	names, req := declared(t, ctx, tools)
+	if diff := cmp.Diff([]string{"echo", "read_file", "search_tools"}, names); diff != "" {
+		t.Errorf("declared tools mismatch (-want +got):\n%s", diff)
+	}
+	if _, ok := req.Tools["get_weather"]; ok {
+		t.Error("get_weather is callable, want it hidden")
+	}
+	if got := req.Config.SystemInstruction; got == nil || !containsText(got, "3 more tools are available") {
+		t.Errorf("SystemInstruction = %v, want the search instructions", got)
+	}
+
+	// The tools found are declared in the next requests of the invocation.
+	search := tools[len(tools)-1].(toolinternal.FunctionTool)
+	toolCtx := toolinternal.NewToolContext(ctx, "call1", &session.EventActions{}, nil)
+	got, err := search.Run(toolCtx, map[string]any{"query": "weather forecast"})
+	if err != nil {
+		t.Fatalf("search_tools failed: %v", err)
+	}
+	want := map[string]any{"tools": []foundTool{{Name: "get_weather", Description: "Returns the weather forecast of a city."}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("search_tools mismatch (-want +got):\n%s", diff)
+	}
+	names, req = declared(t, ctx, tools)
+	if diff := cmp.Diff([]string{"echo", "get_weather", "read_file", "search_tools"}, names); diff != "" {
+		t.Errorf("declared tools after search mismatch (-want +got):\n%s", diff)
+	}
+	if req.Tools["get_weather"] != weather {
+		t.Errorf("get_weather is registered as %T, want the wrapped tool", req.Tools["get_weather"])
+	}
+	if _, err := search.Run(toolCtx, map[string]any{}); err == nil {
+		t.Error("search_tools without query succeeded, want error")
+	}
+
+	// Another invocation gets its own selection.
+	ctx = newInvocation(t, sess, "inv2", "Open an issue")
+	tools, err = ts.Tools(icontext.NewReadonlyContext(ctx))
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	if names, _ := declared(t, ctx, tools); !cmp.Equal([]string{"create_issue", "echo", "search_tools"}, names) {
+		t.Errorf("declared tools = %v, want create_issue selected", names)
+	}
+}
+
+// fixedEmbedder embeds texts as fixed vectors, or fails with err.
+type fixedEmbedder struct {
+	vectors map[string][]float32
+	err     error
+	calls   int
+}
+
+func (e *fixedEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	if e.err != nil {
+		return nil, e.err
+	}
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.vectors[text]
+		if vectors[i] == nil {
+			vectors[i] = []float32{0, 0}
+		}
+	}
+	return vectors, nil
+}
+
+func TestToolset_Embedder(t *testing.T) {
+	embedder := &fixedEmbedder{vectors: map[string][]float32{
+		"Is it going to rain?":                                 {1, 0},
+		"get_weather: Returns the weather forecast of a city.": {0.9, 0.1},
+		"read_file: Reads a file.":                             {0, 1},
+	}}
+	var logs strings.Builder
+	ts, err := New(Config{
+		Tools: []tool.Tool{
+			newTool(t, "read_file", "Reads a file."),
+			newTool(t, "get_weather", "Returns the weather forecast of a city."),
+		},
+		MaxTools: 1,
+		Embedder: embedder,
+		Logger:   log.New(&logs, "", 0),
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	sess := newSession(t)
+	for i, id := range []string{"inv1", "inv2"} {
+		ctx := newInvocation(t, sess, id, "Is it going to rain?")
+		// The user content is embedded once per invocation.
+		for range 2 {
+			tools, err := ts.Tools(icontext.NewReadonlyContext(ctx))
+			if err != nil {
+				t.Fatalf("Tools() failed: %v", err)
+			}
+			if names, _ := declared(t, ctx, tools); !cmp.Equal([]string{"get_weather", "search_tools"}, names) {
+				t.Errorf("declared tools = %v, want get_weather selected by similarity", names)
+			}
+		}
+		// The embeddings of the tools are cached.
+		if got := ts.(*set).embeddingsCount(); got != 2 || embedder.calls != i+1 {
+			t.Errorf("%d embeddings cached after %d calls, want 2 after %d", got, embedder.calls, i+1)
+		}
+	}
+
+	// Failed embeddings fall back to the keyword ranking.
+	embedder.err = errors.New("unavailable")
+	ctx := newInvocation(t, sess, "inv3", "read it")
+	tools, err := ts.Tools(icontext.NewReadonlyContext(ctx))
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	if names, _ := declared(t, ctx, tools); !cmp.Equal([]string{"read_file", "search_tools"}, names) {
+		t.Errorf("declared tools = %v, want read_file selected by keywords", names)
+	}
+	if !strings.Contains(logs.String(), "unavailable") {
+		t.Errorf("logs = %q, want the embedding failure", logs.String())
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "no tools", cfg: Config{}},
+		{name: "nil toolset", cfg: Config{Toolsets: []tool.Toolset{nil}}},
+		{name: "negative max tools", cfg: Config{Tools: []tool.Tool{newTool(t, "a", "")}, MaxTools: -1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := New(tc.cfg); err == nil {
+				t.Error("New() succeeded, want error")
+			}
+		})
+	}
+}
+
+func (s *set) embeddingsCount() int {
+	s.embeddingsMu.Lock()
+	defer s.embeddingsMu.Unlock()
+	return len(s.embeddings)
+}
+
+func containsText(c *genai.Content, s string) bool {
+	for _, p := range c.Parts {
+		if strings.Contains(p.Text, s) {
+			return true
+		}
+	}
+	return false
+}
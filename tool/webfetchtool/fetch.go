@@ -0,0 +1,457 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webfetchtool provides a tool reading web pages.
+//
+// The tool fetches a URL and returns its content as text: HTML pages are
+// converted to Markdown-like text, text and JSON are returned as is and the
+// text of PDF files is extracted. Together with a search tool like
+// duckduckgotool, it lets agents read the pages they find.
+//
+// Usage:
+//
+//	fetchTool := webfetchtool.New(&webfetchtool.Config{
+//	    DeniedHosts: []string{"internal.example.com"},
+//	})
+//	agent := &agent.Agent{
+//	    Tools: []tool.Tool{searchTool, fetchTool},
+//	}
+package webfetchtool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+
+	artifactinternal "google.golang.org/adk/internal/artifact"
+	"google.golang.org/adk/internal/htmltext"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+const (
+	defaultTimeout       = 20 * time.Second
+	defaultMaxBodySize   = 5 << 20
+	defaultMaxTextLength = 100_000
+	maxRedirects         = 10
+)
+
+// Config holds configuration for the web fetch tool.
+type Config struct {
+	// HTTPClient allows providing a custom HTTP client (optional). Its
+	// redirect policy is replaced to apply the host lists to redirects, and
+	// its transport is cloned to deny private addresses, unless it is not
+	// an *http.Transport or has custom dial functions.
+	HTTPClient *http.Client
+	// Timeout for fetch requests (default: 20s). It is not applied to an
+	// HTTPClient with a timeout.
+	Timeout time.Duration
+	// MaxBodySize is the maximum number of bytes read from a response
+	// (default: 5 MiB). Larger responses are truncated.
+	MaxBodySize int64
+	// MaxTextLength is the maximum number of characters of text returned
+	// to the model (default: 100000). Longer text is truncated.
+	MaxTextLength int
+
+	// AllowedHosts restricts the fetched URLs to these hosts and their
+	// subdomains if not empty.
+	AllowedHosts []string
+	// DeniedHosts are hosts, including their subdomains, which are never
+	// fetched.
+	DeniedHosts []string
+	// AllowPrivateNetworks allows fetching hosts resolving to loopback,
+	// private and link-local addresses, which are denied by default so
+	// that the model cannot reach internal services. The addresses are
+	// checked when connecting, so that a host cannot resolve to a public
+	// address when checked and to a private one when fetched. For the same
+	// reason, proxies are not used unless private networks are allowed.
+	AllowPrivateNetworks bool
+
+	// SaveArtifacts saves the raw response body as an artifact, if an
+	// artifact service is configured, and returns its name.
+	SaveArtifacts bool
+	// UserAgent is sent with the requests (default: "Mozilla/5.0
+	// (compatible; ADK-Go/1.0)").
+	UserAgent string
+}
+
+// WebFetch is a tool fetching web pages and returning their content as
+// text. It implements the tool.Tool interface and works with any LLM
+// through the standard function call mechanism.
+type WebFetch struct {
+	httpClient           *http.Client
+	maxBodySize          int64
+	maxTextLength        int
+	allowedHosts         []string
+	deniedHosts          []string
+	allowPrivateNetworks bool
+	// dialChecked reports whether the transport checks the addresses it
+	// connects to, which are otherwise checked when the host is resolved
+	// before the request.
+	dialChecked   bool
+	saveArtifacts bool
+	userAgent     string
+}
+
+// New creates a new web fetch tool. Pass nil for default configuration.
+func New(cfg *Config) *WebFetch {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	f := &WebFetch{
+		maxBodySize:          defaultMaxBodySize,
+		maxTextLength:        defaultMaxTextLength,
+		allowedHosts:         normalizeHosts(cfg.AllowedHosts),
+		deniedHosts:          normalizeHosts(cfg.DeniedHosts),
+		allowPrivateNetworks: cfg.AllowPrivateNetworks,
+		saveArtifacts:        cfg.SaveArtifacts,
+		userAgent:            "Mozilla/5.0 (compatible; ADK-Go/1.0)",
+	}
+	if cfg.MaxBodySize > 0 {
+		f.maxBodySize = cfg.MaxBodySize
+	}
+	if cfg.MaxTextLength > 0 {
+		f.maxTextLength = cfg.MaxTextLength
+	}
+	if cfg.UserAgent != "" {
+		f.userAgent = cfg.UserAgent
+	}
+
+	timeout := defaultTimeout
+	if cfg.Timeout > 0 {
+		timeout = cfg.Timeout
+	}
+	if cfg.HTTPClient != nil {
+		client := *cfg.HTTPClient
+		f.httpClient = &client
+	} else {
+		f.httpClient = &http.Client{}
+	}
+	if f.httpClient.Timeout == 0 {
+		f.httpClient.Timeout = timeout
+	}
+	if !f.allowPrivateNetworks {
+		f.dialChecked = denyPrivateAddresses(f.httpClient)
+	}
+	f.httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return f.checkURL(req.Context(), req.URL)
+	}
+	return f
+}
+
+// denyPrivateAddresses replaces the transport of client with one refusing
+// to connect to private addresses. The transport does not use a proxy, as
+// it would only check the address of the proxy. It reports false if the
+// transport of client cannot be changed to do so.
+func denyPrivateAddresses(client *http.Client) bool {
+	var transport *http.Transport
+	if client.Transport == nil {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	} else {
+		t, ok := client.Transport.(*http.Transport)
+		// Custom dial functions cannot be made to check the addresses.
+		if !ok || t.DialContext != nil || t.Dial != nil || t.DialTLSContext != nil || t.DialTLS != nil {
+			return false
+		}
+		transport = t.Clone()
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   denyPrivateAddress,
+	}
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+	client.Transport = transport
+	return true
+}
+
+// denyPrivateAddress is a net.Dialer Control function refusing connections
+// to private addresses. It checks the address actually dialled, unlike a
+// check of the host resolved beforehand, which DNS rebinding gets around.
+func denyPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("unexpected address %q", address)
+	}
+	if isPrivate(ip) {
+		return fmt.Errorf("connection to the private address %s is denied", ip)
+	}
+	return nil
+}
+
+// nonPublicNetworks are the networks denied besides the loopback, private,
+// link-local and unspecified addresses: the shared address space of carrier
+// grade NATs and the NAT64 prefixes, which embed IPv4 addresses.
+var nonPublicNetworks = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// isPrivate reports whether ip is a loopback, private, link-local,
+// unspecified, shared or NAT64 address.
+func isPrivate(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, network := range nonPublicNetworks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Name implements tool.Tool.
+func (f *WebFetch) Name() string {
+	return "web_fetch"
+}
+
+// Description implements tool.Tool.
+func (f *WebFetch) Description() string {
+	return "Fetches a web page or document by URL and returns its content as text. " +
+		"HTML pages are converted to Markdown, PDF text is extracted. " +
+		"Use this to read pages found by a web search."
+}
+
+// IsLongRunning implements tool.Tool.
+func (f *WebFetch) IsLongRunning() bool {
+	return false
+}
+
+// Declaration returns the function declaration for the fetch tool.
+func (f *WebFetch) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        f.Name(),
+		Description: f.Description(),
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"url": {
+					Type:        "STRING",
+					Description: "The http or https URL to fetch.",
+				},
+			},
+			Required: []string{"url"},
+		},
+	}
+}
+
+// ProcessRequest registers the tool with the LLM request so the model
+// can discover and call it.
+func (f *WebFetch) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolutils.PackTool(req, f)
+}
+
+// Run fetches the URL given in the arguments.
+func (f *WebFetch) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]any, got %T", args)
+	}
+	rawURL, ok := m["url"].(string)
+	if !ok || strings.TrimSpace(rawURL) == "" {
+		return nil, fmt.Errorf("missing required parameter: url")
+	}
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if err := f.checkURL(ctx, u); err != nil {
+		return nil, err
+	}
+	return f.fetch(ctx, u)
+}
+
+// fetch fetches the URL and converts the response body to text.
+func (f *WebFetch) fetch(ctx tool.Context, u *url.URL) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain,application/pdf;q=0.9,*/*;q=0.8")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("fetch returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	truncated := int64(len(body)) > f.maxBodySize
+	if truncated {
+		body = body[:f.maxBodySize]
+	}
+
+	mediaType := responseMediaType(resp.Header.Get("Content-Type"), body)
+	result := map[string]any{
+		"url":          resp.Request.URL.String(),
+		"content_type": mediaType,
+	}
+
+	if f.saveArtifacts && ctx.Artifacts() != nil {
+		name := artifactinternal.FileName("web_fetch_"+ctx.FunctionCallID(), mediaType)
+		saved, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(body, mediaType))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save response as artifact: %w", err)
+		}
+		result["artifact"] = name
+		result["artifact_version"] = saved.Version
+	}
+
+	var text string
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		var title string
+		title, text = htmltext.Markdown(string(body), resp.Request.URL)
+		if title != "" {
+			result["title"] = title
+		}
+	case mediaType == "application/pdf":
+		text = pdfText(body)
+	case isText(mediaType):
+		text = strings.ToValidUTF8(string(body), "�")
+	default:
+		if _, ok := result["artifact"]; ok {
+			return result, nil
+		}
+		return nil, fmt.Errorf("unsupported content type %q", mediaType)
+	}
+
+	if utf8.RuneCountInString(text) > f.maxTextLength {
+		text = string([]rune(text)[:f.maxTextLength])
+		truncated = true
+	}
+	result["content"] = text
+	if truncated {
+		result["truncated"] = true
+	}
+	return result, nil
+}
+
+// checkURL returns an error if the URL must not be fetched.
+func (f *WebFetch) checkURL(ctx context.Context, u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme %q, only http and https are supported", u.Scheme)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "" {
+		return errors.New("url has no host")
+	}
+	if matchesHost(host, f.deniedHosts) {
+		return fmt.Errorf("host %q is denied", host)
+	}
+	if len(f.allowedHosts) > 0 && !matchesHost(host, f.allowedHosts) {
+		return fmt.Errorf("host %q is not allowed", host)
+	}
+	if f.allowPrivateNetworks {
+		return nil
+	}
+
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		if f.dialChecked {
+			// The addresses the host resolves to are checked when
+			// connecting.
+			return nil
+		}
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return fmt.Errorf("failed to resolve host %q: %w", host, err)
+		}
+		ips = ips[:0]
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+	for _, ip := range ips {
+		if isPrivate(ip) {
+			return fmt.Errorf("host %q resolves to the private address %s", host, ip)
+		}
+	}
+	return nil
+}
+
+// matchesHost reports whether host is one of hosts or a subdomain of one.
+func matchesHost(host string, hosts []string) bool {
+	return slices.ContainsFunc(hosts, func(h string) bool {
+		return host == h || strings.HasSuffix(host, "."+h)
+	})
+}
+
+func normalizeHosts(hosts []string) []string {
+	out := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		if h = strings.ToLower(strings.Trim(strings.TrimSpace(h), ".")); h != "" {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// responseMediaType returns the media type of the response, sniffed from
+// the body if the server does not declare it.
+func responseMediaType(contentType string, body []byte) string {
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+// isText reports whether the media type is text returned as is.
+func isText(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") ||
+		mediaType == "application/javascript"
+}
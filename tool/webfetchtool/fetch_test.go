@@ -0,0 +1,302 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webfetchtool
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	artifactinternal "google.golang.org/adk/internal/artifact"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+)
+
+func createToolContext(t *testing.T, withArtifacts bool) tool.Context {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := icontext.InvocationContextParams{Agent: a}
+	if withArtifacts {
+		params.Artifacts = &artifactinternal.Artifacts{
+			Service:   artifact.InMemoryService(),
+			AppName:   "app",
+			UserID:    "user",
+			SessionID: "session",
+		}
+	}
+	return toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), params), "call1", nil, nil)
+}
+
+// testPDF returns a PDF file with a compressed content stream showing the
+// given lines.
+func testPDF(t *testing.T, lines ...string) []byte {
+	t.Helper()
+	var content strings.Builder
+	content.WriteString("BT /F1 12 Tf 72 712 Td ")
+	for i, line := range lines {
+		if i > 0 {
+			content.WriteString("0 -14 Td ")
+		}
+		content.WriteString("(" + strings.NewReplacer("(", `\(`, ")", `\)`).Replace(line) + ") Tj ")
+	}
+	content.WriteString("ET")
+
+	var stream bytes.Buffer
+	zw := zlib.NewWriter(&stream)
+	zw.Write([]byte(content.String()))
+	zw.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n1 0 obj\n<< /Length ")
+	pdf.WriteString(strconv.Itoa(stream.Len()))
+	pdf.WriteString(" /Filter /FlateDecode >>\nstream\n")
+	pdf.Write(stream.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestRun(t *testing.T) {
+	pdf := testPDF(t, "Hello (PDF) world", "Second line")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, `<html><head><title>Docs</title></head><body><h1>Intro</h1><p>See <a href="/more">more</a>.</p></body></html>`)
+	})
+	mux.HandleFunc("/notes.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "plain notes")
+	})
+	mux.HandleFunc("/paper.pdf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write(pdf)
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("\x89PNG"))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		path string
+		want map[string]any
+	}{
+		{
+			name: "html",
+			path: "/page",
+			want: map[string]any{
+				"url":          server.URL + "/page",
+				"content_type": "text/html",
+				"title":        "Docs",
+				"content":      "# Intro\n\nSee [more](" + server.URL + "/more).",
+			},
+		},
+		{
+			name: "redirect",
+			path: "/redirect",
+			want: map[string]any{
+				"url":          server.URL + "/page",
+				"content_type": "text/html",
+				"title":        "Docs",
+				"content":      "# Intro\n\nSee [more](" + server.URL + "/more).",
+			},
+		},
+		{
+			name: "plain text",
+			path: "/notes.txt",
+			want: map[string]any{
+				"url":          server.URL + "/notes.txt",
+				"content_type": "text/plain",
+				"content":      "plain notes",
+			},
+		},
+		{
+			name: "pdf",
+			path: "/paper.pdf",
+			want: map[string]any{
+				"url":          server.URL + "/paper.pdf",
+				"content_type": "application/pdf",
+				"content":      "Hello (PDF) world\nSecond line",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := New(&Config{AllowPrivateNetworks: true})
+			got, err := f.Run(createToolContext(t, false), map[string]any{"url": server.URL + tc.path})
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		f := New(&Config{AllowPrivateNetworks: true, MaxTextLength: 5})
+		got, err := f.Run(createToolContext(t, false), map[string]any{"url": server.URL + "/notes.txt"})
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if got["content"] != "plain" || got["truncated"] != true {
+			t.Errorf("Run() = %v, want truncated content %q", got, "plain")
+		}
+	})
+
+	t.Run("artifact", func(t *testing.T) {
+		f := New(&Config{AllowPrivateNetworks: true, SaveArtifacts: true})
+		ctx := createToolContext(t, true)
+		got, err := f.Run(ctx, map[string]any{"url": server.URL + "/image.png"})
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if got["artifact"] != "web_fetch_call1.png" {
+			t.Fatalf("Run() artifact = %v, want web_fetch_call1.png", got["artifact"])
+		}
+		resp, err := ctx.Artifacts().Load(ctx, "web_fetch_call1.png")
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if !bytes.Equal(resp.Part.InlineData.Data, []byte("\x89PNG")) {
+			t.Errorf("artifact data = %q, want the response body", resp.Part.InlineData.Data)
+		}
+	})
+
+	t.Run("unsupported content", func(t *testing.T) {
+		f := New(&Config{AllowPrivateNetworks: true})
+		if _, err := f.Run(createToolContext(t, false), map[string]any{"url": server.URL + "/image.png"}); err == nil {
+			t.Error("Run() succeeded, want unsupported content type error")
+		}
+	})
+}
+
+func TestRun_Denied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://denied.example.com/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		cfg     *Config
+		url     string
+		wantErr string
+	}{
+		{name: "private network", cfg: nil, url: server.URL, wantErr: "private address"},
+		// Host names are checked when connecting, against the address dialled.
+		{name: "private host name", cfg: nil, url: strings.Replace(server.URL, "127.0.0.1", "localhost", 1), wantErr: "connection to the private address"},
+		{name: "private host name with client", cfg: &Config{HTTPClient: &http.Client{Transport: &http.Transport{}}}, url: strings.Replace(server.URL, "127.0.0.1", "localhost", 1), wantErr: "connection to the private address"},
+		{name: "scheme", cfg: nil, url: "file:///etc/passwd", wantErr: "unsupported url scheme"},
+		{name: "denied host", cfg: &Config{DeniedHosts: []string{"example.com"}}, url: "https://www.example.com/", wantErr: `host "www.example.com" is denied`},
+		{name: "not allowed host", cfg: &Config{AllowedHosts: []string{"golang.org"}}, url: "https://example.com/", wantErr: `host "example.com" is not allowed`},
+		{name: "denied redirect", cfg: &Config{AllowPrivateNetworks: true, DeniedHosts: []string{"denied.example.com"}}, url: server.URL, wantErr: `host "denied.example.com" is denied`},
+		{name: "missing url", cfg: nil, url: "", wantErr: "missing required parameter: url"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.cfg).Run(createToolContext(t, false), map[string]any{"url": tc.url})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Run() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_Timeout(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		want time.Duration
+	}{
+		{name: "default", cfg: nil, want: defaultTimeout},
+		{name: "config", cfg: &Config{Timeout: time.Second}, want: time.Second},
+		{name: "client without timeout", cfg: &Config{HTTPClient: &http.Client{}, Timeout: time.Second}, want: time.Second},
+		{name: "client timeout", cfg: &Config{HTTPClient: &http.Client{Timeout: time.Minute}, Timeout: time.Second}, want: time.Minute},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := New(tc.cfg).httpClient.Timeout; got != tc.want {
+				t.Errorf("New() client timeout = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNew_Proxy(t *testing.T) {
+	proxy := http.ProxyURL(&url.URL{Scheme: "http", Host: "proxy.example.com:3128"})
+	tests := []struct {
+		name      string
+		cfg       *Config
+		wantProxy bool
+	}{
+		{name: "default", cfg: nil, wantProxy: false},
+		{name: "client", cfg: &Config{HTTPClient: &http.Client{Transport: &http.Transport{Proxy: proxy}}}, wantProxy: false},
+		{name: "private networks", cfg: &Config{HTTPClient: &http.Client{Transport: &http.Transport{Proxy: proxy}}, AllowPrivateNetworks: true}, wantProxy: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := New(tc.cfg).httpClient.Transport.(*http.Transport)
+			if got := transport.Proxy != nil; got != tc.wantProxy {
+				t.Errorf("New() transport uses a proxy = %v, want %v", got, tc.wantProxy)
+			}
+		})
+	}
+}
+
+func TestIsPrivate(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "127.0.0.1", want: true},
+		{ip: "10.1.2.3", want: true},
+		{ip: "169.254.169.254", want: true},
+		{ip: "100.64.0.1", want: true},
+		{ip: "100.127.255.254", want: true},
+		{ip: "::ffff:100.64.0.1", want: true},
+		{ip: "64:ff9b::a9fe:a9fe", want: true},
+		{ip: "64:ff9b:1::a00:1", want: true},
+		{ip: "fd00::1", want: true},
+		{ip: "100.128.0.1", want: false},
+		{ip: "8.8.8.8", want: false},
+		{ip: "2001:4860:4860::8888", want: false},
+	}
+	for _, tc := range tests {
+		if got := isPrivate(net.ParseIP(tc.ip)); got != tc.want {
+			t.Errorf("isPrivate(%s) = %v, want %v", tc.ip, got, tc.want)
+		}
+	}
+}
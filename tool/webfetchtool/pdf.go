@@ -0,0 +1,227 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webfetchtool
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// streamRe matches the streams of a PDF file.
+var streamRe = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`)
+
+// maxStreamSize limits the decompressed size of a PDF stream.
+const maxStreamSize = 16 << 20
+
+// pdfText extracts the text of a PDF file on a best-effort basis: it reads
+// the text operators of the content streams, which works for PDFs with
+// standard font encodings but not for fonts with custom encodings or
+// scanned documents.
+func pdfText(data []byte) string {
+	var sb strings.Builder
+	for _, m := range streamRe.FindAllSubmatch(data, -1) {
+		content := m[1]
+		if r, err := zlib.NewReader(bytes.NewReader(content)); err == nil {
+			decompressed, err := io.ReadAll(io.LimitReader(r, maxStreamSize))
+			if err == nil || len(decompressed) > 0 {
+				content = decompressed
+			}
+		}
+		if !bytes.Contains(content, []byte("BT")) {
+			continue
+		}
+		extractText(&sb, content)
+	}
+	lines := strings.Split(sb.String(), "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// extractText writes the text shown by the text objects of a content
+// stream.
+func extractText(sb *strings.Builder, content []byte) {
+	inText := false
+	var operands []string
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '(':
+			s, n := literalString(content[i:])
+			operands = append(operands, s)
+			i += n
+		case c == '<' && i+1 < len(content) && content[i+1] != '<':
+			end := bytes.IndexByte(content[i:], '>')
+			if end < 0 {
+				return
+			}
+			operands = append(operands, hexString(content[i+1:i+end]))
+			i += end + 1
+		case c == '[' || c == ']':
+			i++
+		case c == '%':
+			if end := bytes.IndexByte(content[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				return
+			}
+		case isDelimiter(c):
+			i++
+		default:
+			start := i
+			for i < len(content) && !isDelimiter(content[i]) && content[i] != '(' && content[i] != '<' && content[i] != '[' && content[i] != ']' {
+				i++
+			}
+			token := string(content[start:i])
+			if i == start {
+				i++
+				continue
+			}
+			switch token {
+			case "BT":
+				inText = true
+			case "ET":
+				inText = false
+				sb.WriteString("\n")
+			case "Tj", "TJ":
+				if inText {
+					sb.WriteString(strings.Join(operands, ""))
+				}
+			case "'", "\"":
+				if inText {
+					sb.WriteString("\n" + strings.Join(operands, ""))
+				}
+			case "T*", "Td", "TD":
+				if inText {
+					sb.WriteString("\n")
+				}
+			default:
+				// Large negative adjustments between the strings of a TJ
+				// array separate words.
+				if len(token) > 0 && token[0] == '-' && len(token) >= 4 {
+					operands = append(operands, " ")
+				}
+				continue
+			}
+			operands = operands[:0]
+		}
+	}
+}
+
+func isDelimiter(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0 || c == '/' || c == '{' || c == '}' || c == '>'
+}
+
+// literalString decodes the PDF literal string at the start of b and
+// returns it with the number of bytes read.
+func literalString(b []byte) (string, int) {
+	var sb strings.Builder
+	depth := 0
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch c {
+		case '(':
+			if depth > 0 {
+				sb.WriteByte(c)
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return printable(sb.String()), i + 1
+			}
+			sb.WriteByte(c)
+		case '\\':
+			if i+1 >= len(b) {
+				break
+			}
+			i++
+			switch e := b[i]; e {
+			case 'n', 'r':
+				sb.WriteByte(' ')
+			case 't':
+				sb.WriteByte('\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// Line continuation.
+			default:
+				if e >= '0' && e <= '7' {
+					v := 0
+					j := i
+					for ; j < len(b) && j < i+3 && b[j] >= '0' && b[j] <= '7'; j++ {
+						v = v*8 + int(b[j]-'0')
+					}
+					sb.WriteByte(byte(v))
+					i = j - 1
+				} else {
+					sb.WriteByte(e)
+				}
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return printable(sb.String()), len(b)
+}
+
+// hexString decodes a PDF hex string, which is only kept if it is
+// printable single-byte text.
+func hexString(b []byte) string {
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, string(b))
+	if len(s)%2 == 1 {
+		s += "0"
+	}
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return ""
+	}
+	for _, c := range decoded {
+		if c < 0x20 || c > 0x7e {
+			return ""
+		}
+	}
+	return string(decoded)
+}
+
+// printable decodes the Latin-1 bytes of a string, dropping control
+// characters.
+func printable(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\t':
+			sb.WriteByte(' ')
+		case c < 0x20 || c == 0x7f:
+		default:
+			sb.WriteRune(rune(c))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websearchtool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/internal/htmltext"
+)
+
+const (
+	defaultTimeout   = 15 * time.Second
+	braveEndpoint    = "https://api.search.brave.com/res/v1/web/search"
+	bingEndpoint     = "https://api.bing.microsoft.com/v7.0/search"
+	maxErrorResponse = 500
+)
+
+// SearXNGConfig holds configuration for the SearXNG provider.
+type SearXNGConfig struct {
+	// BaseURL of the SearXNG instance, like "http://localhost:8888". The
+	// instance must allow the JSON output format.
+	BaseURL string
+	// Engines restricts the search to these SearXNG engines (optional).
+	Engines []string
+	// HTTPClient allows providing a custom HTTP client (optional).
+	HTTPClient *http.Client
+}
+
+// NewSearXNG returns a provider searching with a SearXNG instance.
+func NewSearXNG(cfg SearXNGConfig) SearchProvider {
+	return &searxng{
+		baseURL:    strings.TrimSuffix(cfg.BaseURL, "/"),
+		engines:    cfg.Engines,
+		httpClient: httpClient(cfg.HTTPClient),
+	}
+}
+
+type searxng struct {
+	baseURL    string
+	engines    []string
+	httpClient *http.Client
+}
+
+func (s *searxng) Name() string {
+	return "searxng"
+}
+
+func (s *searxng) Search(ctx context.Context, query string, opts SearchOptions) ([]Result, error) {
+	if s.baseURL == "" {
+		return nil, errors.New("SearXNG base URL is not configured")
+	}
+	params := url.Values{"q": {query}, "format": {"json"}}
+	if len(s.engines) > 0 {
+		params.Set("engines", strings.Join(s.engines, ","))
+	}
+	var resp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := getJSON(ctx, s.httpClient, s.baseURL+"/search?"+params.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	var results []Result
+	for _, r := range resp.Results {
+		results = append(results, Result{Title: r.Title, URL: r.URL, Snippet: r.Content})
+	}
+	return limit(results, opts.MaxResults), nil
+}
+
+// BraveConfig holds configuration for the Brave Search provider.
+type BraveConfig struct {
+	// APIKey is the subscription token of the Brave Search API.
+	APIKey string
+	// Endpoint overrides the web search endpoint (optional).
+	Endpoint string
+	// HTTPClient allows providing a custom HTTP client (optional).
+	HTTPClient *http.Client
+}
+
+// NewBrave returns a provider searching with the Brave Search API.
+func NewBrave(cfg BraveConfig) SearchProvider {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = braveEndpoint
+	}
+	return &brave{
+		apiKey:     cfg.APIKey,
+		endpoint:   endpoint,
+		httpClient: httpClient(cfg.HTTPClient),
+	}
+}
+
+type brave struct {
+	apiKey     string
+	endpoint   string
+	httpClient *http.Client
+}
+
+func (b *brave) Name() string {
+	return "brave"
+}
+
+func (b *brave) Search(ctx context.Context, query string, opts SearchOptions) ([]Result, error) {
+	params := url.Values{"q": {query}}
+	if opts.MaxResults > 0 {
+		params.Set("count", strconv.Itoa(min(opts.MaxResults, 20)))
+	}
+	var resp struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	header := http.Header{"X-Subscription-Token": {b.apiKey}}
+	if err := getJSON(ctx, b.httpClient, b.endpoint+"?"+params.Encode(), header, &resp); err != nil {
+		return nil, err
+	}
+	var results []Result
+	for _, r := range resp.Web.Results {
+		// Brave highlights the query terms with HTML tags.
+		results = append(results, Result{Title: htmltext.Text(r.Title), URL: r.URL, Snippet: htmltext.Text(r.Description)})
+	}
+	return limit(results, opts.MaxResults), nil
+}
+
+// BingConfig holds configuration for the Bing Web Search provider, which
+// also works with services implementing the same JSON API.
+type BingConfig struct {
+	// APIKey is the subscription key of the API.
+	APIKey string
+	// Endpoint overrides the web search endpoint (optional).
+	Endpoint string
+	// Market like "en-US" (optional).
+	Market string
+	// HTTPClient allows providing a custom HTTP client (optional).
+	HTTPClient *http.Client
+}
+
+// NewBing returns a provider searching with the Bing Web Search API.
+func NewBing(cfg BingConfig) SearchProvider {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = bingEndpoint
+	}
+	return &bing{
+		apiKey:     cfg.APIKey,
+		endpoint:   endpoint,
+		market:     cfg.Market,
+		httpClient: httpClient(cfg.HTTPClient),
+	}
+}
+
+type bing struct {
+	apiKey     string
+	endpoint   string
+	market     string
+	httpClient *http.Client
+}
+
+func (b *bing) Name() string {
+	return "bing"
+}
+
+func (b *bing) Search(ctx context.Context, query string, opts SearchOptions) ([]Result, error) {
+	params := url.Values{"q": {query}, "responseFilter": {"Webpages"}}
+	if opts.MaxResults > 0 {
+		params.Set("count", strconv.Itoa(min(opts.MaxResults, 50)))
+	}
+	if b.market != "" {
+		params.Set("mkt", b.market)
+	}
+	var resp struct {
+		WebPages struct {
+			Value []struct {
+				Name    string `json:"name"`
+				URL     string `json:"url"`
+				Snippet string `json:"snippet"`
+			} `json:"value"`
+		} `json:"webPages"`
+	}
+	header := http.Header{"Ocp-Apim-Subscription-Key": {b.apiKey}}
+	if err := getJSON(ctx, b.httpClient, b.endpoint+"?"+params.Encode(), header, &resp); err != nil {
+		return nil, err
+	}
+	var results []Result
+	for _, r := range resp.WebPages.Value {
+		results = append(results, Result{Title: r.Name, URL: r.URL, Snippet: r.Snippet})
+	}
+	return limit(results, opts.MaxResults), nil
+}
+
+func httpClient(c *http.Client) *http.Client {
+	if c != nil {
+		return c
+	}
+	return &http.Client{Timeout: defaultTimeout}
+}
+
+// getJSON sends a GET request and decodes the JSON response into v.
+func getJSON(ctx context.Context, client *http.Client, url string, header http.Header, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("search request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorResponse))
+		return fmt.Errorf("search returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func limit(results []Result, n int) []Result {
+	if n > 0 && len(results) > n {
+		return results[:n]
+	}
+	return results
+}
@@ -0,0 +1,305 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package websearchtool provides a web search tool backed by pluggable
+// search providers.
+//
+// Unlike geminitool.GoogleSearch, which runs inside Gemini models, the tool
+// calls search APIs like SearXNG, Brave or Bing and works with any LLM
+// through the standard function call mechanism. Results of several
+// providers are deduplicated and ranked together, and always have the same
+// schema.
+//
+// Usage:
+//
+//	searchTool, err := websearchtool.New(websearchtool.Config{
+//	    Providers: []websearchtool.SearchProvider{
+//	        websearchtool.NewBrave(websearchtool.BraveConfig{APIKey: os.Getenv("BRAVE_API_KEY")}),
+//	        websearchtool.NewSearXNG(websearchtool.SearXNGConfig{BaseURL: "http://localhost:8888"}),
+//	    },
+//	})
+//	agent := &agent.Agent{
+//	    Tools: []tool.Tool{searchTool},
+//	}
+package websearchtool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+const (
+	defaultMaxResults = 5
+	// rrfK is the constant of the reciprocal rank fusion of the results of
+	// several providers.
+	rrfK = 60
+)
+
+// Result is a web search result.
+type Result struct {
+	Title   string
+	URL     string
+	Snippet string
+}
+
+// SearchOptions are the options of a search.
+type SearchOptions struct {
+	// MaxResults is the maximum number of results to return.
+	MaxResults int
+}
+
+// SearchProvider searches the web with a search engine.
+type SearchProvider interface {
+	// Name identifies the provider in the results, like "brave".
+	Name() string
+	// Search returns the results for the query, best first.
+	Search(ctx context.Context, query string, opts SearchOptions) ([]Result, error)
+}
+
+// Config holds configuration for the web search tool.
+type Config struct {
+	// Providers are queried concurrently; their results are deduplicated
+	// and ranked together. At least one provider is required.
+	Providers []SearchProvider
+	// MaxResults is the maximum number of search results to return
+	// (default: 5).
+	MaxResults int
+}
+
+// WebSearch is a web search tool querying search providers. It implements
+// the tool.Tool interface and works with any LLM through the standard
+// function call mechanism.
+type WebSearch struct {
+	providers  []SearchProvider
+	maxResults int
+}
+
+// New creates a new web search tool.
+func New(cfg Config) (*WebSearch, error) {
+	if len(cfg.Providers) == 0 {
+		return nil, errors.New("at least one search provider is required")
+	}
+	if slices.Contains(cfg.Providers, nil) {
+		return nil, errors.New("search providers must not be nil")
+	}
+	s := &WebSearch{
+		providers:  cfg.Providers,
+		maxResults: defaultMaxResults,
+	}
+	if cfg.MaxResults > 0 {
+		s.maxResults = cfg.MaxResults
+	}
+	return s, nil
+}
+
+// Name implements tool.Tool.
+func (s *WebSearch) Name() string {
+	return "web_search"
+}
+
+// Description implements tool.Tool.
+func (s *WebSearch) Description() string {
+	return "Searches the web and returns relevant results " +
+		"including titles, snippets, and URLs. Use this to find current " +
+		"information or research topics."
+}
+
+// IsLongRunning implements tool.Tool.
+func (s *WebSearch) IsLongRunning() bool {
+	return false
+}
+
+// Declaration returns the function declaration for the search tool.
+func (s *WebSearch) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        s.Name(),
+		Description: s.Description(),
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"query": {
+					Type:        "STRING",
+					Description: "The search query.",
+				},
+			},
+			Required: []string{"query"},
+		},
+	}
+}
+
+// ProcessRequest registers the tool with the LLM request so the model
+// can discover and call it.
+func (s *WebSearch) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolutils.PackTool(req, s)
+}
+
+// Run executes the web search with the given arguments.
+func (s *WebSearch) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]any, got %T", args)
+	}
+	query, ok := m["query"].(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("missing required parameter: query")
+	}
+
+	results, err := s.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]map[string]any, len(results))
+	for i, r := range results {
+		out[i] = map[string]any{
+			"title":   r.Title,
+			"url":     r.URL,
+			"snippet": r.Snippet,
+			"sources": r.Sources,
+		}
+	}
+	return map[string]any{
+		"query":   query,
+		"results": out,
+	}, nil
+}
+
+// RankedResult is a search result merged from the results of the providers.
+type RankedResult struct {
+	Result
+	// Sources are the names of the providers which returned the result.
+	Sources []string
+}
+
+// Search queries all providers concurrently and returns their merged
+// results. It fails only if all providers fail.
+func (s *WebSearch) Search(ctx context.Context, query string) ([]RankedResult, error) {
+	opts := SearchOptions{MaxResults: s.maxResults}
+	perProvider := make([][]Result, len(s.providers))
+	errs := make([]error, len(s.providers))
+	var wg sync.WaitGroup
+	for i, p := range s.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results, err := p.Search(ctx, query, opts)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s search failed: %w", p.Name(), err)
+				return
+			}
+			perProvider[i] = results
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == len(s.providers) {
+		return nil, errors.Join(errs...)
+	}
+	return s.merge(perProvider), nil
+}
+
+// merge deduplicates the results of the providers by URL and ranks them by
+// reciprocal rank fusion, so that results found by several providers rank
+// first.
+func (s *WebSearch) merge(perProvider [][]Result) []RankedResult {
+	type entry struct {
+		result RankedResult
+		score  float64
+		order  int
+	}
+	byURL := map[string]*entry{}
+	var entries []*entry
+	for i, results := range perProvider {
+		rank := 0
+		for _, r := range results {
+			key := normalizeURL(r.URL)
+			if key == "" {
+				continue
+			}
+			rank++
+			e, ok := byURL[key]
+			if !ok {
+				e = &entry{result: RankedResult{Result: r}, order: len(entries)}
+				byURL[key] = e
+				entries = append(entries, e)
+			}
+			name := s.providers[i].Name()
+			if slices.Contains(e.result.Sources, name) {
+				// Duplicates within the results of a provider count once.
+				continue
+			}
+			e.score += 1 / float64(rrfK+rank)
+			e.result.Sources = append(e.result.Sources, name)
+			if e.result.Title == "" {
+				e.result.Title = r.Title
+			}
+			if len(r.Snippet) > len(e.result.Snippet) {
+				e.result.Snippet = r.Snippet
+			}
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b *entry) int {
+		switch {
+		case a.score > b.score:
+			return -1
+		case a.score < b.score:
+			return 1
+		default:
+			return a.order - b.order
+		}
+	})
+	results := make([]RankedResult, 0, min(len(entries), s.maxResults))
+	for _, e := range entries[:min(len(entries), s.maxResults)] {
+		results = append(results, e.result)
+	}
+	return results
+}
+
+// normalizeURL returns the URL used to deduplicate results, ignoring the
+// scheme, "www." prefix, fragment, trailing slash and tracking parameters.
+func normalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	q := u.Query()
+	for k := range q {
+		if strings.HasPrefix(k, "utm_") {
+			q.Del(k)
+		}
+	}
+	key := host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if len(q) > 0 {
+		key += "?" + q.Encode()
+	}
+	return key
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websearchtool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeProvider struct {
+	name    string
+	results []Result
+	err     error
+}
+
+func (p *fakeProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeProvider) Search(ctx context.Context, query string, opts SearchOptions) ([]Result, error) {
+	return p.results, p.err
+}
+
+func TestProviders(t *testing.T) {
+	want := []Result{
+		{Title: "Go", URL: "https://go.dev/", Snippet: "The Go programming language"},
+		{Title: "Tour", URL: "https://go.dev/tour", Snippet: "A tour of Go"},
+	}
+	tests := []struct {
+		name       string
+		newFunc    func(url string) SearchProvider
+		wantPath   string
+		wantHeader string
+		response   string
+	}{
+		{
+			name:     "searxng",
+			newFunc:  func(url string) SearchProvider { return NewSearXNG(SearXNGConfig{BaseURL: url + "/"}) },
+			wantPath: "/search",
+			response: `{"results":[{"title":"Go","url":"https://go.dev/","content":"The Go programming language"},{"title":"Tour","url":"https://go.dev/tour","content":"A tour of Go"}]}`,
+		},
+		{
+			name:       "brave",
+			newFunc:    func(url string) SearchProvider { return NewBrave(BraveConfig{APIKey: "key", Endpoint: url + "/web"}) },
+			wantPath:   "/web",
+			wantHeader: "X-Subscription-Token",
+			response:   `{"web":{"results":[{"title":"Go","url":"https://go.dev/","description":"The <strong>Go</strong> programming language"},{"title":"Tour","url":"https://go.dev/tour","description":"A tour of Go"}]}}`,
+		},
+		{
+			name:       "bing",
+			newFunc:    func(url string) SearchProvider { return NewBing(BingConfig{APIKey: "key", Endpoint: url + "/web"}) },
+			wantPath:   "/web",
+			wantHeader: "Ocp-Apim-Subscription-Key",
+			response:   `{"webPages":{"value":[{"name":"Go","url":"https://go.dev/","snippet":"The Go programming language"},{"name":"Tour","url":"https://go.dev/tour","snippet":"A tour of Go"}]}}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tc.wantPath {
+					t.Errorf("path = %q, want %q", r.URL.Path, tc.wantPath)
+				}
+				if got := r.URL.Query().Get("q"); got != "golang" {
+					t.Errorf("query = %q, want %q", got, "golang")
+				}
+				if tc.wantHeader != "" && r.Header.Get(tc.wantHeader) != "key" {
+					t.Errorf("header %s = %q, want %q", tc.wantHeader, r.Header.Get(tc.wantHeader), "key")
+				}
+				w.Header().Set("Content-Type", "application/json")
+				io.WriteString(w, tc.response)
+			}))
+			defer server.Close()
+
+			got, err := tc.newFunc(server.URL).Search(t.Context(), "golang", SearchOptions{MaxResults: 5})
+			if err != nil {
+				t.Fatalf("Search() failed: %v", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+			}
+
+			got, err = tc.newFunc(server.URL).Search(t.Context(), "golang", SearchOptions{MaxResults: 1})
+			if err != nil {
+				t.Fatalf("Search() failed: %v", err)
+			}
+			if diff := cmp.Diff(want[:1], got); diff != "" {
+				t.Errorf("Search() with MaxResults 1 mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProviders_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid subscription token", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewBrave(BraveConfig{Endpoint: server.URL}).Search(t.Context(), "golang", SearchOptions{})
+	if err == nil || !strings.Contains(err.Error(), "status 401: invalid subscription token") {
+		t.Errorf("Search() error = %v, want status 401", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	a := &fakeProvider{name: "a", results: []Result{
+		{Title: "One", URL: "https://one.example.com/", Snippet: "one"},
+		{Title: "Two", URL: "https://two.example.com/page", Snippet: "two"},
+		{Title: "Two again", URL: "https://two.example.com/page#top", Snippet: "duplicate"},
+		{Title: "No URL"},
+	}}
+	b := &fakeProvider{name: "b", results: []Result{
+		{Title: "Two", URL: "http://www.two.example.com/page/?utm_source=x", Snippet: "two, longer snippet"},
+		{Title: "Three", URL: "https://three.example.com/", Snippet: "three"},
+	}}
+	failing := &fakeProvider{name: "failing", err: errors.New("unavailable")}
+
+	s, err := New(Config{Providers: []SearchProvider{a, b, failing}})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	got, err := s.Search(t.Context(), "query")
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	want := []RankedResult{
+		{Result: Result{Title: "Two", URL: "https://two.example.com/page", Snippet: "two, longer snippet"}, Sources: []string{"a", "b"}},
+		{Result: Result{Title: "One", URL: "https://one.example.com/", Snippet: "one"}, Sources: []string{"a"}},
+		{Result: Result{Title: "Three", URL: "https://three.example.com/", Snippet: "three"}, Sources: []string{"b"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+
+	s, err = New(Config{Providers: []SearchProvider{a, b}, MaxResults: 1})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	got, err = s.Search(t.Context(), "query")
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	if diff := cmp.Diff(want[:1], got); diff != "" {
+		t.Errorf("Search() with MaxResults 1 mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearch_AllProvidersFail(t *testing.T) {
+	s, err := New(Config{Providers: []SearchProvider{
+		&fakeProvider{name: "a", err: errors.New("quota exceeded")},
+		&fakeProvider{name: "b", err: errors.New("unavailable")},
+	}})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	_, err = s.Search(t.Context(), "query")
+	if err == nil || !strings.Contains(err.Error(), "a search failed: quota exceeded") || !strings.Contains(err.Error(), "b search failed: unavailable") {
+		t.Errorf("Search() error = %v, want errors of both providers", err)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Error("New() without providers succeeded, want error")
+	}
+	if _, err := New(Config{Providers: []SearchProvider{nil}}); err == nil {
+		t.Error("New() with nil provider succeeded, want error")
+	}
+}
+
+func TestRun(t *testing.T) {
+	p := &fakeProvider{name: "a", results: []Result{{Title: "Go", URL: "https://go.dev/", Snippet: "Go"}}}
+	s, err := New(Config{Providers: []SearchProvider{p}})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	got, err := s.Run(nil, map[string]any{"query": "golang"})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	want := map[string]any{
+		"query": "golang",
+		"results": []map[string]any{
+			{"title": "Go", "url": "https://go.dev/", "snippet": "Go", "sources": []string{"a"}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := s.Run(nil, map[string]any{"query": " "}); err == nil {
+		t.Error("Run() with empty query succeeded, want error")
+	}
+}